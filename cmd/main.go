@@ -51,7 +51,10 @@ func main() {
 	if err != nil {
 		log.Fatalf("Failed to initialize services: %v", err)
 	}
-	defer services.ShutdownServices(db, logService, emailService, jobQueue)
+
+	// Start background jobs
+	scheduler := services.SetupJobs(db, jobQueue, emailService)
+	defer services.ShutdownServices(db, logService, emailService, jobQueue, scheduler)
 
 	// Setup middleware (without geolocation and device detection)
 	middleware.SetupMiddleware(app, appConfig, logHandler)
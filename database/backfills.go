@@ -0,0 +1,84 @@
+package database
+
+import (
+	"log"
+	"ticket-zetu-api/modules/events/models/events"
+	"ticket-zetu-api/modules/events/models/seats"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const backfillBatchSize = 500
+
+// backfillEventSeats gives every active seat reservation its event seat row,
+// then clears the venue-wide status of seats that were only held or booked
+// through reservations, since that status now lives per event. Reservations
+// that already have their event seat are left alone.
+func backfillEventSeats(db *gorm.DB) error {
+	var reservations []seats.SeatReservation
+	created := 0
+	result := db.Where("status IN ? AND deleted_at IS NULL", []string{"held", "confirmed"}).
+		Order("created_at ASC").
+		FindInBatches(&reservations, backfillBatchSize, func(tx *gorm.DB, batch int) error {
+			rows := make([]seats.EventSeat, 0, len(reservations))
+			for _, reservation := range reservations {
+				status := seats.EventSeatHeld
+				if reservation.Status == "confirmed" {
+					status = seats.EventSeatBooked
+				}
+				reservationID := reservation.ID
+				rows = append(rows, seats.EventSeat{
+					EventID:       reservation.EventID,
+					SeatID:        reservation.SeatID,
+					Status:        status,
+					ReservationID: &reservationID,
+				})
+			}
+			// Only one reservation per seat and event can be kept; the oldest wins.
+			// A new statement on the batch's connection drops the reservation query.
+			insert := tx.Session(&gorm.Session{NewDB: true}).Clauses(clause.OnConflict{DoNothing: true}).Create(&rows)
+			created += int(insert.RowsAffected)
+			return insert.Error
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	reset := db.Model(&seats.Seat{}).
+		Where("status <> ? AND id IN (?)", "available", db.Model(&seats.SeatReservation{}).Select("seat_id")).
+		Update("status", "available")
+	if reset.Error != nil {
+		return reset.Error
+	}
+
+	log.Printf("Backfilled %d event seats and reset %d seat statuses\n", created, reset.RowsAffected)
+	return nil
+}
+
+// migrateEventStatuses maps the statuses events had before the lifecycle
+// states: active events were on sale, so they become published, and
+// inactive ones go back to draft.
+func migrateEventStatuses(db *gorm.DB) error {
+	published := db.Model(&events.Event{}).
+		Where("status = ?", "active").
+		UpdateColumns(map[string]interface{}{
+			"status":       events.EventPublished,
+			"published_at": gorm.Expr("COALESCE(published_at, created_at)"),
+		})
+	if published.Error != nil {
+		return published.Error
+	}
+
+	drafted := db.Model(&events.Event{}).
+		Where("status = ?", "inactive").
+		UpdateColumn("status", events.EventDraft)
+	if drafted.Error != nil {
+		return drafted.Error
+	}
+
+	if published.RowsAffected+drafted.RowsAffected > 0 {
+		log.Printf("Moved %d active and %d inactive events to lifecycle statuses", published.RowsAffected, drafted.RowsAffected)
+	}
+	return nil
+}
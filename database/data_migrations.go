@@ -0,0 +1,62 @@
+package database
+
+import (
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// DataMigration records a one-off data migration that has been applied, so
+// it is not run again on the next startup
+type DataMigration struct {
+	Version   string    `gorm:"type:varchar(100);primaryKey"`
+	AppliedAt time.Time `gorm:"autoCreateTime"`
+}
+
+func (DataMigration) TableName() string {
+	return "data_migrations"
+}
+
+type dataMigration struct {
+	version string
+	run     func(db *gorm.DB) error
+}
+
+// dataMigrations change existing rows after the schema has been migrated.
+// They run once each, in this order; add new ones at the end.
+var dataMigrations = []dataMigration{
+	{version: "0001_backfill_event_seats", run: backfillEventSeats},
+	{version: "0002_event_lifecycle_statuses", run: migrateEventStatuses},
+}
+
+// runDataMigrations applies the data migrations not yet recorded. Each one
+// is recorded in the same transaction it runs in, so a failed migration is
+// tried again in full on the next startup.
+func runDataMigrations(db *gorm.DB) error {
+	var applied []string
+	if err := db.Model(&DataMigration{}).Pluck("version", &applied).Error; err != nil {
+		return err
+	}
+	done := make(map[string]bool, len(applied))
+	for _, version := range applied {
+		done[version] = true
+	}
+
+	for _, migration := range dataMigrations {
+		if done[migration.version] {
+			continue
+		}
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := migration.run(tx); err != nil {
+				return err
+			}
+			return tx.Create(&DataMigration{Version: migration.version}).Error
+		})
+		if err != nil {
+			return err
+		}
+		log.Printf("Applied data migration %s\n", migration.version)
+	}
+	return nil
+}
@@ -9,6 +9,7 @@ import (
 	OrganizationSubscription "ticket-zetu-api/modules/organizers/models"
 	Organizer "ticket-zetu-api/modules/organizers/models"
 
+	EventPermissionGrant "ticket-zetu-api/modules/users/models/authorization"
 	Permission "ticket-zetu-api/modules/users/models/authorization"
 	Role "ticket-zetu-api/modules/users/models/authorization"
 	RolePermission "ticket-zetu-api/modules/users/models/authorization"
@@ -20,17 +21,51 @@ import (
 
 	Comment "ticket-zetu-api/modules/events/models/events"
 	Event "ticket-zetu-api/modules/events/models/events"
+	EventCancellation "ticket-zetu-api/modules/events/models/events"
+	EventCancellationFailure "ticket-zetu-api/modules/events/models/events"
 	EventImage "ticket-zetu-api/modules/events/models/events"
+	EventSeries "ticket-zetu-api/modules/events/models/events"
+	EventSeriesException "ticket-zetu-api/modules/events/models/events"
+	EventSeriesTicketType "ticket-zetu-api/modules/events/models/events"
 	Favorite "ticket-zetu-api/modules/events/models/events"
 	Venue "ticket-zetu-api/modules/events/models/events"
 	Vote "ticket-zetu-api/modules/events/models/events"
+	EventSeat "ticket-zetu-api/modules/events/models/seats"
 	Seat "ticket-zetu-api/modules/events/models/seats"
 	SeatReservation "ticket-zetu-api/modules/events/models/seats"
 
+	CompGrant "ticket-zetu-api/modules/tickets/models/tickets"
 	DiscountCode "ticket-zetu-api/modules/tickets/models/tickets"
+	DiscountRedemption "ticket-zetu-api/modules/tickets/models/tickets"
+	EventPurchaseLimit "ticket-zetu-api/modules/tickets/models/tickets"
+	Order "ticket-zetu-api/modules/tickets/models/tickets"
+	PaymentRefund "ticket-zetu-api/modules/tickets/models/tickets"
+	PaymentTransaction "ticket-zetu-api/modules/tickets/models/tickets"
+	PaymentWebhookEvent "ticket-zetu-api/modules/tickets/models/tickets"
 	PriceTier "ticket-zetu-api/modules/tickets/models/tickets"
+	PricingRule "ticket-zetu-api/modules/tickets/models/tickets"
+	RefundPolicy "ticket-zetu-api/modules/tickets/models/tickets"
+	RefundRequest "ticket-zetu-api/modules/tickets/models/tickets"
+	RefundRequestItem "ticket-zetu-api/modules/tickets/models/tickets"
+	ResalePayout "ticket-zetu-api/modules/tickets/models/tickets"
+	ResalePolicy "ticket-zetu-api/modules/tickets/models/tickets"
 	Ticket "ticket-zetu-api/modules/tickets/models/tickets"
+	TicketHold "ticket-zetu-api/modules/tickets/models/tickets"
+	TicketResale "ticket-zetu-api/modules/tickets/models/tickets"
+	TicketScan "ticket-zetu-api/modules/tickets/models/tickets"
+	TicketStock "ticket-zetu-api/modules/tickets/models/tickets"
+	TicketTransfer "ticket-zetu-api/modules/tickets/models/tickets"
 	TicketType "ticket-zetu-api/modules/tickets/models/tickets"
+	UserTicketLimits "ticket-zetu-api/modules/tickets/models/tickets"
+	WaitlistEntry "ticket-zetu-api/modules/tickets/models/tickets"
+
+	LedgerAccount "ticket-zetu-api/modules/ledger/models"
+	LedgerEntry "ticket-zetu-api/modules/ledger/models"
+	LedgerTransaction "ticket-zetu-api/modules/ledger/models"
+	PayoutRequest "ticket-zetu-api/modules/ledger/models"
+
+	Invoice "ticket-zetu-api/modules/invoices/models"
+	InvoiceSequence "ticket-zetu-api/modules/invoices/models"
 
 	VenueImage "ticket-zetu-api/modules/events/models/events"
 
@@ -47,11 +82,13 @@ func Migrate(db *gorm.DB) error {
 	models := []interface{}{
 		// System Models
 		&Logs.Log{},
+		&DataMigration{},
 
 		// Authorization Models
 		&Permission.Permission{},
 		&Role.Role{},
 		&RolePermission.RolePermission{},
+		&EventPermissionGrant.EventPermissionGrant{},
 
 		// User Models
 		&User.User{},
@@ -71,19 +108,55 @@ func Migrate(db *gorm.DB) error {
 		// Event Models
 		&Venue.Venue{},
 		&VenueImage.VenueImage{},
+		&EventSeries.EventSeries{},
+		&EventSeriesTicketType.EventSeriesTicketType{},
+		&EventSeriesException.EventSeriesException{},
 		&Event.Event{},
 		&EventImage.EventImage{},
+		&EventCancellation.EventCancellation{},
+		&EventCancellationFailure.EventCancellationFailure{},
 		&Favorite.Favorite{},
 		&Vote.Vote{},
 		&Comment.Comment{},
 		&Seat.Seat{},
 		&SeatReservation.SeatReservation{},
+		&EventSeat.EventSeat{},
 
 		// Ticket Models
 		&PriceTier.PriceTier{},
 		&TicketType.TicketType{},
+		&TicketStock.TicketStock{},
+		&PricingRule.PricingRule{},
+		&TicketHold.TicketHold{},
 		&DiscountCode.DiscountCode{},
+		&EventPurchaseLimit.EventPurchaseLimit{},
+		&UserTicketLimits.UserTicketLimits{},
+		&Order.Order{},
+		&DiscountRedemption.DiscountRedemption{},
 		&Ticket.Ticket{},
+		&TicketScan.TicketScan{},
+		&TicketTransfer.TicketTransfer{},
+		&PaymentTransaction.PaymentTransaction{},
+		&PaymentWebhookEvent.PaymentWebhookEvent{},
+		&PaymentRefund.PaymentRefund{},
+		&RefundPolicy.RefundPolicy{},
+		&RefundRequest.RefundRequest{},
+		&RefundRequestItem.RefundRequestItem{},
+		&ResalePolicy.ResalePolicy{},
+		&TicketResale.TicketResale{},
+		&ResalePayout.ResalePayout{},
+		&WaitlistEntry.WaitlistEntry{},
+		&CompGrant.CompGrant{},
+
+		// Ledger Models
+		&LedgerAccount.LedgerAccount{},
+		&LedgerTransaction.LedgerTransaction{},
+		&LedgerEntry.LedgerEntry{},
+		&PayoutRequest.PayoutRequest{},
+
+		// Invoice Models
+		&InvoiceSequence.InvoiceSequence{},
+		&Invoice.Invoice{},
 
 		//Notification
 		&Notification.Notification{},
@@ -95,36 +168,28 @@ func Migrate(db *gorm.DB) error {
 
 	db = db.Debug()
 
-	// Check if any migrations are needed
-	migrationsNeeded := false
-	for _, model := range models {
-		if !db.Migrator().HasTable(model) {
-			migrationsNeeded = true
-			break
-		}
-	}
-
-	if !migrationsNeeded {
-		log.Println("No migrations needed - all tables exist")
-		return nil
-	}
-
+	// AutoMigrate is run for every model, not only missing tables, so that
+	// columns and indexes added to existing models are applied as well
 	log.Println("Running database migrations...")
 	migrationCount := 0
 	for _, model := range models {
 		if !db.Migrator().HasTable(model) {
-			if err := db.AutoMigrate(model); err != nil {
-				return err
-			}
 			migrationCount++
 			log.Printf("Migrated table for %T\n", model)
 		}
+		if err := db.AutoMigrate(model); err != nil {
+			return err
+		}
+	}
+
+	if err := runDataMigrations(db); err != nil {
+		return err
 	}
 
 	if migrationCount == 0 {
-		log.Println("No new migrations were applied")
+		log.Println("No new tables were created")
 	} else {
-		log.Printf("Migrations completed successfully. Applied %d migrations\n", migrationCount)
+		log.Printf("Migrations completed successfully. Created %d tables\n", migrationCount)
 	}
 	return nil
 }
@@ -2,7 +2,12 @@ module ticket-zetu-api
 
 go 1.24.0
 
-require github.com/swaggo/swag v1.16.4
+require (
+	github.com/go-pdf/fpdf v0.9.0
+	github.com/shopspring/decimal v1.4.0
+	github.com/skip2/go-qrcode v0.0.0-20200617195104-da1b6568686e
+	github.com/swaggo/swag v1.16.4
+)
 
 require (
 	filippo.io/edwards25519 v1.1.0 // indirect
@@ -12,9 +12,13 @@ import (
 	logs "ticket-zetu-api/logs/routes/v1"
 	"ticket-zetu-api/logs/service"
 	"ticket-zetu-api/mail"
+	event_jobs "ticket-zetu-api/modules/events/jobs"
 	events "ticket-zetu-api/modules/events/routes/v1"
+	invoices "ticket-zetu-api/modules/invoices/routes/v1"
+	ledger "ticket-zetu-api/modules/ledger/routes/v1"
 	notifications "ticket-zetu-api/modules/notifications/routes/v1"
 	organization "ticket-zetu-api/modules/organizers/routes/v1"
+	ticket_jobs "ticket-zetu-api/modules/tickets/jobs"
 	tickets "ticket-zetu-api/modules/tickets/routes/v1"
 	mail_service "ticket-zetu-api/modules/users/authentication/mail"
 	"ticket-zetu-api/modules/users/helpers"
@@ -59,7 +63,20 @@ func SetupServices(cfg *config.AppConfig, db *gorm.DB, logService *service.LogSe
 	return cloudinaryService, emailService, jobQueue, geoService, deviceService, nil
 }
 
-func ShutdownServices(db *gorm.DB, logService *service.LogService, emailService mail_service.EmailService, jobQueue *queue.JobQueue) {
+// SetupJobs registers and starts the periodic background jobs
+func SetupJobs(db *gorm.DB, jobQueue *queue.JobQueue, emailService mail_service.EmailService) *queue.Scheduler {
+	scheduler := queue.NewScheduler(database.GetRedisClient(), jobQueue)
+	ticket_jobs.RegisterTicketJobs(scheduler, db, emailService)
+	event_jobs.RegisterEventJobs(scheduler, db)
+	scheduler.Start()
+	return scheduler
+}
+
+func ShutdownServices(db *gorm.DB, logService *service.LogService, emailService mail_service.EmailService, jobQueue *queue.JobQueue, scheduler *queue.Scheduler) {
+	// Stop scheduling before the job queue closes so no job is enqueued on a closed queue
+	if scheduler != nil {
+		scheduler.Stop()
+	}
 	if db != nil {
 		database.CloseDB()
 	}
@@ -89,4 +106,6 @@ func SetupRoutes(app *fiber.App, db *gorm.DB, logService *service.LogService, cl
 	events.SetupEventsRoutes(api, db, logHandler, cloudinaryService)
 	tickets.SetupTicketMainRoutes(api, db, logHandler, cloudinaryService, emailService)
 	notifications.SetupNotificationMainRoutes(api, db, logHandler, emailService)
+	ledger.SetupLedgerMainRoutes(api, db, logHandler)
+	invoices.SetupInvoiceMainRoutes(api, db, logHandler, emailService)
 }
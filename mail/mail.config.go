@@ -18,9 +18,13 @@ type EmailConfig struct {
 
 // EmailTemplateConfig holds email template paths
 type EmailTemplateConfig struct {
-	VerificationTemplatePath  string
-	LoginWarningTemplatePath  string
-	PasswordResetTemplatePath string
+	VerificationTemplatePath   string
+	LoginWarningTemplatePath   string
+	PasswordResetTemplatePath  string
+	WaitlistOfferTemplatePath  string
+	InvoiceTemplatePath        string
+	CompTicketTemplatePath     string
+	EventCancelledTemplatePath string
 }
 
 // AppConfig holds application URLs
@@ -57,9 +61,13 @@ func NewConfig(logHandler *handler.LogHandler) (*Config, error) {
 			FromEmail:    os.Getenv("FROM_EMAIL"),
 		},
 		TemplateConfig: EmailTemplateConfig{
-			VerificationTemplatePath:  os.Getenv("VERIFICATION_TEMPLATE_PATH"),
-			LoginWarningTemplatePath:  os.Getenv("LOGIN_WARNING_TEMPLATE_PATH"),
-			PasswordResetTemplatePath: os.Getenv("PASSWORD_RESET_TEMPLATE_PATH"),
+			VerificationTemplatePath:   os.Getenv("VERIFICATION_TEMPLATE_PATH"),
+			LoginWarningTemplatePath:   os.Getenv("LOGIN_WARNING_TEMPLATE_PATH"),
+			PasswordResetTemplatePath:  os.Getenv("PASSWORD_RESET_TEMPLATE_PATH"),
+			WaitlistOfferTemplatePath:  os.Getenv("WAITLIST_OFFER_TEMPLATE_PATH"),
+			InvoiceTemplatePath:        os.Getenv("INVOICE_TEMPLATE_PATH"),
+			CompTicketTemplatePath:     os.Getenv("COMP_TICKET_TEMPLATE_PATH"),
+			EventCancelledTemplatePath: os.Getenv("EVENT_CANCELLED_TEMPLATE_PATH"),
 		},
 		AppConfig: AppConfig{
 			SecurityURL: os.Getenv("SECURITY_URL"),
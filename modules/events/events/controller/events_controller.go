@@ -5,6 +5,7 @@ import (
 	"ticket-zetu-api/cloudinary"
 	"ticket-zetu-api/logs/handler"
 	"ticket-zetu-api/modules/events/events/service"
+	"ticket-zetu-api/modules/events/models/events"
 	"time"
 
 	"github.com/go-playground/validator/v10"
@@ -119,7 +120,7 @@ func (c *EventController) GetEventsForOrganizer(ctx *fiber.Ctx) error {
 // @Param end_date query string false "End date filter (ISO 8601)" Format(date-time)
 // @Param event_type query string false "Event type filter" Enums(online, offline, hybrid)
 // @Param is_free query boolean false "Filter by free or paid events"
-// @Param status query string false "Event status filter" Enums(published, draft, cancelled)
+// @Param status query string false "Event status filter" Enums(draft, scheduled, published, postponed, cancelled, completed)
 // @Param min_price query number false "Minimum ticket price filter"
 // @Param max_price query number false "Maximum ticket price filter"
 // @Param page query integer false "Page number (default: 1)" Minimum(1)
@@ -133,6 +134,30 @@ func (c *EventController) GetEventsForOrganizer(ctx *fiber.Ctx) error {
 func (c *EventController) SearchEvents(ctx *fiber.Ctx) error {
 	userID := ctx.Locals("user_id").(string)
 
+	filter, err := c.parseSearchFilter(ctx)
+	if err != nil {
+		return c.logHandler.LogError(ctx, err, fiber.StatusBadRequest)
+	}
+
+	// Call service
+	result, err := c.service.SearchEvents(userID, filter)
+	if err != nil {
+		switch err.Error() {
+		case "user lacks read:events permission":
+			return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusForbidden, err.Error()), fiber.StatusForbidden)
+		case "organizer not found":
+			return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusNotFound, err.Error()), fiber.StatusNotFound)
+		default:
+			return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusInternalServerError, "Internal server error"), fiber.StatusInternalServerError)
+		}
+	}
+
+	return c.logHandler.LogSuccess(ctx, result, "Events retrieved successfully", true)
+}
+
+// parseSearchFilter reads the search filters and pagination from the query
+// string
+func (c *EventController) parseSearchFilter(ctx *fiber.Ctx) (service.SearchFilter, error) {
 	// Parse query parameters
 	var filter service.SearchFilter
 
@@ -143,14 +168,14 @@ func (c *EventController) SearchEvents(ctx *fiber.Ctx) error {
 	if startDate := ctx.Query("start_date"); startDate != "" {
 		parsedDate, err := time.Parse(time.RFC3339, startDate)
 		if err != nil {
-			return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, "Invalid start_date format"), fiber.StatusBadRequest)
+			return filter, fiber.NewError(fiber.StatusBadRequest, "Invalid start_date format")
 		}
 		filter.StartDate = &parsedDate
 	}
 	if endDate := ctx.Query("end_date"); endDate != "" {
 		parsedDate, err := time.Parse(time.RFC3339, endDate)
 		if err != nil {
-			return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, "Invalid end_date format"), fiber.StatusBadRequest)
+			return filter, fiber.NewError(fiber.StatusBadRequest, "Invalid end_date format")
 		}
 		filter.EndDate = &parsedDate
 	}
@@ -158,7 +183,7 @@ func (c *EventController) SearchEvents(ctx *fiber.Ctx) error {
 	// Event type
 	if eventType := ctx.Query("event_type"); eventType != "" {
 		if eventType != "online" && eventType != "offline" && eventType != "hybrid" {
-			return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, "Invalid event_type. Must be one of: online, offline, hybrid"), fiber.StatusBadRequest)
+			return filter, fiber.NewError(fiber.StatusBadRequest, "Invalid event_type. Must be one of: online, offline, hybrid")
 		}
 		filter.EventType = eventType
 	}
@@ -167,15 +192,17 @@ func (c *EventController) SearchEvents(ctx *fiber.Ctx) error {
 	if isFree := ctx.Query("is_free"); isFree != "" {
 		parsedBool, err := strconv.ParseBool(isFree)
 		if err != nil {
-			return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, "Invalid is_free format"), fiber.StatusBadRequest)
+			return filter, fiber.NewError(fiber.StatusBadRequest, "Invalid is_free format")
 		}
 		filter.IsFree = &parsedBool
 	}
 
 	// Status
 	if status := ctx.Query("status"); status != "" {
-		if status != "published" && status != "draft" && status != "cancelled" {
-			return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, "Invalid status. Must be one of: published, draft, cancelled"), fiber.StatusBadRequest)
+		switch events.EventStatus(status) {
+		case events.EventDraft, events.EventScheduled, events.EventPublished, events.EventPostponed, events.EventCancelled, events.EventCompleted:
+		default:
+			return filter, fiber.NewError(fiber.StatusBadRequest, "Invalid status. Must be one of: draft, scheduled, published, postponed, cancelled, completed")
 		}
 		filter.Status = status
 	}
@@ -184,14 +211,14 @@ func (c *EventController) SearchEvents(ctx *fiber.Ctx) error {
 	if minPrice := ctx.Query("min_price"); minPrice != "" {
 		parsedPrice, err := strconv.ParseFloat(minPrice, 64)
 		if err != nil || parsedPrice < 0 {
-			return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, "Invalid min_price format or negative value"), fiber.StatusBadRequest)
+			return filter, fiber.NewError(fiber.StatusBadRequest, "Invalid min_price format or negative value")
 		}
 		filter.MinPrice = &parsedPrice
 	}
 	if maxPrice := ctx.Query("max_price"); maxPrice != "" {
 		parsedPrice, err := strconv.ParseFloat(maxPrice, 64)
 		if err != nil || parsedPrice < 0 {
-			return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, "Invalid max_price format or negative value"), fiber.StatusBadRequest)
+			return filter, fiber.NewError(fiber.StatusBadRequest, "Invalid max_price format or negative value")
 		}
 		filter.MaxPrice = &parsedPrice
 	}
@@ -199,28 +226,15 @@ func (c *EventController) SearchEvents(ctx *fiber.Ctx) error {
 	// Pagination
 	page, err := strconv.Atoi(ctx.Query("page", "1"))
 	if err != nil || page < 1 {
-		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, "Invalid page number"), fiber.StatusBadRequest)
+		return filter, fiber.NewError(fiber.StatusBadRequest, "Invalid page number")
 	}
 	filter.Page = page
 
 	pageSize, err := strconv.Atoi(ctx.Query("page_size", "20"))
 	if err != nil || pageSize < 1 || pageSize > 100 {
-		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, "Invalid page_size. Must be between 1 and 100"), fiber.StatusBadRequest)
+		return filter, fiber.NewError(fiber.StatusBadRequest, "Invalid page_size. Must be between 1 and 100")
 	}
 	filter.PageSize = pageSize
 
-	// Call service
-	result, err := c.service.SearchEvents(userID, filter)
-	if err != nil {
-		switch err.Error() {
-		case "user lacks read:events permission":
-			return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusForbidden, err.Error()), fiber.StatusForbidden)
-		case "organizer not found":
-			return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusNotFound, err.Error()), fiber.StatusNotFound)
-		default:
-			return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusInternalServerError, "Internal server error"), fiber.StatusInternalServerError)
-		}
-	}
-
-	return c.logHandler.LogSuccess(ctx, result, "Events retrieved successfully", true)
+	return filter, nil
 }
@@ -0,0 +1,175 @@
+package controller
+
+import (
+	"ticket-zetu-api/modules/events/events/dto"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func (c *EventController) handleLifecycleError(ctx *fiber.Ctx, err error) error {
+	switch err.Error() {
+	case "user lacks update:events permission":
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusForbidden, err.Error()), fiber.StatusForbidden)
+	case "event not found", "organizer not found", "event cancellation not found":
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusNotFound, err.Error()), fiber.StatusNotFound)
+	case "invalid event ID format", "end time must be after start time", "new date must be in the future",
+		"publish time must be before the event starts", "a postponed event must start later than before":
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, err.Error()), fiber.StatusBadRequest)
+	case "invalid event status transition", "event has already ended", "event is not scheduled":
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusConflict, err.Error()), fiber.StatusConflict)
+	default:
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusInternalServerError, err.Error()), fiber.StatusInternalServerError)
+	}
+}
+
+// PublishEvent godoc
+// @Summary Publish an event
+// @Description Publishes a draft or scheduled event straight away, or schedules it to be published automatically at publish_at.
+// @Tags Event Group
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Event ID" Format(uuid)
+// @Param input body dto.PublishEvent false "Optional future publish time"
+// @Success 200 {object} map[string]interface{} "Event published successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid request payload"
+// @Failure 403 {object} map[string]interface{} "User lacks update:events permission"
+// @Failure 404 {object} map[string]interface{} "Event not found"
+// @Failure 409 {object} map[string]interface{} "Event cannot be published from its current status"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /events/{id}/publish [post]
+func (c *EventController) PublishEvent(ctx *fiber.Ctx) error {
+	userID := ctx.Locals("user_id").(string)
+
+	var input dto.PublishEvent
+	if len(ctx.Body()) > 0 {
+		if err := ctx.BodyParser(&input); err != nil {
+			return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, "Invalid request body"), fiber.StatusBadRequest)
+		}
+	}
+
+	event, err := c.service.PublishEvent(userID, ctx.Params("id"), input)
+	if err != nil {
+		return c.handleLifecycleError(ctx, err)
+	}
+	if event.Status == "scheduled" {
+		return c.logHandler.LogSuccess(ctx, event, "Event scheduled for publishing", true)
+	}
+	return c.logHandler.LogSuccess(ctx, event, "Event published successfully", true)
+}
+
+// UnscheduleEvent godoc
+// @Summary Cancel the scheduled publishing of an event
+// @Description Returns a scheduled event to draft so it is not published automatically.
+// @Tags Event Group
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Event ID" Format(uuid)
+// @Success 200 {object} map[string]interface{} "Event unscheduled successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid event ID format"
+// @Failure 403 {object} map[string]interface{} "User lacks update:events permission"
+// @Failure 404 {object} map[string]interface{} "Event not found"
+// @Failure 409 {object} map[string]interface{} "Event is not scheduled"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /events/{id}/unschedule [post]
+func (c *EventController) UnscheduleEvent(ctx *fiber.Ctx) error {
+	userID := ctx.Locals("user_id").(string)
+
+	event, err := c.service.UnscheduleEvent(userID, ctx.Params("id"))
+	if err != nil {
+		return c.handleLifecycleError(ctx, err)
+	}
+	return c.logHandler.LogSuccess(ctx, event, "Event unscheduled successfully", true)
+}
+
+// PostponeEvent godoc
+// @Summary Postpone an event
+// @Description Moves a published event to a later date. Existing tickets stay valid for the new date and ticket holders are notified.
+// @Tags Event Group
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Event ID" Format(uuid)
+// @Param input body dto.PostponeEvent true "New start and end time"
+// @Success 200 {object} map[string]interface{} "Event postponed successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid request payload or date"
+// @Failure 403 {object} map[string]interface{} "User lacks update:events permission"
+// @Failure 404 {object} map[string]interface{} "Event not found"
+// @Failure 409 {object} map[string]interface{} "Event cannot be postponed from its current status"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /events/{id}/postpone [post]
+func (c *EventController) PostponeEvent(ctx *fiber.Ctx) error {
+	userID := ctx.Locals("user_id").(string)
+
+	var input dto.PostponeEvent
+	if err := ctx.BodyParser(&input); err != nil {
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, "Invalid request body"), fiber.StatusBadRequest)
+	}
+	if err := c.validator.Struct(input); err != nil {
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, err.Error()), fiber.StatusBadRequest)
+	}
+
+	event, err := c.service.PostponeEvent(userID, ctx.Params("id"), input)
+	if err != nil {
+		return c.handleLifecycleError(ctx, err)
+	}
+	return c.logHandler.LogSuccess(ctx, event, "Event postponed successfully", true)
+}
+
+// CancelEvent godoc
+// @Summary Cancel an event
+// @Description Cancels an event for good and stops its ticket sales. Holds, seats and resale listings are then released, unpaid orders cancelled, paid orders refunded at the event's cancellation refund percentage and every ticket holder notified by a background job; follow its progress on the cancellation endpoint.
+// @Tags Event Group
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Event ID" Format(uuid)
+// @Param input body dto.CancelEvent false "Reason shared with ticket holders"
+// @Success 200 {object} map[string]interface{} "Event cancelled; refunds and notifications are in progress"
+// @Failure 400 {object} map[string]interface{} "Invalid request payload"
+// @Failure 403 {object} map[string]interface{} "User lacks update:events permission"
+// @Failure 404 {object} map[string]interface{} "Event not found"
+// @Failure 409 {object} map[string]interface{} "Event cannot be cancelled from its current status"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /events/{id}/cancel [post]
+func (c *EventController) CancelEvent(ctx *fiber.Ctx) error {
+	userID := ctx.Locals("user_id").(string)
+
+	var input dto.CancelEvent
+	if len(ctx.Body()) > 0 {
+		if err := ctx.BodyParser(&input); err != nil {
+			return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, "Invalid request body"), fiber.StatusBadRequest)
+		}
+	}
+	if err := c.validator.Struct(input); err != nil {
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, err.Error()), fiber.StatusBadRequest)
+	}
+
+	cancellation, err := c.service.CancelEvent(userID, ctx.Params("id"), input)
+	if err != nil {
+		return c.handleLifecycleError(ctx, err)
+	}
+	return c.logHandler.LogSuccess(ctx, cancellation, "Event cancelled; refunds and notifications are in progress", true)
+}
+
+// GetEventCancellation godoc
+// @Summary Follow the cancellation of an event
+// @Description Reports how far the cancellation of one of the organizer's events has got: its current stage and how many orders, refunds, tickets and holders it has handled.
+// @Tags Event Group
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Event ID" Format(uuid)
+// @Success 200 {object} map[string]interface{} "Event cancellation retrieved successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid event ID format"
+// @Failure 404 {object} map[string]interface{} "Event cancellation not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /events/{id}/cancellation [get]
+func (c *EventController) GetEventCancellation(ctx *fiber.Ctx) error {
+	userID := ctx.Locals("user_id").(string)
+
+	cancellation, err := c.service.GetEventCancellation(userID, ctx.Params("id"))
+	if err != nil {
+		return c.handleLifecycleError(ctx, err)
+	}
+	return c.logHandler.LogSuccess(ctx, cancellation, "Event cancellation retrieved successfully", true)
+}
@@ -19,6 +19,7 @@ import (
 // @Failure 400 {object} map[string]interface{} "Invalid request payload"
 // @Failure 403 {object} map[string]interface{} "User lacks permission"
 // @Failure 404 {object} map[string]interface{} "Event, venue or subcategory not found"
+// @Failure 409 {object} map[string]interface{} "Status change not allowed"
 // @Failure 500 {object} map[string]interface{} "Internal server error"
 // @Router /events/{id} [put]
 func (c *EventController) UpdateEvent(ctx *fiber.Ctx) error {
@@ -47,6 +48,10 @@ func (c *EventController) UpdateEvent(ctx *fiber.Ctx) error {
 			return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, err.Error()), fiber.StatusBadRequest)
 		case "invalid event ID format", "invalid subcategory ID format", "invalid venue ID format":
 			return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, err.Error()), fiber.StatusBadRequest)
+		case "status must be changed through its lifecycle action":
+			return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, err.Error()), fiber.StatusBadRequest)
+		case "invalid event status transition", "event has already ended":
+			return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusConflict, err.Error()), fiber.StatusConflict)
 		default:
 			return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusInternalServerError, err.Error()), fiber.StatusInternalServerError)
 		}
@@ -57,7 +62,7 @@ func (c *EventController) UpdateEvent(ctx *fiber.Ctx) error {
 
 // DeleteEvent godoc
 // @Summary Delete an event
-// @Description Deletes an event and its associated resources
+// @Description Deletes an event and its associated resources. Events with ticket holders must be cancelled instead, and can be deleted once the cancellation has finished.
 // @Tags Event Group
 // @Accept json
 // @Produce json
@@ -67,6 +72,7 @@ func (c *EventController) UpdateEvent(ctx *fiber.Ctx) error {
 // @Failure 400 {object} map[string]interface{} "Invalid event ID"
 // @Failure 403 {object} map[string]interface{} "User lacks permission"
 // @Failure 404 {object} map[string]interface{} "Event not found"
+// @Failure 409 {object} map[string]interface{} "Event has ticket holders or is still being cancelled"
 // @Failure 500 {object} map[string]interface{} "Internal server error"
 // @Router /events/{id} [delete]
 func (c *EventController) DeleteEvent(ctx *fiber.Ctx) error {
@@ -84,6 +90,8 @@ func (c *EventController) DeleteEvent(ctx *fiber.Ctx) error {
 			return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, err.Error()), fiber.StatusBadRequest)
 		case "cannot delete an active event":
 			return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, err.Error()), fiber.StatusBadRequest)
+		case "event has ticket holders; cancel it instead", "event cancellation is still in progress":
+			return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusConflict, err.Error()), fiber.StatusConflict)
 		default:
 			return c.logHandler.LogError(ctx, err, fiber.StatusInternalServerError)
 		}
@@ -0,0 +1,65 @@
+package controller
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetPublicEvents godoc
+// @Summary Browse published events
+// @Description Searches the events anyone can see: published, postponed, cancelled and completed events. Drafts and events waiting to be published are never listed.
+// @Tags Public Event Group
+// @Produce json
+// @Param query query string false "Search term for event title or description"
+// @Param start_date query string false "Start date filter (ISO 8601)" Format(date-time)
+// @Param end_date query string false "End date filter (ISO 8601)" Format(date-time)
+// @Param event_type query string false "Event type filter" Enums(online, offline, hybrid)
+// @Param is_free query boolean false "Filter by free or paid events"
+// @Param status query string false "Event status filter" Enums(published, postponed, cancelled, completed)
+// @Param min_price query number false "Minimum ticket price filter"
+// @Param max_price query number false "Maximum ticket price filter"
+// @Param page query integer false "Page number (default: 1)" Minimum(1)
+// @Param page_size query integer false "Items per page (default: 20, max: 100)" Minimum(1) Maximum(100)
+// @Success 200 {object} map[string]interface{} "Events retrieved successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid query parameters"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /public/events [get]
+func (c *EventController) GetPublicEvents(ctx *fiber.Ctx) error {
+	filter, err := c.parseSearchFilter(ctx)
+	if err != nil {
+		return c.logHandler.LogError(ctx, err, fiber.StatusBadRequest)
+	}
+
+	result, err := c.service.GetPublicEvents(filter)
+	if err != nil {
+		switch err.Error() {
+		case "invalid status filter":
+			return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, err.Error()), fiber.StatusBadRequest)
+		default:
+			return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusInternalServerError, "Internal server error"), fiber.StatusInternalServerError)
+		}
+	}
+	return c.logHandler.LogSuccess(ctx, result, "Events retrieved successfully", true)
+}
+
+// GetPublicEvent godoc
+// @Summary Retrieve a published event
+// @Description Retrieves an event anyone can see by its ID or slug. Drafts and events waiting to be published are not found.
+// @Tags Public Event Group
+// @Produce json
+// @Param id path string true "Event ID or slug"
+// @Success 200 {object} map[string]interface{} "Event retrieved successfully"
+// @Failure 404 {object} map[string]interface{} "Event not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /public/events/{id} [get]
+func (c *EventController) GetPublicEvent(ctx *fiber.Ctx) error {
+	event, err := c.service.GetPublicEvent(ctx.Params("id"))
+	if err != nil {
+		switch err.Error() {
+		case "event not found":
+			return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusNotFound, err.Error()), fiber.StatusNotFound)
+		default:
+			return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusInternalServerError, "Internal server error"), fiber.StatusInternalServerError)
+		}
+	}
+	return c.logHandler.LogSuccess(ctx, event, "Event retrieved successfully", true)
+}
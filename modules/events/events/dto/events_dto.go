@@ -4,6 +4,7 @@ import (
 	"ticket-zetu-api/modules/events/models/categories"
 	"ticket-zetu-api/modules/events/models/events"
 	"ticket-zetu-api/modules/tickets/models/tickets"
+	pricing_dto "ticket-zetu-api/modules/tickets/pricing/dto"
 	"time"
 )
 
@@ -42,6 +43,7 @@ type TicketTypeResponse struct {
 	SalesStart        time.Time                `json:"sales_start"`
 	SalesEnd          *time.Time               `json:"sales_end,omitempty"`
 	PriceTiers        []PriceTierResponse      `json:"price_tiers,omitempty"`
+	Pricing           *pricing_dto.PriceQuote  `json:"pricing,omitempty"`
 	CreatedAt         time.Time                `json:"created_at"`
 	UpdatedAt         time.Time                `json:"updated_at"`
 }
@@ -100,6 +102,23 @@ type UpdateEvent struct {
 	TicketTypes   *[]TicketTypeResponse `json:"ticket_types,omitempty" validate:"dive"`
 }
 
+// PublishEvent publishes an event straight away, or at PublishAt when it is
+// in the future
+type PublishEvent struct {
+	PublishAt *time.Time `json:"publish_at,omitempty"`
+}
+
+// PostponeEvent moves an event to a later date; its tickets stay valid
+type PostponeEvent struct {
+	StartTime time.Time `json:"start_time" validate:"required"`
+	EndTime   time.Time `json:"end_time" validate:"required"`
+}
+
+// CancelEvent cancels an event; the reason is passed on to its ticket holders
+type CancelEvent struct {
+	Reason string `json:"reason" validate:"max=500"`
+}
+
 // SubcategoryResponse contains essential fields for a subcategory
 type SubcategoryResponse struct {
 	ID         string `json:"id"`
@@ -161,10 +180,13 @@ type EventResponse struct {
 	Status         string               `json:"status"`
 	EventImages    []events.EventImage  `json:"event_images,omitempty"`
 	PublishedAt    *time.Time           `json:"published_at,omitempty"`
+	PublishAt      *time.Time           `json:"publish_at,omitempty"`
+	OriginalStart  *time.Time           `json:"original_start_time,omitempty"`
 	CreatedAt      time.Time            `json:"created_at"`
 	UpdatedAt      time.Time            `json:"updated_at"`
 	TicketTypes    []TicketTypeResponse `json:"ticket_types,omitempty"`
 	ReservedSeats  []ReservedSeat       `json:"reserved_seats,omitempty"`
+	SeriesID       *string              `json:"series_id,omitempty"`
 }
 
 // MinimalEventResponse for listing multiple events
@@ -186,4 +208,5 @@ type MinimalEventResponse struct {
 	UpdatedAt   time.Time            `json:"updated_at"`
 	EventImages []events.EventImage  `json:"event_images,omitempty"`
 	TicketTypes []TicketTypeResponse `json:"ticket_types,omitempty"`
+	SeriesID    *string              `json:"series_id,omitempty"`
 }
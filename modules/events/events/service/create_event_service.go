@@ -82,7 +82,7 @@ func (s *eventService) CreateEvent(createDto dto.CreateEvent, userID string) (*d
 			IsFree:        createDto.IsFree,
 			HasTickets:    createDto.HasTickets,
 			IsFeatured:    createDto.IsFeatured,
-			Status:        "draft",
+			Status:        events.EventDraft,
 			OrganizerID:   organizer.ID,
 			CreatedAt:     time.Now(),
 			UpdatedAt:     time.Now(),
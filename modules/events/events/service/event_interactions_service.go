@@ -47,7 +47,7 @@ func (s *eventService) ToggleFavorite(userID, eventID string) error {
 			Title       string `json:"title"`
 			OrganizerID string `json:"organizer_id"`
 		}
-		if err := tx.Table("events").Where("id = ?", eventID).First(&event).Error; err != nil {
+		if err := tx.Table("events").Where("id = ? AND status IN ? AND deleted_at IS NULL", eventID, events.PublicEventStatuses).First(&event).Error; err != nil {
 			tx.Rollback()
 			return fmt.Errorf("failed to get event details: %w", err)
 		}
@@ -149,7 +149,7 @@ func (s *eventService) ToggleUpvote(userID, eventID string) (string, error) {
 			Title       string `json:"title"`
 			OrganizerID string `json:"organizer_id"`
 		}
-		if err := tx.Table("events").Where("id = ?", eventID).First(&event).Error; err != nil {
+		if err := tx.Table("events").Where("id = ? AND status IN ? AND deleted_at IS NULL", eventID, events.PublicEventStatuses).First(&event).Error; err != nil {
 			return fmt.Errorf("failed to get event details: %w", err)
 		}
 
@@ -265,7 +265,7 @@ func (s *eventService) ToggleDownvote(userID, eventID string) (string, error) {
 			Title       string `json:"title"`
 			OrganizerID string `json:"organizer_id"`
 		}
-		if err := tx.Table("events").Where("id = ?", eventID).First(&event).Error; err != nil {
+		if err := tx.Table("events").Where("id = ? AND status IN ? AND deleted_at IS NULL", eventID, events.PublicEventStatuses).First(&event).Error; err != nil {
 			return fmt.Errorf("failed to get event details: %w", err)
 		}
 
@@ -99,7 +99,7 @@ func (s *eventService) AddComment(userID, eventID, content string) (*events.Comm
 		Title       string `json:"title"`
 		OrganizerID string `json:"organizer_id"`
 	}
-	if err := tx.Table("events").Where("id = ?", eventID).First(&event).Error; err != nil {
+	if err := tx.Table("events").Where("id = ? AND status IN ? AND deleted_at IS NULL", eventID, events.PublicEventStatuses).First(&event).Error; err != nil {
 		tx.Rollback()
 		return nil, fmt.Errorf("failed to get event details: %w", err)
 	}
@@ -213,7 +213,7 @@ func (s *eventService) AddReply(userID, eventID, commentID, content string) (*ev
 		Title       string `json:"title"`
 		OrganizerID string `json:"organizer_id"`
 	}
-	if err := tx.Table("events").Where("id = ?", eventID).First(&event).Error; err != nil {
+	if err := tx.Table("events").Where("id = ? AND status IN ? AND deleted_at IS NULL", eventID, events.PublicEventStatuses).First(&event).Error; err != nil {
 		tx.Rollback()
 		return nil, fmt.Errorf("failed to get event details: %w", err)
 	}
@@ -0,0 +1,352 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"ticket-zetu-api/modules/events/events/dto"
+	"ticket-zetu-api/modules/events/models/events"
+	"ticket-zetu-api/modules/tickets/models/tickets"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// holderNotificationBatch is how many ticket holders share one notification
+const holderNotificationBatch = 1000
+
+// changeStatus moves an event to the next lifecycle status if the
+// transition is allowed
+func changeStatus(event *events.Event, next events.EventStatus) error {
+	if !event.Status.CanTransitionTo(next) {
+		return errors.New("invalid event status transition")
+	}
+	event.Status = next
+	return nil
+}
+
+// markPublished publishes an event now
+func markPublished(event *events.Event) error {
+	if err := changeStatus(event, events.EventPublished); err != nil {
+		return err
+	}
+	now := time.Now()
+	event.PublishedAt = &now
+	event.PublishAt = nil
+	return nil
+}
+
+// updateOwnedEvent loads one of the user's events for update and saves the
+// changes made to it by apply
+func (s *eventService) updateOwnedEvent(userID, id string, apply func(tx *gorm.DB, event *events.Event) error) (*events.Event, error) {
+	hasPerm, err := s.HasPermission(userID, "update:events")
+	if err != nil {
+		return nil, fmt.Errorf("permission check failed: %w", err)
+	}
+	if !hasPerm {
+		return nil, errors.New("user lacks update:events permission")
+	}
+
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, errors.New("invalid event ID format")
+	}
+
+	organizer, err := s.getUserOrganizer(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var event events.Event
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND organizer_id = ? AND deleted_at IS NULL", id, organizer.ID).
+			First(&event).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("event not found")
+			}
+			return err
+		}
+
+		if err := apply(tx, &event); err != nil {
+			return err
+		}
+
+		event.Version++
+		event.UpdatedAt = time.Now()
+		if err := tx.Save(&event).Error; err != nil {
+			return fmt.Errorf("failed to update event: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+// PublishEvent publishes an event now, or schedules it to be published at a
+// future time by the publishing job
+func (s *eventService) PublishEvent(userID, id string, input dto.PublishEvent) (*dto.EventResponse, error) {
+	event, err := s.updateOwnedEvent(userID, id, func(tx *gorm.DB, event *events.Event) error {
+		if !event.EndTime.After(time.Now()) {
+			return errors.New("event has already ended")
+		}
+
+		if input.PublishAt == nil || !input.PublishAt.After(time.Now()) {
+			return markPublished(event)
+		}
+
+		if !input.PublishAt.Before(event.StartTime) {
+			return errors.New("publish time must be before the event starts")
+		}
+		// A scheduled event can be given a new publish time
+		if event.Status != events.EventScheduled {
+			if err := changeStatus(event, events.EventScheduled); err != nil {
+				return err
+			}
+		}
+		event.PublishAt = input.PublishAt
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	dtoResult, err := s.toDto(event, true)
+	if err != nil {
+		return nil, err
+	}
+	return &dtoResult.Full, nil
+}
+
+// UnscheduleEvent cancels the scheduled publishing of an event, returning it
+// to draft
+func (s *eventService) UnscheduleEvent(userID, id string) (*dto.EventResponse, error) {
+	event, err := s.updateOwnedEvent(userID, id, func(tx *gorm.DB, event *events.Event) error {
+		if event.Status != events.EventScheduled {
+			return errors.New("event is not scheduled")
+		}
+		event.Status = events.EventDraft
+		event.PublishAt = nil
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	dtoResult, err := s.toDto(event, true)
+	if err != nil {
+		return nil, err
+	}
+	return &dtoResult.Full, nil
+}
+
+// PostponeEvent moves a published event to a later date. Tickets stay valid
+// for the new date, and sales windows, resale listings and comp claims that
+// ran until the old date now run until the new one.
+func (s *eventService) PostponeEvent(userID, id string, input dto.PostponeEvent) (*dto.EventResponse, error) {
+	if !input.EndTime.After(input.StartTime) {
+		return nil, errors.New("end time must be after start time")
+	}
+	if !input.StartTime.After(time.Now()) {
+		return nil, errors.New("new date must be in the future")
+	}
+
+	var previousStart time.Time
+	event, err := s.updateOwnedEvent(userID, id, func(tx *gorm.DB, event *events.Event) error {
+		if !event.EndTime.After(time.Now()) {
+			return errors.New("event has already ended")
+		}
+		if !input.StartTime.After(event.StartTime) {
+			return errors.New("a postponed event must start later than before")
+		}
+		if err := changeStatus(event, events.EventPostponed); err != nil {
+			return err
+		}
+
+		previousStart = event.StartTime
+		previousEnd := event.EndTime
+		if event.OriginalStartTime == nil {
+			originalStart := previousStart
+			event.OriginalStartTime = &originalStart
+		}
+		event.StartTime = input.StartTime
+		event.EndTime = input.EndTime
+
+		if err := tx.Model(&tickets.TicketType{}).
+			Where("event_id = ? AND sales_end = ?", event.ID, previousStart).
+			UpdateColumn("sales_end", event.StartTime).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&tickets.TicketResale{}).
+			Where("event_id = ? AND status = ? AND expires_at = ?", event.ID, tickets.ResaleListed, previousStart).
+			UpdateColumn("expires_at", event.StartTime).Error; err != nil {
+			return err
+		}
+		return tx.Model(&tickets.CompGrant{}).
+			Where("event_id = ? AND status = ? AND claim_expires_at = ?", event.ID, tickets.CompPendingClaim, previousEnd).
+			UpdateColumn("claim_expires_at", event.EndTime).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.notifyTicketHolders(userID, event, "event_postponed",
+		event.Title+" has been postponed",
+		fmt.Sprintf("%s has moved to %s. Your tickets remain valid for the new date.", event.Title, event.StartTime.Format(time.RFC1123)),
+		map[string]interface{}{
+			"event_id":            event.ID,
+			"event_title":         event.Title,
+			"previous_start_time": previousStart,
+			"start_time":          event.StartTime,
+			"end_time":            event.EndTime,
+		})
+
+	dtoResult, err := s.toDto(event, true)
+	if err != nil {
+		return nil, err
+	}
+	return &dtoResult.Full, nil
+}
+
+// CancelEvent cancels an event for good. Ticket sales stop at once; the
+// cascade that follows, releasing holds and seats, refunding orders at the
+// event's cancellation refund rate and telling every ticket holder, is
+// carried out in batches by the cancellation job. The returned record
+// reports its progress.
+func (s *eventService) CancelEvent(userID, id string, input dto.CancelEvent) (*events.EventCancellation, error) {
+	var cancellation events.EventCancellation
+	_, err := s.updateOwnedEvent(userID, id, func(tx *gorm.DB, event *events.Event) error {
+		if err := changeStatus(event, events.EventCancelled); err != nil {
+			return err
+		}
+		event.PublishAt = nil
+
+		// The refund rate is fixed now so a later policy change cannot alter it midway
+		percentage := 100.0
+		var policy tickets.RefundPolicy
+		err := tx.Where("event_id = ?", event.ID).First(&policy).Error
+		if err == nil {
+			percentage = policy.CancellationPercentage()
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		cancellation = events.EventCancellation{
+			EventID:          event.ID,
+			CancelledBy:      userID,
+			Reason:           input.Reason,
+			RefundPercentage: percentage,
+		}
+		return tx.Create(&cancellation).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &cancellation, nil
+}
+
+// GetEventCancellation reports the progress of the cancellation of one of
+// the user's events
+func (s *eventService) GetEventCancellation(userID, id string) (*events.EventCancellation, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, errors.New("invalid event ID format")
+	}
+
+	organizer, err := s.getUserOrganizer(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var cancellation events.EventCancellation
+	if err := s.db.Joins("JOIN events ON events.id = event_cancellations.event_id").
+		Where("event_cancellations.event_id = ? AND events.organizer_id = ?", id, organizer.ID).
+		First(&cancellation).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("event cancellation not found")
+		}
+		return nil, err
+	}
+	return &cancellation, nil
+}
+
+// notifyTicketHolders notifies everyone holding a live ticket for the event
+func (s *eventService) notifyTicketHolders(senderID string, event *events.Event, action, title, message string, metadata map[string]interface{}) {
+	var holderIDs []string
+	if err := s.db.Model(&tickets.Ticket{}).
+		Where("event_id = ? AND status IN ? AND deleted_at IS NULL", event.ID, []tickets.TicketStatus{tickets.TicketValid, tickets.TicketPending}).
+		Distinct("user_id").
+		Pluck("user_id", &holderIDs).Error; err != nil {
+		fmt.Printf("Failed to load ticket holders for %s: %v\n", event.ID, err)
+		return
+	}
+
+	for start := 0; start < len(holderIDs); start += holderNotificationBatch {
+		end := start + holderNotificationBatch
+		if end > len(holderIDs) {
+			end = len(holderIDs)
+		}
+		s.sendNotification(action, title, message, senderID, event.ID, holderIDs[start:end], metadata)
+	}
+}
+
+// PublishScheduledEvents publishes scheduled events whose publish time has
+// come. It returns how many were published.
+func (s *eventService) PublishScheduledEvents(limit int) (int, error) {
+	var due []events.Event
+	if err := s.db.Where("status = ? AND publish_at <= ? AND deleted_at IS NULL", events.EventScheduled, time.Now()).
+		Order("publish_at ASC").
+		Limit(limit).
+		Find(&due).Error; err != nil {
+		return 0, err
+	}
+
+	published := 0
+	for _, event := range due {
+		// The event may have been unscheduled or published since it was read
+		result := s.db.Model(&events.Event{}).
+			Where("id = ? AND status = ?", event.ID, events.EventScheduled).
+			UpdateColumns(map[string]interface{}{
+				"status":       events.EventPublished,
+				"published_at": time.Now(),
+				"publish_at":   nil,
+				"version":      gorm.Expr("version + 1"),
+				"updated_at":   time.Now(),
+			})
+		if result.Error != nil {
+			return published, result.Error
+		}
+		published += int(result.RowsAffected)
+	}
+	return published, nil
+}
+
+// CompleteEndedEvents marks published and postponed events completed once
+// they have ended. It returns how many were completed.
+func (s *eventService) CompleteEndedEvents(limit int) (int, error) {
+	var ended []events.Event
+	if err := s.db.Where("status IN ? AND end_time <= ? AND deleted_at IS NULL", events.OnSaleEventStatuses, time.Now()).
+		Order("end_time ASC").
+		Limit(limit).
+		Find(&ended).Error; err != nil {
+		return 0, err
+	}
+
+	completed := 0
+	for _, event := range ended {
+		// The event may have been postponed or cancelled since it was read
+		result := s.db.Model(&events.Event{}).
+			Where("id = ? AND status IN ? AND end_time <= ?", event.ID, events.OnSaleEventStatuses, time.Now()).
+			UpdateColumns(map[string]interface{}{
+				"status":     events.EventCompleted,
+				"version":    gorm.Expr("version + 1"),
+				"updated_at": time.Now(),
+			})
+		if result.Error != nil {
+			return completed, result.Error
+		}
+		completed += int(result.RowsAffected)
+	}
+	return completed, nil
+}
@@ -7,6 +7,7 @@ import (
 	"ticket-zetu-api/modules/events/events/dto"
 	"ticket-zetu-api/modules/events/models/categories"
 	"ticket-zetu-api/modules/events/models/events"
+	"ticket-zetu-api/modules/tickets/models/tickets"
 	"time"
 
 	"github.com/google/uuid"
@@ -110,8 +111,25 @@ func (s *eventService) UpdateEvent(updateDto dto.UpdateEvent, userID, id string)
 		if updateDto.IsFeatured != nil {
 			event.IsFeatured = *updateDto.IsFeatured
 		}
-		if updateDto.Status != nil {
-			event.Status = events.EventStatus(*updateDto.Status)
+		if updateDto.Status != nil && events.EventStatus(*updateDto.Status) != event.Status {
+			// Scheduling, postponing, cancelling and completing have their own
+			// actions; an update can only publish an event or unschedule it
+			switch next := events.EventStatus(*updateDto.Status); next {
+			case events.EventPublished:
+				if !event.EndTime.After(time.Now()) {
+					return errors.New("event has already ended")
+				}
+				if err := markPublished(&event); err != nil {
+					return err
+				}
+			case events.EventDraft:
+				if err := changeStatus(&event, next); err != nil {
+					return err
+				}
+				event.PublishAt = nil
+			default:
+				return errors.New("status must be changed through its lifecycle action")
+			}
 		}
 
 		event.Version++
@@ -162,11 +180,39 @@ func (s *eventService) DeleteEvent(userID, id string) error {
 		return err
 	}
 
-	// Replace 'EventStatusActive' with the correct constant or string value for active status
-	if event.Status == events.EventStatus("active") {
+	// Published and postponed events may have ticket holders
+	if event.Status.IsOnSale() {
 		return errors.New("cannot delete an active event")
 	}
 
+	// Deleting would orphan tickets and payments; such events are cancelled,
+	// and can be deleted once the cancellation has refunded everyone
+	var inProgress int64
+	if err := s.db.Model(&events.EventCancellation{}).
+		Where("event_id = ? AND stage <> ?", event.ID, events.CancellationCompleted).
+		Count(&inProgress).Error; err != nil {
+		return err
+	}
+	if inProgress > 0 {
+		return errors.New("event cancellation is still in progress")
+	}
+	var holders int64
+	if err := s.db.Model(&tickets.Ticket{}).
+		Where("event_id = ? AND status IN ? AND deleted_at IS NULL", event.ID, []tickets.TicketStatus{tickets.TicketValid, tickets.TicketPending}).
+		Count(&holders).Error; err != nil {
+		return err
+	}
+	if holders == 0 {
+		if err := s.db.Model(&tickets.Order{}).
+			Where("event_id = ? AND status IN ?", event.ID, []tickets.OrderStatus{tickets.OrderPending, tickets.OrderConfirmed}).
+			Count(&holders).Error; err != nil {
+			return err
+		}
+	}
+	if holders > 0 {
+		return errors.New("event has ticket holders; cancel it instead")
+	}
+
 	var eventImages []events.EventImage
 	if err := s.db.Where("event_id = ? AND deleted_at IS NULL", event.ID).Find(&eventImages).Error; err != nil {
 		return err
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"ticket-zetu-api/modules/events/events/dto"
+	"ticket-zetu-api/modules/events/models/events"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// GetPublicEvent retrieves an event anyone can see by its ID or slug. Drafts
+// and events that have not been published yet are not found.
+func (s *eventService) GetPublicEvent(idOrSlug string) (*dto.EventResponse, error) {
+	query := s.db.Where("status IN ? AND deleted_at IS NULL", events.PublicEventStatuses)
+	if _, err := uuid.Parse(idOrSlug); err == nil {
+		query = query.Where("id = ?", idOrSlug)
+	} else {
+		query = query.Where("slug = ?", idOrSlug)
+	}
+
+	var event events.Event
+	if err := query.First(&event).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("event not found")
+		}
+		return nil, fmt.Errorf("failed to fetch event: %v", err)
+	}
+
+	dtoResult, err := s.toDto(&event, true)
+	if err != nil {
+		return nil, err
+	}
+	return &dtoResult.Full, nil
+}
+
+// GetPublicEvents searches the events anyone can see, soonest first
+func (s *eventService) GetPublicEvents(filter SearchFilter) (*PaginatedResponse, error) {
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.PageSize < 1 || filter.PageSize > 100 {
+		filter.PageSize = 20
+	}
+	if filter.Status != "" && !events.EventStatus(filter.Status).IsPublic() {
+		return nil, errors.New("invalid status filter")
+	}
+
+	query := s.db.Model(&events.Event{}).
+		Where("events.status IN ? AND events.deleted_at IS NULL", events.PublicEventStatuses)
+
+	return s.searchEvents(query, filter, "events.start_time ASC")
+}
@@ -150,6 +150,11 @@ func (s *eventService) SearchEvents(userID string, filter SearchFilter) (*Pagina
 	query := s.db.Model(&events.Event{}).
 		Where("organizer_id = ? AND deleted_at IS NULL", organizer.ID)
 
+	return s.searchEvents(query, filter, "created_at DESC")
+}
+
+// searchEvents applies the search filters and pagination to an events query
+func (s *eventService) searchEvents(query *gorm.DB, filter SearchFilter, order string) (*PaginatedResponse, error) {
 	// Apply search query
 	if filter.Query != "" {
 		searchTerm := "%" + strings.ToLower(filter.Query) + "%"
@@ -198,7 +203,7 @@ func (s *eventService) SearchEvents(userID string, filter SearchFilter) (*Pagina
 	// Fetch paginated events
 	var events []events.Event
 	if err := query.
-		Order("created_at DESC").
+		Order(order).
 		Offset((filter.Page - 1) * filter.PageSize).
 		Limit(filter.PageSize).
 		Find(&events).Error; err != nil {
@@ -3,6 +3,7 @@ package service
 import (
 	"errors"
 	"fmt"
+	"log"
 	"strconv"
 	"strings"
 	"ticket-zetu-api/cloudinary"
@@ -12,6 +13,7 @@ import (
 	notification_service "ticket-zetu-api/modules/notifications/service"
 	organizers "ticket-zetu-api/modules/organizers/models"
 	"ticket-zetu-api/modules/tickets/models/tickets"
+	pricing_service "ticket-zetu-api/modules/tickets/pricing/service"
 	authorization_service "ticket-zetu-api/modules/users/authorization/service"
 	"time"
 
@@ -61,6 +63,16 @@ type EventService interface {
 	DeleteEventImage(userID, eventID, imageID string) error
 	HasPermission(userID, permission string) (bool, error)
 
+	PublishEvent(userID, id string, input dto.PublishEvent) (*dto.EventResponse, error)
+	UnscheduleEvent(userID, id string) (*dto.EventResponse, error)
+	PostponeEvent(userID, id string, input dto.PostponeEvent) (*dto.EventResponse, error)
+	CancelEvent(userID, id string, input dto.CancelEvent) (*events.EventCancellation, error)
+	GetEventCancellation(userID, id string) (*events.EventCancellation, error)
+	PublishScheduledEvents(limit int) (int, error)
+	CompleteEndedEvents(limit int) (int, error)
+	GetPublicEvent(idOrSlug string) (*dto.EventResponse, error)
+	GetPublicEvents(filter SearchFilter) (*PaginatedResponse, error)
+
 	ToggleFavorite(userID, eventID string) error
 	ToggleUpvote(userID, eventID string) (string, error)
 	ToggleDownvote(userID, eventID string) (string, error)
@@ -201,6 +213,7 @@ func (s *eventService) toDto(event *events.Event, fullDetails bool) (*struct {
 		CreatedAt:   event.CreatedAt,
 		UpdatedAt:   event.UpdatedAt,
 		EventImages: eventImages,
+		SeriesID:    event.SeriesID,
 	}
 
 	if !fullDetails {
@@ -239,6 +252,12 @@ func (s *eventService) toDto(event *events.Event, fullDetails bool) (*struct {
 		return nil, fmt.Errorf("failed to fetch ticket types: %v", err)
 	}
 
+	// Prices are only shown here, so the event is still returned without them
+	prices, err := pricing_service.ResolveAll(s.db, ticketTypes, time.Now())
+	if err != nil {
+		log.Printf("Failed to price ticket types of event %s: %v", event.ID, err)
+	}
+
 	// Convert ticket types to DTO
 	ticketTypeResponses := make([]dto.TicketTypeResponse, len(ticketTypes))
 	for i, tt := range ticketTypes {
@@ -272,6 +291,7 @@ func (s *eventService) toDto(event *events.Event, fullDetails bool) (*struct {
 			SalesStart:        tt.SalesStart,
 			SalesEnd:          tt.SalesEnd,
 			PriceTiers:        priceTierResponses,
+			Pricing:           prices[tt.ID],
 			CreatedAt:         tt.CreatedAt,
 			UpdatedAt:         tt.UpdatedAt,
 		}
@@ -330,10 +350,13 @@ func (s *eventService) toDto(event *events.Event, fullDetails bool) (*struct {
 		Status:        string(event.Status),
 		EventImages:   eventImages,
 		PublishedAt:   event.PublishedAt,
+		PublishAt:     event.PublishAt,
+		OriginalStart: event.OriginalStartTime,
 		CreatedAt:     event.CreatedAt,
 		UpdatedAt:     event.UpdatedAt,
 		TicketTypes:   ticketTypeResponses,
 		ReservedSeats: reservedSeats,
+		SeriesID:      event.SeriesID,
 	}
 
 	return &struct {
@@ -0,0 +1,55 @@
+package event_jobs
+
+import (
+	"log"
+	event_service "ticket-zetu-api/modules/events/events/service"
+	"ticket-zetu-api/modules/events/seat_allocation/services"
+	notification_service "ticket-zetu-api/modules/notifications/service"
+	"ticket-zetu-api/modules/users/authorization/service"
+	"ticket-zetu-api/queue"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	sweepInterval  = 30 * time.Second
+	sweepBatchSize = 500
+)
+
+// RegisterEventJobs schedules the background jobs of the events module
+func RegisterEventJobs(scheduler *queue.Scheduler, db *gorm.DB) {
+	authService := authorization_service.NewPermissionService(db)
+	reservationService := services.NewSeatReservationService(db, authService)
+	eventService := event_service.NewEventService(db, authService, nil, notification_service.NewNotificationService(db, authService))
+
+	scheduler.Every("events:expire-seat-holds", sweepInterval, func() {
+		released, err := reservationService.SweepExpiredReservations(sweepBatchSize)
+		if err != nil {
+			log.Printf("Failed to sweep expired seat reservations: %v", err)
+		}
+		if released > 0 {
+			log.Printf("Released %d expired seat reservations", released)
+		}
+	})
+
+	scheduler.Every("events:publish-scheduled", sweepInterval, func() {
+		published, err := eventService.PublishScheduledEvents(sweepBatchSize)
+		if err != nil {
+			log.Printf("Failed to publish scheduled events: %v", err)
+		}
+		if published > 0 {
+			log.Printf("Published %d scheduled events", published)
+		}
+	})
+
+	scheduler.Every("events:complete-ended", sweepInterval, func() {
+		completed, err := eventService.CompleteEndedEvents(sweepBatchSize)
+		if err != nil {
+			log.Printf("Failed to complete ended events: %v", err)
+		}
+		if completed > 0 {
+			log.Printf("Completed %d ended events", completed)
+		}
+	})
+}
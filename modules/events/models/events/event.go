@@ -12,10 +12,56 @@ import (
 type EventStatus string
 
 const (
-	EventActive   EventStatus = "active"
-	EventInactive EventStatus = "inactive"
+	EventDraft     EventStatus = "draft"
+	EventScheduled EventStatus = "scheduled" // published automatically at PublishAt
+	EventPublished EventStatus = "published"
+	EventPostponed EventStatus = "postponed" // moved to a new date; tickets stay valid
+	EventCancelled EventStatus = "cancelled"
+	EventCompleted EventStatus = "completed"
 )
 
+// eventTransitions lists the statuses an event may move to from each status
+var eventTransitions = map[EventStatus][]EventStatus{
+	EventDraft:     {EventScheduled, EventPublished, EventCancelled},
+	EventScheduled: {EventDraft, EventPublished, EventCancelled},
+	EventPublished: {EventPostponed, EventCancelled, EventCompleted},
+	EventPostponed: {EventPostponed, EventCancelled, EventCompleted},
+}
+
+// PublicEventStatuses are the statuses in which anyone can see an event
+var PublicEventStatuses = []EventStatus{EventPublished, EventPostponed, EventCancelled, EventCompleted}
+
+// OnSaleEventStatuses are the statuses in which an event can sell tickets
+var OnSaleEventStatuses = []EventStatus{EventPublished, EventPostponed}
+
+// CanTransitionTo reports whether an event may move from s to next
+func (s EventStatus) CanTransitionTo(next EventStatus) bool {
+	for _, allowed := range eventTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+func (s EventStatus) IsPublic() bool {
+	for _, status := range PublicEventStatuses {
+		if status == s {
+			return true
+		}
+	}
+	return false
+}
+
+func (s EventStatus) IsOnSale() bool {
+	for _, status := range OnSaleEventStatuses {
+		if status == s {
+			return true
+		}
+	}
+	return false
+}
+
 type EventType string
 
 const (
@@ -46,7 +92,7 @@ type Event struct {
 	IsFree     bool        `gorm:"default:false" json:"is_free"`
 	HasTickets bool        `gorm:"default:true" json:"has_tickets"`
 	IsFeatured bool        `gorm:"default:false;index" json:"is_featured"`
-	Status     EventStatus `gorm:"size:20;not null;default:'active';index" json:"status"`
+	Status     EventStatus `gorm:"size:20;not null;default:'draft';index" json:"status"`
 
 	PublishedAt *time.Time `gorm:"index" json:"published_at,omitempty"`
 
@@ -56,6 +102,17 @@ type Event struct {
 	UpdatedAt time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
 	Version   int            `gorm:"default:1" json:"-"`
+
+	// Occurrences of an EventSeries point back at it. OccurrenceStart is the
+	// start the recurrence rule gives the occurrence, which identifies it even
+	// after it has been moved on its own.
+	SeriesID        *string    `gorm:"type:char(36);index" json:"series_id,omitempty"`
+	OccurrenceStart *time.Time `gorm:"index" json:"occurrence_start,omitempty"`
+
+	// PublishAt is when a scheduled event is published. OriginalStartTime is
+	// the start a postponed event had before it was first moved.
+	PublishAt         *time.Time `gorm:"index" json:"publish_at,omitempty"`
+	OriginalStartTime *time.Time `json:"original_start_time,omitempty"`
 }
 
 func (e *Event) BeforeCreate(tx *gorm.DB) (err error) {
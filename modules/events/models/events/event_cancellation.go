@@ -0,0 +1,116 @@
+package events
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type CancellationStage string
+
+// A cancellation works through its stages in order, a batch at a time
+const (
+	// CancellationReleasing frees ticket holds, seats, resale listings,
+	// pending transfers and unclaimed comps
+	CancellationReleasing CancellationStage = "releasing"
+	// CancellationRefunding cancels unpaid orders and refunds paid ones
+	CancellationRefunding CancellationStage = "refunding"
+	// CancellationVoiding cancels tickets issued without an order
+	CancellationVoiding CancellationStage = "voiding"
+	// CancellationNotifying tells every ticket holder by notification and email
+	CancellationNotifying CancellationStage = "notifying"
+	CancellationCompleted CancellationStage = "completed"
+)
+
+// EventCancellation tracks the cascade that follows cancelling an event. It
+// is processed in batches by a background job; Cursor is the last row the
+// current stage handled, so an interrupted cancellation resumes where it
+// stopped.
+type EventCancellation struct {
+	ID          string            `gorm:"type:char(36);primaryKey" json:"id"`
+	EventID     string            `gorm:"type:char(36);not null;uniqueIndex" json:"event_id"`
+	CancelledBy string            `gorm:"type:char(36);not null" json:"cancelled_by"`
+	Reason      string            `gorm:"type:text" json:"reason,omitempty"`
+	Stage       CancellationStage `gorm:"type:varchar(20);not null;default:'releasing';index" json:"stage"`
+	Cursor      string            `gorm:"size:36" json:"-"`
+	// ClaimedUntil is when the run working on the cancellation loses its claim
+	ClaimedUntil *time.Time `json:"-"`
+	// RefundPercentage is fixed from the event's refund policy when it is cancelled
+	RefundPercentage float64 `gorm:"type:numeric(5,2);not null" json:"refund_percentage"`
+
+	OrdersCancelled  int        `gorm:"not null;default:0" json:"orders_cancelled"`
+	OrdersRefunded   int        `gorm:"not null;default:0" json:"orders_refunded"`
+	RefundsFailed    int        `gorm:"not null;default:0" json:"refunds_failed"` // orders whose refund is still failing
+	TicketsCancelled int        `gorm:"not null;default:0" json:"tickets_cancelled"`
+	HoldersNotified  int        `gorm:"not null;default:0" json:"holders_notified"`
+	CompletedAt      *time.Time `json:"completed_at,omitempty"`
+
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+	Version   int       `gorm:"default:1" json:"-"`
+
+	Event Event `gorm:"foreignKey:EventID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+}
+
+func (c *EventCancellation) BeforeCreate(tx *gorm.DB) error {
+	if c.ID == "" {
+		c.ID = uuid.New().String()
+	}
+	if c.EventID == "" || c.CancelledBy == "" {
+		return errors.New("event_id and cancelled_by cannot be empty")
+	}
+	if c.Stage == "" {
+		c.Stage = CancellationReleasing
+	}
+	return nil
+}
+
+func (EventCancellation) TableName() string {
+	return "event_cancellations"
+}
+
+type CancellationFailureKind string
+
+const (
+	CancellationFailedRefund       CancellationFailureKind = "refund"
+	CancellationFailedEmail        CancellationFailureKind = "email"
+	CancellationFailedNotification CancellationFailureKind = "notification"
+)
+
+// EventCancellationFailure is an order a cancellation could not refund or a
+// holder it could not tell. Failures are retried once their stage has been
+// through every row, and left for support once they run out of attempts.
+type EventCancellationFailure struct {
+	ID             string                  `gorm:"type:char(36);primaryKey" json:"id"`
+	CancellationID string                  `gorm:"type:char(36);not null;uniqueIndex:idx_cancellation_failure" json:"cancellation_id"`
+	Kind           CancellationFailureKind `gorm:"type:varchar(20);not null;uniqueIndex:idx_cancellation_failure;check:kind IN ('refund','email','notification')" json:"kind"`
+	// ReferenceID is the order for a refund and the holder's user otherwise
+	ReferenceID string     `gorm:"type:char(36);not null;uniqueIndex:idx_cancellation_failure" json:"reference_id"`
+	Error       string     `gorm:"type:varchar(255)" json:"error"`
+	Attempts    int        `gorm:"not null;default:1" json:"attempts"`
+	ResolvedAt  *time.Time `json:"resolved_at,omitempty"`
+
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+
+	Cancellation EventCancellation `gorm:"foreignKey:CancellationID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+}
+
+func (f *EventCancellationFailure) BeforeCreate(tx *gorm.DB) error {
+	if f.ID == "" {
+		f.ID = uuid.New().String()
+	}
+	if f.CancellationID == "" || f.ReferenceID == "" {
+		return errors.New("cancellation_id and reference_id cannot be empty")
+	}
+	if f.Attempts == 0 {
+		f.Attempts = 1
+	}
+	return nil
+}
+
+func (EventCancellationFailure) TableName() string {
+	return "event_cancellation_failures"
+}
@@ -0,0 +1,122 @@
+package events
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type RecurrenceFrequency string
+
+const (
+	RecurrenceWeekly  RecurrenceFrequency = "weekly"
+	RecurrenceMonthly RecurrenceFrequency = "monthly"
+)
+
+// EventSeries is a recurring event. Its recurrence rule follows RFC 5545
+// RRULE semantics and every occurrence it produces is a real Event row
+// pointing back at the series through Event.SeriesID.
+type EventSeries struct {
+	ID            string    `gorm:"type:char(36);primaryKey" json:"id"`
+	OrganizerID   string    `gorm:"type:char(36);not null;index" json:"-"`
+	Title         string    `gorm:"size:255;not null" json:"title"`
+	Description   string    `gorm:"type:text" json:"description"`
+	SubcategoryID string    `gorm:"not null;index" json:"subcategory_id"`
+	VenueID       string    `gorm:"type:char(36);index" json:"venue_id"`
+	Timezone      string    `gorm:"size:100" json:"timezone,omitempty"`
+	Language      string    `gorm:"size:50" json:"language,omitempty"`
+	EventType     EventType `gorm:"size:20;default:'offline'" json:"event_type"`
+	MinAge        int       `gorm:"not null;default:0" json:"min_age"`
+	IsFree        bool      `gorm:"default:false" json:"is_free"`
+	HasTickets    bool      `gorm:"default:true" json:"has_tickets"`
+
+	// Recurrence rule. StartTime is the DTSTART of the rule: the start of the
+	// first occurrence, whose wall-clock time every occurrence keeps in the
+	// series timezone.
+	StartTime       time.Time           `gorm:"not null" json:"start_time"`
+	DurationMinutes int                 `gorm:"not null;check:duration_minutes > 0" json:"duration_minutes"`
+	Frequency       RecurrenceFrequency `gorm:"size:20;not null" json:"frequency"`
+	Interval        int                 `gorm:"column:recurrence_interval;not null;default:1;check:recurrence_interval >= 1" json:"interval"`
+	ByDay           string              `gorm:"size:100" json:"by_day,omitempty"`       // e.g. "MO,WE" weekly, "2TU,-1FR" monthly
+	ByMonthDay      string              `gorm:"size:100" json:"by_month_day,omitempty"` // e.g. "1,15,-1"
+	Until           *time.Time          `json:"until,omitempty"`
+	Count           int                 `gorm:"not null;default:0" json:"count,omitempty"` // 0 means the rule ends at Until
+
+	TicketTypes []EventSeriesTicketType `gorm:"foreignKey:SeriesID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"ticket_types"`
+	Exceptions  []EventSeriesException  `gorm:"foreignKey:SeriesID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"exceptions"`
+
+	CreatedAt time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
+	Version   int            `gorm:"default:1" json:"-"`
+}
+
+func (es *EventSeries) BeforeCreate(tx *gorm.DB) (err error) {
+	if es.ID == "" {
+		es.ID = uuid.New().String()
+	}
+
+	if _, err := uuid.Parse(es.OrganizerID); err != nil {
+		return errors.New("invalid organizer_id format")
+	}
+	return nil
+}
+
+// EventSeriesTicketType is the template for a ticket type every occurrence
+// of the series gets its own copy of, along with its own stock
+type EventSeriesTicketType struct {
+	ID                string  `gorm:"type:char(36);primaryKey" json:"id"`
+	SeriesID          string  `gorm:"type:char(36);not null;index" json:"series_id"`
+	Name              string  `gorm:"size:100;not null" json:"name"`
+	Description       string  `gorm:"type:text" json:"description"`
+	BasePrice         float64 `gorm:"type:numeric(10,2);not null;default:0" json:"base_price"`
+	PriceModifier     float64 `gorm:"type:numeric(5,2);not null;default:1.0" json:"price_modifier"`
+	Benefits          string  `gorm:"type:text" json:"benefits"`
+	MinTicketsPerUser int     `gorm:"default:1" json:"min_tickets_per_user"`
+	MaxTicketsPerUser int     `gorm:"default:4" json:"max_tickets_per_user"`
+	IsDefault         bool    `gorm:"default:false" json:"is_default"`
+	TransfersDisabled bool    `gorm:"default:false" json:"transfers_disabled"`
+	MaxTransfers      int     `gorm:"default:0" json:"max_transfers"`
+	Quantity          *int    `json:"quantity,omitempty"`                        // stock per occurrence; nil means no stock is tracked
+	SalesOpenDays     int     `gorm:"not null;default:0" json:"sales_open_days"` // days before an occurrence its sales open; 0 opens them straight away
+
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (st *EventSeriesTicketType) BeforeCreate(tx *gorm.DB) (err error) {
+	if st.ID == "" {
+		st.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// EventSeriesException removes one occurrence from the series, like an
+// RRULE EXDATE. OccurrenceStart is the start the rule gives the occurrence.
+type EventSeriesException struct {
+	ID              string    `gorm:"type:char(36);primaryKey" json:"id"`
+	SeriesID        string    `gorm:"type:char(36);not null;uniqueIndex:idx_series_exception" json:"series_id"`
+	OccurrenceStart time.Time `gorm:"not null;uniqueIndex:idx_series_exception" json:"occurrence_start"`
+	CreatedAt       time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (ex *EventSeriesException) BeforeCreate(tx *gorm.DB) (err error) {
+	if ex.ID == "" {
+		ex.ID = uuid.New().String()
+	}
+	return nil
+}
+
+func (EventSeries) TableName() string {
+	return "event_series"
+}
+
+func (EventSeriesTicketType) TableName() string {
+	return "event_series_ticket_types"
+}
+
+func (EventSeriesException) TableName() string {
+	return "event_series_exceptions"
+}
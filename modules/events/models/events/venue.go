@@ -99,6 +99,9 @@ func (v *Venue) validate(tx *gorm.DB) error {
 		if err := json.Unmarshal([]byte(v.Layout), &layout); err != nil {
 			return errors.New("layout must be valid JSON")
 		}
+		if _, err := ParseLayout(v.Layout); err != nil {
+			return err
+		}
 	}
 	if v.AccessibilityFeatures != "" {
 		var features []string
@@ -0,0 +1,332 @@
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// Limits on a structured layout, so one venue cannot generate an unbounded
+// number of seats
+const (
+	maxLayoutSections     = 200
+	maxLayoutRows         = 500
+	maxLayoutSeatsPerRow  = 500
+	maxLayoutSeats        = 100000
+	maxLayoutSectionName  = 50
+	maxLayoutSeatNumber   = 10
+	defaultLayoutSpacing  = 1.0
+	defaultLayoutQuality  = 50
+	maxLayoutQualityScore = 100
+)
+
+// SeatLayout is the structured form of Venue.Layout. Sections hold rows and
+// rows hold seats, each with coordinates a seat map can be drawn from. Seats
+// are generated from it, one per layout seat. Layouts without a sections
+// list are older free-form layouts and generate nothing.
+type SeatLayout struct {
+	Width    float64         `json:"width,omitempty" example:"120"`
+	Height   float64         `json:"height,omitempty" example:"80"`
+	Stage    *LayoutArea     `json:"stage,omitempty"`
+	Sections []LayoutSection `json:"sections"`
+}
+
+// LayoutArea is a labelled rectangle drawn on the map, such as the stage
+type LayoutArea struct {
+	Label  string  `json:"label,omitempty" example:"Stage"`
+	X      float64 `json:"x" example:"40"`
+	Y      float64 `json:"y" example:"0"`
+	Width  float64 `json:"width" example:"40"`
+	Height float64 `json:"height" example:"8"`
+}
+
+// LayoutSection is a named block of rows. Its name becomes the seats'
+// SeatSection, and its quality from 1 to 100 ranks it against other
+// sections, higher being better. Sections without one count as 50.
+type LayoutSection struct {
+	Name        string      `json:"name" example:"Orchestra"`
+	Quality     int         `json:"quality,omitempty" example:"80"`
+	PriceTierID string      `json:"price_tier_id,omitempty"`
+	Rows        []LayoutRow `json:"rows"`
+}
+
+// LayoutRow lists its seats, or gives a seat count to number them from
+// start_number and space them out from x along the row. Accessible seats and
+// aisles can be marked by seat number either way.
+type LayoutRow struct {
+	Label           string       `json:"label" example:"A"`
+	X               float64      `json:"x,omitempty" example:"10"`
+	Y               float64      `json:"y,omitempty" example:"12"`
+	Spacing         float64      `json:"spacing,omitempty" example:"1.5"`
+	SeatCount       int          `json:"seat_count,omitempty" example:"20"`
+	StartNumber     int          `json:"start_number,omitempty" example:"1"`
+	AccessibleSeats []string     `json:"accessible_seats,omitempty"`
+	AislesAfter     []string     `json:"aisles_after,omitempty"`
+	Seats           []LayoutSeat `json:"seats,omitempty"`
+}
+
+// LayoutSeat is one seat of a row. AisleAfter marks an aisle between it and
+// the next seat in the row.
+type LayoutSeat struct {
+	Number      string  `json:"number" example:"1"`
+	X           float64 `json:"x" example:"10"`
+	Y           float64 `json:"y" example:"12"`
+	Accessible  bool    `json:"accessible,omitempty"`
+	AisleAfter  bool    `json:"aisle_after,omitempty"`
+	PriceTierID string  `json:"price_tier_id,omitempty"`
+}
+
+// PlannedSeat is a seat a layout asks for
+type PlannedSeat struct {
+	// Key identifies the seat across layout edits
+	Key          string
+	Section      string
+	Row          string
+	Number       string
+	SeatNumber   string
+	Position     int
+	X            float64
+	Y            float64
+	IsAccessible bool
+	AisleAfter   bool
+	Quality      int
+	PriceTierID  string
+}
+
+// ParseLayout reads a venue's layout. It returns nil for an empty or
+// free-form layout, and an error for a structured one that is not valid.
+func ParseLayout(raw string) (*SeatLayout, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	var probe map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(raw), &probe); err != nil {
+		return nil, errors.New("layout must be valid JSON")
+	}
+	sections, ok := probe["sections"]
+	if !ok || len(sections) == 0 || sections[0] != '[' {
+		return nil, nil
+	}
+
+	var layout SeatLayout
+	if err := json.Unmarshal([]byte(raw), &layout); err != nil {
+		return nil, fmt.Errorf("invalid layout: %v", err)
+	}
+	if err := layout.Validate(); err != nil {
+		return nil, err
+	}
+	return &layout, nil
+}
+
+// Validate checks that sections, rows and seats are named, unique where they
+// need to be, and within the layout limits
+func (l *SeatLayout) Validate() error {
+	if len(l.Sections) == 0 {
+		return errors.New("invalid layout: at least one section is required")
+	}
+	if len(l.Sections) > maxLayoutSections {
+		return fmt.Errorf("invalid layout: at most %d sections are allowed", maxLayoutSections)
+	}
+
+	total := 0
+	sectionNames := map[string]bool{}
+	for i := range l.Sections {
+		section := &l.Sections[i]
+		if section.Name == "" || len(section.Name) > maxLayoutSectionName {
+			return fmt.Errorf("invalid layout: section %d needs a name of up to %d characters", i+1, maxLayoutSectionName)
+		}
+		if sectionNames[section.Name] {
+			return fmt.Errorf("invalid layout: section %q appears more than once", section.Name)
+		}
+		sectionNames[section.Name] = true
+		if section.Quality < 0 || section.Quality > maxLayoutQualityScore {
+			return fmt.Errorf("invalid layout: section %q quality must be between 0 and %d", section.Name, maxLayoutQualityScore)
+		}
+		if err := validTierID(section.PriceTierID); err != nil {
+			return fmt.Errorf("invalid layout: section %q %v", section.Name, err)
+		}
+		if len(section.Rows) == 0 || len(section.Rows) > maxLayoutRows {
+			return fmt.Errorf("invalid layout: section %q needs between 1 and %d rows", section.Name, maxLayoutRows)
+		}
+
+		rowLabels := map[string]bool{}
+		seatNumbers := map[string]bool{}
+		for j := range section.Rows {
+			row := &section.Rows[j]
+			if row.Label == "" {
+				return fmt.Errorf("invalid layout: row %d of section %q needs a label", j+1, section.Name)
+			}
+			if rowLabels[row.Label] {
+				return fmt.Errorf("invalid layout: row %q appears more than once in section %q", row.Label, section.Name)
+			}
+			rowLabels[row.Label] = true
+
+			count, err := row.validate()
+			if err != nil {
+				return fmt.Errorf("invalid layout: row %q of section %q %v", row.Label, section.Name, err)
+			}
+			total += count
+
+			// Seat numbers join the row label and number, so they have to stay unique
+			// across the section as well
+			for _, number := range row.numbers() {
+				if seatNumbers[row.Label+number] {
+					return fmt.Errorf("invalid layout: seat number %q appears more than once in section %q", row.Label+number, section.Name)
+				}
+				seatNumbers[row.Label+number] = true
+			}
+		}
+	}
+	if total > maxLayoutSeats {
+		return fmt.Errorf("invalid layout: at most %d seats are allowed", maxLayoutSeats)
+	}
+	return nil
+}
+
+func (r *LayoutRow) validate() (int, error) {
+	switch {
+	case r.SeatCount != 0 && len(r.Seats) != 0:
+		return 0, errors.New("cannot have both seats and seat_count")
+	case r.SeatCount < 0 || r.SeatCount > maxLayoutSeatsPerRow || len(r.Seats) > maxLayoutSeatsPerRow:
+		return 0, fmt.Errorf("can have at most %d seats", maxLayoutSeatsPerRow)
+	case r.SeatCount == 0 && len(r.Seats) == 0:
+		return 0, errors.New("needs seats or a seat_count")
+	case r.StartNumber < 0 || r.Spacing < 0:
+		return 0, errors.New("start_number and spacing cannot be negative")
+	}
+
+	numbers := map[string]bool{}
+	for _, number := range r.numbers() {
+		if number == "" {
+			return 0, errors.New("has a seat without a number")
+		}
+		if len(r.Label)+len(number) > maxLayoutSeatNumber {
+			return 0, fmt.Errorf("seat %q makes a seat number longer than %d characters", number, maxLayoutSeatNumber)
+		}
+		if numbers[number] {
+			return 0, fmt.Errorf("has seat %q more than once", number)
+		}
+		numbers[number] = true
+	}
+	for _, seat := range r.Seats {
+		if err := validTierID(seat.PriceTierID); err != nil {
+			return 0, fmt.Errorf("seat %q %v", seat.Number, err)
+		}
+	}
+	for _, number := range append(append([]string{}, r.AccessibleSeats...), r.AislesAfter...) {
+		if !numbers[number] {
+			return 0, fmt.Errorf("marks seat %q, which is not in the row", number)
+		}
+	}
+	return len(numbers), nil
+}
+
+// numbers lists the row's seat numbers in order
+func (r *LayoutRow) numbers() []string {
+	if len(r.Seats) > 0 {
+		numbers := make([]string, len(r.Seats))
+		for i, seat := range r.Seats {
+			numbers[i] = seat.Number
+		}
+		return numbers
+	}
+	start := r.StartNumber
+	if start == 0 {
+		start = 1
+	}
+	numbers := make([]string, r.SeatCount)
+	for i := range numbers {
+		numbers[i] = fmt.Sprintf("%d", start+i)
+	}
+	return numbers
+}
+
+// SectionQuality is the quality score of the named section. Sections the
+// layout does not list, and any section of a free-form layout, count as 50.
+func (l *SeatLayout) SectionQuality(name string) int {
+	if l != nil {
+		for i := range l.Sections {
+			if l.Sections[i].Name == name {
+				return l.Sections[i].score()
+			}
+		}
+	}
+	return defaultLayoutQuality
+}
+
+func (s *LayoutSection) score() int {
+	if s.Quality == 0 {
+		return defaultLayoutQuality
+	}
+	return s.Quality
+}
+
+// Expand lists every seat of a validated layout, section by section and row
+// by row in layout order
+func (l *SeatLayout) Expand() []PlannedSeat {
+	var planned []PlannedSeat
+	for _, section := range l.Sections {
+		quality := section.score()
+		for _, row := range section.Rows {
+			accessible := toSet(row.AccessibleSeats)
+			aisles := toSet(row.AislesAfter)
+			spacing := row.Spacing
+			if spacing == 0 {
+				spacing = defaultLayoutSpacing
+			}
+
+			for i, number := range row.numbers() {
+				seat := PlannedSeat{
+					Key:          LayoutSeatKey(section.Name, row.Label, number),
+					Section:      section.Name,
+					Row:          row.Label,
+					Number:       number,
+					SeatNumber:   row.Label + number,
+					Position:     i + 1,
+					X:            row.X + float64(i)*spacing,
+					Y:            row.Y,
+					IsAccessible: accessible[number],
+					AisleAfter:   aisles[number],
+					Quality:      quality,
+					PriceTierID:  section.PriceTierID,
+				}
+				if len(row.Seats) > 0 {
+					explicit := row.Seats[i]
+					seat.X, seat.Y = explicit.X, explicit.Y
+					seat.IsAccessible = seat.IsAccessible || explicit.Accessible
+					seat.AisleAfter = seat.AisleAfter || explicit.AisleAfter
+					if explicit.PriceTierID != "" {
+						seat.PriceTierID = explicit.PriceTierID
+					}
+				}
+				planned = append(planned, seat)
+			}
+		}
+	}
+	return planned
+}
+
+// LayoutSeatKey identifies a layout seat by its section, row and number
+func LayoutSeatKey(section, row, number string) string {
+	return section + "/" + row + "/" + number
+}
+
+func validTierID(id string) error {
+	if id == "" {
+		return nil
+	}
+	if _, err := uuid.Parse(id); err != nil {
+		return errors.New("has an invalid price_tier_id")
+	}
+	return nil
+}
+
+func toSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, value := range values {
+		set[value] = true
+	}
+	return set
+}
@@ -0,0 +1,55 @@
+package seats
+
+import (
+	"errors"
+	"ticket-zetu-api/modules/events/models/events"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	EventSeatAvailable = "available"
+	EventSeatHeld      = "held"
+	EventSeatBooked    = "booked"
+)
+
+// EventSeat is a venue seat's availability for one event. A seat without a
+// row for an event is available for it; rows are added the first time the
+// seat is held or its status is set for the event. Seat.Status is the seat's
+// state at the venue as a whole, such as a seat taken out of service.
+type EventSeat struct {
+	ID      string `gorm:"type:char(36);primaryKey" json:"id"`
+	EventID string `gorm:"type:char(36);not null;uniqueIndex:idx_event_seat,priority:1" json:"event_id"`
+	SeatID  string `gorm:"type:char(36);not null;uniqueIndex:idx_event_seat,priority:2;index" json:"seat_id"`
+	Status  string `gorm:"type:varchar(20);not null;default:'available';index;check:status IN ('available','held','booked')" json:"status"`
+	// ReservationID is the reservation holding or booking the seat, if any
+	ReservationID *string   `gorm:"type:char(36);index" json:"reservation_id,omitempty"`
+	CreatedAt     time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt     time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+	Version       int       `gorm:"default:1" json:"version"`
+
+	Event events.Event `gorm:"foreignKey:EventID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	Seat  Seat         `gorm:"foreignKey:SeatID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+}
+
+func (es *EventSeat) BeforeCreate(tx *gorm.DB) error {
+	if es.ID == "" {
+		es.ID = uuid.New().String()
+	}
+	if es.EventID == "" {
+		return errors.New("event_id cannot be empty")
+	}
+	if es.SeatID == "" {
+		return errors.New("seat_id cannot be empty")
+	}
+	if es.Status == "" {
+		es.Status = EventSeatAvailable
+	}
+	return nil
+}
+
+func (EventSeat) TableName() string {
+	return "event_seats"
+}
@@ -22,6 +22,16 @@ type Seat struct {
 	UpdatedAt   time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
 	DeletedAt   gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
 	Version     int            `gorm:"default:1" json:"version"`
+	// Seats generated from the venue's layout carry their place in it. LayoutKey
+	// is empty for seats created by hand.
+	LayoutKey    string  `gorm:"type:varchar(120);index" json:"layout_key,omitempty"`
+	SeatRow      string  `gorm:"type:varchar(10)" json:"seat_row,omitempty"`
+	Position     int     `gorm:"default:0" json:"position"`
+	X            float64 `gorm:"type:decimal(10,2);default:0" json:"x"`
+	Y            float64 `gorm:"type:decimal(10,2);default:0" json:"y"`
+	IsAccessible bool    `gorm:"default:false" json:"is_accessible"`
+	AisleAfter   bool    `gorm:"default:false" json:"aisle_after"`
+	Quality      int     `gorm:"default:0" json:"quality"`
 
 	PriceTier tickets.PriceTier `gorm:"foreignKey:PriceTierID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;" json:"price_tier,omitempty"`
 	Venue     events.Venue      `gorm:"foreignKey:VenueID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;" json:"venue"`
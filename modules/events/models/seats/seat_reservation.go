@@ -21,6 +21,9 @@ type SeatReservation struct {
 	UpdatedAt time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
 	Version   int            `gorm:"default:1" json:"version"`
+	// OrderID is the order buying the seat, and Price the seat's share of it
+	OrderID *string `gorm:"type:char(36);index" json:"order_id,omitempty"`
+	Price   float64 `gorm:"type:numeric(10,2);not null;default:0" json:"price"`
 
 	User  members.User `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;" json:"user"`
 	Event events.Event `gorm:"foreignKey:EventID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;" json:"event"`
@@ -36,6 +36,13 @@ func SetupEventsRoutes(router fiber.Router, db *gorm.DB, logHandler *handler.Log
 		eventGroup.Post("/:event_id/images", eventController.AddEventImage)
 		eventGroup.Delete("/:event_id/images/:image_id", eventController.DeleteEventImage)
 
+		// Lifecycle routes
+		eventGroup.Post("/:id/publish", eventController.PublishEvent)
+		eventGroup.Post("/:id/unschedule", eventController.UnscheduleEvent)
+		eventGroup.Post("/:id/postpone", eventController.PostponeEvent)
+		eventGroup.Post("/:id/cancel", eventController.CancelEvent)
+		eventGroup.Get("/:id/cancellation", eventController.GetEventCancellation)
+
 		// Interaction routes
 		eventGroup.Post("/:event_id/favorite", eventController.ToggleFavorite)
 		eventGroup.Post("/:event_id/upvote", eventController.ToggleUpvote)
@@ -54,4 +61,8 @@ func SetupEventsRoutes(router fiber.Router, db *gorm.DB, logHandler *handler.Log
 		userInteractionGroup.Get("/favorites", eventController.GetUserFavorites)
 		userInteractionGroup.Get("/comments", eventController.GetUserComments)
 	}
+
+	// Public routes only show events that have been published
+	router.Get("/public/events", eventController.GetPublicEvents)
+	router.Get("/public/events/:id", eventController.GetPublicEvent)
 }
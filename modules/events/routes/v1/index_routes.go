@@ -14,4 +14,5 @@ func SetupEventsMainRoutes(router fiber.Router, db *gorm.DB, logHandler *handler
 	SetupEventsRoutes(router, db, logHandler, cloudinary)
 	VenueRoutes(router, db, logHandler, cloudinary)
 	SeatRoutes(router, db, logHandler)
+	SeriesRoutes(router, db, logHandler)
 }
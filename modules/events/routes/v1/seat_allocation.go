@@ -17,12 +17,16 @@ func SeatRoutes(router fiber.Router, db *gorm.DB, logHandler *handler.LogHandler
 
 	seatService := services.NewSeatService(db, authService)
 	seatReservationService := services.NewSeatReservationService(db, authService)
+	seatMapService := services.NewSeatMapService(db, authService)
 
 	seatController := controller.NewSeatController(seatService, logHandler)
 	seatReservationController := controller.NewSeatReservationController(seatReservationService, logHandler)
+	seatMapController := controller.NewSeatMapController(seatMapService, logHandler)
 
 	seatGroup := router.Group("/seats", authMiddleware)
 	{
+		seatGroup.Get("/map/:venue_id", seatMapController.GetSeatMap)
+		seatGroup.Put("/map/:venue_id", seatMapController.SaveLayout)
 		seatGroup.Get("/:venue_id", seatController.GetSeats)
 		seatGroup.Post("/", seatController.CreateSeat)
 		seatGroup.Put("/:id", seatController.UpdateSeat)
@@ -34,6 +38,7 @@ func SeatRoutes(router fiber.Router, db *gorm.DB, logHandler *handler.LogHandler
 	{
 		reservationGroup.Get("/", seatReservationController.GetSeatReservations)
 		reservationGroup.Post("/", seatReservationController.CreateSeatReservation)
+		reservationGroup.Post("/best-available", seatReservationController.ReserveBestAvailable)
 		reservationGroup.Put("/:id", seatReservationController.UpdateSeatReservation)
 		reservationGroup.Delete("/:id", seatReservationController.DeleteSeatReservation)
 		reservationGroup.Put("/:id/toggle-status", seatReservationController.ToggleSeatReservationStatus)
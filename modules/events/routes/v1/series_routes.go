@@ -0,0 +1,29 @@
+package routes
+
+import (
+	"ticket-zetu-api/logs/handler"
+	series_controller "ticket-zetu-api/modules/events/series/controller"
+	series_service "ticket-zetu-api/modules/events/series/service"
+	inventory_service "ticket-zetu-api/modules/tickets/inventory/service"
+	authorization_service "ticket-zetu-api/modules/users/authorization/service"
+	"ticket-zetu-api/modules/users/middleware"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+func SeriesRoutes(router fiber.Router, db *gorm.DB, logHandler *handler.LogHandler) {
+	authMiddleware := middleware.IsAuthenticated(db, logHandler)
+	authService := authorization_service.NewPermissionService(db)
+	seriesService := series_service.NewSeriesService(db, authService, inventory_service.NewInventoryService(db))
+	seriesController := series_controller.NewSeriesController(seriesService, logHandler)
+
+	seriesGroup := router.Group("/event-series", authMiddleware)
+	{
+		seriesGroup.Get("/", seriesController.GetAllSeries)
+		seriesGroup.Get("/:id", seriesController.GetSeries)
+		seriesGroup.Post("/", seriesController.CreateSeries)
+		seriesGroup.Put("/:id", seriesController.UpdateSeries)
+		seriesGroup.Delete("/:id/occurrences/:event_id", seriesController.RemoveOccurrence)
+	}
+}
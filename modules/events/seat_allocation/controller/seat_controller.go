@@ -61,9 +61,9 @@ func (c *SeatController) CreateSeat(ctx *fiber.Ctx) error {
 			return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusForbidden, err.Error()), fiber.StatusForbidden)
 		case "seat number already exists in this venue":
 			return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusConflict, err.Error()), fiber.StatusConflict)
-		case "invalid venue ID format", "invalid price tier ID format":
+		case "invalid venue ID format", "invalid price tier ID format", "invalid event ID format":
 			return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, err.Error()), fiber.StatusBadRequest)
-		case "venue not found", "price tier not found":
+		case "venue not found", "price tier not found", "event not found":
 			return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusNotFound, err.Error()), fiber.StatusNotFound)
 		default:
 			return c.logHandler.LogError(ctx, err, fiber.StatusInternalServerError)
@@ -74,7 +74,7 @@ func (c *SeatController) CreateSeat(ctx *fiber.Ctx) error {
 
 // GetSeats godoc
 // @Summary Get all Seats
-// @Description Retrieves all seats for a venue with optional filters.
+// @Description Retrieves all seats for a venue with optional filters. With event_id, the seats of the event's venue are returned with their status for that event.
 // @Tags Seat Group
 // @Accept json
 // @Produce json
@@ -83,7 +83,7 @@ func (c *SeatController) CreateSeat(ctx *fiber.Ctx) error {
 // @Success 200 {object} map[string]interface{} "Seats retrieved successfully"
 // @Failure 400 {object} map[string]interface{} "Invalid query parameters"
 // @Failure 403 {object} map[string]interface{} "User lacks read permission"
-// @Failure 404 {object} map[string]interface{} "Venue or price tier not found"
+// @Failure 404 {object} map[string]interface{} "Venue, event or price tier not found"
 // @Failure 500 {object} map[string]interface{} "Internal server error"
 // @Router /seats [get]
 func (c *SeatController) GetSeats(ctx *fiber.Ctx) error {
@@ -103,9 +103,9 @@ func (c *SeatController) GetSeats(ctx *fiber.Ctx) error {
 		switch err.Error() {
 		case "user lacks read:seats permission":
 			return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusForbidden, err.Error()), fiber.StatusForbidden)
-		case "invalid venue ID format", "invalid price tier ID format":
+		case "invalid venue ID format", "invalid price tier ID format", "invalid event ID format":
 			return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, err.Error()), fiber.StatusBadRequest)
-		case "venue not found", "price tier not found":
+		case "venue not found", "price tier not found", "event not found":
 			return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusNotFound, err.Error()), fiber.StatusNotFound)
 		default:
 			return c.logHandler.LogError(ctx, err, fiber.StatusInternalServerError)
@@ -198,7 +198,7 @@ func (c *SeatController) DeleteSeat(ctx *fiber.Ctx) error {
 
 // ToggleSeatStatus godoc
 // @Summary Toggle Seat Status
-// @Description Toggles the status of a seat (available, held, booked).
+// @Description Toggles the status of a seat (available, held, booked). With event_id the status applies to that event only; otherwise it applies to the seat at the venue as a whole.
 // @Tags Seat Group
 // @Accept json
 // @Produce json
@@ -208,7 +208,8 @@ func (c *SeatController) DeleteSeat(ctx *fiber.Ctx) error {
 // @Success 200 {object} map[string]interface{} "Seat status toggled successfully"
 // @Failure 400 {object} map[string]interface{} "Invalid request body or seat ID"
 // @Failure 403 {object} map[string]interface{} "User lacks update permission"
-// @Failure 404 {object} map[string]interface{} "Seat not found"
+// @Failure 404 {object} map[string]interface{} "Seat or event not found"
+// @Failure 409 {object} map[string]interface{} "Seat reserved for the event"
 // @Failure 500 {object} map[string]interface{} "Internal server error"
 // @Router /seats/{id}/toggle-status [put]
 func (c *SeatController) ToggleSeatStatus(ctx *fiber.Ctx) error {
@@ -230,9 +231,11 @@ func (c *SeatController) ToggleSeatStatus(ctx *fiber.Ctx) error {
 		switch err.Error() {
 		case "user lacks update:seats permission":
 			return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusForbidden, err.Error()), fiber.StatusForbidden)
-		case "invalid seat ID format", "seat status already set":
+		case "invalid seat ID format", "seat status already set", "invalid event ID format", "seat is not at the event's venue":
 			return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, err.Error()), fiber.StatusBadRequest)
-		case "seat not found":
+		case "seat is already reserved for this event":
+			return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusConflict, err.Error()), fiber.StatusConflict)
+		case "seat not found", "event not found":
 			return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusNotFound, err.Error()), fiber.StatusNotFound)
 		default:
 			return c.logHandler.LogError(ctx, err, fiber.StatusInternalServerError)
@@ -0,0 +1,96 @@
+package controller
+
+import (
+	"strings"
+	"ticket-zetu-api/logs/handler"
+	"ticket-zetu-api/modules/events/models/events"
+	"ticket-zetu-api/modules/events/seat_allocation/services"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type SeatMapController struct {
+	service    services.SeatMapService
+	logHandler *handler.LogHandler
+}
+
+func NewSeatMapController(service services.SeatMapService, logHandler *handler.LogHandler) *SeatMapController {
+	return &SeatMapController{
+		service:    service,
+		logHandler: logHandler,
+	}
+}
+
+// SaveLayout godoc
+// @Summary Save a venue's seat layout
+// @Description Replaces a venue's layout with a structured one of sections, rows and seats with coordinates, aisles and accessibility flags, and generates the venue's seats from it. Seats already generated are kept and moved to their new place; seats the layout drops are removed unless they are held or booked.
+// @Tags Seat Group
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param venue_id path string true "Venue ID"
+// @Param input body events.SeatLayout true "Seat layout"
+// @Success 200 {object} map[string]interface{} "Seat layout saved successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid layout"
+// @Failure 403 {object} map[string]interface{} "User lacks update permission"
+// @Failure 404 {object} map[string]interface{} "Venue, organizer or price tier not found"
+// @Failure 409 {object} map[string]interface{} "Layout removes seats that are held or booked"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /seats/map/{venue_id} [put]
+func (c *SeatMapController) SaveLayout(ctx *fiber.Ctx) error {
+	userID := ctx.Locals("user_id").(string)
+	venueID := ctx.Params("venue_id")
+
+	var layout events.SeatLayout
+	if err := ctx.BodyParser(&layout); err != nil {
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, "Invalid request body"), fiber.StatusBadRequest)
+	}
+
+	result, err := c.service.SaveLayout(userID, venueID, layout)
+	if err != nil {
+		switch {
+		case err.Error() == "user lacks update:venues permission":
+			return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusForbidden, err.Error()), fiber.StatusForbidden)
+		case err.Error() == "invalid venue ID format", strings.HasPrefix(err.Error(), "invalid layout"):
+			return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, err.Error()), fiber.StatusBadRequest)
+		case err.Error() == "venue not found", err.Error() == "organizer not found", err.Error() == "price tier not found":
+			return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusNotFound, err.Error()), fiber.StatusNotFound)
+		case err.Error() == "layout removes seats that are held or booked":
+			return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusConflict, err.Error()), fiber.StatusConflict)
+		default:
+			return c.logHandler.LogError(ctx, err, fiber.StatusInternalServerError)
+		}
+	}
+	return c.logHandler.LogSuccess(ctx, result, "Seat layout saved successfully", true)
+}
+
+// GetSeatMap godoc
+// @Summary Get a venue's seat map
+// @Description Returns every seat of a venue grouped by section and row in layout order, with coordinates, aisles, accessibility flags and status, ready to draw. With event_id, statuses are the seats' availability for that event.
+// @Tags Seat Group
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param venue_id path string true "Venue ID"
+// @Param event_id query string false "Event at the venue"
+// @Success 200 {object} map[string]interface{} "Seat map retrieved successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid venue or event ID"
+// @Failure 404 {object} map[string]interface{} "Venue or event not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /seats/map/{venue_id} [get]
+func (c *SeatMapController) GetSeatMap(ctx *fiber.Ctx) error {
+	venueID := ctx.Params("venue_id")
+
+	seatMap, err := c.service.GetSeatMap(venueID, ctx.Query("event_id"))
+	if err != nil {
+		switch err.Error() {
+		case "invalid venue ID format", "invalid event ID format":
+			return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, err.Error()), fiber.StatusBadRequest)
+		case "venue not found", "event not found":
+			return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusNotFound, err.Error()), fiber.StatusNotFound)
+		default:
+			return c.logHandler.LogError(ctx, err, fiber.StatusInternalServerError)
+		}
+	}
+	return c.logHandler.LogSuccess(ctx, seatMap, "Seat map retrieved successfully", false)
+}
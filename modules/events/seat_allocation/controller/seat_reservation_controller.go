@@ -57,7 +57,7 @@ func (c *SeatReservationController) CreateSeatReservation(ctx *fiber.Ctx) error
 			return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusForbidden, err.Error()), fiber.StatusForbidden)
 		case "seat is already reserved for this event":
 			return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusConflict, err.Error()), fiber.StatusConflict)
-		case "invalid user ID format", "invalid event ID format", "invalid seat ID format", "invalid expires_at format", "expires_at must be in the future", "seat is not available for reservation":
+		case "invalid user ID format", "invalid event ID format", "invalid seat ID format", "invalid expires_at format", "expires_at must be in the future", "seat is not available for reservation", "seat is not at the event's venue":
 			return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, err.Error()), fiber.StatusBadRequest)
 		case "user not found", "event not found", "seat not found":
 			return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusNotFound, err.Error()), fiber.StatusNotFound)
@@ -68,6 +68,51 @@ func (c *SeatReservationController) CreateSeatReservation(ctx *fiber.Ctx) error
 	return c.logHandler.LogSuccess(ctx, reservation, "Seat reservation created successfully", true)
 }
 
+// ReserveBestAvailable godoc
+// @Summary Reserve the best available seats
+// @Description Picks the best seats left for an event and holds them for the user. Seats are kept together in one row where possible, ranked by section quality, then rows nearer the front and seats nearer the middle; otherwise they are split over a few neighbouring rows of one section. Optionally limited to a section or price tier.
+// @Tags Seat Reservation Group
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param input body dto.BestAvailableSeatsDTO true "Best available request"
+// @Success 200 {object} map[string]interface{} "Seats reserved successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid request body"
+// @Failure 403 {object} map[string]interface{} "User lacks create permission"
+// @Failure 404 {object} map[string]interface{} "User, event, venue, or price tier not found"
+// @Failure 409 {object} map[string]interface{} "Not enough seats available"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /seat-reservations/best-available [post]
+func (c *SeatReservationController) ReserveBestAvailable(ctx *fiber.Ctx) error {
+	userID := ctx.Locals("user_id").(string)
+
+	var input dto.BestAvailableSeatsDTO
+	if err := ctx.BodyParser(&input); err != nil {
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, "Invalid request body"), fiber.StatusBadRequest)
+	}
+
+	if err := c.validator.Struct(input); err != nil {
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, err.Error()), fiber.StatusBadRequest)
+	}
+
+	reservations, err := c.service.ReserveBestAvailable(userID, input)
+	if err != nil {
+		switch err.Error() {
+		case "user lacks create:seat_reservations permission":
+			return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusForbidden, err.Error()), fiber.StatusForbidden)
+		case "not enough seats available":
+			return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusConflict, err.Error()), fiber.StatusConflict)
+		case "quantity must be between 1 and 10", "invalid user ID format", "invalid event ID format", "invalid price tier ID format", "invalid expires_at format", "expires_at must be in the future":
+			return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, err.Error()), fiber.StatusBadRequest)
+		case "user not found", "event not found", "venue not found", "price tier not found":
+			return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusNotFound, err.Error()), fiber.StatusNotFound)
+		default:
+			return c.logHandler.LogError(ctx, err, fiber.StatusInternalServerError)
+		}
+	}
+	return c.logHandler.LogSuccess(ctx, reservations, "Seats reserved successfully", true)
+}
+
 // GetSeatReservations godoc
 // @Summary Get all Seat Reservations
 // @Description Retrieves all seat reservations with optional filters.
@@ -147,7 +192,7 @@ func (c *SeatReservationController) UpdateSeatReservation(ctx *fiber.Ctx) error
 			return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusForbidden, err.Error()), fiber.StatusForbidden)
 		case "seat is already reserved for this event":
 			return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusConflict, err.Error()), fiber.StatusConflict)
-		case "invalid reservation ID format", "invalid user ID format", "invalid event ID format", "invalid seat ID format", "invalid expires_at format", "expires_at must be in the future":
+		case "invalid reservation ID format", "invalid user ID format", "invalid event ID format", "invalid seat ID format", "invalid expires_at format", "expires_at must be in the future", "seat is not available for reservation", "seat is not at the event's venue":
 			return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, err.Error()), fiber.StatusBadRequest)
 		case "reservation not found", "user not found", "event not found", "seat not found":
 			return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusNotFound, err.Error()), fiber.StatusNotFound)
@@ -181,7 +226,7 @@ func (c *SeatReservationController) DeleteSeatReservation(ctx *fiber.Ctx) error
 		switch err.Error() {
 		case "user lacks delete:seat_reservations permission":
 			return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusForbidden, err.Error()), fiber.StatusForbidden)
-		case "invalid reservation ID format", "cannot delete a confirmed reservation":
+		case "invalid reservation ID format", "cannot delete a confirmed reservation", "cannot delete a reservation that is part of an order":
 			return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, err.Error()), fiber.StatusBadRequest)
 		case "reservation not found":
 			return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusNotFound, err.Error()), fiber.StatusNotFound)
@@ -205,6 +250,7 @@ func (c *SeatReservationController) DeleteSeatReservation(ctx *fiber.Ctx) error
 // @Failure 400 {object} map[string]interface{} "Invalid request body or reservation ID"
 // @Failure 403 {object} map[string]interface{} "User lacks update permission"
 // @Failure 404 {object} map[string]interface{} "Reservation or seat not found"
+// @Failure 409 {object} map[string]interface{} "Seat already reserved"
 // @Failure 500 {object} map[string]interface{} "Internal server error"
 // @Router /seat-reservations/{id}/toggle-status [put]
 func (c *SeatReservationController) ToggleSeatReservationStatus(ctx *fiber.Ctx) error {
@@ -226,6 +272,8 @@ func (c *SeatReservationController) ToggleSeatReservationStatus(ctx *fiber.Ctx)
 		switch err.Error() {
 		case "user lacks update:seat_reservations permission":
 			return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusForbidden, err.Error()), fiber.StatusForbidden)
+		case "seat is already reserved for this event":
+			return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusConflict, err.Error()), fiber.StatusConflict)
 		case "invalid reservation ID format", "reservation status already set":
 			return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, err.Error()), fiber.StatusBadRequest)
 		case "reservation not found", "seat not found":
@@ -17,6 +17,7 @@ type GetSeatReservationDTO struct {
 	UserName  string            `json:"user_name,omitempty"`
 	EventName string            `json:"event_name,omitempty"`
 	SeatInfo  *seats.Seat       `json:"seat_info,omitempty"`
+	OrderID   *string           `json:"order_id,omitempty"`
 }
 
 type CreateSeatReservationDTO struct {
@@ -47,4 +48,14 @@ type SeatReservationFilterDTO struct {
 	Status    string `json:"status,omitempty" binding:"omitempty,oneof=held confirmed released"`
 	Page      int    `json:"page,omitempty" binding:"omitempty,min=1"`
 	PageSize  int    `json:"page_size,omitempty" binding:"omitempty,min=1,max=100"`
-}
\ No newline at end of file
+}
+// BestAvailableSeatsDTO asks for the best seats left for an event, kept
+// together where possible, optionally within a section or price tier
+type BestAvailableSeatsDTO struct {
+	UserID      string `json:"user_id" binding:"required,uuid"`
+	EventID     string `json:"event_id" binding:"required,uuid"`
+	Quantity    int    `json:"quantity" binding:"required,min=1,max=10"`
+	SeatSection string `json:"seat_section,omitempty"`
+	PriceTierID string `json:"price_tier_id,omitempty" binding:"omitempty,uuid"`
+	ExpiresAt   string `json:"expires_at" binding:"required,datetime=2006-01-02T15:04:05Z07:00"`
+}
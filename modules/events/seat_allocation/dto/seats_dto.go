@@ -1,6 +1,9 @@
 package dto
 
-import "ticket-zetu-api/modules/tickets/models/tickets"
+import (
+	"ticket-zetu-api/modules/events/models/events"
+	"ticket-zetu-api/modules/tickets/models/tickets"
+)
 
 type GetSeatDTO struct {
 	ID          string             `json:"id"`
@@ -14,6 +17,8 @@ type GetSeatDTO struct {
 	DeletedAt   *string            `json:"deleted_at,omitempty"`
 	VenueName   string             `json:"venue_name,omitempty"`
 	PriceTier   *tickets.PriceTier `json:"price_tier,omitempty"`
+	// EventID is set when Status is the seat's status for that event
+	EventID string `json:"event_id,omitempty"`
 }
 
 type CreateSeatDTO struct {
@@ -33,9 +38,12 @@ type UpdateSeatDTO struct {
 	PriceTierID string `json:"price_tier_id,omitempty"`
 }
 
+// ToggleSeatStatusDTO sets a seat's status for one event when EventID is
+// given, and for the venue as a whole otherwise
 type ToggleSeatStatusDTO struct {
-	ID     string `json:"id" binding:"required"`
-	Status string `json:"status" binding:"required,oneof=available held booked"`
+	ID      string `json:"id" binding:"required"`
+	Status  string `json:"status" binding:"required,oneof=available held booked"`
+	EventID string `json:"event_id,omitempty"`
 }
 
 type SeatFilterDTO struct {
@@ -44,6 +52,51 @@ type SeatFilterDTO struct {
 	SeatSection string `json:"seat_section,omitempty"`
 	Status      string `json:"status,omitempty" binding:"omitempty,oneof=available held booked"`
 	PriceTierID string `json:"price_tier_id,omitempty"`
+	EventID     string `json:"event_id,omitempty"`
 	Page        int    `json:"page,omitempty" binding:"omitempty,min=1"`
 	PageSize    int    `json:"page_size,omitempty" binding:"omitempty,min=1,max=100"`
 }
+
+// SeatLayoutSyncDTO reports how a venue's seats changed to match its layout
+type SeatLayoutSyncDTO struct {
+	VenueID string `json:"venue_id"`
+	Created int    `json:"created"`
+	Updated int    `json:"updated"`
+	Removed int    `json:"removed"`
+	Total   int    `json:"total"`
+}
+
+// SeatMapDTO is a venue's seats grouped by section and row, in drawing order
+type SeatMapDTO struct {
+	VenueID    string              `json:"venue_id"`
+	VenueName  string              `json:"venue_name"`
+	EventID    string              `json:"event_id,omitempty"`
+	Width      float64             `json:"width,omitempty"`
+	Height     float64             `json:"height,omitempty"`
+	Stage      *events.LayoutArea  `json:"stage,omitempty"`
+	TotalSeats int                 `json:"total_seats"`
+	Sections   []SeatMapSectionDTO `json:"sections"`
+}
+
+type SeatMapSectionDTO struct {
+	Name    string          `json:"name"`
+	Quality int             `json:"quality"`
+	Rows    []SeatMapRowDTO `json:"rows"`
+}
+
+type SeatMapRowDTO struct {
+	Label string           `json:"label"`
+	Seats []SeatMapSeatDTO `json:"seats"`
+}
+
+type SeatMapSeatDTO struct {
+	ID           string  `json:"id"`
+	SeatNumber   string  `json:"seat_number"`
+	Number       string  `json:"number"`
+	X            float64 `json:"x"`
+	Y            float64 `json:"y"`
+	IsAccessible bool    `json:"is_accessible"`
+	AisleAfter   bool    `json:"aisle_after"`
+	Status       string  `json:"status"`
+	PriceTierID  string  `json:"price_tier_id,omitempty"`
+}
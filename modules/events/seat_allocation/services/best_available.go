@@ -0,0 +1,445 @@
+package services
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"ticket-zetu-api/modules/events/models/events"
+	"ticket-zetu-api/modules/events/models/seats"
+	"ticket-zetu-api/modules/events/seat_allocation/dto"
+	"ticket-zetu-api/modules/tickets/models/tickets"
+	"ticket-zetu-api/modules/users/models/members"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	maxBestAvailableSeats = 10
+	// maxRowSpread is how many neighbouring rows of a section a group may be
+	// split over when no single row has enough seats together
+	maxRowSpread = 3
+	// bestAvailableAttempts is how often seats are picked again when another
+	// buyer takes one of them first
+	bestAvailableAttempts = 3
+)
+
+// seatRow is one row of a section with its seats in seat order
+type seatRow struct {
+	section string
+	label   string
+	order   int
+	// quality is the section's score from the venue layout
+	quality int
+	seats   []rowSeat
+}
+
+type rowSeat struct {
+	seat seats.Seat
+	// number is the seat's place in its row, or 0 when it cannot be told
+	number    int
+	available bool
+}
+
+// seatBlock is a group of seats that could be offered for a request
+type seatBlock struct {
+	seats     []seats.Seat
+	quality   int
+	rows      int
+	order     int
+	offCentre float64
+}
+
+// ReserveBestAvailable picks the best seats left for an event and holds them
+// for the user. Seats are kept together in one row where possible, in the
+// section with the best quality score and the row nearest the front, as close
+// to the middle of the row as they fit. Failing that, they are split over a
+// few neighbouring rows of one section.
+func (s *seatReservationService) ReserveBestAvailable(userID string, input dto.BestAvailableSeatsDTO) ([]dto.GetSeatReservationDTO, error) {
+	hasPerm, err := s.authService.HasPermission(userID, "create:seat_reservations")
+	if err != nil {
+		return nil, err
+	}
+	if !hasPerm {
+		return nil, errors.New("user lacks create:seat_reservations permission")
+	}
+
+	if input.Quantity < 1 || input.Quantity > maxBestAvailableSeats {
+		return nil, errors.New("quantity must be between 1 and 10")
+	}
+	if _, err := uuid.Parse(input.UserID); err != nil {
+		return nil, errors.New("invalid user ID format")
+	}
+	if _, err := uuid.Parse(input.EventID); err != nil {
+		return nil, errors.New("invalid event ID format")
+	}
+	if input.PriceTierID != "" {
+		if _, err := uuid.Parse(input.PriceTierID); err != nil {
+			return nil, errors.New("invalid price tier ID format")
+		}
+	}
+
+	expiresAt, err := time.Parse(time.RFC3339, input.ExpiresAt)
+	if err != nil {
+		return nil, errors.New("invalid expires_at format")
+	}
+	if expiresAt.Before(time.Now()) {
+		return nil, errors.New("expires_at must be in the future")
+	}
+
+	var user members.User
+	if err := s.db.Where("id = ? AND deleted_at IS NULL", input.UserID).First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("user not found")
+		}
+		return nil, err
+	}
+
+	var event events.Event
+	if err := s.db.Where("id = ? AND deleted_at IS NULL", input.EventID).First(&event).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("event not found")
+		}
+		return nil, err
+	}
+
+	var venue events.Venue
+	if err := s.db.Where("id = ? AND deleted_at IS NULL", event.VenueID).First(&venue).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("venue not found")
+		}
+		return nil, err
+	}
+
+	if input.PriceTierID != "" {
+		var priceTier tickets.PriceTier
+		if err := s.db.Where("id = ? AND deleted_at IS NULL", input.PriceTierID).First(&priceTier).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, errors.New("price tier not found")
+			}
+			return nil, err
+		}
+	}
+
+	// A free-form layout only loses the layout's row order
+	layout, _ := events.ParseLayout(venue.Layout)
+
+	for attempt := 0; attempt < bestAvailableAttempts; attempt++ {
+		block, err := s.findBestSeats(&event, layout, input)
+		if err != nil {
+			return nil, err
+		}
+
+		reservationIDs, err := s.holdSeats(input.UserID, event.ID, block.seats, expiresAt)
+		if err != nil {
+			// Someone else took one of the seats since they were picked
+			if err.Error() == "seat is already reserved for this event" {
+				continue
+			}
+			return nil, err
+		}
+
+		reservationsDTO := make([]dto.GetSeatReservationDTO, 0, len(reservationIDs))
+		for _, id := range reservationIDs {
+			var reservation seats.SeatReservation
+			if err := s.db.Preload("User").Preload("Event").Preload("Seat").First(&reservation, "id = ?", id).Error; err != nil {
+				return nil, err
+			}
+			reservationsDTO = append(reservationsDTO, *s.toDTO(&reservation))
+		}
+		return reservationsDTO, nil
+	}
+
+	return nil, errors.New("not enough seats available")
+}
+
+// holdSeats holds all of the seats for the user, or none of them
+func (s *seatReservationService) holdSeats(userID, eventID string, seatList []seats.Seat, expiresAt time.Time) ([]string, error) {
+	reservationIDs := make([]string, 0, len(seatList))
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		for _, seat := range seatList {
+			reservation := seats.SeatReservation{
+				UserID:    userID,
+				EventID:   eventID,
+				SeatID:    seat.ID,
+				Status:    "held",
+				ExpiresAt: expiresAt,
+			}
+			if err := tx.Create(&reservation).Error; err != nil {
+				return err
+			}
+			if err := takeEventSeat(tx, eventID, seat.ID, reservation.ID, seats.EventSeatHeld); err != nil {
+				return err
+			}
+			reservationIDs = append(reservationIDs, reservation.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reservationIDs, nil
+}
+
+// findBestSeats picks the seats to offer from what is left for the event
+func (s *seatReservationService) findBestSeats(event *events.Event, layout *events.SeatLayout, input dto.BestAvailableSeatsDTO) (*seatBlock, error) {
+	query := s.db.Select("id", "venue_id", "seat_number", "seat_section", "status", "price_tier_id", "seat_row", "position", "aisle_after").
+		Where("venue_id = ? AND deleted_at IS NULL", event.VenueID)
+	if input.SeatSection != "" {
+		query = query.Where("seat_section = ?", input.SeatSection)
+	}
+	if input.PriceTierID != "" {
+		query = query.Where("price_tier_id = ?", input.PriceTierID)
+	}
+
+	var venueSeats []seats.Seat
+	if err := query.Find(&venueSeats).Error; err != nil {
+		return nil, err
+	}
+
+	var takenIDs []string
+	if err := s.db.Model(&seats.EventSeat{}).
+		Where("event_id = ? AND status <> ?", event.ID, seats.EventSeatAvailable).
+		Pluck("seat_id", &takenIDs).Error; err != nil {
+		return nil, err
+	}
+	taken := make(map[string]bool, len(takenIDs))
+	for _, id := range takenIDs {
+		taken[id] = true
+	}
+
+	rows := groupRows(venueSeats, taken, layout)
+	if block := bestRowBlock(rows, input.Quantity); block != nil {
+		return block, nil
+	}
+	if block := bestSpreadBlock(rows, input.Quantity); block != nil {
+		return block, nil
+	}
+	return nil, errors.New("not enough seats available")
+}
+
+// groupRows sorts seats into rows, ordered section by section as the layout
+// lists them. Rows the layout does not know, such as those of seats made by
+// hand, follow in label order.
+func groupRows(venueSeats []seats.Seat, taken map[string]bool, layout *events.SeatLayout) []*seatRow {
+	layoutOrder := map[string]int{}
+	if layout != nil {
+		for _, section := range layout.Sections {
+			for j, row := range section.Rows {
+				layoutOrder[section.Name+"/"+row.Label] = j
+			}
+		}
+	}
+
+	byKey := map[string]*seatRow{}
+	var rows []*seatRow
+	for _, seat := range venueSeats {
+		label, number := seatPlace(&seat)
+		key := seat.SeatSection + "/" + label
+		row, ok := byKey[key]
+		if !ok {
+			row = &seatRow{section: seat.SeatSection, label: label, quality: layout.SectionQuality(seat.SeatSection)}
+			byKey[key] = row
+			rows = append(rows, row)
+		}
+		row.seats = append(row.seats, rowSeat{
+			seat:      seat,
+			number:    number,
+			available: seat.Status == "available" && !taken[seat.ID],
+		})
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		a, b := rows[i], rows[j]
+		if a.section != b.section {
+			return a.section < b.section
+		}
+		ai, aKnown := layoutOrder[a.section+"/"+a.label]
+		bi, bKnown := layoutOrder[b.section+"/"+b.label]
+		if aKnown != bKnown {
+			return aKnown
+		}
+		if aKnown {
+			return ai < bi
+		}
+		if len(a.label) != len(b.label) {
+			return len(a.label) < len(b.label)
+		}
+		return a.label < b.label
+	})
+
+	for i, row := range rows {
+		if i > 0 && rows[i-1].section == row.section {
+			row.order = rows[i-1].order + 1
+		}
+		sort.SliceStable(row.seats, func(a, b int) bool {
+			x, y := row.seats[a].number, row.seats[b].number
+			if (x == 0) != (y == 0) {
+				return y == 0
+			}
+			return x < y
+		})
+	}
+	return rows
+}
+
+// seatPlace is the row a seat sits in and its number in that row. Seats from
+// the layout carry both; for seats made by hand they are read from a seat
+// number such as "B12".
+func seatPlace(seat *seats.Seat) (string, int) {
+	if seat.SeatRow != "" {
+		return seat.SeatRow, seat.Position
+	}
+	i := strings.IndexFunc(seat.SeatNumber, unicode.IsDigit)
+	if i < 0 {
+		return "", 0
+	}
+	number, err := strconv.Atoi(seat.SeatNumber[i:])
+	if err != nil {
+		return seat.SeatNumber[:i], 0
+	}
+	return seat.SeatNumber[:i], number
+}
+
+// runs splits a row's available seats into groups sitting side by side, with
+// no taken seat or aisle between them
+func (r *seatRow) runs() [][]rowSeat {
+	var runs [][]rowSeat
+	var current []rowSeat
+	for i, seat := range r.seats {
+		if !seat.available {
+			current = nil
+			continue
+		}
+		if len(current) > 0 {
+			prev := r.seats[i-1]
+			if !prev.available || prev.number == 0 || seat.number != prev.number+1 || prev.seat.AisleAfter {
+				current = nil
+			}
+		}
+		if current == nil {
+			runs = append(runs, nil)
+		}
+		current = append(current, seat)
+		runs[len(runs)-1] = current
+	}
+	return runs
+}
+
+// centre is the middle of the row by seat number
+func (r *seatRow) centre() float64 {
+	low, high := 0, 0
+	for _, seat := range r.seats {
+		if seat.number == 0 {
+			continue
+		}
+		if low == 0 || seat.number < low {
+			low = seat.number
+		}
+		if seat.number > high {
+			high = seat.number
+		}
+	}
+	return float64(low+high) / 2
+}
+
+// window takes the size seats of a run closest to the middle of the row
+func (r *seatRow) window(run []rowSeat, size int) ([]rowSeat, float64) {
+	centre := r.centre()
+	best, bestOff := 0, math.MaxFloat64
+	for start := 0; start+size <= len(run); start++ {
+		off := 0.0
+		if run[start].number != 0 {
+			off = math.Abs(float64(run[start].number+run[start+size-1].number)/2 - centre)
+		}
+		if off < bestOff {
+			best, bestOff = start, off
+		}
+	}
+	return run[best : best+size], bestOff
+}
+
+// bestRowBlock looks for the best group of seats together in a single row
+func bestRowBlock(rows []*seatRow, quantity int) *seatBlock {
+	var best *seatBlock
+	for _, row := range rows {
+		for _, run := range row.runs() {
+			if len(run) < quantity {
+				continue
+			}
+			picked, off := row.window(run, quantity)
+			block := newSeatBlock(picked, 1, row, off)
+			if best == nil || block.betterThan(best) {
+				best = block
+			}
+		}
+	}
+	return best
+}
+
+// bestSpreadBlock falls back to splitting the group over neighbouring rows of
+// a section, taking the largest group left in each
+func bestSpreadBlock(rows []*seatRow, quantity int) *seatBlock {
+	var best *seatBlock
+	for i, first := range rows {
+		var picked []rowSeat
+		offCentre := 0.0
+		spread := 0
+		for j := i; j < len(rows) && j < i+maxRowSpread && len(picked) < quantity; j++ {
+			row := rows[j]
+			if row.section != first.section {
+				break
+			}
+			var largest []rowSeat
+			for _, run := range row.runs() {
+				if len(run) > len(largest) {
+					largest = run
+				}
+			}
+			if len(largest) == 0 {
+				continue
+			}
+			part, off := row.window(largest, min(len(largest), quantity-len(picked)))
+			picked = append(picked, part...)
+			offCentre += off
+			spread = j - i + 1
+		}
+		if len(picked) < quantity {
+			continue
+		}
+		block := newSeatBlock(picked, spread, first, offCentre)
+		if best == nil || block.betterThan(best) {
+			best = block
+		}
+	}
+	return best
+}
+
+// newSeatBlock makes a block of seats from rows of one section, starting at
+// the first row
+func newSeatBlock(picked []rowSeat, rows int, first *seatRow, offCentre float64) *seatBlock {
+	block := &seatBlock{quality: first.quality, rows: rows, order: first.order, offCentre: offCentre}
+	for _, seat := range picked {
+		block.seats = append(block.seats, seat.seat)
+	}
+	return block
+}
+
+// betterThan ranks blocks by section quality, then by fewer rows, rows nearer
+// the front and seats nearer the middle
+func (b *seatBlock) betterThan(other *seatBlock) bool {
+	if b.quality != other.quality {
+		return b.quality > other.quality
+	}
+	if b.rows != other.rows {
+		return b.rows < other.rows
+	}
+	if b.order != other.order {
+		return b.order < other.order
+	}
+	return b.offCentre < other.offCentre
+}
@@ -0,0 +1,107 @@
+package services
+
+import (
+	"errors"
+	"ticket-zetu-api/modules/events/models/seats"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// eventSeatStatus is the event seat status a reservation in the given status
+// puts its seat in
+func eventSeatStatus(reservationStatus string) string {
+	switch reservationStatus {
+	case "held":
+		return seats.EventSeatHeld
+	case "confirmed":
+		return seats.EventSeatBooked
+	}
+	return seats.EventSeatAvailable
+}
+
+// ensureEventSeat adds the seat's row for the event if it has none yet
+func ensureEventSeat(tx *gorm.DB, eventID, seatID string) error {
+	return tx.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&seats.EventSeat{EventID: eventID, SeatID: seatID, Status: seats.EventSeatAvailable}).Error
+}
+
+// takeEventSeat puts a seat in the given status for an event on behalf of a
+// reservation. It only succeeds while the seat is available for the event or
+// already belongs to the same reservation, so two reservations cannot take
+// the same seat.
+func takeEventSeat(tx *gorm.DB, eventID, seatID, reservationID, status string) error {
+	if err := ensureEventSeat(tx, eventID, seatID); err != nil {
+		return err
+	}
+	result := tx.Model(&seats.EventSeat{}).
+		Where("event_id = ? AND seat_id = ?", eventID, seatID).
+		Where("status = ? OR reservation_id = ?", seats.EventSeatAvailable, reservationID).
+		UpdateColumns(map[string]interface{}{
+			"status":         status,
+			"reservation_id": reservationID,
+			"version":        gorm.Expr("version + 1"),
+			"updated_at":     time.Now(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("seat is already reserved for this event")
+	}
+	return nil
+}
+
+// holdSeat saves a held reservation and takes its seat for the event. The
+// reservation only stands if the seat can be taken, so of several holds
+// placed on a seat at once exactly one succeeds.
+func holdSeat(db *gorm.DB, reservation *seats.SeatReservation) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(reservation).Error; err != nil {
+			return err
+		}
+		return takeEventSeat(tx, reservation.EventID, reservation.SeatID, reservation.ID, seats.EventSeatHeld)
+	})
+}
+
+// releaseEventSeat makes a seat available for an event again, if it is still
+// taken by the reservation
+func releaseEventSeat(tx *gorm.DB, eventID, seatID, reservationID string) error {
+	return tx.Model(&seats.EventSeat{}).
+		Where("event_id = ? AND seat_id = ? AND reservation_id = ?", eventID, seatID, reservationID).
+		UpdateColumns(map[string]interface{}{
+			"status":         seats.EventSeatAvailable,
+			"reservation_id": nil,
+			"version":        gorm.Expr("version + 1"),
+			"updated_at":     time.Now(),
+		}).Error
+}
+
+// eventSeatStatuses looks up the status of seats for an event. Seats without
+// a row for the event are left out and are available.
+func eventSeatStatuses(db *gorm.DB, eventID string, seatIDs []string) (map[string]string, error) {
+	statuses := make(map[string]string, len(seatIDs))
+	for start := 0; start < len(seatIDs); start += seatBatchSize {
+		var rows []seats.EventSeat
+		if err := db.Select("seat_id", "status").
+			Where("event_id = ? AND seat_id IN ?", eventID, seatIDs[start:min(start+seatBatchSize, len(seatIDs))]).
+			Find(&rows).Error; err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			statuses[row.SeatID] = row.Status
+		}
+	}
+	return statuses, nil
+}
+
+// effectiveStatus is a seat's status for an event: the event's own status
+// unless the seat is available there, in which case the seat's status at the
+// venue applies
+func effectiveStatus(seat *seats.Seat, eventStatus string) string {
+	if eventStatus != "" && eventStatus != seats.EventSeatAvailable {
+		return eventStatus
+	}
+	return seat.Status
+}
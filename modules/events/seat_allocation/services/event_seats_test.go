@@ -0,0 +1,76 @@
+package services
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"ticket-zetu-api/internal/testdb"
+	"ticket-zetu-api/modules/events/models/seats"
+
+	"github.com/google/uuid"
+)
+
+// TestSimultaneousHoldsOnOneSeat places many holds on the same seat for the
+// same event at once. Exactly one may win; the rest must be turned away and
+// leave no reservation behind.
+func TestSimultaneousHoldsOnOneSeat(t *testing.T) {
+	db := testdb.Open(t, &seats.SeatReservation{}, &seats.EventSeat{})
+
+	const buyers = 25
+	eventID := uuid.New().String()
+	seatID := uuid.New().String()
+	t.Cleanup(func() {
+		db.Unscoped().Where("event_id = ?", eventID).Delete(&seats.SeatReservation{})
+		db.Where("event_id = ?", eventID).Delete(&seats.EventSeat{})
+	})
+
+	errs := make([]error, buyers)
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	for i := 0; i < buyers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			errs[i] = holdSeat(db, &seats.SeatReservation{
+				UserID:    uuid.New().String(),
+				EventID:   eventID,
+				SeatID:    seatID,
+				Status:    "held",
+				ExpiresAt: time.Now().Add(10 * time.Minute),
+			})
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+
+	won := 0
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			won++
+		case err.Error() != "seat is already reserved for this event":
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+	if won != 1 {
+		t.Errorf("%d holds succeeded, want exactly 1", won)
+	}
+
+	var reservations int64
+	if err := db.Model(&seats.SeatReservation{}).Where("event_id = ? AND seat_id = ?", eventID, seatID).Count(&reservations).Error; err != nil {
+		t.Fatalf("count reservations: %v", err)
+	}
+	if reservations != 1 {
+		t.Errorf("%d reservations saved, want 1", reservations)
+	}
+
+	var eventSeats []seats.EventSeat
+	if err := db.Where("event_id = ? AND seat_id = ?", eventID, seatID).Find(&eventSeats).Error; err != nil {
+		t.Fatalf("load event seat: %v", err)
+	}
+	if len(eventSeats) != 1 || eventSeats[0].Status != seats.EventSeatHeld || eventSeats[0].ReservationID == nil {
+		t.Errorf("event seat rows = %+v, want one held row", eventSeats)
+	}
+}
@@ -0,0 +1,196 @@
+package services
+
+import (
+	"errors"
+	"ticket-zetu-api/modules/events/models/seats"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// LockHeldSeats locks the user's held seat reservations for an event so they
+// can be bought in an order. Each must still be held, unexpired and not
+// already part of another order.
+func LockHeldSeats(tx *gorm.DB, userID, eventID string, reservationIDs []string) ([]seats.SeatReservation, error) {
+	seen := make(map[string]bool, len(reservationIDs))
+	for _, id := range reservationIDs {
+		if _, err := uuid.Parse(id); err != nil {
+			return nil, errors.New("invalid seat reservation ID format")
+		}
+		if seen[id] {
+			return nil, errors.New("duplicate seat reservation")
+		}
+		seen[id] = true
+	}
+
+	var reservations []seats.SeatReservation
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Preload("Seat").
+		Where("id IN ? AND user_id = ? AND event_id = ? AND deleted_at IS NULL", reservationIDs, userID, eventID).
+		Order("created_at ASC").
+		Find(&reservations).Error; err != nil {
+		return nil, err
+	}
+	if len(reservations) != len(reservationIDs) {
+		return nil, errors.New("seat reservation not found")
+	}
+
+	now := time.Now()
+	for _, reservation := range reservations {
+		if reservation.Status != "held" || !reservation.ExpiresAt.After(now) {
+			return nil, errors.New("seat reservation is no longer held")
+		}
+		if reservation.OrderID != nil {
+			return nil, errors.New("seat reservation is already part of an order")
+		}
+	}
+	return reservations, nil
+}
+
+// AttachSeatsToOrder links locked reservations to the order buying them with
+// each seat's price, and keeps them held at least until the order expires
+func AttachSeatsToOrder(tx *gorm.DB, reservations []seats.SeatReservation, orderID string, prices []float64, orderExpiresAt time.Time) error {
+	for i := range reservations {
+		reservation := &reservations[i]
+		expiresAt := reservation.ExpiresAt
+		if orderExpiresAt.After(expiresAt) {
+			expiresAt = orderExpiresAt
+		}
+		if err := tx.Model(&seats.SeatReservation{}).
+			Where("id = ?", reservation.ID).
+			UpdateColumns(map[string]interface{}{
+				"order_id":   orderID,
+				"price":      prices[i],
+				"expires_at": expiresAt,
+				"version":    gorm.Expr("version + 1"),
+				"updated_at": time.Now(),
+			}).Error; err != nil {
+			return err
+		}
+		reservation.OrderID = &orderID
+		reservation.Price = prices[i]
+		reservation.ExpiresAt = expiresAt
+	}
+	return nil
+}
+
+// BookOrderSeats confirms the seat reservations of a paid order and books
+// their seats for the event. Seats released while the payment was in flight
+// are booked again if nobody else has taken them.
+func BookOrderSeats(tx *gorm.DB, orderID string) ([]seats.SeatReservation, error) {
+	var reservations []seats.SeatReservation
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Preload("Seat").
+		Where("order_id = ? AND deleted_at IS NULL", orderID).
+		Order("created_at ASC").
+		Find(&reservations).Error; err != nil {
+		return nil, err
+	}
+
+	for i := range reservations {
+		reservation := &reservations[i]
+		if reservation.Status == "confirmed" {
+			continue
+		}
+		if err := takeEventSeat(tx, reservation.EventID, reservation.SeatID, reservation.ID, seats.EventSeatBooked); err != nil {
+			return nil, errors.New("seat is no longer available")
+		}
+		if err := tx.Model(&seats.SeatReservation{}).
+			Where("id = ?", reservation.ID).
+			UpdateColumns(map[string]interface{}{
+				"status":     "confirmed",
+				"version":    gorm.Expr("version + 1"),
+				"updated_at": time.Now(),
+			}).Error; err != nil {
+			return nil, err
+		}
+		reservation.Status = "confirmed"
+	}
+	return reservations, nil
+}
+
+// ReleaseOrderSeats releases the held seat reservations of an order that will
+// not be paid, freeing their seats for the event
+func ReleaseOrderSeats(tx *gorm.DB, orderID string) error {
+	var reservations []seats.SeatReservation
+	if err := tx.Where("order_id = ? AND status = ? AND deleted_at IS NULL", orderID, "held").
+		Find(&reservations).Error; err != nil {
+		return err
+	}
+
+	return releaseReservations(tx, reservations)
+}
+
+// ReleaseTicketSeats releases the confirmed seat reservations of an order's
+// tickets that were refunded or cancelled, freeing their seats for the event
+func ReleaseTicketSeats(tx *gorm.DB, orderID string, seatIDs []string) error {
+	if len(seatIDs) == 0 {
+		return nil
+	}
+	var reservations []seats.SeatReservation
+	if err := tx.Where("order_id = ? AND seat_id IN ? AND status = ? AND deleted_at IS NULL", orderID, seatIDs, "confirmed").
+		Find(&reservations).Error; err != nil {
+		return err
+	}
+	return releaseReservations(tx, reservations)
+}
+
+// ReleaseEventSeats releases up to limit held or confirmed seat reservations
+// of a cancelled event and frees their seats. It returns how many it
+// released.
+func ReleaseEventSeats(tx *gorm.DB, eventID string, limit int) (int, error) {
+	var reservations []seats.SeatReservation
+	if err := tx.Where("event_id = ? AND status IN ? AND deleted_at IS NULL", eventID, []string{"held", "confirmed"}).
+		Order("created_at ASC").
+		Limit(limit).
+		Find(&reservations).Error; err != nil {
+		return 0, err
+	}
+
+	return len(reservations), releaseReservations(tx, reservations)
+}
+
+// releaseReservations marks seat reservations released and frees their seats
+// for the event
+func releaseReservations(tx *gorm.DB, reservations []seats.SeatReservation) error {
+	for _, reservation := range reservations {
+		if err := tx.Model(&seats.SeatReservation{}).
+			Where("id = ?", reservation.ID).
+			UpdateColumns(map[string]interface{}{
+				"status":     "released",
+				"version":    gorm.Expr("version + 1"),
+				"updated_at": time.Now(),
+			}).Error; err != nil {
+			return err
+		}
+		if err := releaseEventSeat(tx, reservation.EventID, reservation.SeatID, reservation.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MoveBookedSeat moves a booked seat reservation to another seat at the same
+// venue, taking the new seat for the event and freeing the old one
+func MoveBookedSeat(tx *gorm.DB, reservation *seats.SeatReservation, seat *seats.Seat) error {
+	if err := takeEventSeat(tx, reservation.EventID, seat.ID, reservation.ID, seats.EventSeatBooked); err != nil {
+		return err
+	}
+	if err := releaseEventSeat(tx, reservation.EventID, reservation.SeatID, reservation.ID); err != nil {
+		return err
+	}
+	if err := tx.Model(&seats.SeatReservation{}).
+		Where("id = ?", reservation.ID).
+		UpdateColumns(map[string]interface{}{
+			"seat_id":    seat.ID,
+			"version":    gorm.Expr("version + 1"),
+			"updated_at": time.Now(),
+		}).Error; err != nil {
+		return err
+	}
+	reservation.SeatID = seat.ID
+	reservation.Seat = *seat
+	return nil
+}
@@ -0,0 +1,422 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+	"ticket-zetu-api/modules/events/models/events"
+	"ticket-zetu-api/modules/events/models/seats"
+	"ticket-zetu-api/modules/events/seat_allocation/dto"
+	organizers_services "ticket-zetu-api/modules/organizers/services"
+	"ticket-zetu-api/modules/tickets/models/tickets"
+	"ticket-zetu-api/modules/users/authorization/service"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// seatBatchSize is how many generated seats are inserted per statement
+const seatBatchSize = 500
+
+type SeatMapService interface {
+	SaveLayout(userID, venueID string, layout events.SeatLayout) (*dto.SeatLayoutSyncDTO, error)
+	GetSeatMap(venueID, eventID string) (*dto.SeatMapDTO, error)
+}
+
+type seatMapService struct {
+	db          *gorm.DB
+	authService authorization_service.PermissionService
+}
+
+func NewSeatMapService(db *gorm.DB, authService authorization_service.PermissionService) SeatMapService {
+	return &seatMapService{
+		db:          db,
+		authService: authService,
+	}
+}
+
+// SaveLayout replaces one of the organizer's venue layouts with a structured
+// one and brings the venue's seats in line with it
+func (s *seatMapService) SaveLayout(userID, venueID string, layout events.SeatLayout) (*dto.SeatLayoutSyncDTO, error) {
+	hasPerm, err := s.authService.HasPermission(userID, "update:venues")
+	if err != nil {
+		return nil, err
+	}
+	if !hasPerm {
+		return nil, errors.New("user lacks update:venues permission")
+	}
+
+	if _, err := uuid.Parse(venueID); err != nil {
+		return nil, errors.New("invalid venue ID format")
+	}
+	if err := layout.Validate(); err != nil {
+		return nil, err
+	}
+	raw, err := json.Marshal(layout)
+	if err != nil {
+		return nil, err
+	}
+
+	organizer, err := organizers_services.GetUserOrganizer(s.db, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var result *dto.SeatLayoutSyncDTO
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		var venue events.Venue
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND organizer_id = ? AND deleted_at IS NULL", venueID, organizer.ID).
+			First(&venue).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("venue not found")
+			}
+			return err
+		}
+
+		venue.Layout = string(raw)
+		if err := tx.Model(&events.Venue{}).Where("id = ?", venue.ID).
+			UpdateColumns(map[string]interface{}{
+				"layout":     venue.Layout,
+				"version":    gorm.Expr("version + 1"),
+				"updated_at": time.Now(),
+			}).Error; err != nil {
+			return err
+		}
+
+		result, err = SyncVenueSeats(tx, &venue)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// SyncVenueSeats brings a venue's seats in line with its structured layout,
+// inside the caller's transaction. Layout seats that already exist are moved
+// to their new place, new ones are created, and generated seats the layout
+// no longer has are removed. Seats made by hand with the same section and
+// number as a layout seat are taken over by the layout. A venue with a
+// free-form layout is left alone.
+func SyncVenueSeats(tx *gorm.DB, venue *events.Venue) (*dto.SeatLayoutSyncDTO, error) {
+	result := &dto.SeatLayoutSyncDTO{VenueID: venue.ID}
+	layout, err := events.ParseLayout(venue.Layout)
+	if err != nil || layout == nil {
+		return result, err
+	}
+	planned := layout.Expand()
+	if err := checkPriceTiers(tx, planned); err != nil {
+		return nil, err
+	}
+
+	var existing []seats.Seat
+	if err := tx.Where("venue_id = ? AND deleted_at IS NULL", venue.ID).Find(&existing).Error; err != nil {
+		return nil, err
+	}
+	byKey := make(map[string]*seats.Seat, len(existing))
+	byNumber := make(map[string]*seats.Seat, len(existing))
+	for i := range existing {
+		seat := &existing[i]
+		if seat.LayoutKey != "" {
+			byKey[seat.LayoutKey] = seat
+		} else {
+			byNumber[seat.SeatSection+"/"+seat.SeatNumber] = seat
+		}
+	}
+
+	now := time.Now()
+	kept := make(map[string]bool, len(planned))
+	var created []seats.Seat
+	for _, plan := range planned {
+		seat, ok := byKey[plan.Key]
+		if !ok {
+			seat, ok = byNumber[plan.Section+"/"+plan.SeatNumber]
+		}
+		if !ok {
+			created = append(created, seats.Seat{
+				ID:           uuid.New().String(),
+				VenueID:      venue.ID,
+				SeatNumber:   plan.SeatNumber,
+				SeatSection:  plan.Section,
+				Status:       "available",
+				PriceTierID:  plan.PriceTierID,
+				LayoutKey:    plan.Key,
+				SeatRow:      plan.Row,
+				Position:     plan.Position,
+				X:            plan.X,
+				Y:            plan.Y,
+				IsAccessible: plan.IsAccessible,
+				AisleAfter:   plan.AisleAfter,
+				Quality:      plan.Quality,
+			})
+			continue
+		}
+
+		kept[seat.ID] = true
+		changes := placementChanges(seat, &plan)
+		if len(changes) == 0 {
+			continue
+		}
+		changes["updated_at"] = now
+		changes["version"] = gorm.Expr("version + 1")
+		if err := tx.Model(&seats.Seat{}).Where("id = ?", seat.ID).UpdateColumns(changes).Error; err != nil {
+			return nil, err
+		}
+		result.Updated++
+	}
+
+	var removed []string
+	for _, seat := range byKey {
+		if !kept[seat.ID] {
+			removed = append(removed, seat.ID)
+		}
+	}
+	if len(removed) > 0 {
+		inUse, err := seatsInUse(tx, removed)
+		if err != nil {
+			return nil, err
+		}
+		if inUse {
+			return nil, errors.New("layout removes seats that are held or booked")
+		}
+		for start := 0; start < len(removed); start += seatBatchSize {
+			end := min(start+seatBatchSize, len(removed))
+			if err := tx.Model(&seats.Seat{}).Where("id IN ?", removed[start:end]).
+				UpdateColumns(map[string]interface{}{
+					"deleted_at": now,
+					"updated_at": now,
+				}).Error; err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if len(created) > 0 {
+		if err := tx.CreateInBatches(&created, seatBatchSize).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	result.Created = len(created)
+	result.Removed = len(removed)
+	result.Total = len(planned)
+	return result, nil
+}
+
+// GetSeatMap lists a venue's seats section by section and row by row, with
+// what a frontend needs to draw them. Sections follow the layout's order,
+// then any seats made by hand in sections the layout does not have. Given an
+// event at the venue, seats carry their status for that event.
+func (s *seatMapService) GetSeatMap(venueID, eventID string) (*dto.SeatMapDTO, error) {
+	if _, err := uuid.Parse(venueID); err != nil {
+		return nil, errors.New("invalid venue ID format")
+	}
+	if eventID != "" {
+		if _, err := uuid.Parse(eventID); err != nil {
+			return nil, errors.New("invalid event ID format")
+		}
+	}
+
+	var venue events.Venue
+	if err := s.db.Where("id = ? AND deleted_at IS NULL", venueID).First(&venue).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("venue not found")
+		}
+		return nil, err
+	}
+
+	var venueSeats []seats.Seat
+	if err := s.db.Where("venue_id = ? AND deleted_at IS NULL", venue.ID).
+		Order("seat_section ASC, seat_row ASC, position ASC, seat_number ASC").
+		Find(&venueSeats).Error; err != nil {
+		return nil, err
+	}
+
+	var statuses map[string]string
+	if eventID != "" {
+		var event events.Event
+		if err := s.db.Where("id = ? AND venue_id = ? AND deleted_at IS NULL", eventID, venue.ID).First(&event).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, errors.New("event not found")
+			}
+			return nil, err
+		}
+		seatIDs := make([]string, len(venueSeats))
+		for i := range venueSeats {
+			seatIDs[i] = venueSeats[i].ID
+		}
+		var err error
+		if statuses, err = eventSeatStatuses(s.db, event.ID, seatIDs); err != nil {
+			return nil, err
+		}
+	}
+
+	seatMap := &dto.SeatMapDTO{
+		VenueID:    venue.ID,
+		EventID:    eventID,
+		VenueName:  venue.Name,
+		TotalSeats: len(venueSeats),
+		Sections:   []dto.SeatMapSectionDTO{},
+	}
+	// A free-form layout still leaves the hand-made seats to show
+	layout, _ := events.ParseLayout(venue.Layout)
+
+	sectionOrder := map[string]int{}
+	rowOrder := map[string]int{}
+	if layout != nil {
+		seatMap.Width = layout.Width
+		seatMap.Height = layout.Height
+		seatMap.Stage = layout.Stage
+		for i, section := range layout.Sections {
+			sectionOrder[section.Name] = i
+			for j, row := range section.Rows {
+				rowOrder[section.Name+"/"+row.Label] = j
+			}
+		}
+	}
+
+	sort.SliceStable(venueSeats, func(i, j int) bool {
+		a, b := &venueSeats[i], &venueSeats[j]
+		if a.SeatSection != b.SeatSection {
+			return order(sectionOrder, a.SeatSection, b.SeatSection)
+		}
+		if a.SeatRow != b.SeatRow {
+			return order(rowOrder, a.SeatSection+"/"+a.SeatRow, b.SeatSection+"/"+b.SeatRow)
+		}
+		return false
+	})
+
+	for i := range venueSeats {
+		seat := &venueSeats[i]
+		sections := seatMap.Sections
+		if len(sections) == 0 || sections[len(sections)-1].Name != seat.SeatSection {
+			seatMap.Sections = append(seatMap.Sections, dto.SeatMapSectionDTO{Name: seat.SeatSection, Quality: seat.Quality})
+		}
+		section := &seatMap.Sections[len(seatMap.Sections)-1]
+		if len(section.Rows) == 0 || section.Rows[len(section.Rows)-1].Label != seat.SeatRow {
+			section.Rows = append(section.Rows, dto.SeatMapRowDTO{Label: seat.SeatRow})
+		}
+		row := &section.Rows[len(section.Rows)-1]
+		row.Seats = append(row.Seats, dto.SeatMapSeatDTO{
+			ID:           seat.ID,
+			SeatNumber:   seat.SeatNumber,
+			Number:       strings.TrimPrefix(seat.SeatNumber, seat.SeatRow),
+			X:            seat.X,
+			Y:            seat.Y,
+			IsAccessible: seat.IsAccessible,
+			AisleAfter:   seat.AisleAfter,
+			Status:       effectiveStatus(seat, statuses[seat.ID]),
+			PriceTierID:  seat.PriceTierID,
+		})
+	}
+	return seatMap, nil
+}
+
+// order sorts names the layout knows by their place in it, ahead of any it
+// does not, which sort by name
+func order(positions map[string]int, a, b string) bool {
+	i, aKnown := positions[a]
+	j, bKnown := positions[b]
+	switch {
+	case aKnown && bKnown:
+		return i < j
+	case aKnown != bKnown:
+		return aKnown
+	}
+	return a < b
+}
+
+// placementChanges lists the columns of a seat that differ from its place in
+// the layout
+func placementChanges(seat *seats.Seat, plan *events.PlannedSeat) map[string]interface{} {
+	changes := map[string]interface{}{}
+	if seat.LayoutKey != plan.Key {
+		changes["layout_key"] = plan.Key
+	}
+	if seat.SeatNumber != plan.SeatNumber {
+		changes["seat_number"] = plan.SeatNumber
+	}
+	if seat.SeatSection != plan.Section {
+		changes["seat_section"] = plan.Section
+	}
+	if seat.SeatRow != plan.Row {
+		changes["seat_row"] = plan.Row
+	}
+	if seat.Position != plan.Position {
+		changes["position"] = plan.Position
+	}
+	if seat.X != plan.X {
+		changes["x"] = plan.X
+	}
+	if seat.Y != plan.Y {
+		changes["y"] = plan.Y
+	}
+	if seat.IsAccessible != plan.IsAccessible {
+		changes["is_accessible"] = plan.IsAccessible
+	}
+	if seat.AisleAfter != plan.AisleAfter {
+		changes["aisle_after"] = plan.AisleAfter
+	}
+	if seat.Quality != plan.Quality {
+		changes["quality"] = plan.Quality
+	}
+	// A price tier set on the seat by hand is kept unless the layout names one
+	if plan.PriceTierID != "" && seat.PriceTierID != plan.PriceTierID {
+		changes["price_tier_id"] = plan.PriceTierID
+	}
+	return changes
+}
+
+// checkPriceTiers makes sure every price tier a layout names exists
+func checkPriceTiers(tx *gorm.DB, planned []events.PlannedSeat) error {
+	ids := map[string]bool{}
+	for _, plan := range planned {
+		if plan.PriceTierID != "" {
+			ids[plan.PriceTierID] = true
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	tierIDs := make([]string, 0, len(ids))
+	for id := range ids {
+		tierIDs = append(tierIDs, id)
+	}
+
+	var count int64
+	if err := tx.Model(&tickets.PriceTier{}).Where("id IN ? AND deleted_at IS NULL", tierIDs).Count(&count).Error; err != nil {
+		return err
+	}
+	if int(count) != len(tierIDs) {
+		return errors.New("price tier not found")
+	}
+	return nil
+}
+
+// seatsInUse reports whether any of the seats is held or booked, at the venue
+// or for any event
+func seatsInUse(tx *gorm.DB, seatIDs []string) (bool, error) {
+	for start := 0; start < len(seatIDs); start += seatBatchSize {
+		batch := seatIDs[start:min(start+seatBatchSize, len(seatIDs))]
+		var count int64
+		if err := tx.Model(&seats.Seat{}).Where("id IN ? AND status <> ?", batch, "available").Count(&count).Error; err != nil {
+			return false, err
+		}
+		if count > 0 {
+			return true, nil
+		}
+		if err := tx.Model(&seats.EventSeat{}).
+			Where("seat_id IN ? AND status <> ?", batch, seats.EventSeatAvailable).
+			Count(&count).Error; err != nil {
+			return false, err
+		}
+		if count > 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -16,9 +16,11 @@ import (
 type SeatReservationService interface {
 	GetSeatReservations(userID string, filter dto.SeatReservationFilterDTO) ([]dto.GetSeatReservationDTO, error)
 	CreateSeatReservation(userID string, input dto.CreateSeatReservationDTO) (*dto.GetSeatReservationDTO, error)
+	ReserveBestAvailable(userID string, input dto.BestAvailableSeatsDTO) ([]dto.GetSeatReservationDTO, error)
 	UpdateSeatReservation(userID string, input dto.UpdateSeatReservationDTO) (*dto.GetSeatReservationDTO, error)
 	DeleteSeatReservation(userID, id string) error
 	ToggleSeatReservationStatus(userID string, input dto.ToggleSeatReservationStatusDTO) error
+	SweepExpiredReservations(limit int) (int, error)
 }
 
 type seatReservationService struct {
@@ -65,6 +67,7 @@ func (s *seatReservationService) toDTO(reservation *seats.SeatReservation) *dto.
 		UserName:  userName,
 		EventName: eventName,
 		SeatInfo:  seatInfo,
+		OrderID:   reservation.OrderID,
 	}
 }
 
@@ -200,15 +203,13 @@ func (s *seatReservationService) CreateSeatReservation(userID string, input dto.
 		}
 		return nil, err
 	}
+	if seat.VenueID != event.VenueID {
+		return nil, errors.New("seat is not at the event's venue")
+	}
 	if seat.Status != "available" {
 		return nil, errors.New("seat is not available for reservation")
 	}
 
-	var existingReservation seats.SeatReservation
-	if err := s.db.Where("seat_id = ? AND event_id = ? AND status IN ('held', 'confirmed') AND deleted_at IS NULL", input.SeatID, input.EventID).First(&existingReservation).Error; err == nil {
-		return nil, errors.New("seat is already reserved for this event")
-	}
-
 	reservation := seats.SeatReservation{
 		UserID:    input.UserID,
 		EventID:   input.EventID,
@@ -217,12 +218,7 @@ func (s *seatReservationService) CreateSeatReservation(userID string, input dto.
 		ExpiresAt: expiresAt,
 	}
 
-	if err := s.db.Create(&reservation).Error; err != nil {
-		return nil, err
-	}
-
-	// Update seat status to held
-	if err := s.db.Model(&seat).Update("status", "held").Error; err != nil {
+	if err := holdSeat(s.db, &reservation); err != nil {
 		return nil, err
 	}
 
@@ -282,35 +278,33 @@ func (s *seatReservationService) UpdateSeatReservation(userID string, input dto.
 		return nil, err
 	}
 
-	if input.SeatID != reservation.SeatID || input.EventID != reservation.EventID {
-		var existingReservation seats.SeatReservation
-		if err := s.db.Where("seat_id = ? AND event_id = ? AND status IN ('held', 'confirmed') AND id != ? AND deleted_at IS NULL", input.SeatID, input.EventID, input.ID).First(&existingReservation).Error; err == nil {
-			return nil, errors.New("seat is already reserved for this event")
-		}
+	if seat.VenueID != event.VenueID {
+		return nil, errors.New("seat is not at the event's venue")
 	}
-
-	// Update old seat status if seat_id changes
-	if input.SeatID != reservation.SeatID {
-		var oldSeat seats.Seat
-		if err := s.db.Where("id = ?", reservation.SeatID).First(&oldSeat).Error; err == nil {
-			if err := s.db.Model(&oldSeat).Update("status", "available").Error; err != nil {
-				return nil, err
-			}
-		}
+	if input.SeatID != reservation.SeatID && seat.Status != "available" {
+		return nil, errors.New("seat is not available for reservation")
 	}
 
+	oldEventID, oldSeatID := reservation.EventID, reservation.SeatID
 	reservation.UserID = input.UserID
 	reservation.EventID = input.EventID
 	reservation.SeatID = input.SeatID
 	reservation.Status = input.Status
 	reservation.ExpiresAt = expiresAt
 
-	if err := s.db.Save(&reservation).Error; err != nil {
-		return nil, err
-	}
-
-	// Update new seat status
-	if err := s.db.Model(&seat).Update("status", input.Status).Error; err != nil {
+	if err := s.db.Transaction(func(tx *gorm.DB) error {
+		if oldEventID != reservation.EventID || oldSeatID != reservation.SeatID || reservation.Status == "released" {
+			if err := releaseEventSeat(tx, oldEventID, oldSeatID, reservation.ID); err != nil {
+				return err
+			}
+		}
+		if reservation.Status != "released" {
+			if err := takeEventSeat(tx, reservation.EventID, reservation.SeatID, reservation.ID, eventSeatStatus(reservation.Status)); err != nil {
+				return err
+			}
+		}
+		return tx.Save(&reservation).Error
+	}); err != nil {
 		return nil, err
 	}
 
@@ -345,20 +339,16 @@ func (s *seatReservationService) DeleteSeatReservation(userID, id string) error
 	if reservation.Status == "confirmed" {
 		return errors.New("cannot delete a confirmed reservation")
 	}
+	if reservation.OrderID != nil {
+		return errors.New("cannot delete a reservation that is part of an order")
+	}
 
-	// Update seat status to available
-	var seat seats.Seat
-	if err := s.db.Where("id = ?", reservation.SeatID).First(&seat).Error; err == nil {
-		if err := s.db.Model(&seat).Update("status", "available").Error; err != nil {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := releaseEventSeat(tx, reservation.EventID, reservation.SeatID, reservation.ID); err != nil {
 			return err
 		}
-	}
-
-	if err := s.db.Model(&reservation).Update("deleted_at", time.Now()).Error; err != nil {
-		return err
-	}
-
-	return nil
+		return tx.Model(&reservation).Update("deleted_at", time.Now()).Error
+	})
 }
 
 func (s *seatReservationService) ToggleSeatReservationStatus(userID string, input dto.ToggleSeatReservationStatusDTO) error {
@@ -386,20 +376,59 @@ func (s *seatReservationService) ToggleSeatReservationStatus(userID string, inpu
 		return errors.New("reservation status already set")
 	}
 
-	var seat seats.Seat
-	if err := s.db.Where("id = ?", reservation.SeatID).First(&seat).Error; err != nil {
-		return errors.New("seat not found")
-	}
-
 	reservation.Status = input.Status
-	if err := s.db.Save(&reservation).Error; err != nil {
-		return err
-	}
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if reservation.Status == "released" {
+			if err := releaseEventSeat(tx, reservation.EventID, reservation.SeatID, reservation.ID); err != nil {
+				return err
+			}
+		} else if err := takeEventSeat(tx, reservation.EventID, reservation.SeatID, reservation.ID, eventSeatStatus(reservation.Status)); err != nil {
+			return err
+		}
+		return tx.Save(&reservation).Error
+	})
+}
 
-	// Update seat status to match reservation status
-	if err := s.db.Model(&seat).Update("status", input.Status).Error; err != nil {
-		return err
+// SweepExpiredReservations releases held reservations whose expiry has passed
+// and frees their seats for the event. It returns how many were released.
+func (s *seatReservationService) SweepExpiredReservations(limit int) (int, error) {
+	var expired []seats.SeatReservation
+	if err := s.db.Where("status = ? AND expires_at <= ? AND deleted_at IS NULL", "held", time.Now()).
+		Order("expires_at ASC").
+		Limit(limit).
+		Find(&expired).Error; err != nil {
+		return 0, err
+	}
+
+	released := 0
+	for i := range expired {
+		reservation := expired[i]
+		freed := false
+		err := s.db.Transaction(func(tx *gorm.DB) error {
+			// The reservation may have been confirmed or extended since it was read
+			result := tx.Model(&seats.SeatReservation{}).
+				Where("id = ? AND status = ? AND expires_at <= ?", reservation.ID, "held", time.Now()).
+				UpdateColumns(map[string]interface{}{
+					"status":     "released",
+					"version":    gorm.Expr("version + 1"),
+					"updated_at": time.Now(),
+				})
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return nil
+			}
+			freed = true
+			return releaseEventSeat(tx, reservation.EventID, reservation.SeatID, reservation.ID)
+		})
+		if err != nil {
+			return released, err
+		}
+		if freed {
+			released++
+		}
 	}
 
-	return nil
+	return released, nil
 }
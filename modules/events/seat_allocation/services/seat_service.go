@@ -11,6 +11,7 @@ import (
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type SeatService interface {
@@ -85,6 +86,12 @@ func (s *seatService) GetSeats(userID string, filter dto.SeatFilterDTO) ([]dto.G
 		}
 	}
 
+	if filter.EventID != "" {
+		if _, err := uuid.Parse(filter.EventID); err != nil {
+			return nil, errors.New("invalid event ID format")
+		}
+	}
+
 	// Validate pagination parameters
 	if filter.Page <= 0 {
 		filter.Page = 1
@@ -118,7 +125,25 @@ func (s *seatService) GetSeats(userID string, filter dto.SeatFilterDTO) ([]dto.G
 		query = query.Where("seat_section = ?", filter.SeatSection)
 	}
 
-	if filter.Status != "" {
+	if filter.EventID != "" {
+		var event events.Event
+		if err := s.db.Where("id = ? AND deleted_at IS NULL", filter.EventID).First(&event).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, errors.New("event not found")
+			}
+			return nil, err
+		}
+		query = query.Where("venue_id = ?", event.VenueID)
+
+		// A seat's status for the event is its event seat status, unless the
+		// seat is available there and the venue-wide status applies
+		if filter.Status != "" {
+			query = query.Where(
+				"(id IN (SELECT seat_id FROM event_seats WHERE event_id = ? AND status = ? AND status <> ?) OR (status = ? AND id NOT IN (SELECT seat_id FROM event_seats WHERE event_id = ? AND status <> ?)))",
+				filter.EventID, filter.Status, seats.EventSeatAvailable, filter.Status, filter.EventID, seats.EventSeatAvailable,
+			)
+		}
+	} else if filter.Status != "" {
 		query = query.Where("status = ?", filter.Status)
 	}
 
@@ -144,9 +169,25 @@ func (s *seatService) GetSeats(userID string, filter dto.SeatFilterDTO) ([]dto.G
 		return []dto.GetSeatDTO{}, nil
 	}
 
+	var statuses map[string]string
+	if filter.EventID != "" {
+		seatIDs := make([]string, len(seats))
+		for i, seat := range seats {
+			seatIDs[i] = seat.ID
+		}
+		if statuses, err = eventSeatStatuses(s.db, filter.EventID, seatIDs); err != nil {
+			return nil, err
+		}
+	}
+
 	var seatsDTO []dto.GetSeatDTO
 	for _, seat := range seats {
-		seatsDTO = append(seatsDTO, *s.toDTO(&seat))
+		seatDTO := s.toDTO(&seat)
+		if filter.EventID != "" {
+			seatDTO.Status = effectiveStatus(&seat, statuses[seat.ID])
+			seatDTO.EventID = filter.EventID
+		}
+		seatsDTO = append(seatsDTO, *seatDTO)
 	}
 	return seatsDTO, nil
 }
@@ -189,7 +230,7 @@ func (s *seatService) CreateSeat(userID string, input dto.CreateSeatDTO) (*dto.G
 	}
 
 	var existingSeat seats.Seat
-	if err := s.db.Where("venue_id = ? AND seat_number = ? AND deleted_at IS NULL", input.VenueID, input.SeatNumber).First(&existingSeat).Error; err == nil {
+	if err := s.db.Where("venue_id = ? AND seat_section = ? AND seat_number = ? AND deleted_at IS NULL", input.VenueID, input.SeatSection, input.SeatNumber).First(&existingSeat).Error; err == nil {
 		return nil, errors.New("seat number already exists in this venue")
 	}
 
@@ -252,7 +293,7 @@ func (s *seatService) UpdateSeat(userID string, input dto.UpdateSeatDTO) (*dto.G
 	}
 
 	var existingSeat seats.Seat
-	if err := s.db.Where("venue_id = ? AND seat_number = ? AND id != ? AND deleted_at IS NULL", input.VenueID, input.SeatNumber, input.ID).First(&existingSeat).Error; err == nil {
+	if err := s.db.Where("venue_id = ? AND seat_section = ? AND seat_number = ? AND id != ? AND deleted_at IS NULL", input.VenueID, input.SeatSection, input.SeatNumber, input.ID).First(&existingSeat).Error; err == nil {
 		return nil, errors.New("seat number already exists in this venue")
 	}
 
@@ -304,6 +345,16 @@ func (s *seatService) DeleteSeat(userID, id string) error {
 		return errors.New("cannot delete a seat that is held or booked")
 	}
 
+	var taken int64
+	if err := s.db.Model(&seats.EventSeat{}).
+		Where("seat_id = ? AND status <> ?", seat.ID, seats.EventSeatAvailable).
+		Count(&taken).Error; err != nil {
+		return err
+	}
+	if taken > 0 {
+		return errors.New("cannot delete a seat that is held or booked")
+	}
+
 	if err := s.db.Model(&seat).Update("deleted_at", time.Now()).Error; err != nil {
 		return err
 	}
@@ -332,6 +383,10 @@ func (s *seatService) ToggleSeatStatus(userID string, input dto.ToggleSeatStatus
 		return err
 	}
 
+	if input.EventID != "" {
+		return s.toggleEventSeatStatus(&seat, input)
+	}
+
 	if seat.Status == input.Status {
 		return errors.New("seat status already set")
 	}
@@ -343,3 +398,47 @@ func (s *seatService) ToggleSeatStatus(userID string, input dto.ToggleSeatStatus
 
 	return nil
 }
+
+// toggleEventSeatStatus sets a seat's status for a single event. Seats held or
+// booked by a reservation are left to the reservation.
+func (s *seatService) toggleEventSeatStatus(seat *seats.Seat, input dto.ToggleSeatStatusDTO) error {
+	if _, err := uuid.Parse(input.EventID); err != nil {
+		return errors.New("invalid event ID format")
+	}
+
+	var event events.Event
+	if err := s.db.Where("id = ? AND deleted_at IS NULL", input.EventID).First(&event).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("event not found")
+		}
+		return err
+	}
+	if event.VenueID != seat.VenueID {
+		return errors.New("seat is not at the event's venue")
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := ensureEventSeat(tx, event.ID, seat.ID); err != nil {
+			return err
+		}
+
+		var eventSeat seats.EventSeat
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("event_id = ? AND seat_id = ?", event.ID, seat.ID).
+			First(&eventSeat).Error; err != nil {
+			return err
+		}
+		if eventSeat.ReservationID != nil {
+			return errors.New("seat is already reserved for this event")
+		}
+		if eventSeat.Status == input.Status {
+			return errors.New("seat status already set")
+		}
+
+		return tx.Model(&eventSeat).UpdateColumns(map[string]interface{}{
+			"status":     input.Status,
+			"version":    gorm.Expr("version + 1"),
+			"updated_at": time.Now(),
+		}).Error
+	})
+}
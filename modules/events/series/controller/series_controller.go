@@ -0,0 +1,184 @@
+package series_controller
+
+import (
+	"ticket-zetu-api/logs/handler"
+	"ticket-zetu-api/modules/events/series/dto"
+	"ticket-zetu-api/modules/events/series/service"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+type SeriesController struct {
+	service    service.SeriesService
+	logHandler *handler.LogHandler
+	validator  *validator.Validate
+}
+
+func NewSeriesController(service service.SeriesService, logHandler *handler.LogHandler) *SeriesController {
+	return &SeriesController{
+		service:    service,
+		logHandler: logHandler,
+		validator:  validator.New(),
+	}
+}
+
+func (c *SeriesController) handleError(ctx *fiber.Ctx, err error) error {
+	switch err.Error() {
+	case "user lacks update:events permission", "user lacks delete:events permission":
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusForbidden, err.Error()), fiber.StatusForbidden)
+	case "organizer is not active", "organizer is banned", "organizer is flagged for review":
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusForbidden, err.Error()), fiber.StatusForbidden)
+	case "organizer not found", "series not found", "occurrence not found":
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusNotFound, err.Error()), fiber.StatusNotFound)
+	case "subcategory not found", "venue not found":
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, err.Error()), fiber.StatusBadRequest)
+	case "invalid series ID format", "invalid event ID format", "invalid subcategory ID format", "invalid venue ID format",
+		"end time must be after start time", "occurrences must last at least a minute", "invalid timezone",
+		"invalid recurrence frequency", "interval must be at least 1", "count must be at least 1",
+		"recurrence must end with until or count", "until must be after start time",
+		"invalid weekday in by_day", "monthly by_day needs an ordinal such as 2TU or -1FR",
+		"by_month_day only applies to monthly recurrence", "invalid day in by_month_day",
+		"use either by_day or by_month_day for monthly recurrence", "too many occurrences in series",
+		"series has no occurrences", "override does not match an occurrence",
+		"max_tickets_per_user cannot be less than min_tickets_per_user",
+		"occurrence_id is required for this scope", "recurrence can only change for future or all occurrences":
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, err.Error()), fiber.StatusBadRequest)
+	case "occurrence has already ended", "occurrence has been cancelled", "cannot remove an occurrence that has orders":
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusConflict, err.Error()), fiber.StatusConflict)
+	default:
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusInternalServerError, err.Error()), fiber.StatusInternalServerError)
+	}
+}
+
+// CreateSeries godoc
+// @Summary Create a recurring event series
+// @Description Creates an event series from a recurrence rule (weekly or monthly, with exceptions and one-off overrides) and generates every occurrence as an event with its own ticket types and stock.
+// @Tags Event Series Group
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param input body dto.CreateSeriesInput true "Series details, recurrence rule and ticket types"
+// @Success 200 {object} map[string]interface{} "Event series created successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid request payload or recurrence rule"
+// @Failure 403 {object} map[string]interface{} "Organizer is inactive, flagged, or banned"
+// @Failure 404 {object} map[string]interface{} "Organizer not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /event-series [post]
+func (c *SeriesController) CreateSeries(ctx *fiber.Ctx) error {
+	userID := ctx.Locals("user_id").(string)
+
+	var input dto.CreateSeriesInput
+	if err := ctx.BodyParser(&input); err != nil {
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, "Invalid request body"), fiber.StatusBadRequest)
+	}
+	if err := c.validator.Struct(input); err != nil {
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, err.Error()), fiber.StatusBadRequest)
+	}
+
+	series, err := c.service.CreateSeries(userID, input)
+	if err != nil {
+		return c.handleError(ctx, err)
+	}
+	return c.logHandler.LogSuccess(ctx, series, "Event series created successfully", true)
+}
+
+// GetAllSeries godoc
+// @Summary List event series
+// @Description Retrieves the organizer's event series with their occurrences.
+// @Tags Event Series Group
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} map[string]interface{} "Event series retrieved successfully"
+// @Failure 404 {object} map[string]interface{} "Organizer not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /event-series [get]
+func (c *SeriesController) GetAllSeries(ctx *fiber.Ctx) error {
+	userID := ctx.Locals("user_id").(string)
+
+	series, err := c.service.GetAllSeries(userID)
+	if err != nil {
+		return c.handleError(ctx, err)
+	}
+	return c.logHandler.LogSuccess(ctx, series, "Event series retrieved successfully", true)
+}
+
+// GetSeries godoc
+// @Summary Get an event series
+// @Description Retrieves an event series with its recurrence rule, exceptions, ticket types and occurrences.
+// @Tags Event Series Group
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Series ID" Format(uuid)
+// @Success 200 {object} map[string]interface{} "Event series retrieved successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid series ID format"
+// @Failure 404 {object} map[string]interface{} "Organizer or series not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /event-series/{id} [get]
+func (c *SeriesController) GetSeries(ctx *fiber.Ctx) error {
+	userID := ctx.Locals("user_id").(string)
+
+	series, err := c.service.GetSeries(userID, ctx.Params("id"))
+	if err != nil {
+		return c.handleError(ctx, err)
+	}
+	return c.logHandler.LogSuccess(ctx, series, "Event series retrieved successfully", true)
+}
+
+// UpdateSeries godoc
+// @Summary Update an event series
+// @Description Edits one occurrence (scope "this"), the chosen occurrence and every later one (scope "future"), or the whole series (scope "all"). Occurrences that have ended are not changed.
+// @Tags Event Series Group
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Series ID" Format(uuid)
+// @Param input body dto.UpdateSeriesInput true "Scope and the details to change"
+// @Success 200 {object} map[string]interface{} "Event series updated successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid request payload or recurrence rule"
+// @Failure 403 {object} map[string]interface{} "User lacks update:events permission"
+// @Failure 404 {object} map[string]interface{} "Series or occurrence not found"
+// @Failure 409 {object} map[string]interface{} "Occurrence has ended or has orders"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /event-series/{id} [put]
+func (c *SeriesController) UpdateSeries(ctx *fiber.Ctx) error {
+	userID := ctx.Locals("user_id").(string)
+
+	var input dto.UpdateSeriesInput
+	if err := ctx.BodyParser(&input); err != nil {
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, "Invalid request body"), fiber.StatusBadRequest)
+	}
+	if err := c.validator.Struct(input); err != nil {
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, err.Error()), fiber.StatusBadRequest)
+	}
+
+	series, err := c.service.UpdateSeries(userID, ctx.Params("id"), input)
+	if err != nil {
+		return c.handleError(ctx, err)
+	}
+	return c.logHandler.LogSuccess(ctx, series, "Event series updated successfully", true)
+}
+
+// RemoveOccurrence godoc
+// @Summary Remove an occurrence from an event series
+// @Description Cancels a single occurrence by adding an exception for it to the series. Occurrences with orders cannot be removed.
+// @Tags Event Series Group
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Series ID" Format(uuid)
+// @Param event_id path string true "Occurrence event ID" Format(uuid)
+// @Success 200 {object} map[string]interface{} "Occurrence removed successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid ID format"
+// @Failure 403 {object} map[string]interface{} "User lacks delete:events permission"
+// @Failure 404 {object} map[string]interface{} "Series or occurrence not found"
+// @Failure 409 {object} map[string]interface{} "Occurrence has orders"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /event-series/{id}/occurrences/{event_id} [delete]
+func (c *SeriesController) RemoveOccurrence(ctx *fiber.Ctx) error {
+	userID := ctx.Locals("user_id").(string)
+
+	if err := c.service.RemoveOccurrence(userID, ctx.Params("id"), ctx.Params("event_id")); err != nil {
+		return c.handleError(ctx, err)
+	}
+	return c.logHandler.LogSuccess(ctx, nil, "Occurrence removed successfully", true)
+}
@@ -0,0 +1,132 @@
+package dto
+
+import (
+	"ticket-zetu-api/modules/events/models/events"
+	"time"
+)
+
+// RecurrenceInput describes when a series repeats, after RFC 5545 RRULE.
+// ByDay takes weekday codes (MO, TU, ...) for weekly series and ordinal
+// weekdays (2TU, -1FR) for monthly ones; ByMonthDay counts back from the end
+// of the month when negative. The rule must end with Until or Count.
+type RecurrenceInput struct {
+	Frequency  string     `json:"frequency" validate:"required,oneof=weekly monthly"`
+	Interval   int        `json:"interval,omitempty" validate:"omitempty,min=1"`
+	ByDay      []string   `json:"by_day,omitempty"`
+	ByMonthDay []int      `json:"by_month_day,omitempty"`
+	Until      *time.Time `json:"until,omitempty"`
+	Count      int        `json:"count,omitempty" validate:"omitempty,min=1"`
+}
+
+// SeriesTicketTypeInput is a ticket type every occurrence gets its own copy of
+type SeriesTicketTypeInput struct {
+	Name              string  `json:"name" validate:"required,max=100"`
+	Description       string  `json:"description,omitempty"`
+	BasePrice         float64 `json:"base_price" validate:"min=0"`
+	PriceModifier     float64 `json:"price_modifier,omitempty" validate:"omitempty,min=0"`
+	Benefits          string  `json:"benefits,omitempty"`
+	MinTicketsPerUser int     `json:"min_tickets_per_user,omitempty" validate:"omitempty,min=1"`
+	MaxTicketsPerUser int     `json:"max_tickets_per_user,omitempty" validate:"omitempty,min=1"`
+	IsDefault         bool    `json:"is_default"`
+	TransfersDisabled bool    `json:"transfers_disabled"`
+	MaxTransfers      int     `json:"max_transfers" validate:"min=0"`
+	Quantity          *int    `json:"quantity,omitempty" validate:"omitempty,min=0"`
+	SalesOpenDays     int     `json:"sales_open_days" validate:"min=0"`
+}
+
+// OccurrenceOverrideInput changes a single occurrence of a new series.
+// OccurrenceStart is the start the recurrence rule gives that occurrence.
+type OccurrenceOverrideInput struct {
+	OccurrenceStart time.Time  `json:"occurrence_start" validate:"required"`
+	StartTime       *time.Time `json:"start_time,omitempty"`
+	EndTime         *time.Time `json:"end_time,omitempty"`
+	Title           *string    `json:"title,omitempty"`
+	Description     *string    `json:"description,omitempty"`
+}
+
+// CreateSeriesInput creates a series. StartTime and EndTime are those of the
+// first occurrence.
+type CreateSeriesInput struct {
+	Title         string                    `json:"title" validate:"required"`
+	Description   string                    `json:"description,omitempty"`
+	SubcategoryID string                    `json:"subcategory_id" validate:"required"`
+	VenueID       string                    `json:"venue_id" validate:"required"`
+	StartTime     time.Time                 `json:"start_time" validate:"required"`
+	EndTime       time.Time                 `json:"end_time" validate:"required"`
+	Timezone      string                    `json:"timezone,omitempty"`
+	Language      string                    `json:"language,omitempty"`
+	EventType     string                    `json:"event_type" validate:"oneof=online offline hybrid"`
+	MinAge        int                       `json:"min_age"`
+	IsFree        bool                      `json:"is_free"`
+	HasTickets    bool                      `json:"has_tickets"`
+	Recurrence    RecurrenceInput           `json:"recurrence"`
+	Exceptions    []time.Time               `json:"exceptions,omitempty"`
+	Overrides     []OccurrenceOverrideInput `json:"overrides,omitempty" validate:"dive"`
+	TicketTypes   []SeriesTicketTypeInput   `json:"ticket_types,omitempty" validate:"dive"`
+}
+
+// UpdateSeriesInput edits one occurrence ("this"), the chosen occurrence and
+// every one after it ("future"), or the whole series ("all"). For "future"
+// and "all", StartTime and EndTime are the new times of the first occurrence
+// edited and every other occurrence moves by the same amount.
+type UpdateSeriesInput struct {
+	Scope         string           `json:"scope" validate:"required,oneof=this future all"`
+	OccurrenceID  string           `json:"occurrence_id,omitempty" validate:"omitempty,uuid"`
+	Title         *string          `json:"title,omitempty"`
+	Description   *string          `json:"description,omitempty"`
+	SubcategoryID *string          `json:"subcategory_id,omitempty"`
+	VenueID       *string          `json:"venue_id,omitempty"`
+	StartTime     *time.Time       `json:"start_time,omitempty"`
+	EndTime       *time.Time       `json:"end_time,omitempty"`
+	Language      *string          `json:"language,omitempty"`
+	EventType     *string          `json:"event_type,omitempty" validate:"omitempty,oneof=online offline hybrid"`
+	MinAge        *int             `json:"min_age,omitempty"`
+	IsFree        *bool            `json:"is_free,omitempty"`
+	HasTickets    *bool            `json:"has_tickets,omitempty"`
+	Recurrence    *RecurrenceInput `json:"recurrence,omitempty"`
+}
+
+// RecurrenceResponse is the recurrence rule of a series
+type RecurrenceResponse struct {
+	Frequency  string     `json:"frequency"`
+	Interval   int        `json:"interval"`
+	ByDay      []string   `json:"by_day,omitempty"`
+	ByMonthDay []int      `json:"by_month_day,omitempty"`
+	Until      *time.Time `json:"until,omitempty"`
+	Count      int        `json:"count,omitempty"`
+}
+
+// OccurrenceResponse is one occurrence of a series
+type OccurrenceResponse struct {
+	ID              string     `json:"id"`
+	Title           string     `json:"title"`
+	Slug            string     `json:"slug"`
+	VenueID         string     `json:"venue_id"`
+	StartTime       time.Time  `json:"start_time"`
+	EndTime         time.Time  `json:"end_time"`
+	OccurrenceStart *time.Time `json:"occurrence_start,omitempty"`
+	Status          string     `json:"status"`
+}
+
+// SeriesResponse is a series with its rule and occurrences
+type SeriesResponse struct {
+	ID              string                         `json:"id"`
+	Title           string                         `json:"title"`
+	Description     string                         `json:"description,omitempty"`
+	SubcategoryID   string                         `json:"subcategory_id"`
+	VenueID         string                         `json:"venue_id"`
+	Timezone        string                         `json:"timezone,omitempty"`
+	Language        string                         `json:"language,omitempty"`
+	EventType       string                         `json:"event_type"`
+	MinAge          int                            `json:"min_age"`
+	IsFree          bool                           `json:"is_free"`
+	HasTickets      bool                           `json:"has_tickets"`
+	StartTime       time.Time                      `json:"start_time"`
+	DurationMinutes int                            `json:"duration_minutes"`
+	Recurrence      RecurrenceResponse             `json:"recurrence"`
+	Exceptions      []time.Time                    `json:"exceptions,omitempty"`
+	TicketTypes     []events.EventSeriesTicketType `json:"ticket_types,omitempty"`
+	Occurrences     []OccurrenceResponse           `json:"occurrences"`
+	CreatedAt       time.Time                      `json:"created_at"`
+	UpdatedAt       time.Time                      `json:"updated_at"`
+}
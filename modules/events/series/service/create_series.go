@@ -0,0 +1,145 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"ticket-zetu-api/modules/events/models/categories"
+	"ticket-zetu-api/modules/events/models/events"
+	"ticket-zetu-api/modules/events/series/dto"
+	organizers_services "ticket-zetu-api/modules/organizers/services"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (s *seriesService) CreateSeries(userID string, input dto.CreateSeriesInput) (*dto.SeriesResponse, error) {
+	organizer, err := organizers_services.GetUserOrganizer(s.db, userID)
+	if err != nil {
+		return nil, err
+	}
+	if organizer.Status != "active" {
+		return nil, errors.New("organizer is not active")
+	}
+	if organizer.IsBanned {
+		return nil, errors.New("organizer is banned")
+	}
+	if organizer.IsFlagged {
+		return nil, errors.New("organizer is flagged for review")
+	}
+
+	startTime := input.StartTime.Truncate(time.Second)
+	if !input.EndTime.After(startTime) {
+		return nil, errors.New("end time must be after start time")
+	}
+
+	series := &events.EventSeries{
+		OrganizerID:     organizer.ID,
+		Title:           input.Title,
+		Description:     input.Description,
+		SubcategoryID:   input.SubcategoryID,
+		VenueID:         input.VenueID,
+		Timezone:        input.Timezone,
+		Language:        input.Language,
+		EventType:       events.EventType(input.EventType),
+		MinAge:          input.MinAge,
+		IsFree:          input.IsFree,
+		HasTickets:      input.HasTickets,
+		StartTime:       startTime,
+		DurationMinutes: int(input.EndTime.Sub(startTime) / time.Minute),
+	}
+	if series.DurationMinutes < 1 {
+		return nil, errors.New("occurrences must last at least a minute")
+	}
+	applyRecurrence(series, input.Recurrence)
+	if _, err := parseRecurrence(series); err != nil {
+		return nil, err
+	}
+
+	overrides := make(map[int64]dto.OccurrenceOverrideInput, len(input.Overrides))
+	for _, override := range input.Overrides {
+		overrides[override.OccurrenceStart.Unix()] = override
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		var subcategory categories.Subcategory
+		if err := tx.Where("id = ? AND deleted_at IS NULL", input.SubcategoryID).First(&subcategory).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("subcategory not found")
+			}
+			return err
+		}
+
+		var venue events.Venue
+		if err := tx.Where("id = ? AND deleted_at IS NULL", input.VenueID).First(&venue).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("venue not found")
+			}
+			return err
+		}
+
+		if err := tx.Create(series).Error; err != nil {
+			return fmt.Errorf("failed to create series: %w", err)
+		}
+
+		for _, ticketType := range input.TicketTypes {
+			template := events.EventSeriesTicketType{
+				SeriesID:          series.ID,
+				Name:              ticketType.Name,
+				Description:       ticketType.Description,
+				BasePrice:         ticketType.BasePrice,
+				PriceModifier:     ticketType.PriceModifier,
+				Benefits:          ticketType.Benefits,
+				MinTicketsPerUser: ticketType.MinTicketsPerUser,
+				MaxTicketsPerUser: ticketType.MaxTicketsPerUser,
+				IsDefault:         ticketType.IsDefault,
+				TransfersDisabled: ticketType.TransfersDisabled,
+				MaxTransfers:      ticketType.MaxTransfers,
+				Quantity:          ticketType.Quantity,
+				SalesOpenDays:     ticketType.SalesOpenDays,
+			}
+			if template.PriceModifier == 0 {
+				template.PriceModifier = 1
+			}
+			if template.MinTicketsPerUser == 0 {
+				template.MinTicketsPerUser = 1
+			}
+			if template.MaxTicketsPerUser == 0 {
+				template.MaxTicketsPerUser = 4
+			}
+			if template.MaxTicketsPerUser < template.MinTicketsPerUser {
+				return errors.New("max_tickets_per_user cannot be less than min_tickets_per_user")
+			}
+			if err := tx.Create(&template).Error; err != nil {
+				return err
+			}
+		}
+
+		for _, exception := range input.Exceptions {
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&events.EventSeriesException{
+				SeriesID:        series.ID,
+				OccurrenceStart: exception,
+			}).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := s.syncOccurrences(tx, series, time.Time{}, overrides); err != nil {
+			return err
+		}
+
+		var count int64
+		if err := tx.Model(&events.Event{}).Where("series_id = ?", series.ID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count == 0 {
+			return errors.New("series has no occurrences")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.toResponse(series)
+}
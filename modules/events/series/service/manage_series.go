@@ -0,0 +1,418 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"ticket-zetu-api/modules/events/models/categories"
+	"ticket-zetu-api/modules/events/models/events"
+	"ticket-zetu-api/modules/events/series/dto"
+	organizers_services "ticket-zetu-api/modules/organizers/services"
+	"ticket-zetu-api/modules/tickets/models/tickets"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// UpdateSeries edits one occurrence, the chosen occurrence and the ones after
+// it, or the whole series. Occurrences that have already ended are left as
+// they were.
+func (s *seriesService) UpdateSeries(userID, id string, input dto.UpdateSeriesInput) (*dto.SeriesResponse, error) {
+	hasPerm, err := s.HasPermission(userID, "update:events")
+	if err != nil {
+		return nil, fmt.Errorf("permission check failed: %w", err)
+	}
+	if !hasPerm {
+		return nil, errors.New("user lacks update:events permission")
+	}
+
+	organizer, err := organizers_services.GetUserOrganizer(s.db, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Scope != "all" && input.OccurrenceID == "" {
+		return nil, errors.New("occurrence_id is required for this scope")
+	}
+	if input.Scope == "this" && input.Recurrence != nil {
+		return nil, errors.New("recurrence can only change for future or all occurrences")
+	}
+
+	var target *events.EventSeries
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		series, err := s.lockSeries(tx, organizer.ID, id)
+		if err != nil {
+			return err
+		}
+		target = series
+
+		if err := s.validateReferences(tx, input); err != nil {
+			return err
+		}
+
+		switch input.Scope {
+		case "this":
+			occurrence, err := s.getOccurrence(tx, series.ID, input.OccurrenceID)
+			if err != nil {
+				return err
+			}
+			return s.updateOccurrence(tx, occurrence, input)
+		case "future":
+			occurrence, err := s.getOccurrence(tx, series.ID, input.OccurrenceID)
+			if err != nil {
+				return err
+			}
+			if !occurrence.OccurrenceStart.Equal(series.StartTime) {
+				if target, err = s.splitSeries(tx, series, *occurrence.OccurrenceStart); err != nil {
+					return err
+				}
+			}
+		}
+		return s.updateWholeSeries(tx, target, input)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// A "future" edit returns the series the edited occurrences now belong to
+	return s.toResponse(target)
+}
+
+// RemoveOccurrence cancels a single occurrence by adding an exception to the
+// series for it
+func (s *seriesService) RemoveOccurrence(userID, id, eventID string) error {
+	hasPerm, err := s.HasPermission(userID, "delete:events")
+	if err != nil {
+		return err
+	}
+	if !hasPerm {
+		return errors.New("user lacks delete:events permission")
+	}
+
+	organizer, err := organizers_services.GetUserOrganizer(s.db, userID)
+	if err != nil {
+		return err
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		series, err := s.lockSeries(tx, organizer.ID, id)
+		if err != nil {
+			return err
+		}
+		occurrence, err := s.getOccurrence(tx, series.ID, eventID)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&events.EventSeriesException{
+			SeriesID:        series.ID,
+			OccurrenceStart: *occurrence.OccurrenceStart,
+		}).Error; err != nil {
+			return err
+		}
+		return s.removeOccurrence(tx, occurrence)
+	})
+}
+
+// validateReferences checks the subcategory and venue an edit moves to
+func (s *seriesService) validateReferences(tx *gorm.DB, input dto.UpdateSeriesInput) error {
+	if input.SubcategoryID != nil {
+		if _, err := uuid.Parse(*input.SubcategoryID); err != nil {
+			return errors.New("invalid subcategory ID format")
+		}
+		var subcategory categories.Subcategory
+		if err := tx.Where("id = ? AND deleted_at IS NULL", *input.SubcategoryID).First(&subcategory).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("subcategory not found")
+			}
+			return err
+		}
+	}
+	if input.VenueID != nil {
+		if _, err := uuid.Parse(*input.VenueID); err != nil {
+			return errors.New("invalid venue ID format")
+		}
+		var venue events.Venue
+		if err := tx.Where("id = ? AND deleted_at IS NULL", *input.VenueID).First(&venue).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("venue not found")
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// updateOccurrence edits a single occurrence without touching the series
+func (s *seriesService) updateOccurrence(tx *gorm.DB, occurrence *events.Event, input dto.UpdateSeriesInput) error {
+	if !occurrence.EndTime.After(time.Now()) || occurrence.Status == events.EventCompleted {
+		return errors.New("occurrence has already ended")
+	}
+	if occurrence.Status == events.EventCancelled {
+		return errors.New("occurrence has been cancelled")
+	}
+
+	startTime := occurrence.StartTime
+	if input.StartTime != nil {
+		duration := occurrence.EndTime.Sub(occurrence.StartTime)
+		occurrence.StartTime = *input.StartTime
+		occurrence.EndTime = occurrence.StartTime.Add(duration)
+	}
+	if input.EndTime != nil {
+		occurrence.EndTime = *input.EndTime
+	}
+	if !occurrence.EndTime.After(occurrence.StartTime) {
+		return errors.New("end time must be after start time")
+	}
+
+	if err := s.applyEventFields(tx, occurrence, input); err != nil {
+		return err
+	}
+	return s.saveOccurrence(tx, occurrence, startTime)
+}
+
+// updateWholeSeries applies an edit to a series and to every one of its
+// occurrences that has not ended. A new start time moves every occurrence by
+// the same amount; a new recurrence rule adds and removes occurrences to
+// match it.
+func (s *seriesService) updateWholeSeries(tx *gorm.DB, series *events.EventSeries, input dto.UpdateSeriesInput) error {
+	var shift time.Duration
+	if input.StartTime != nil {
+		shift = input.StartTime.Truncate(time.Second).Sub(series.StartTime)
+	}
+	duration := time.Duration(series.DurationMinutes) * time.Minute
+	newDuration := duration
+	if input.EndTime != nil {
+		newDuration = input.EndTime.Sub(series.StartTime.Add(shift))
+		if newDuration < time.Minute {
+			return errors.New("end time must be after start time")
+		}
+	}
+
+	if input.Title != nil {
+		series.Title = *input.Title
+	}
+	if input.Description != nil {
+		series.Description = *input.Description
+	}
+	if input.SubcategoryID != nil {
+		series.SubcategoryID = *input.SubcategoryID
+	}
+	if input.VenueID != nil {
+		series.VenueID = *input.VenueID
+	}
+	if input.Language != nil {
+		series.Language = *input.Language
+	}
+	if input.EventType != nil {
+		series.EventType = events.EventType(*input.EventType)
+	}
+	if input.MinAge != nil {
+		series.MinAge = *input.MinAge
+	}
+	if input.IsFree != nil {
+		series.IsFree = *input.IsFree
+	}
+	if input.HasTickets != nil {
+		series.HasTickets = *input.HasTickets
+	}
+	series.StartTime = series.StartTime.Add(shift)
+	series.DurationMinutes = int(newDuration / time.Minute)
+	if input.Recurrence != nil {
+		applyRecurrence(series, *input.Recurrence)
+	}
+	if _, err := parseRecurrence(series); err != nil {
+		return err
+	}
+
+	series.Version++
+	if err := tx.Save(series).Error; err != nil {
+		return fmt.Errorf("failed to update series: %w", err)
+	}
+
+	if shift != 0 {
+		var exceptions []events.EventSeriesException
+		if err := tx.Where("series_id = ?", series.ID).Find(&exceptions).Error; err != nil {
+			return err
+		}
+		for _, exception := range exceptions {
+			if err := tx.Model(&events.EventSeriesException{}).
+				Where("id = ?", exception.ID).
+				UpdateColumn("occurrence_start", exception.OccurrenceStart.Add(shift)).Error; err != nil {
+				return err
+			}
+		}
+	}
+
+	var occurrences []events.Event
+	if err := tx.Where("series_id = ? AND deleted_at IS NULL", series.ID).Find(&occurrences).Error; err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for i := range occurrences {
+		occurrence := &occurrences[i]
+		occurrenceStart := occurrence.OccurrenceStart.Add(shift)
+		occurrence.OccurrenceStart = &occurrenceStart
+
+		// Ended and cancelled occurrences only follow the rule so it still
+		// recognises them
+		if !occurrence.EndTime.After(now) || occurrence.Status == events.EventCancelled || occurrence.Status == events.EventCompleted {
+			if err := tx.Model(&events.Event{}).
+				Where("id = ?", occurrence.ID).
+				UpdateColumn("occurrence_start", occurrenceStart).Error; err != nil {
+				return err
+			}
+			continue
+		}
+
+		startTime := occurrence.StartTime
+		occurrence.StartTime = occurrence.StartTime.Add(shift)
+		if newDuration != duration {
+			occurrence.EndTime = occurrence.StartTime.Add(newDuration)
+		} else {
+			occurrence.EndTime = occurrence.EndTime.Add(shift)
+		}
+		if err := s.applyEventFields(tx, occurrence, input); err != nil {
+			return err
+		}
+		if err := s.saveOccurrence(tx, occurrence, startTime); err != nil {
+			return err
+		}
+	}
+
+	if shift != 0 || input.Recurrence != nil {
+		return s.syncOccurrences(tx, series, now, nil)
+	}
+	return nil
+}
+
+// applyEventFields copies the edited event details onto an occurrence
+func (s *seriesService) applyEventFields(tx *gorm.DB, occurrence *events.Event, input dto.UpdateSeriesInput) error {
+	if input.Title != nil && *input.Title != occurrence.Title {
+		occurrence.Title = *input.Title
+		slugDate := occurrence.StartTime
+		if occurrence.OccurrenceStart != nil {
+			slugDate = *occurrence.OccurrenceStart
+		}
+		slug, err := s.generateSlug(tx, fmt.Sprintf("%s %s", occurrence.Title, slugDate.Format("2006-01-02")))
+		if err != nil {
+			return fmt.Errorf("failed to generate slug: %w", err)
+		}
+		occurrence.Slug = slug
+	}
+	if input.Description != nil {
+		occurrence.Description = *input.Description
+	}
+	if input.SubcategoryID != nil {
+		occurrence.SubcategoryID = *input.SubcategoryID
+	}
+	if input.VenueID != nil {
+		occurrence.VenueID = *input.VenueID
+	}
+	if input.Language != nil {
+		occurrence.Language = *input.Language
+	}
+	if input.EventType != nil {
+		occurrence.EventType = events.EventType(*input.EventType)
+	}
+	if input.MinAge != nil {
+		occurrence.MinAge = *input.MinAge
+	}
+	if input.IsFree != nil {
+		occurrence.IsFree = *input.IsFree
+	}
+	if input.HasTickets != nil {
+		occurrence.HasTickets = *input.HasTickets
+	}
+	return nil
+}
+
+// saveOccurrence saves an edited occurrence. Ticket types whose sales closed
+// at the old start close at the new one instead.
+func (s *seriesService) saveOccurrence(tx *gorm.DB, occurrence *events.Event, oldStart time.Time) error {
+	occurrence.Version++
+	occurrence.UpdatedAt = time.Now()
+	if err := tx.Save(occurrence).Error; err != nil {
+		return fmt.Errorf("failed to update event: %w", err)
+	}
+
+	if !occurrence.StartTime.Equal(oldStart) {
+		if err := tx.Model(&tickets.TicketType{}).
+			Where("event_id = ? AND sales_end = ?", occurrence.ID, oldStart).
+			UpdateColumn("sales_end", occurrence.StartTime).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// splitSeries ends a series just before pivot and moves the occurrences from
+// pivot on, with their exceptions, to a new series with the same rule and
+// ticket types. This is how a "future" edit leaves earlier occurrences alone.
+func (s *seriesService) splitSeries(tx *gorm.DB, series *events.EventSeries, pivot time.Time) (*events.EventSeries, error) {
+	rule, err := parseRecurrence(series)
+	if err != nil {
+		return nil, err
+	}
+	slots, err := rule.slots()
+	if err != nil {
+		return nil, err
+	}
+	before := 0
+	for _, slot := range slots {
+		if slot.Before(pivot) {
+			before++
+		}
+	}
+
+	next := *series
+	next.ID = ""
+	next.StartTime = pivot
+	next.TicketTypes = nil
+	next.Exceptions = nil
+	next.CreatedAt = time.Time{}
+	next.UpdatedAt = time.Time{}
+	next.Version = 1
+	if series.Count > 0 {
+		next.Count = series.Count - before
+		series.Count = 0
+	}
+	if err := tx.Create(&next).Error; err != nil {
+		return nil, fmt.Errorf("failed to create series: %w", err)
+	}
+
+	until := pivot.Add(-time.Second)
+	series.Until = &until
+	series.Version++
+	if err := tx.Save(series).Error; err != nil {
+		return nil, fmt.Errorf("failed to update series: %w", err)
+	}
+
+	var templates []events.EventSeriesTicketType
+	if err := tx.Where("series_id = ?", series.ID).Order("created_at ASC").Find(&templates).Error; err != nil {
+		return nil, err
+	}
+	for _, template := range templates {
+		template.ID = ""
+		template.SeriesID = next.ID
+		template.CreatedAt = time.Time{}
+		template.UpdatedAt = time.Time{}
+		if err := tx.Create(&template).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Model(&events.EventSeriesException{}).
+		Where("series_id = ? AND occurrence_start >= ?", series.ID, pivot).
+		UpdateColumn("series_id", next.ID).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.Model(&events.Event{}).
+		Where("series_id = ? AND occurrence_start >= ?", series.ID, pivot).
+		UpdateColumn("series_id", next.ID).Error; err != nil {
+		return nil, err
+	}
+	return &next, nil
+}
@@ -0,0 +1,190 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"ticket-zetu-api/modules/events/models/events"
+	"ticket-zetu-api/modules/events/series/dto"
+	"ticket-zetu-api/modules/tickets/models/tickets"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// syncOccurrences makes the occurrences of a series starting at or after from
+// match its recurrence rule: missing occurrences are created with their own
+// ticket types and stock, and ones the rule no longer produces are removed.
+// Overrides, keyed by the rule start they change, apply to created ones.
+func (s *seriesService) syncOccurrences(tx *gorm.DB, series *events.EventSeries, from time.Time, overrides map[int64]dto.OccurrenceOverrideInput) error {
+	var exceptions []events.EventSeriesException
+	if err := tx.Where("series_id = ?", series.ID).Find(&exceptions).Error; err != nil {
+		return err
+	}
+
+	slots, err := occurrenceSlots(series, exceptions)
+	if err != nil {
+		return err
+	}
+
+	var existing []events.Event
+	if err := tx.Where("series_id = ? AND occurrence_start >= ? AND deleted_at IS NULL", series.ID, from).
+		Find(&existing).Error; err != nil {
+		return err
+	}
+	existingBySlot := make(map[int64]bool, len(existing))
+	for _, occurrence := range existing {
+		existingBySlot[occurrence.OccurrenceStart.Unix()] = true
+	}
+
+	var templates []events.EventSeriesTicketType
+	if err := tx.Where("series_id = ?", series.ID).Order("created_at ASC").Find(&templates).Error; err != nil {
+		return err
+	}
+
+	wanted := make(map[int64]bool, len(slots))
+	for _, slot := range slots {
+		if slot.Before(from) {
+			continue
+		}
+		wanted[slot.Unix()] = true
+		if existingBySlot[slot.Unix()] {
+			continue
+		}
+		override, hasOverride := overrides[slot.Unix()]
+		if hasOverride {
+			delete(overrides, slot.Unix())
+		}
+		if err := s.createOccurrence(tx, series, templates, slot, override, hasOverride); err != nil {
+			return err
+		}
+	}
+	if len(overrides) > 0 {
+		return errors.New("override does not match an occurrence")
+	}
+
+	for i := range existing {
+		if wanted[existing[i].OccurrenceStart.Unix()] {
+			continue
+		}
+		if err := s.removeOccurrence(tx, &existing[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// createOccurrence creates the event for one occurrence of a series and gives
+// it a copy of each of the series' ticket types
+func (s *seriesService) createOccurrence(tx *gorm.DB, series *events.EventSeries, templates []events.EventSeriesTicketType, slot time.Time, override dto.OccurrenceOverrideInput, hasOverride bool) error {
+	title := series.Title
+	description := series.Description
+	startTime := slot
+	endTime := slot.Add(time.Duration(series.DurationMinutes) * time.Minute)
+	if hasOverride {
+		if override.Title != nil {
+			title = *override.Title
+		}
+		if override.Description != nil {
+			description = *override.Description
+		}
+		if override.StartTime != nil {
+			duration := endTime.Sub(startTime)
+			startTime = *override.StartTime
+			endTime = startTime.Add(duration)
+		}
+		if override.EndTime != nil {
+			endTime = *override.EndTime
+		}
+		if !endTime.After(startTime) {
+			return errors.New("end time must be after start time")
+		}
+	}
+
+	slug, err := s.generateSlug(tx, fmt.Sprintf("%s %s", title, slot.Format("2006-01-02")))
+	if err != nil {
+		return fmt.Errorf("failed to generate slug: %v", err)
+	}
+
+	seriesID := series.ID
+	occurrenceStart := slot
+	event := &events.Event{
+		Title:           title,
+		Slug:            slug,
+		Description:     description,
+		SubcategoryID:   series.SubcategoryID,
+		VenueID:         series.VenueID,
+		StartTime:       startTime,
+		EndTime:         endTime,
+		Timezone:        series.Timezone,
+		Language:        series.Language,
+		EventType:       series.EventType,
+		MinAge:          series.MinAge,
+		IsFree:          series.IsFree,
+		HasTickets:      series.HasTickets,
+		Status:          events.EventDraft,
+		OrganizerID:     series.OrganizerID,
+		SeriesID:        &seriesID,
+		OccurrenceStart: &occurrenceStart,
+	}
+	if err := tx.Create(event).Error; err != nil {
+		return fmt.Errorf("failed to create event: %w", err)
+	}
+
+	now := time.Now()
+	for _, template := range templates {
+		salesStart := now
+		if template.SalesOpenDays > 0 {
+			salesStart = startTime.AddDate(0, 0, -template.SalesOpenDays)
+		}
+		salesEnd := startTime
+		ticketType := &tickets.TicketType{
+			EventID:           event.ID,
+			OrganizerID:       series.OrganizerID,
+			Name:              template.Name,
+			Description:       template.Description,
+			BasePrice:         template.BasePrice,
+			PriceModifier:     template.PriceModifier,
+			Benefits:          template.Benefits,
+			MinTicketsPerUser: template.MinTicketsPerUser,
+			MaxTicketsPerUser: template.MaxTicketsPerUser,
+			Status:            tickets.TicketTypeActive,
+			IsDefault:         template.IsDefault,
+			TransfersDisabled: template.TransfersDisabled,
+			MaxTransfers:      template.MaxTransfers,
+			SalesStart:        salesStart,
+			SalesEnd:          &salesEnd,
+		}
+		if err := tx.Create(ticketType).Error; err != nil {
+			return err
+		}
+		if template.Quantity != nil {
+			if _, err := s.inventoryService.CreateStock(tx, ticketType, *template.Quantity); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// removeOccurrence deletes an occurrence nobody has bought into, along with
+// its ticket types
+func (s *seriesService) removeOccurrence(tx *gorm.DB, event *events.Event) error {
+	var orders int64
+	if err := tx.Model(&tickets.Order{}).
+		Where("event_id = ? AND status NOT IN ?", event.ID, []tickets.OrderStatus{tickets.OrderCanceled, tickets.OrderExpired}).
+		Count(&orders).Error; err != nil {
+		return err
+	}
+	var sold int64
+	if err := tx.Model(&tickets.Ticket{}).Where("event_id = ?", event.ID).Count(&sold).Error; err != nil {
+		return err
+	}
+	if orders > 0 || sold > 0 {
+		return errors.New("cannot remove an occurrence that has orders")
+	}
+
+	if err := tx.Where("event_id = ?", event.ID).Delete(&tickets.TicketType{}).Error; err != nil {
+		return err
+	}
+	return tx.Delete(event).Error
+}
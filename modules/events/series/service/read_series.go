@@ -0,0 +1,56 @@
+package service
+
+import (
+	"errors"
+	"ticket-zetu-api/modules/events/models/events"
+	"ticket-zetu-api/modules/events/series/dto"
+	organizers_services "ticket-zetu-api/modules/organizers/services"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func (s *seriesService) GetSeries(userID, id string) (*dto.SeriesResponse, error) {
+	organizer, err := organizers_services.GetUserOrganizer(s.db, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, errors.New("invalid series ID format")
+	}
+
+	var series events.EventSeries
+	if err := s.db.Where("id = ? AND organizer_id = ? AND deleted_at IS NULL", id, organizer.ID).First(&series).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("series not found")
+		}
+		return nil, err
+	}
+
+	return s.toResponse(&series)
+}
+
+func (s *seriesService) GetAllSeries(userID string) ([]dto.SeriesResponse, error) {
+	organizer, err := organizers_services.GetUserOrganizer(s.db, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var seriesList []events.EventSeries
+	if err := s.db.Where("organizer_id = ? AND deleted_at IS NULL", organizer.ID).
+		Order("start_time ASC").
+		Find(&seriesList).Error; err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.SeriesResponse, 0, len(seriesList))
+	for i := range seriesList {
+		response, err := s.toResponse(&seriesList[i])
+		if err != nil {
+			return nil, err
+		}
+		responses = append(responses, *response)
+	}
+	return responses, nil
+}
@@ -0,0 +1,340 @@
+package service
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"ticket-zetu-api/modules/events/models/events"
+	"ticket-zetu-api/modules/events/series/dto"
+	"time"
+)
+
+// maxSeriesOccurrences caps how many occurrences one series can generate
+const maxSeriesOccurrences = 200
+
+// maxMonthlyPeriods stops a monthly rule whose days rarely exist (the 31st
+// every twelve months from February) from searching forever
+const maxMonthlyPeriods = 600
+
+var weekdayCodes = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+// byDay is one BYDAY entry; ordinal is 0 for weekly rules and picks the nth
+// (or nth from last when negative) weekday of the month for monthly ones
+type byDay struct {
+	ordinal int
+	weekday time.Weekday
+}
+
+// recurrenceRule is the parsed recurrence rule of a series
+type recurrenceRule struct {
+	start      time.Time
+	frequency  events.RecurrenceFrequency
+	interval   int
+	byDay      []byDay
+	byMonthDay []int
+	until      *time.Time
+	count      int
+}
+
+// applyRecurrence copies a recurrence input onto the series
+func applyRecurrence(series *events.EventSeries, input dto.RecurrenceInput) {
+	series.Frequency = events.RecurrenceFrequency(input.Frequency)
+	series.Interval = input.Interval
+	if series.Interval == 0 {
+		series.Interval = 1
+	}
+
+	days := make([]string, len(input.ByDay))
+	for i, day := range input.ByDay {
+		days[i] = strings.ToUpper(strings.TrimSpace(day))
+	}
+	series.ByDay = strings.Join(days, ",")
+
+	monthDays := make([]string, len(input.ByMonthDay))
+	for i, day := range input.ByMonthDay {
+		monthDays[i] = strconv.Itoa(day)
+	}
+	series.ByMonthDay = strings.Join(monthDays, ",")
+
+	series.Until = input.Until
+	series.Count = input.Count
+}
+
+// toRecurrenceResponse converts the stored recurrence rule of a series
+func toRecurrenceResponse(series *events.EventSeries) dto.RecurrenceResponse {
+	response := dto.RecurrenceResponse{
+		Frequency: string(series.Frequency),
+		Interval:  series.Interval,
+		Until:     series.Until,
+		Count:     series.Count,
+	}
+	if series.ByDay != "" {
+		response.ByDay = strings.Split(series.ByDay, ",")
+	}
+	if series.ByMonthDay != "" {
+		for _, part := range strings.Split(series.ByMonthDay, ",") {
+			if day, err := strconv.Atoi(part); err == nil {
+				response.ByMonthDay = append(response.ByMonthDay, day)
+			}
+		}
+	}
+	return response
+}
+
+// seriesLocation is the timezone occurrences keep their wall-clock time in
+func seriesLocation(timezone string) (*time.Location, error) {
+	if timezone == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, errors.New("invalid timezone")
+	}
+	return loc, nil
+}
+
+// parseRecurrence validates the recurrence rule of a series
+func parseRecurrence(series *events.EventSeries) (*recurrenceRule, error) {
+	loc, err := seriesLocation(series.Timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	rule := &recurrenceRule{
+		start:     series.StartTime.In(loc).Truncate(time.Second),
+		frequency: series.Frequency,
+		interval:  series.Interval,
+		until:     series.Until,
+		count:     series.Count,
+	}
+
+	if rule.frequency != events.RecurrenceWeekly && rule.frequency != events.RecurrenceMonthly {
+		return nil, errors.New("invalid recurrence frequency")
+	}
+	if rule.interval < 1 {
+		return nil, errors.New("interval must be at least 1")
+	}
+	if rule.count < 0 {
+		return nil, errors.New("count must be at least 1")
+	}
+	if rule.until == nil && rule.count == 0 {
+		return nil, errors.New("recurrence must end with until or count")
+	}
+	if rule.until != nil && rule.until.Before(series.StartTime) {
+		return nil, errors.New("until must be after start time")
+	}
+
+	if series.ByDay != "" {
+		for _, part := range strings.Split(series.ByDay, ",") {
+			day, err := parseByDay(part, rule.frequency)
+			if err != nil {
+				return nil, err
+			}
+			rule.byDay = append(rule.byDay, day)
+		}
+	}
+
+	if series.ByMonthDay != "" {
+		if rule.frequency != events.RecurrenceMonthly {
+			return nil, errors.New("by_month_day only applies to monthly recurrence")
+		}
+		for _, part := range strings.Split(series.ByMonthDay, ",") {
+			day, err := strconv.Atoi(part)
+			if err != nil || day == 0 || day < -31 || day > 31 {
+				return nil, errors.New("invalid day in by_month_day")
+			}
+			rule.byMonthDay = append(rule.byMonthDay, day)
+		}
+	}
+
+	if len(rule.byDay) > 0 && len(rule.byMonthDay) > 0 {
+		return nil, errors.New("use either by_day or by_month_day for monthly recurrence")
+	}
+	return rule, nil
+}
+
+// parseByDay parses a BYDAY entry such as "MO" or, for monthly rules, "-1FR"
+func parseByDay(value string, frequency events.RecurrenceFrequency) (byDay, error) {
+	value = strings.TrimSpace(value)
+	if len(value) < 2 {
+		return byDay{}, errors.New("invalid weekday in by_day")
+	}
+
+	weekday, ok := weekdayCodes[value[len(value)-2:]]
+	if !ok {
+		return byDay{}, errors.New("invalid weekday in by_day")
+	}
+
+	prefix := value[:len(value)-2]
+	if frequency == events.RecurrenceWeekly {
+		if prefix != "" {
+			return byDay{}, errors.New("invalid weekday in by_day")
+		}
+		return byDay{weekday: weekday}, nil
+	}
+
+	ordinal, err := strconv.Atoi(prefix)
+	if err != nil || ordinal == 0 || ordinal < -5 || ordinal > 5 {
+		return byDay{}, errors.New("monthly by_day needs an ordinal such as 2TU or -1FR")
+	}
+	return byDay{ordinal: ordinal, weekday: weekday}, nil
+}
+
+// slots returns the starts of every occurrence the rule generates, in order
+func (r *recurrenceRule) slots() ([]time.Time, error) {
+	var slots []time.Time
+	done := false
+
+	emit := func(t time.Time) error {
+		if t.Before(r.start) {
+			return nil
+		}
+		if r.until != nil && t.After(*r.until) {
+			done = true
+			return nil
+		}
+		slots = append(slots, t)
+		if len(slots) > maxSeriesOccurrences {
+			return errors.New("too many occurrences in series")
+		}
+		if r.count > 0 && len(slots) == r.count {
+			done = true
+		}
+		return nil
+	}
+
+	if r.frequency == events.RecurrenceWeekly {
+		offsets := r.weekdayOffsets()
+		weekStart := r.start.AddDate(0, 0, -mondayOffset(r.start.Weekday()))
+		for period := 0; !done; period += r.interval {
+			week := weekStart.AddDate(0, 0, 7*period)
+			for _, offset := range offsets {
+				if err := emit(r.at(week.AddDate(0, 0, offset))); err != nil {
+					return nil, err
+				}
+				if done {
+					break
+				}
+			}
+		}
+		return slots, nil
+	}
+
+	for period := 0; !done && period < maxMonthlyPeriods; period += r.interval {
+		month := time.Date(r.start.Year(), r.start.Month()+time.Month(period), 1, 0, 0, 0, 0, r.start.Location())
+		if r.until != nil && month.After(*r.until) {
+			break
+		}
+		for _, day := range r.monthDays(month) {
+			if err := emit(r.at(month.AddDate(0, 0, day-1))); err != nil {
+				return nil, err
+			}
+			if done {
+				break
+			}
+		}
+	}
+	return slots, nil
+}
+
+// at places an occurrence on the given date at the rule's wall-clock time
+func (r *recurrenceRule) at(date time.Time) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(), r.start.Hour(), r.start.Minute(), r.start.Second(), 0, r.start.Location())
+}
+
+// weekdayOffsets are the days of a Monday-based week a weekly rule falls on
+func (r *recurrenceRule) weekdayOffsets() []int {
+	if len(r.byDay) == 0 {
+		return []int{mondayOffset(r.start.Weekday())}
+	}
+	seen := make(map[int]bool)
+	var offsets []int
+	for _, day := range r.byDay {
+		offset := mondayOffset(day.weekday)
+		if !seen[offset] {
+			seen[offset] = true
+			offsets = append(offsets, offset)
+		}
+	}
+	sort.Ints(offsets)
+	return offsets
+}
+
+// monthDays are the days of the given month a monthly rule falls on
+func (r *recurrenceRule) monthDays(month time.Time) []int {
+	daysInMonth := month.AddDate(0, 1, -1).Day()
+	seen := make(map[int]bool)
+	var days []int
+	add := func(day int) {
+		if day >= 1 && day <= daysInMonth && !seen[day] {
+			seen[day] = true
+			days = append(days, day)
+		}
+	}
+
+	switch {
+	case len(r.byDay) > 0:
+		for _, entry := range r.byDay {
+			if entry.ordinal > 0 {
+				first := 1 + (int(entry.weekday)-int(month.Weekday())+7)%7
+				add(first + 7*(entry.ordinal-1))
+			} else {
+				lastWeekday := month.AddDate(0, 0, daysInMonth-1).Weekday()
+				last := daysInMonth - (int(lastWeekday)-int(entry.weekday)+7)%7
+				add(last + 7*(entry.ordinal+1))
+			}
+		}
+	case len(r.byMonthDay) > 0:
+		for _, day := range r.byMonthDay {
+			if day < 0 {
+				day = daysInMonth + 1 + day
+			}
+			add(day)
+		}
+	default:
+		// Months without the start day are skipped, as RFC 5545 does
+		add(r.start.Day())
+	}
+
+	sort.Ints(days)
+	return days
+}
+
+func mondayOffset(weekday time.Weekday) int {
+	return (int(weekday) + 6) % 7
+}
+
+// occurrenceSlots returns the occurrence starts of a series, leaving out its
+// exceptions
+func occurrenceSlots(series *events.EventSeries, exceptions []events.EventSeriesException) ([]time.Time, error) {
+	rule, err := parseRecurrence(series)
+	if err != nil {
+		return nil, err
+	}
+	slots, err := rule.slots()
+	if err != nil {
+		return nil, err
+	}
+
+	excluded := make(map[int64]bool, len(exceptions))
+	for _, exception := range exceptions {
+		excluded[exception.OccurrenceStart.Unix()] = true
+	}
+
+	kept := slots[:0]
+	for _, slot := range slots {
+		if !excluded[slot.Unix()] {
+			kept = append(kept, slot)
+		}
+	}
+	return kept, nil
+}
@@ -0,0 +1,175 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"ticket-zetu-api/modules/events/models/events"
+	"ticket-zetu-api/modules/events/series/dto"
+	inventory_service "ticket-zetu-api/modules/tickets/inventory/service"
+	authorization_service "ticket-zetu-api/modules/users/authorization/service"
+
+	"github.com/google/uuid"
+	"github.com/gosimple/slug"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SeriesService interface {
+	CreateSeries(userID string, input dto.CreateSeriesInput) (*dto.SeriesResponse, error)
+	UpdateSeries(userID, id string, input dto.UpdateSeriesInput) (*dto.SeriesResponse, error)
+	RemoveOccurrence(userID, id, eventID string) error
+	GetSeries(userID, id string) (*dto.SeriesResponse, error)
+	GetAllSeries(userID string) ([]dto.SeriesResponse, error)
+	HasPermission(userID, permission string) (bool, error)
+}
+
+type seriesService struct {
+	db                   *gorm.DB
+	authorizationService authorization_service.PermissionService
+	inventoryService     inventory_service.InventoryService
+}
+
+func NewSeriesService(db *gorm.DB, authService authorization_service.PermissionService, inventoryService inventory_service.InventoryService) SeriesService {
+	return &seriesService{
+		db:                   db,
+		authorizationService: authService,
+		inventoryService:     inventoryService,
+	}
+}
+
+func (s *seriesService) HasPermission(userID, permission string) (bool, error) {
+	if _, err := uuid.Parse(userID); err != nil {
+		return false, errors.New("invalid user ID format")
+	}
+	hasPerm, err := s.authorizationService.HasPermission(userID, permission)
+	if err != nil {
+		return false, err
+	}
+	return hasPerm, nil
+}
+
+// lockSeries loads an organizer's series for update, so edits to the same
+// series run one after another
+func (s *seriesService) lockSeries(tx *gorm.DB, organizerID, id string) (*events.EventSeries, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, errors.New("invalid series ID format")
+	}
+
+	var series events.EventSeries
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND organizer_id = ? AND deleted_at IS NULL", id, organizerID).
+		First(&series).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("series not found")
+		}
+		return nil, err
+	}
+	return &series, nil
+}
+
+// getOccurrence loads an occurrence of the series
+func (s *seriesService) getOccurrence(tx *gorm.DB, seriesID, eventID string) (*events.Event, error) {
+	if _, err := uuid.Parse(eventID); err != nil {
+		return nil, errors.New("invalid event ID format")
+	}
+
+	var event events.Event
+	if err := tx.Where("id = ? AND series_id = ? AND deleted_at IS NULL", eventID, seriesID).First(&event).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("occurrence not found")
+		}
+		return nil, err
+	}
+	return &event, nil
+}
+
+// generateSlug returns a slug no other event uses. It runs on the caller's
+// transaction so occurrences created earlier in it are taken into account.
+func (s *seriesService) generateSlug(tx *gorm.DB, title string) (string, error) {
+	baseSlug := slug.Make(title)
+	if baseSlug == "" {
+		return "", fmt.Errorf("invalid title for slug generation")
+	}
+
+	var count int64
+	if err := tx.Model(&events.Event{}).
+		Where("slug = ? AND deleted_at IS NULL", baseSlug).
+		Count(&count).Error; err != nil {
+		return "", fmt.Errorf("failed to check slug: %v", err)
+	}
+	if count == 0 {
+		return baseSlug, nil
+	}
+
+	var existingSlugs []string
+	if err := tx.Model(&events.Event{}).
+		Where("slug LIKE ? AND deleted_at IS NULL", baseSlug+"-%").
+		Pluck("slug", &existingSlugs).Error; err != nil {
+		return "", fmt.Errorf("failed to fetch existing slugs: %v", err)
+	}
+
+	maxSuffix := 0
+	for _, existingSlug := range existingSlugs {
+		suffix := strings.TrimPrefix(existingSlug, baseSlug+"-")
+		if num, err := strconv.Atoi(suffix); err == nil && num > maxSuffix {
+			maxSuffix = num
+		}
+	}
+	return fmt.Sprintf("%s-%d", baseSlug, maxSuffix+1), nil
+}
+
+func (s *seriesService) toResponse(series *events.EventSeries) (*dto.SeriesResponse, error) {
+	var templates []events.EventSeriesTicketType
+	if err := s.db.Where("series_id = ?", series.ID).Order("created_at ASC").Find(&templates).Error; err != nil {
+		return nil, err
+	}
+
+	var exceptions []events.EventSeriesException
+	if err := s.db.Where("series_id = ?", series.ID).Order("occurrence_start ASC").Find(&exceptions).Error; err != nil {
+		return nil, err
+	}
+
+	var occurrences []events.Event
+	if err := s.db.Where("series_id = ? AND deleted_at IS NULL", series.ID).Order("start_time ASC").Find(&occurrences).Error; err != nil {
+		return nil, err
+	}
+
+	response := &dto.SeriesResponse{
+		ID:              series.ID,
+		Title:           series.Title,
+		Description:     series.Description,
+		SubcategoryID:   series.SubcategoryID,
+		VenueID:         series.VenueID,
+		Timezone:        series.Timezone,
+		Language:        series.Language,
+		EventType:       string(series.EventType),
+		MinAge:          series.MinAge,
+		IsFree:          series.IsFree,
+		HasTickets:      series.HasTickets,
+		StartTime:       series.StartTime,
+		DurationMinutes: series.DurationMinutes,
+		Recurrence:      toRecurrenceResponse(series),
+		TicketTypes:     templates,
+		Occurrences:     make([]dto.OccurrenceResponse, len(occurrences)),
+		CreatedAt:       series.CreatedAt,
+		UpdatedAt:       series.UpdatedAt,
+	}
+	for _, exception := range exceptions {
+		response.Exceptions = append(response.Exceptions, exception.OccurrenceStart)
+	}
+	for i, occurrence := range occurrences {
+		response.Occurrences[i] = dto.OccurrenceResponse{
+			ID:              occurrence.ID,
+			Title:           occurrence.Title,
+			Slug:            occurrence.Slug,
+			VenueID:         occurrence.VenueID,
+			StartTime:       occurrence.StartTime,
+			EndTime:         occurrence.EndTime,
+			OccurrenceStart: occurrence.OccurrenceStart,
+			Status:          string(occurrence.Status),
+		}
+	}
+	return response, nil
+}
@@ -1,6 +1,7 @@
 package venues_controller
 
 import (
+	"strings"
 	venue_dto "ticket-zetu-api/modules/events/venues/dto"
 
 	"github.com/gofiber/fiber/v2"
@@ -66,6 +67,12 @@ func (c *VenueController) CreateVenue(ctx *fiber.Ctx) error {
 		if err.Error() == "organizer not found" {
 			return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, err.Error()), fiber.StatusBadRequest)
 		}
+		if err.Error() == "layout must be valid JSON" || strings.HasPrefix(err.Error(), "invalid layout") {
+			return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, err.Error()), fiber.StatusBadRequest)
+		}
+		if err.Error() == "price tier not found" {
+			return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusNotFound, err.Error()), fiber.StatusNotFound)
+		}
 		return c.logHandler.LogError(ctx, err, fiber.StatusInternalServerError)
 	}
 	return c.logHandler.LogSuccess(ctx, nil, "Venue created successfully", true)
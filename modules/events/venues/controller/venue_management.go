@@ -90,13 +90,15 @@ func (c *VenueController) UpdateVenue(ctx *fiber.Ctx) error {
 		newUpdateVenue,
 	)
 	if err != nil {
-		switch err.Error() {
-		case "user lacks update:venues permission":
+		switch {
+		case err.Error() == "user lacks update:venues permission":
 			return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusForbidden, err.Error()), fiber.StatusForbidden)
-		case "venue not found":
+		case err.Error() == "venue not found", err.Error() == "price tier not found":
 			return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusNotFound, err.Error()), fiber.StatusNotFound)
-		case "organizer not found":
+		case err.Error() == "organizer not found", err.Error() == "layout must be valid JSON", strings.HasPrefix(err.Error(), "invalid layout"):
 			return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, err.Error()), fiber.StatusBadRequest)
+		case err.Error() == "layout removes seats that are held or booked":
+			return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusConflict, err.Error()), fiber.StatusConflict)
 		default:
 			return c.logHandler.LogError(ctx, err, fiber.StatusInternalServerError)
 		}
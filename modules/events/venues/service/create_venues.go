@@ -5,7 +5,10 @@ import (
 	"encoding/json"
 	"errors"
 	"ticket-zetu-api/modules/events/models/events"
+	seat_services "ticket-zetu-api/modules/events/seat_allocation/services"
 	venue_dto "ticket-zetu-api/modules/events/venues/dto"
+
+	"gorm.io/gorm"
 )
 
 func (s *venueService) CreateVenue(userID string, dto venue_dto.CreateVenueDto) (*venue_dto.CreateVenueDto, error) {
@@ -28,6 +31,9 @@ func (s *venueService) CreateVenue(userID string, dto venue_dto.CreateVenueDto)
 		if !json.Valid([]byte(dto.Layout)) {
 			return nil, errors.New("layout must be valid JSON")
 		}
+		if _, err := events.ParseLayout(dto.Layout); err != nil {
+			return nil, err
+		}
 	}
 	if dto.AccessibilityFeatures != "" {
 		if !json.Valid([]byte(dto.AccessibilityFeatures)) {
@@ -87,7 +93,14 @@ func (s *venueService) CreateVenue(userID string, dto venue_dto.CreateVenueDto)
 		Status:                events.VenueStatus(dto.Status),
 	}
 
-	if err := s.db.Create(&venue).Error; err != nil {
+	// Seats are generated along with the venue when its layout is structured
+	if err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&venue).Error; err != nil {
+			return err
+		}
+		_, err := seat_services.SyncVenueSeats(tx, &venue)
+		return err
+	}); err != nil {
 		return nil, err
 	}
 
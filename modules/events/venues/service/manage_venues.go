@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"ticket-zetu-api/modules/events/models/events"
+	seat_services "ticket-zetu-api/modules/events/seat_allocation/services"
 	venue_dto "ticket-zetu-api/modules/events/venues/dto"
 
 	"github.com/google/uuid"
@@ -31,13 +32,15 @@ func (s *venueService) UpdateVenue(userID, id string, dto venue_dto.UpdateVenueD
 		return nil, errors.New("invalid venue ID format")
 	}
 
-	// Validate and normalize JSON fields
+	// Validate and normalize JSON fields. An empty layout keeps the venue's
+	// current one, so the seats generated from it stay as they are.
 	if dto.Layout != "" {
 		if !json.Valid([]byte(dto.Layout)) {
 			return nil, errors.New("layout must be valid JSON")
 		}
-	} else {
-		dto.Layout = "{}"
+		if _, err := events.ParseLayout(dto.Layout); err != nil {
+			return nil, err
+		}
 	}
 
 	if dto.AccessibilityFeatures != "" {
@@ -94,7 +97,6 @@ func (s *venueService) UpdateVenue(userID, id string, dto venue_dto.UpdateVenueD
 	venue.Country = dto.Country
 	venue.Capacity = dto.Capacity
 	venue.VenueType = events.VenueType(dto.VenueType)
-	venue.Layout = dto.Layout
 	venue.AccessibilityFeatures = dto.AccessibilityFeatures
 	venue.Facilities = dto.Facilities
 	venue.ContactInfo = dto.ContactInfo
@@ -105,7 +107,13 @@ func (s *venueService) UpdateVenue(userID, id string, dto venue_dto.UpdateVenueD
 	venue.Version++
 	venue.UpdatedAt = time.Now()
 
-	if err := s.db.Save(&venue).Error; err != nil {
+	if err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&venue).Error; err != nil {
+			return err
+		}
+		_, err := seat_services.SyncVenueSeats(tx, &venue)
+		return err
+	}); err != nil {
 		return nil, err
 	}
 
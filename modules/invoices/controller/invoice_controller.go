@@ -0,0 +1,108 @@
+package invoice_controller
+
+import (
+	"fmt"
+	"ticket-zetu-api/logs/handler"
+	"ticket-zetu-api/modules/invoices/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type InvoiceController struct {
+	service    invoice_service.InvoiceService
+	logHandler *handler.LogHandler
+}
+
+func NewInvoiceController(service invoice_service.InvoiceService, logHandler *handler.LogHandler) *InvoiceController {
+	return &InvoiceController{
+		service:    service,
+		logHandler: logHandler,
+	}
+}
+
+// GetOrderInvoices godoc
+// @Summary Get an order's receipts and invoices
+// @Description Lists the documents issued for a paid order. Buyers see their receipt; the event's organizer also sees the platform's commission invoice.
+// @Tags Invoices
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param order_id path string true "Order ID"
+// @Success 200 {object} map[string]interface{} "Invoices retrieved successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid order ID"
+// @Failure 404 {object} map[string]interface{} "Order not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /invoices/orders/{order_id} [get]
+func (c *InvoiceController) GetOrderInvoices(ctx *fiber.Ctx) error {
+	userID := ctx.Locals("user_id").(string)
+	orderID := ctx.Params("order_id")
+
+	invoices, err := c.service.GetOrderInvoices(userID, orderID)
+	if err != nil {
+		return c.handleError(ctx, err)
+	}
+
+	return c.logHandler.LogSuccess(ctx, invoices, "Invoices retrieved successfully", false)
+}
+
+// GetOrganizerInvoices godoc
+// @Summary Get the organizer's receipts and commission invoices
+// @Description Lists the receipts the organizer has issued to buyers and the commission invoices the platform has issued to the organizer, newest first. Each kind is numbered in its own gap-free sequence.
+// @Tags Invoices
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param kind query string false "Filter by kind (receipt, commission)"
+// @Success 200 {object} map[string]interface{} "Invoices retrieved successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid invoice kind"
+// @Failure 404 {object} map[string]interface{} "Organizer not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /invoices/organizer [get]
+func (c *InvoiceController) GetOrganizerInvoices(ctx *fiber.Ctx) error {
+	userID := ctx.Locals("user_id").(string)
+
+	invoices, err := c.service.GetOrganizerInvoices(userID, ctx.Query("kind"))
+	if err != nil {
+		return c.handleError(ctx, err)
+	}
+
+	return c.logHandler.LogSuccess(ctx, invoices, "Invoices retrieved successfully", false)
+}
+
+// DownloadInvoice godoc
+// @Summary Download a receipt or invoice
+// @Description Returns a receipt or commission invoice as a PDF.
+// @Tags Invoices
+// @Produce application/pdf
+// @Security ApiKeyAuth
+// @Param id path string true "Invoice ID"
+// @Success 200 {file} file "Invoice PDF"
+// @Failure 400 {object} map[string]interface{} "Invalid invoice ID"
+// @Failure 404 {object} map[string]interface{} "Invoice not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /invoices/{id}/download [get]
+func (c *InvoiceController) DownloadInvoice(ctx *fiber.Ctx) error {
+	userID := ctx.Locals("user_id").(string)
+	id := ctx.Params("id")
+
+	document, err := c.service.DownloadInvoice(userID, id)
+	if err != nil {
+		return c.handleError(ctx, err)
+	}
+
+	ctx.Set(fiber.HeaderContentType, "application/pdf")
+	ctx.Set(fiber.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", document.Filename))
+	ctx.Set(fiber.HeaderCacheControl, "private, no-store")
+	return ctx.Send(document.Content)
+}
+
+func (c *InvoiceController) handleError(ctx *fiber.Ctx, err error) error {
+	switch err.Error() {
+	case "invalid order ID format", "invalid invoice ID format", "invalid invoice kind":
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, err.Error()), fiber.StatusBadRequest)
+	case "order not found", "invoice not found", "organizer not found":
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusNotFound, err.Error()), fiber.StatusNotFound)
+	default:
+		return c.logHandler.LogError(ctx, err, fiber.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,47 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// InvoiceLineResponse defines one line item of an invoice
+type InvoiceLineResponse struct {
+	Description string          `json:"description"`
+	Quantity    int             `json:"quantity"`
+	UnitPrice   decimal.Decimal `json:"unit_price" swaggertype:"string"`
+	Amount      decimal.Decimal `json:"amount" swaggertype:"string"`
+}
+
+// InvoiceResponse defines the response structure for a receipt or commission invoice
+type InvoiceResponse struct {
+	ID                   string                `json:"id"`
+	Kind                 string                `json:"kind"`
+	Number               string                `json:"number"`
+	OrganizerID          string                `json:"organizer_id"`
+	OrderID              string                `json:"order_id"`
+	IssuerName           string                `json:"issuer_name"`
+	IssuerCompanyName    string                `json:"issuer_company_name,omitempty"`
+	IssuerTaxID          string                `json:"issuer_tax_id,omitempty"`
+	RecipientName        string                `json:"recipient_name"`
+	RecipientEmail       string                `json:"recipient_email,omitempty"`
+	RecipientCompanyName string                `json:"recipient_company_name,omitempty"`
+	RecipientTaxID       string                `json:"recipient_tax_id,omitempty"`
+	Currency             string                `json:"currency"`
+	Lines                []InvoiceLineResponse `json:"lines"`
+	Subtotal             decimal.Decimal       `json:"subtotal" swaggertype:"string"`
+	DiscountAmount       decimal.Decimal       `json:"discount_amount" swaggertype:"string"`
+	FeeAmount            decimal.Decimal       `json:"fee_amount" swaggertype:"string"`
+	TaxRate              decimal.Decimal       `json:"tax_rate" swaggertype:"string"`
+	TaxAmount            decimal.Decimal       `json:"tax_amount" swaggertype:"string"`
+	Total                decimal.Decimal       `json:"total" swaggertype:"string"`
+	IssuedAt             time.Time             `json:"issued_at"`
+	EmailedAt            *time.Time            `json:"emailed_at,omitempty"`
+}
+
+// InvoiceDocument is a rendered invoice ready to download
+type InvoiceDocument struct {
+	Filename string
+	Content  []byte
+}
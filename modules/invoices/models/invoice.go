@@ -0,0 +1,120 @@
+package invoices
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+type InvoiceKind string
+
+const (
+	// KindReceipt is the organizer's receipt to the buyer of an order
+	KindReceipt InvoiceKind = "receipt"
+	// KindCommission is the platform's invoice to the organizer for its commission on an order
+	KindCommission InvoiceKind = "commission"
+)
+
+// Prefix starts the numbers of invoices of the kind
+func (k InvoiceKind) Prefix() string {
+	if k == KindCommission {
+		return "COM"
+	}
+	return "RCT"
+}
+
+// InvoiceLine is one line item of an invoice
+type InvoiceLine struct {
+	Description string          `json:"description"`
+	Quantity    int             `json:"quantity"`
+	UnitPrice   decimal.Decimal `json:"unit_price"`
+	Amount      decimal.Decimal `json:"amount"`
+}
+
+// Invoice is an issued receipt or invoice for an order. Parties and amounts
+// are copied when it is issued, so it reads the same after the organizer,
+// buyer or order change. Amounts include tax; TaxAmount is the part of Total
+// that is tax.
+type Invoice struct {
+	ID          string      `gorm:"type:char(36);primaryKey" json:"id"`
+	OrganizerID string      `gorm:"type:char(36);not null;uniqueIndex:idx_invoice_sequence,priority:1" json:"organizer_id"`
+	Kind        InvoiceKind `gorm:"type:varchar(20);not null;uniqueIndex:idx_invoice_sequence,priority:2;uniqueIndex:idx_invoice_order,priority:2;check:kind IN ('receipt','commission')" json:"kind"`
+	// Sequence numbers an organizer's invoices of a kind from 1 with no gaps
+	Sequence int    `gorm:"not null;uniqueIndex:idx_invoice_sequence,priority:3" json:"sequence"`
+	Number   string `gorm:"type:varchar(40);not null;uniqueIndex" json:"number"`
+	OrderID  string `gorm:"type:char(36);not null;uniqueIndex:idx_invoice_order,priority:1" json:"order_id"`
+	UserID   string `gorm:"type:char(36);not null;index" json:"user_id"`
+
+	IssuerName           string `gorm:"type:varchar(255);not null" json:"issuer_name"`
+	IssuerCompanyName    string `gorm:"type:varchar(255)" json:"issuer_company_name,omitempty"`
+	IssuerTaxID          string `gorm:"type:varchar(100)" json:"issuer_tax_id,omitempty"`
+	RecipientName        string `gorm:"type:varchar(255);not null" json:"recipient_name"`
+	RecipientEmail       string `gorm:"type:varchar(255)" json:"recipient_email,omitempty"`
+	RecipientCompanyName string `gorm:"type:varchar(255)" json:"recipient_company_name,omitempty"`
+	RecipientTaxID       string `gorm:"type:varchar(100)" json:"recipient_tax_id,omitempty"`
+
+	Currency       string          `gorm:"type:varchar(3);not null" json:"currency"`
+	Lines          []InvoiceLine   `gorm:"type:json;serializer:json;not null" json:"lines"`
+	Subtotal       decimal.Decimal `gorm:"type:numeric(14,2);not null" json:"subtotal"`
+	DiscountAmount decimal.Decimal `gorm:"type:numeric(14,2);not null;default:0" json:"discount_amount"`
+	FeeAmount      decimal.Decimal `gorm:"type:numeric(14,2);not null;default:0" json:"fee_amount"`
+	TaxRate        decimal.Decimal `gorm:"type:numeric(5,2);not null;default:0" json:"tax_rate"`
+	TaxAmount      decimal.Decimal `gorm:"type:numeric(14,2);not null;default:0" json:"tax_amount"`
+	Total          decimal.Decimal `gorm:"type:numeric(14,2);not null" json:"total"`
+
+	// Document is the rendered PDF, filled in after the invoice is issued
+	Document   []byte     `gorm:"type:mediumblob" json:"-"`
+	RenderedAt *time.Time `json:"rendered_at,omitempty"`
+	EmailedAt  *time.Time `json:"emailed_at,omitempty"`
+	IssuedAt   time.Time  `gorm:"not null" json:"issued_at"`
+	CreatedAt  time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt  time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// InvoiceNumber formats an organizer's invoice number, for example RCT-1A2B3C4D-000042
+func InvoiceNumber(kind InvoiceKind, organizerID string, sequence int) string {
+	code := strings.ToUpper(strings.ReplaceAll(organizerID, "-", ""))
+	if len(code) > 8 {
+		code = code[:8]
+	}
+	return fmt.Sprintf("%s-%s-%06d", kind.Prefix(), code, sequence)
+}
+
+func (i *Invoice) BeforeCreate(tx *gorm.DB) error {
+	if i.ID == "" {
+		i.ID = uuid.New().String()
+	}
+	if i.OrganizerID == "" || i.OrderID == "" || i.Number == "" || i.Currency == "" {
+		return errors.New("organizer_id, order_id, number and currency cannot be empty")
+	}
+	if i.Sequence <= 0 {
+		return errors.New("sequence must be greater than 0")
+	}
+	if i.Total.IsNegative() {
+		return errors.New("total cannot be negative")
+	}
+	return nil
+}
+
+func (Invoice) TableName() string {
+	return "invoices"
+}
+
+// InvoiceSequence holds the last number issued to an organizer's invoices of
+// a kind. It is locked and advanced in the transaction that issues the
+// invoice, so a rolled back invoice also rolls back its number.
+type InvoiceSequence struct {
+	OrganizerID string      `gorm:"type:char(36);primaryKey" json:"organizer_id"`
+	Kind        InvoiceKind `gorm:"type:varchar(20);primaryKey" json:"kind"`
+	LastNumber  int         `gorm:"not null;default:0" json:"last_number"`
+	UpdatedAt   time.Time   `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (InvoiceSequence) TableName() string {
+	return "invoice_sequences"
+}
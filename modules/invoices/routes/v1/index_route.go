@@ -0,0 +1,13 @@
+package routes
+
+import (
+	"ticket-zetu-api/logs/handler"
+	mail_service "ticket-zetu-api/modules/users/authentication/mail"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+func SetupInvoiceMainRoutes(router fiber.Router, db *gorm.DB, logHandler *handler.LogHandler, emailService mail_service.EmailService) {
+	SetupInvoiceRoutes(router, db, logHandler, emailService)
+}
@@ -0,0 +1,28 @@
+package routes
+
+import (
+	"ticket-zetu-api/logs/handler"
+	invoice_controller "ticket-zetu-api/modules/invoices/controller"
+	invoice_service "ticket-zetu-api/modules/invoices/service"
+	mail_service "ticket-zetu-api/modules/users/authentication/mail"
+	"ticket-zetu-api/modules/users/authorization/service"
+	"ticket-zetu-api/modules/users/middleware"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+func SetupInvoiceRoutes(router fiber.Router, db *gorm.DB, logHandler *handler.LogHandler, emailService mail_service.EmailService) {
+	authMiddleware := middleware.IsAuthenticated(db, logHandler)
+	authService := authorization_service.NewPermissionService(db)
+
+	invoiceService := invoice_service.NewInvoiceService(db, authService, emailService)
+	invoiceController := invoice_controller.NewInvoiceController(invoiceService, logHandler)
+
+	invoiceGroup := router.Group("/invoices", authMiddleware)
+	{
+		invoiceGroup.Get("/organizer", invoiceController.GetOrganizerInvoices)
+		invoiceGroup.Get("/orders/:order_id", invoiceController.GetOrderInvoices)
+		invoiceGroup.Get("/:id/download", invoiceController.DownloadInvoice)
+	}
+}
@@ -0,0 +1,256 @@
+package invoice_service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"ticket-zetu-api/modules/events/models/events"
+	"ticket-zetu-api/modules/invoices/dto"
+	invoices "ticket-zetu-api/modules/invoices/models"
+	organizers_services "ticket-zetu-api/modules/organizers/services"
+	"ticket-zetu-api/modules/tickets/models/tickets"
+	mail_service "ticket-zetu-api/modules/users/authentication/mail"
+	"ticket-zetu-api/modules/users/authorization/service"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type InvoiceService interface {
+	GetOrderInvoices(userID, orderID string) ([]dto.InvoiceResponse, error)
+	GetOrganizerInvoices(userID, kind string) ([]dto.InvoiceResponse, error)
+	DownloadInvoice(userID, invoiceID string) (*dto.InvoiceDocument, error)
+	DeliverPending(limit int) (int, error)
+}
+
+type invoiceService struct {
+	db                   *gorm.DB
+	authorizationService authorization_service.PermissionService
+	emailService         mail_service.EmailService
+}
+
+func NewInvoiceService(db *gorm.DB, authService authorization_service.PermissionService, emailService mail_service.EmailService) InvoiceService {
+	return &invoiceService{
+		db:                   db,
+		authorizationService: authService,
+		emailService:         emailService,
+	}
+}
+
+// GetOrderInvoices lists the invoices of an order the user can see: the
+// buyer gets their receipt, the organizer also gets its commission invoice
+func (s *invoiceService) GetOrderInvoices(userID, orderID string) ([]dto.InvoiceResponse, error) {
+	if _, err := uuid.Parse(orderID); err != nil {
+		return nil, errors.New("invalid order ID format")
+	}
+
+	var order tickets.Order
+	if err := s.db.Where("id = ?", orderID).First(&order).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("order not found")
+		}
+		return nil, err
+	}
+
+	query := s.db.Where("order_id = ?", order.ID)
+	ownsOrganizer, err := s.ownsEventOrganizer(userID, order.EventID)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case ownsOrganizer:
+	case order.UserID == userID:
+		query = query.Where("kind = ?", invoices.KindReceipt)
+	default:
+		return nil, errors.New("order not found")
+	}
+
+	var issued []invoices.Invoice
+	if err := query.Omit("document").Order("issued_at ASC").Find(&issued).Error; err != nil {
+		return nil, err
+	}
+	return toInvoiceDTOs(issued), nil
+}
+
+// GetOrganizerInvoices lists the receipts issued by, or commission invoices
+// issued to, the user's organizer, newest first
+func (s *invoiceService) GetOrganizerInvoices(userID, kind string) ([]dto.InvoiceResponse, error) {
+	organizer, err := organizers_services.GetUserOrganizer(s.db, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	query := s.db.Where("organizer_id = ?", organizer.ID)
+	if kind != "" {
+		if kind != string(invoices.KindReceipt) && kind != string(invoices.KindCommission) {
+			return nil, errors.New("invalid invoice kind")
+		}
+		query = query.Where("kind = ?", kind)
+	}
+
+	var issued []invoices.Invoice
+	if err := query.Omit("document").Order("issued_at DESC, sequence DESC").Find(&issued).Error; err != nil {
+		return nil, err
+	}
+	return toInvoiceDTOs(issued), nil
+}
+
+// DownloadInvoice returns an invoice's PDF, rendering it first if the
+// delivery job has not got to it yet
+func (s *invoiceService) DownloadInvoice(userID, invoiceID string) (*dto.InvoiceDocument, error) {
+	if _, err := uuid.Parse(invoiceID); err != nil {
+		return nil, errors.New("invalid invoice ID format")
+	}
+
+	var invoice invoices.Invoice
+	if err := s.db.Where("id = ?", invoiceID).First(&invoice).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("invoice not found")
+		}
+		return nil, err
+	}
+
+	if invoice.Kind != invoices.KindReceipt || invoice.UserID != userID {
+		organizer, err := organizers_services.GetUserOrganizer(s.db, userID)
+		if err != nil || organizer.ID != invoice.OrganizerID {
+			return nil, errors.New("invoice not found")
+		}
+	}
+
+	if err := s.render(&invoice); err != nil {
+		return nil, err
+	}
+	return &dto.InvoiceDocument{
+		Filename: invoice.Number + ".pdf",
+		Content:  invoice.Document,
+	}, nil
+}
+
+// DeliverPending renders and emails up to limit issued invoices that have
+// not been sent yet, and reports how many it queued. An invoice whose email
+// cannot be queued is tried again on the next run.
+func (s *invoiceService) DeliverPending(limit int) (int, error) {
+	var pending []invoices.Invoice
+	if err := s.db.Where("emailed_at IS NULL AND recipient_email <> ''").
+		Order("issued_at ASC").
+		Limit(limit).
+		Find(&pending).Error; err != nil {
+		return 0, err
+	}
+
+	delivered := 0
+	for i := range pending {
+		invoice := &pending[i]
+		if err := s.render(invoice); err != nil {
+			log.Printf("Failed to render invoice %s: %v", invoice.Number, err)
+			continue
+		}
+
+		title := "Receipt"
+		if invoice.Kind == invoices.KindCommission {
+			title = "Commission Invoice"
+		}
+		description := fmt.Sprintf("order %s", invoice.OrderID)
+		if len(invoice.Lines) > 0 {
+			description = invoice.Lines[0].Description
+		}
+		total := fmt.Sprintf("%s %s", invoice.Currency, invoice.Total.StringFixed(2))
+		if err := s.emailService.SendInvoiceEmail(nil, invoice.RecipientEmail, invoice.RecipientName, title, invoice.Number, description, total, invoice.IssuedAt, invoice.Document); err != nil {
+			// The queue is full; leave the rest for the next run
+			return delivered, err
+		}
+
+		now := time.Now()
+		if err := s.db.Model(&invoices.Invoice{}).Where("id = ?", invoice.ID).
+			UpdateColumns(map[string]interface{}{
+				"emailed_at": now,
+				"updated_at": now,
+			}).Error; err != nil {
+			return delivered, err
+		}
+		delivered++
+	}
+	return delivered, nil
+}
+
+// render fills in an invoice's PDF and stores it, once. Invoices never change
+// after they are issued, so whichever render is stored first is kept.
+func (s *invoiceService) render(invoice *invoices.Invoice) error {
+	if len(invoice.Document) > 0 {
+		return nil
+	}
+	document, err := renderPDF(invoice)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	result := s.db.Model(&invoices.Invoice{}).
+		Where("id = ? AND document IS NULL", invoice.ID).
+		UpdateColumns(map[string]interface{}{
+			"document":    document,
+			"rendered_at": now,
+			"updated_at":  now,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return s.db.Select("document", "rendered_at").Where("id = ?", invoice.ID).First(invoice).Error
+	}
+	invoice.Document = document
+	invoice.RenderedAt = &now
+	return nil
+}
+
+// ownsEventOrganizer reports whether the user runs the organizer of an event
+func (s *invoiceService) ownsEventOrganizer(userID, eventID string) (bool, error) {
+	var count int64
+	err := s.db.Model(&events.Event{}).
+		Joins("JOIN organizers ON organizers.id = events.organizer_id").
+		Where("events.id = ? AND organizers.created_by = ? AND organizers.deleted_at IS NULL", eventID, userID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func toInvoiceDTOs(issued []invoices.Invoice) []dto.InvoiceResponse {
+	responses := make([]dto.InvoiceResponse, len(issued))
+	for i := range issued {
+		invoice := &issued[i]
+		lines := make([]dto.InvoiceLineResponse, len(invoice.Lines))
+		for j, line := range invoice.Lines {
+			lines[j] = dto.InvoiceLineResponse{
+				Description: line.Description,
+				Quantity:    line.Quantity,
+				UnitPrice:   line.UnitPrice,
+				Amount:      line.Amount,
+			}
+		}
+		responses[i] = dto.InvoiceResponse{
+			ID:                   invoice.ID,
+			Kind:                 string(invoice.Kind),
+			Number:               invoice.Number,
+			OrganizerID:          invoice.OrganizerID,
+			OrderID:              invoice.OrderID,
+			IssuerName:           invoice.IssuerName,
+			IssuerCompanyName:    invoice.IssuerCompanyName,
+			IssuerTaxID:          invoice.IssuerTaxID,
+			RecipientName:        invoice.RecipientName,
+			RecipientEmail:       invoice.RecipientEmail,
+			RecipientCompanyName: invoice.RecipientCompanyName,
+			RecipientTaxID:       invoice.RecipientTaxID,
+			Currency:             invoice.Currency,
+			Lines:                lines,
+			Subtotal:             invoice.Subtotal,
+			DiscountAmount:       invoice.DiscountAmount,
+			FeeAmount:            invoice.FeeAmount,
+			TaxRate:              invoice.TaxRate,
+			TaxAmount:            invoice.TaxAmount,
+			Total:                invoice.Total,
+			IssuedAt:             invoice.IssuedAt,
+			EmailedAt:            invoice.EmailedAt,
+		}
+	}
+	return responses
+}
@@ -0,0 +1,198 @@
+package invoice_service
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	invoices "ticket-zetu-api/modules/invoices/models"
+	ledger_service "ticket-zetu-api/modules/ledger/service"
+	organizers "ticket-zetu-api/modules/organizers/models"
+	"ticket-zetu-api/modules/tickets/models/tickets"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// IssueOrderInvoices numbers a paid order's receipt to its buyer and the
+// platform's commission invoice to its organizer. It runs inside the
+// transaction that fulfills the order, so numbers are only used up by orders
+// that are actually paid. Documents are rendered and emailed afterwards by
+// DeliverPending. Orders that already have their invoices are left alone.
+func IssueOrderInvoices(tx *gorm.DB, orderID, currency string) error {
+	var order tickets.Order
+	if err := tx.Preload("Event").Preload("User").Preload("TicketType").
+		Where("id = ?", orderID).First(&order).Error; err != nil {
+		return err
+	}
+	var organizer organizers.Organizer
+	if err := tx.Where("id = ?", order.Event.OrganizerID).First(&organizer).Error; err != nil {
+		return err
+	}
+
+	receipt := buildReceipt(&order, &organizer, currency)
+	if err := issue(tx, receipt); err != nil {
+		return err
+	}
+
+	commission := buildCommissionInvoice(&order, &organizer, currency)
+	if !commission.Total.IsPositive() {
+		return nil
+	}
+	return issue(tx, commission)
+}
+
+func buildReceipt(order *tickets.Order, organizer *organizers.Organizer, currency string) *invoices.Invoice {
+	unitPrice := ledger_service.Amount(order.UnitPrice)
+	subtotal := unitPrice.Mul(decimal.NewFromInt(int64(order.Quantity))).Round(2)
+	discount := ledger_service.Amount(order.DiscountAmount)
+	total := ledger_service.Amount(order.TotalAmount)
+
+	description := fmt.Sprintf("%s - %s", order.Event.Title, order.TicketType.Name)
+	if order.PriceTierName != "" {
+		description += fmt.Sprintf(" (%s)", order.PriceTierName)
+	}
+	taxRate := decimal.NewFromFloat(organizer.TaxRate).Round(2)
+
+	return &invoices.Invoice{
+		OrganizerID:       organizer.ID,
+		Kind:              invoices.KindReceipt,
+		OrderID:           order.ID,
+		UserID:            order.UserID,
+		IssuerName:        organizer.Name,
+		IssuerCompanyName: organizer.CompanyName,
+		IssuerTaxID:       organizer.TaxID,
+		RecipientName:     fullName(order.User.FirstName, order.User.LastName, order.User.Username),
+		RecipientEmail:    order.User.Email,
+		Currency:          currency,
+		Lines: []invoices.InvoiceLine{{
+			Description: description,
+			Quantity:    order.Quantity,
+			UnitPrice:   unitPrice,
+			Amount:      subtotal,
+		}},
+		Subtotal:       subtotal,
+		DiscountAmount: discount,
+		// Anything charged beyond the discounted tickets is a fee
+		FeeAmount: total.Sub(subtotal.Sub(discount)),
+		TaxRate:   taxRate,
+		TaxAmount: includedTax(total, taxRate),
+		Total:     total,
+	}
+}
+
+func buildCommissionInvoice(order *tickets.Order, organizer *organizers.Organizer, currency string) *invoices.Invoice {
+	commission := ledger_service.Commission(ledger_service.Amount(order.TotalAmount), organizer.CommissionRate)
+	issuer := platformIssuer()
+
+	return &invoices.Invoice{
+		OrganizerID:          organizer.ID,
+		Kind:                 invoices.KindCommission,
+		OrderID:              order.ID,
+		UserID:               organizer.CreatedBy,
+		IssuerName:           issuer.name,
+		IssuerCompanyName:    issuer.companyName,
+		IssuerTaxID:          issuer.taxID,
+		RecipientName:        organizer.Name,
+		RecipientEmail:       organizer.Email,
+		RecipientCompanyName: organizer.CompanyName,
+		RecipientTaxID:       organizer.TaxID,
+		Currency:             currency,
+		Lines: []invoices.InvoiceLine{{
+			Description: fmt.Sprintf("Platform commission at %s%% on order %s (%s)",
+				decimal.NewFromFloat(organizer.CommissionRate).String(), order.OrderNumber, order.Event.Title),
+			Quantity:  1,
+			UnitPrice: commission,
+			Amount:    commission,
+		}},
+		Subtotal:       commission,
+		DiscountAmount: decimal.Zero,
+		FeeAmount:      decimal.Zero,
+		TaxRate:        issuer.taxRate,
+		TaxAmount:      includedTax(commission, issuer.taxRate),
+		Total:          commission,
+	}
+}
+
+// issue takes the next number in the organizer's sequence for the invoice's
+// kind and saves the invoice. The sequence row stays locked until the
+// caller's transaction ends, so numbers are handed out one at a time.
+func issue(tx *gorm.DB, invoice *invoices.Invoice) error {
+	var existing int64
+	if err := tx.Model(&invoices.Invoice{}).
+		Where("order_id = ? AND kind = ?", invoice.OrderID, invoice.Kind).
+		Count(&existing).Error; err != nil {
+		return err
+	}
+	if existing > 0 {
+		return nil
+	}
+
+	sequence := invoices.InvoiceSequence{OrganizerID: invoice.OrganizerID, Kind: invoice.Kind}
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&sequence).Error; err != nil {
+		return err
+	}
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("organizer_id = ? AND kind = ?", invoice.OrganizerID, invoice.Kind).
+		First(&sequence).Error; err != nil {
+		return err
+	}
+	next := sequence.LastNumber + 1
+	if err := tx.Model(&invoices.InvoiceSequence{}).
+		Where("organizer_id = ? AND kind = ?", invoice.OrganizerID, invoice.Kind).
+		UpdateColumns(map[string]interface{}{
+			"last_number": next,
+			"updated_at":  time.Now(),
+		}).Error; err != nil {
+		return err
+	}
+
+	invoice.Sequence = next
+	invoice.Number = invoices.InvoiceNumber(invoice.Kind, invoice.OrganizerID, next)
+	invoice.IssuedAt = time.Now()
+	return tx.Create(invoice).Error
+}
+
+type issuer struct {
+	name        string
+	companyName string
+	taxID       string
+	taxRate     decimal.Decimal
+}
+
+// platformIssuer reads the platform's own details for the invoices it issues
+// to organizers
+func platformIssuer() issuer {
+	name := os.Getenv("APP_NAME")
+	if name == "" {
+		name = "Ticket Zetu"
+	}
+	taxRate, err := decimal.NewFromString(os.Getenv("PLATFORM_TAX_RATE"))
+	if err != nil || taxRate.IsNegative() {
+		taxRate = decimal.Zero
+	}
+	return issuer{
+		name:        name,
+		companyName: os.Getenv("PLATFORM_COMPANY_NAME"),
+		taxID:       os.Getenv("PLATFORM_TAX_ID"),
+		taxRate:     taxRate.Round(2),
+	}
+}
+
+// includedTax is the tax contained in a tax-inclusive amount
+func includedTax(amount, rate decimal.Decimal) decimal.Decimal {
+	if !rate.IsPositive() {
+		return decimal.Zero
+	}
+	hundred := decimal.NewFromInt(100)
+	return amount.Sub(amount.Mul(hundred).Div(hundred.Add(rate))).Round(2)
+}
+
+func fullName(firstName, lastName, fallback string) string {
+	name := strings.TrimSpace(firstName + " " + lastName)
+	if name == "" {
+		return fallback
+	}
+	return name
+}
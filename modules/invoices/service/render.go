@@ -0,0 +1,102 @@
+package invoice_service
+
+import (
+	"bytes"
+	"fmt"
+	invoices "ticket-zetu-api/modules/invoices/models"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/shopspring/decimal"
+)
+
+// renderPDF lays an invoice out as a one-page A4 PDF
+func renderPDF(invoice *invoices.Invoice) ([]byte, error) {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetTitle(invoice.Number, true)
+	pdf.SetCreator(invoice.IssuerName, true)
+	pdf.SetMargins(20, 20, 20)
+	pdf.AddPage()
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+
+	title := "RECEIPT"
+	if invoice.Kind == invoices.KindCommission {
+		title = "COMMISSION INVOICE"
+	}
+	pdf.SetFont("Helvetica", "B", 18)
+	pdf.CellFormat(0, 10, title, "", 1, "L", false, 0, "")
+
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.CellFormat(0, 6, tr("Number: "+invoice.Number), "", 1, "L", false, 0, "")
+	pdf.CellFormat(0, 6, "Date: "+invoice.IssuedAt.Format("2006-01-02"), "", 1, "L", false, 0, "")
+	pdf.Ln(6)
+
+	// Issuer and recipient side by side
+	top := pdf.GetY()
+	party(pdf, tr, 20, top, "From", invoice.IssuerName, invoice.IssuerCompanyName, invoice.IssuerTaxID, "")
+	party(pdf, tr, 110, top, "Billed to", invoice.RecipientName, invoice.RecipientCompanyName, invoice.RecipientTaxID, invoice.RecipientEmail)
+	pdf.SetXY(20, top+32)
+
+	pdf.SetFont("Helvetica", "B", 10)
+	pdf.SetFillColor(235, 235, 235)
+	pdf.CellFormat(95, 8, "Description", "B", 0, "L", true, 0, "")
+	pdf.CellFormat(15, 8, "Qty", "B", 0, "R", true, 0, "")
+	pdf.CellFormat(30, 8, "Unit price", "B", 0, "R", true, 0, "")
+	pdf.CellFormat(30, 8, "Amount", "B", 1, "R", true, 0, "")
+
+	pdf.SetFont("Helvetica", "", 10)
+	for _, line := range invoice.Lines {
+		pdf.CellFormat(95, 7, tr(line.Description), "", 0, "L", false, 0, "")
+		pdf.CellFormat(15, 7, fmt.Sprintf("%d", line.Quantity), "", 0, "R", false, 0, "")
+		pdf.CellFormat(30, 7, line.UnitPrice.StringFixed(2), "", 0, "R", false, 0, "")
+		pdf.CellFormat(30, 7, line.Amount.StringFixed(2), "", 1, "R", false, 0, "")
+	}
+	pdf.Ln(4)
+
+	total(pdf, "Subtotal", invoice.Subtotal, invoice.Currency, false)
+	if !invoice.DiscountAmount.IsZero() {
+		total(pdf, "Discount", invoice.DiscountAmount.Neg(), invoice.Currency, false)
+	}
+	total(pdf, "Fees", invoice.FeeAmount, invoice.Currency, false)
+	total(pdf, "Total", invoice.Total, invoice.Currency, true)
+	if invoice.TaxRate.IsPositive() {
+		total(pdf, fmt.Sprintf("Includes tax at %s%%", invoice.TaxRate.String()), invoice.TaxAmount, invoice.Currency, false)
+	} else {
+		total(pdf, "Tax", invoice.TaxAmount, invoice.Currency, false)
+	}
+
+	pdf.Ln(10)
+	pdf.SetFont("Helvetica", "I", 8)
+	pdf.MultiCell(0, 4, tr(fmt.Sprintf("Issued for order %s. All amounts are in %s.", invoice.OrderID, invoice.Currency)), "", "L", false)
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func party(pdf *fpdf.Fpdf, tr func(string) string, x, y float64, heading, name, companyName, taxID, email string) {
+	pdf.SetXY(x, y)
+	pdf.SetFont("Helvetica", "B", 10)
+	pdf.CellFormat(85, 6, heading, "", 2, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 10)
+	for _, line := range []string{name, companyName, email} {
+		if line != "" {
+			pdf.CellFormat(85, 5, tr(line), "", 2, "L", false, 0, "")
+		}
+	}
+	if taxID != "" {
+		pdf.CellFormat(85, 5, tr("Tax ID: "+taxID), "", 2, "L", false, 0, "")
+	}
+}
+
+func total(pdf *fpdf.Fpdf, label string, amount decimal.Decimal, currency string, bold bool) {
+	style := ""
+	if bold {
+		style = "B"
+	}
+	pdf.SetFont("Helvetica", style, 10)
+	pdf.CellFormat(110, 7, "", "", 0, "L", false, 0, "")
+	pdf.CellFormat(30, 7, label, "", 0, "R", false, 0, "")
+	pdf.CellFormat(30, 7, currency+" "+amount.StringFixed(2), "", 1, "R", false, 0, "")
+}
@@ -0,0 +1,236 @@
+package ledger_controller
+
+import (
+	"ticket-zetu-api/logs/handler"
+	"ticket-zetu-api/modules/ledger/dto"
+	"ticket-zetu-api/modules/ledger/service"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+type LedgerController struct {
+	service    ledger_service.LedgerService
+	logHandler *handler.LogHandler
+	validator  *validator.Validate
+}
+
+func NewLedgerController(service ledger_service.LedgerService, logHandler *handler.LogHandler) *LedgerController {
+	return &LedgerController{
+		service:    service,
+		logHandler: logHandler,
+		validator:  validator.New(),
+	}
+}
+
+// GetStatement godoc
+// @Summary Get the organizer's statement
+// @Description Lists every ledger entry posted to the organizer's balance in a period (ticket sales, commission, refunds and payouts) with opening, running and closing balances. The period defaults to the last 30 days and cannot exceed a year.
+// @Tags Ledger
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param from query string false "Start of the period (RFC 3339 or YYYY-MM-DD)"
+// @Param to query string false "End of the period (RFC 3339 or YYYY-MM-DD)"
+// @Param currency query string false "Currency of the statement" default(KES)
+// @Success 200 {object} map[string]interface{} "Statement retrieved successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid period"
+// @Failure 404 {object} map[string]interface{} "Organizer not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /ledger/statement [get]
+func (c *LedgerController) GetStatement(ctx *fiber.Ctx) error {
+	userID := ctx.Locals("user_id").(string)
+
+	statement, err := c.service.GetStatement(userID, ctx.Query("from"), ctx.Query("to"), ctx.Query("currency"))
+	if err != nil {
+		return c.handleError(ctx, err)
+	}
+
+	return c.logHandler.LogSuccess(ctx, statement, "Statement retrieved successfully", false)
+}
+
+// RequestPayout godoc
+// @Summary Request a payout
+// @Description Asks for part of the organizer's available balance to be paid out. Pending requests hold back their amount until an admin approves or rejects them.
+// @Tags Ledger
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param input body dto.PayoutRequestInput true "Payout details"
+// @Success 200 {object} map[string]interface{} "Payout requested successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid request body or insufficient balance"
+// @Failure 404 {object} map[string]interface{} "Organizer not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /ledger/payouts [post]
+func (c *LedgerController) RequestPayout(ctx *fiber.Ctx) error {
+	userID := ctx.Locals("user_id").(string)
+
+	var input dto.PayoutRequestInput
+	if err := ctx.BodyParser(&input); err != nil {
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, "Invalid request body"), fiber.StatusBadRequest)
+	}
+
+	if err := c.validator.Struct(input); err != nil {
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, err.Error()), fiber.StatusBadRequest)
+	}
+
+	payout, err := c.service.RequestPayout(userID, input)
+	if err != nil {
+		return c.handleError(ctx, err)
+	}
+
+	return c.logHandler.LogSuccess(ctx, payout, "Payout requested successfully", true)
+}
+
+// GetPayoutRequests godoc
+// @Summary Get the organizer's payout requests
+// @Description Retrieves the organizer's payout requests, newest first.
+// @Tags Ledger
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} map[string]interface{} "Payout requests retrieved successfully"
+// @Failure 404 {object} map[string]interface{} "Organizer not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /ledger/payouts [get]
+func (c *LedgerController) GetPayoutRequests(ctx *fiber.Ctx) error {
+	userID := ctx.Locals("user_id").(string)
+
+	payouts, err := c.service.GetPayoutRequests(userID)
+	if err != nil {
+		return c.handleError(ctx, err)
+	}
+
+	return c.logHandler.LogSuccess(ctx, payouts, "Payout requests retrieved successfully", false)
+}
+
+// CancelPayoutRequest godoc
+// @Summary Cancel a payout request
+// @Description Withdraws one of the organizer's pending payout requests.
+// @Tags Ledger
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Payout request ID"
+// @Success 200 {object} map[string]interface{} "Payout request canceled successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid payout request ID"
+// @Failure 404 {object} map[string]interface{} "Payout request not found"
+// @Failure 409 {object} map[string]interface{} "Payout request is not pending"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /ledger/payouts/{id} [delete]
+func (c *LedgerController) CancelPayoutRequest(ctx *fiber.Ctx) error {
+	userID := ctx.Locals("user_id").(string)
+	payoutID := ctx.Params("id")
+
+	payout, err := c.service.CancelPayoutRequest(userID, payoutID)
+	if err != nil {
+		return c.handleError(ctx, err)
+	}
+
+	return c.logHandler.LogSuccess(ctx, payout, "Payout request canceled successfully", true)
+}
+
+// ListPayoutRequests godoc
+// @Summary List payout requests for review
+// @Description Lists payout requests from all organizers, oldest first. Requires the manage:payouts permission.
+// @Tags Ledger
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param status query string false "Filter by status (pending, approved, rejected, canceled)"
+// @Success 200 {object} map[string]interface{} "Payout requests retrieved successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid status"
+// @Failure 403 {object} map[string]interface{} "User lacks manage:payouts permission"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /ledger/admin/payouts [get]
+func (c *LedgerController) ListPayoutRequests(ctx *fiber.Ctx) error {
+	userID := ctx.Locals("user_id").(string)
+
+	payouts, err := c.service.ListPayoutRequests(userID, ctx.Query("status"))
+	if err != nil {
+		return c.handleError(ctx, err)
+	}
+
+	return c.logHandler.LogSuccess(ctx, payouts, "Payout requests retrieved successfully", false)
+}
+
+// ApprovePayoutRequest godoc
+// @Summary Approve a payout request
+// @Description Pays out a pending request, posting it against the organizer's balance. Requires the manage:payouts permission.
+// @Tags Ledger
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Payout request ID"
+// @Success 200 {object} map[string]interface{} "Payout request approved successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid payout request ID or insufficient balance"
+// @Failure 403 {object} map[string]interface{} "User lacks manage:payouts permission"
+// @Failure 404 {object} map[string]interface{} "Payout request not found"
+// @Failure 409 {object} map[string]interface{} "Payout request is not pending"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /ledger/admin/payouts/{id}/approve [post]
+func (c *LedgerController) ApprovePayoutRequest(ctx *fiber.Ctx) error {
+	userID := ctx.Locals("user_id").(string)
+	payoutID := ctx.Params("id")
+
+	payout, err := c.service.ApprovePayoutRequest(userID, payoutID)
+	if err != nil {
+		return c.handleError(ctx, err)
+	}
+
+	return c.logHandler.LogSuccess(ctx, payout, "Payout request approved successfully", true)
+}
+
+// RejectPayoutRequest godoc
+// @Summary Reject a payout request
+// @Description Declines a pending payout request with a reason, releasing the amount it held back. Requires the manage:payouts permission.
+// @Tags Ledger
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Payout request ID"
+// @Param input body dto.RejectPayoutInput true "Rejection reason"
+// @Success 200 {object} map[string]interface{} "Payout request rejected successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid request body or payout request ID"
+// @Failure 403 {object} map[string]interface{} "User lacks manage:payouts permission"
+// @Failure 404 {object} map[string]interface{} "Payout request not found"
+// @Failure 409 {object} map[string]interface{} "Payout request is not pending"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /ledger/admin/payouts/{id}/reject [post]
+func (c *LedgerController) RejectPayoutRequest(ctx *fiber.Ctx) error {
+	userID := ctx.Locals("user_id").(string)
+	payoutID := ctx.Params("id")
+
+	var input dto.RejectPayoutInput
+	if err := ctx.BodyParser(&input); err != nil {
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, "Invalid request body"), fiber.StatusBadRequest)
+	}
+
+	if err := c.validator.Struct(input); err != nil {
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, err.Error()), fiber.StatusBadRequest)
+	}
+
+	payout, err := c.service.RejectPayoutRequest(userID, payoutID, input)
+	if err != nil {
+		return c.handleError(ctx, err)
+	}
+
+	return c.logHandler.LogSuccess(ctx, payout, "Payout request rejected successfully", true)
+}
+
+func (c *LedgerController) handleError(ctx *fiber.Ctx, err error) error {
+	switch err.Error() {
+	case "invalid user ID format", "invalid payout request ID format", "invalid date format", "invalid payout status",
+		"statement start must be before its end", "statement period cannot exceed one year",
+		"amount must be greater than zero", "amount cannot have more than two decimal places", "insufficient balance":
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, err.Error()), fiber.StatusBadRequest)
+	case "user lacks manage:payouts permission":
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusForbidden, err.Error()), fiber.StatusForbidden)
+	case "organizer not found", "payout request not found":
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusNotFound, err.Error()), fiber.StatusNotFound)
+	case "payout request is not pending":
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusConflict, err.Error()), fiber.StatusConflict)
+	default:
+		return c.logHandler.LogError(ctx, err, fiber.StatusInternalServerError)
+	}
+}
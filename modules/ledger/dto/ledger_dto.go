@@ -0,0 +1,66 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// StatementEntry defines one line of an organizer's statement. Amount is
+// positive for credits to the organizer and negative for debits.
+type StatementEntry struct {
+	EntryID       string          `json:"entry_id"`
+	TransactionID string          `json:"transaction_id"`
+	Kind          string          `json:"kind"`
+	ReferenceType string          `json:"reference_type"`
+	ReferenceID   string          `json:"reference_id"`
+	Description   string          `json:"description"`
+	Direction     string          `json:"direction"`
+	Amount        decimal.Decimal `json:"amount" swaggertype:"string"`
+	Balance       decimal.Decimal `json:"balance" swaggertype:"string"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
+
+// StatementResponse defines the response structure for an organizer's statement over a period
+type StatementResponse struct {
+	OrganizerID      string           `json:"organizer_id"`
+	Currency         string           `json:"currency"`
+	From             time.Time        `json:"from"`
+	To               time.Time        `json:"to"`
+	OpeningBalance   decimal.Decimal  `json:"opening_balance" swaggertype:"string"`
+	TotalCredits     decimal.Decimal  `json:"total_credits" swaggertype:"string"`
+	TotalDebits      decimal.Decimal  `json:"total_debits" swaggertype:"string"`
+	ClosingBalance   decimal.Decimal  `json:"closing_balance" swaggertype:"string"`
+	CurrentBalance   decimal.Decimal  `json:"current_balance" swaggertype:"string"`
+	PendingPayouts   decimal.Decimal  `json:"pending_payouts" swaggertype:"string"`
+	AvailableBalance decimal.Decimal  `json:"available_balance" swaggertype:"string"`
+	Entries          []StatementEntry `json:"entries"`
+}
+
+// PayoutRequestInput defines the input structure for requesting a payout
+type PayoutRequestInput struct {
+	Amount   decimal.Decimal `json:"amount" example:"5000.00" swaggertype:"string"`
+	Currency string          `json:"currency,omitempty" example:"KES" validate:"omitempty,len=3"`
+	Notes    string          `json:"notes,omitempty" example:"Monthly settlement" validate:"max=1000"`
+}
+
+// RejectPayoutInput defines the input structure for rejecting a payout request
+type RejectPayoutInput struct {
+	Reason string `json:"reason" example:"Bank details could not be verified" validate:"required,min=3,max=1000"`
+}
+
+// PayoutResponse defines the response structure for a payout request
+type PayoutResponse struct {
+	ID              string          `json:"id"`
+	OrganizerID     string          `json:"organizer_id"`
+	Amount          decimal.Decimal `json:"amount" swaggertype:"string"`
+	Currency        string          `json:"currency"`
+	Status          string          `json:"status"`
+	Notes           string          `json:"notes,omitempty"`
+	RequestedBy     string          `json:"requested_by"`
+	ReviewedBy      string          `json:"reviewed_by,omitempty"`
+	ReviewedAt      *time.Time      `json:"reviewed_at,omitempty"`
+	RejectionReason string          `json:"rejection_reason,omitempty"`
+	TransactionID   string          `json:"transaction_id,omitempty"`
+	CreatedAt       time.Time       `json:"created_at"`
+}
@@ -0,0 +1,216 @@
+package ledger
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+var errImmutable = errors.New("ledger entries are immutable")
+
+type AccountType string
+
+const (
+	// AccountPlatformCash is money the platform has collected and not paid out
+	AccountPlatformCash AccountType = "platform_cash"
+	// AccountPlatformRevenue holds commissions and resale fees earned by the platform
+	AccountPlatformRevenue AccountType = "platform_revenue"
+	// AccountOpeningBalances offsets balances carried over from before the ledger existed
+	AccountOpeningBalances AccountType = "opening_balances"
+	// AccountOrganizerPayable is what the platform owes an organizer
+	AccountOrganizerPayable AccountType = "organizer_payable"
+	// AccountSellerPayable is what the platform owes a fan for their resales
+	AccountSellerPayable AccountType = "seller_payable"
+)
+
+// DebitNormal reports whether debits increase the account's balance. Only
+// cash is an asset; every other account grows with credits.
+func (t AccountType) DebitNormal() bool {
+	return t == AccountPlatformCash
+}
+
+type LedgerAccount struct {
+	ID      string      `gorm:"type:char(36);primaryKey" json:"id"`
+	Type    AccountType `gorm:"type:varchar(30);not null;index;check:type IN ('platform_cash','platform_revenue','opening_balances','organizer_payable','seller_payable')" json:"type"`
+	OwnerID *string     `gorm:"type:char(36);index" json:"owner_id,omitempty"`
+	// Key is type, owner and currency, so each owner has one account of a type per currency
+	Key      string `gorm:"column:account_key;type:varchar(80);not null;uniqueIndex" json:"-"`
+	Currency string `gorm:"type:varchar(3);not null" json:"currency"`
+	// Balance caches the sum of the account's entries in its normal direction
+	Balance   decimal.Decimal `gorm:"type:numeric(14,2);not null;default:0" json:"balance"`
+	CreatedAt time.Time       `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time       `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// AccountKey identifies the account of a type held by an owner in a currency
+func AccountKey(accountType AccountType, ownerID, currency string) string {
+	if ownerID == "" {
+		return string(accountType) + ":" + currency
+	}
+	return string(accountType) + ":" + ownerID + ":" + currency
+}
+
+func (a *LedgerAccount) BeforeCreate(tx *gorm.DB) error {
+	if a.ID == "" {
+		a.ID = uuid.New().String()
+	}
+	if a.Key == "" || a.Currency == "" {
+		return errors.New("key and currency cannot be empty")
+	}
+	return nil
+}
+
+func (LedgerAccount) TableName() string {
+	return "ledger_accounts"
+}
+
+type TransactionKind string
+
+const (
+	KindTicketSale         TransactionKind = "ticket_sale"
+	KindCommission         TransactionKind = "commission"
+	KindRefund             TransactionKind = "refund"
+	KindCommissionReversal TransactionKind = "commission_reversal"
+	KindResaleSale         TransactionKind = "resale_sale"
+	KindResaleFee          TransactionKind = "resale_fee"
+	KindResaleRefund       TransactionKind = "resale_refund"
+	KindPayout             TransactionKind = "payout"
+	KindOpeningBalance     TransactionKind = "opening_balance"
+)
+
+// LedgerTransaction is one balanced posting. Its entries' debits and credits
+// always sum to the same amount, and neither it nor its entries ever change.
+type LedgerTransaction struct {
+	ID            string          `gorm:"type:char(36);primaryKey" json:"id"`
+	Kind          TransactionKind `gorm:"type:varchar(30);not null;index" json:"kind"`
+	ReferenceType string          `gorm:"type:varchar(30);not null" json:"reference_type"`
+	ReferenceID   string          `gorm:"type:char(36);not null;index" json:"reference_id"`
+	// Key is kind and reference, so the same event is never posted twice
+	Key         string    `gorm:"column:posting_key;type:varchar(100);not null;uniqueIndex" json:"-"`
+	Currency    string    `gorm:"type:varchar(3);not null" json:"currency"`
+	Description string    `gorm:"type:varchar(255)" json:"description"`
+	CreatedAt   time.Time `gorm:"autoCreateTime;index" json:"created_at"`
+
+	// Relationships
+	Entries []LedgerEntry `gorm:"foreignKey:TransactionID;constraint:OnUpdate:RESTRICT,OnDelete:RESTRICT;" json:"entries,omitempty"`
+}
+
+// PostingKey identifies the transaction of a kind posted for a reference
+func PostingKey(kind TransactionKind, referenceType, referenceID string) string {
+	return string(kind) + ":" + referenceType + ":" + referenceID
+}
+
+func (t *LedgerTransaction) BeforeCreate(tx *gorm.DB) error {
+	if t.ID == "" {
+		t.ID = uuid.New().String()
+	}
+	if t.Kind == "" || t.ReferenceID == "" || t.Currency == "" {
+		return errors.New("kind, reference_id and currency cannot be empty")
+	}
+	t.Key = PostingKey(t.Kind, t.ReferenceType, t.ReferenceID)
+	return nil
+}
+
+func (t *LedgerTransaction) BeforeUpdate(tx *gorm.DB) error {
+	return errImmutable
+}
+
+func (t *LedgerTransaction) BeforeDelete(tx *gorm.DB) error {
+	return errImmutable
+}
+
+func (LedgerTransaction) TableName() string {
+	return "ledger_transactions"
+}
+
+type EntryDirection string
+
+const (
+	Debit  EntryDirection = "debit"
+	Credit EntryDirection = "credit"
+)
+
+type LedgerEntry struct {
+	ID            string          `gorm:"type:char(36);primaryKey" json:"id"`
+	TransactionID string          `gorm:"type:char(36);not null;index" json:"transaction_id"`
+	AccountID     string          `gorm:"type:char(36);not null;index:idx_ledger_entries_account_created" json:"account_id"`
+	Direction     EntryDirection  `gorm:"type:varchar(6);not null;check:direction IN ('debit','credit')" json:"direction"`
+	Amount        decimal.Decimal `gorm:"type:numeric(14,2);not null;check:amount > 0" json:"amount"`
+	CreatedAt     time.Time       `gorm:"autoCreateTime;index:idx_ledger_entries_account_created" json:"created_at"`
+
+	// Relationships
+	Account     LedgerAccount     `gorm:"foreignKey:AccountID;constraint:OnUpdate:RESTRICT,OnDelete:RESTRICT;" json:"-"`
+	Transaction LedgerTransaction `gorm:"foreignKey:TransactionID;constraint:OnUpdate:RESTRICT,OnDelete:RESTRICT;" json:"-"`
+}
+
+func (e *LedgerEntry) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == "" {
+		e.ID = uuid.New().String()
+	}
+	if e.TransactionID == "" || e.AccountID == "" {
+		return errors.New("transaction_id and account_id cannot be empty")
+	}
+	if !e.Amount.IsPositive() {
+		return errors.New("amount must be greater than zero")
+	}
+	return nil
+}
+
+func (e *LedgerEntry) BeforeUpdate(tx *gorm.DB) error {
+	return errImmutable
+}
+
+func (e *LedgerEntry) BeforeDelete(tx *gorm.DB) error {
+	return errImmutable
+}
+
+func (LedgerEntry) TableName() string {
+	return "ledger_entries"
+}
+
+type PayoutStatus string
+
+const (
+	PayoutPending  PayoutStatus = "pending"
+	PayoutApproved PayoutStatus = "approved"
+	PayoutRejected PayoutStatus = "rejected"
+	PayoutCanceled PayoutStatus = "canceled"
+)
+
+// PayoutRequest is an organizer asking for part of their balance to be paid
+// out. Pending requests count against the balance until an admin decides.
+type PayoutRequest struct {
+	ID              string          `gorm:"type:char(36);primaryKey" json:"id"`
+	OrganizerID     string          `gorm:"type:char(36);not null;index" json:"organizer_id"`
+	Amount          decimal.Decimal `gorm:"type:numeric(14,2);not null;check:amount > 0" json:"amount"`
+	Currency        string          `gorm:"type:varchar(3);not null" json:"currency"`
+	Status          PayoutStatus    `gorm:"type:varchar(20);not null;default:'pending';index;check:status IN ('pending','approved','rejected','canceled')" json:"status"`
+	Notes           string          `gorm:"type:text" json:"notes,omitempty"`
+	RequestedBy     string          `gorm:"type:char(36);not null" json:"requested_by"`
+	ReviewedBy      *string         `gorm:"type:char(36)" json:"reviewed_by,omitempty"`
+	ReviewedAt      *time.Time      `json:"reviewed_at,omitempty"`
+	RejectionReason string          `gorm:"type:text" json:"rejection_reason,omitempty"`
+	TransactionID   *string         `gorm:"type:char(36)" json:"transaction_id,omitempty"`
+	CreatedAt       time.Time       `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt       time.Time       `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (p *PayoutRequest) BeforeCreate(tx *gorm.DB) error {
+	if p.ID == "" {
+		p.ID = uuid.New().String()
+	}
+	if p.OrganizerID == "" || p.RequestedBy == "" || p.Currency == "" {
+		return errors.New("organizer_id, requested_by and currency cannot be empty")
+	}
+	if !p.Amount.IsPositive() {
+		return errors.New("amount must be greater than zero")
+	}
+	return nil
+}
+
+func (PayoutRequest) TableName() string {
+	return "payout_requests"
+}
@@ -0,0 +1,12 @@
+package routes
+
+import (
+	"ticket-zetu-api/logs/handler"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+func SetupLedgerMainRoutes(router fiber.Router, db *gorm.DB, logHandler *handler.LogHandler) {
+	SetupLedgerRoutes(router, db, logHandler)
+}
@@ -0,0 +1,31 @@
+package routes
+
+import (
+	"ticket-zetu-api/logs/handler"
+	ledger_controller "ticket-zetu-api/modules/ledger/controller"
+	ledger_service "ticket-zetu-api/modules/ledger/service"
+	"ticket-zetu-api/modules/users/authorization/service"
+	"ticket-zetu-api/modules/users/middleware"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+func SetupLedgerRoutes(router fiber.Router, db *gorm.DB, logHandler *handler.LogHandler) {
+	authMiddleware := middleware.IsAuthenticated(db, logHandler)
+	authService := authorization_service.NewPermissionService(db)
+
+	ledgerService := ledger_service.NewLedgerService(db, authService)
+	ledgerController := ledger_controller.NewLedgerController(ledgerService, logHandler)
+
+	ledgerGroup := router.Group("/ledger", authMiddleware)
+	{
+		ledgerGroup.Get("/statement", ledgerController.GetStatement)
+		ledgerGroup.Get("/payouts", ledgerController.GetPayoutRequests)
+		ledgerGroup.Post("/payouts", ledgerController.RequestPayout)
+		ledgerGroup.Delete("/payouts/:id", ledgerController.CancelPayoutRequest)
+		ledgerGroup.Get("/admin/payouts", ledgerController.ListPayoutRequests)
+		ledgerGroup.Post("/admin/payouts/:id/approve", ledgerController.ApprovePayoutRequest)
+		ledgerGroup.Post("/admin/payouts/:id/reject", ledgerController.RejectPayoutRequest)
+	}
+}
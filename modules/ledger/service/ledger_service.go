@@ -0,0 +1,170 @@
+package ledger_service
+
+import (
+	"errors"
+	"ticket-zetu-api/modules/ledger/dto"
+	ledger "ticket-zetu-api/modules/ledger/models"
+	organizers_services "ticket-zetu-api/modules/organizers/services"
+	"ticket-zetu-api/modules/tickets/models/tickets"
+	"ticket-zetu-api/modules/users/authorization/service"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+const (
+	// defaultStatementPeriod is the span of a statement requested without a start date
+	defaultStatementPeriod = 30 * 24 * time.Hour
+	// maxStatementPeriod bounds how many entries one statement can return
+	maxStatementPeriod = 366 * 24 * time.Hour
+)
+
+type LedgerService interface {
+	GetStatement(userID, from, to, currency string) (*dto.StatementResponse, error)
+	RequestPayout(userID string, input dto.PayoutRequestInput) (*dto.PayoutResponse, error)
+	GetPayoutRequests(userID string) ([]dto.PayoutResponse, error)
+	CancelPayoutRequest(userID, payoutID string) (*dto.PayoutResponse, error)
+	ListPayoutRequests(adminID, status string) ([]dto.PayoutResponse, error)
+	ApprovePayoutRequest(adminID, payoutID string) (*dto.PayoutResponse, error)
+	RejectPayoutRequest(adminID, payoutID string, input dto.RejectPayoutInput) (*dto.PayoutResponse, error)
+}
+
+type ledgerService struct {
+	db                   *gorm.DB
+	authorizationService authorization_service.PermissionService
+}
+
+func NewLedgerService(db *gorm.DB, authService authorization_service.PermissionService) LedgerService {
+	return &ledgerService{
+		db:                   db,
+		authorizationService: authService,
+	}
+}
+
+// GetStatement lists the entries posted to the organizer's account in a
+// period, with running balances. from and to are RFC 3339 times or dates;
+// the period defaults to the last 30 days.
+func (s *ledgerService) GetStatement(userID, from, to, currency string) (*dto.StatementResponse, error) {
+	organizer, err := organizers_services.GetUserOrganizer(s.db, userID)
+	if err != nil {
+		return nil, err
+	}
+	if currency == "" {
+		currency = tickets.DefaultCurrency
+	}
+
+	end := time.Now()
+	if to != "" {
+		if end, err = parseStatementTime(to); err != nil {
+			return nil, err
+		}
+	}
+	start := end.Add(-defaultStatementPeriod)
+	if from != "" {
+		if start, err = parseStatementTime(from); err != nil {
+			return nil, err
+		}
+	}
+	if !start.Before(end) {
+		return nil, errors.New("statement start must be before its end")
+	}
+	if end.Sub(start) > maxStatementPeriod {
+		return nil, errors.New("statement period cannot exceed one year")
+	}
+
+	// Opening the account on first view carries over any pre-ledger balance
+	var acct *ledger.LedgerAccount
+	if err := s.db.Transaction(func(tx *gorm.DB) error {
+		acct, err = account(tx, ledger.AccountOrganizerPayable, organizer.ID, currency)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	opening, err := s.sumEntries(acct.ID, "created_at < ?", start)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []struct {
+		ledger.LedgerEntry
+		Kind          string
+		ReferenceType string
+		ReferenceID   string
+		Description   string
+	}
+	if err := s.db.Model(&ledger.LedgerEntry{}).
+		Select("ledger_entries.*, ledger_transactions.kind, ledger_transactions.reference_type, ledger_transactions.reference_id, ledger_transactions.description").
+		Joins("JOIN ledger_transactions ON ledger_transactions.id = ledger_entries.transaction_id").
+		Where("ledger_entries.account_id = ? AND ledger_entries.created_at >= ? AND ledger_entries.created_at < ?", acct.ID, start, end).
+		Order("ledger_entries.created_at ASC, ledger_entries.id ASC").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	pending, err := pendingPayouts(s.db, organizer.ID, currency)
+	if err != nil {
+		return nil, err
+	}
+
+	statement := &dto.StatementResponse{
+		OrganizerID:      organizer.ID,
+		Currency:         currency,
+		From:             start,
+		To:               end,
+		OpeningBalance:   opening,
+		TotalCredits:     decimal.Zero,
+		TotalDebits:      decimal.Zero,
+		CurrentBalance:   acct.Balance,
+		PendingPayouts:   pending,
+		AvailableBalance: acct.Balance.Sub(pending),
+		Entries:          make([]dto.StatementEntry, len(rows)),
+	}
+	balance := opening
+	for i, row := range rows {
+		amount := row.Amount
+		if row.Direction == ledger.Debit {
+			amount = amount.Neg()
+			statement.TotalDebits = statement.TotalDebits.Add(row.Amount)
+		} else {
+			statement.TotalCredits = statement.TotalCredits.Add(row.Amount)
+		}
+		balance = balance.Add(amount)
+		statement.Entries[i] = dto.StatementEntry{
+			EntryID:       row.ID,
+			TransactionID: row.TransactionID,
+			Kind:          row.Kind,
+			ReferenceType: row.ReferenceType,
+			ReferenceID:   row.ReferenceID,
+			Description:   row.Description,
+			Direction:     string(row.Direction),
+			Amount:        amount,
+			Balance:       balance,
+			CreatedAt:     row.CreatedAt,
+		}
+	}
+	statement.ClosingBalance = balance
+	return statement, nil
+}
+
+// sumEntries totals an organizer account's entries matching a condition, credits less debits
+func (s *ledgerService) sumEntries(accountID, condition string, args ...interface{}) (decimal.Decimal, error) {
+	var total decimal.Decimal
+	err := s.db.Model(&ledger.LedgerEntry{}).
+		Select("COALESCE(SUM(CASE WHEN direction = ? THEN amount ELSE -amount END), 0)", ledger.Credit).
+		Where("account_id = ?", accountID).
+		Where(condition, args...).
+		Scan(&total).Error
+	return total, err
+}
+
+func parseStatementTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, nil
+	}
+	return time.Time{}, errors.New("invalid date format")
+}
@@ -0,0 +1,308 @@
+package ledger_service
+
+import (
+	"errors"
+	"ticket-zetu-api/modules/ledger/dto"
+	ledger "ticket-zetu-api/modules/ledger/models"
+	organizers_services "ticket-zetu-api/modules/organizers/services"
+	"ticket-zetu-api/modules/tickets/models/tickets"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ManagePayoutsPermission lets admins review organizers' payout requests
+const ManagePayoutsPermission = "manage:payouts"
+
+// RequestPayout asks for part of the organizer's available balance, what is
+// left after other pending requests, to be paid out
+func (s *ledgerService) RequestPayout(userID string, input dto.PayoutRequestInput) (*dto.PayoutResponse, error) {
+	organizer, err := organizers_services.GetUserOrganizer(s.db, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !input.Amount.IsPositive() {
+		return nil, errors.New("amount must be greater than zero")
+	}
+	if !input.Amount.Equal(input.Amount.Round(2)) {
+		return nil, errors.New("amount cannot have more than two decimal places")
+	}
+	currency := input.Currency
+	if currency == "" {
+		currency = tickets.DefaultCurrency
+	}
+
+	var payout ledger.PayoutRequest
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		acct, err := account(tx, ledger.AccountOrganizerPayable, organizer.ID, currency)
+		if err != nil {
+			return err
+		}
+		// The account lock serializes requests, so two cannot both spend the same balance
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", acct.ID).First(acct).Error; err != nil {
+			return err
+		}
+		pending, err := pendingPayouts(tx, organizer.ID, currency)
+		if err != nil {
+			return err
+		}
+		if input.Amount.GreaterThan(acct.Balance.Sub(pending)) {
+			return errors.New("insufficient balance")
+		}
+
+		payout = ledger.PayoutRequest{
+			OrganizerID: organizer.ID,
+			Amount:      input.Amount,
+			Currency:    currency,
+			Status:      ledger.PayoutPending,
+			Notes:       input.Notes,
+			RequestedBy: userID,
+		}
+		return tx.Create(&payout).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toPayoutDTO(&payout), nil
+}
+
+// GetPayoutRequests lists the organizer's payout requests, newest first
+func (s *ledgerService) GetPayoutRequests(userID string) ([]dto.PayoutResponse, error) {
+	organizer, err := organizers_services.GetUserOrganizer(s.db, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var payouts []ledger.PayoutRequest
+	if err := s.db.Where("organizer_id = ?", organizer.ID).Order("created_at DESC").Find(&payouts).Error; err != nil {
+		return nil, err
+	}
+	return toPayoutDTOs(payouts), nil
+}
+
+// CancelPayoutRequest withdraws one of the organizer's pending requests
+func (s *ledgerService) CancelPayoutRequest(userID, payoutID string) (*dto.PayoutResponse, error) {
+	if _, err := uuid.Parse(payoutID); err != nil {
+		return nil, errors.New("invalid payout request ID format")
+	}
+	organizer, err := organizers_services.GetUserOrganizer(s.db, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var payout ledger.PayoutRequest
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND organizer_id = ?", payoutID, organizer.ID).
+			First(&payout).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("payout request not found")
+			}
+			return err
+		}
+		if payout.Status != ledger.PayoutPending {
+			return errors.New("payout request is not pending")
+		}
+		payout.Status = ledger.PayoutCanceled
+		return tx.Model(&payout).UpdateColumns(map[string]interface{}{
+			"status":     payout.Status,
+			"updated_at": time.Now(),
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toPayoutDTO(&payout), nil
+}
+
+// ListPayoutRequests lists payout requests across organizers for review,
+// oldest first, optionally filtered by status
+func (s *ledgerService) ListPayoutRequests(adminID, status string) ([]dto.PayoutResponse, error) {
+	if err := s.requireManagePayouts(adminID); err != nil {
+		return nil, err
+	}
+
+	query := s.db.Order("created_at ASC")
+	if status != "" {
+		switch ledger.PayoutStatus(status) {
+		case ledger.PayoutPending, ledger.PayoutApproved, ledger.PayoutRejected, ledger.PayoutCanceled:
+			query = query.Where("status = ?", status)
+		default:
+			return nil, errors.New("invalid payout status")
+		}
+	}
+
+	var payouts []ledger.PayoutRequest
+	if err := query.Find(&payouts).Error; err != nil {
+		return nil, err
+	}
+	return toPayoutDTOs(payouts), nil
+}
+
+// ApprovePayoutRequest pays out a pending request, posting it against the
+// organizer's balance
+func (s *ledgerService) ApprovePayoutRequest(adminID, payoutID string) (*dto.PayoutResponse, error) {
+	if err := s.requireManagePayouts(adminID); err != nil {
+		return nil, err
+	}
+	if _, err := uuid.Parse(payoutID); err != nil {
+		return nil, errors.New("invalid payout request ID format")
+	}
+
+	var payout ledger.PayoutRequest
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockPendingPayout(tx, payoutID, &payout); err != nil {
+			return err
+		}
+
+		payable, err := account(tx, ledger.AccountOrganizerPayable, payout.OrganizerID, payout.Currency)
+		if err != nil {
+			return err
+		}
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", payable.ID).First(payable).Error; err != nil {
+			return err
+		}
+		// Refunds since the request may have eaten into the balance
+		if payout.Amount.GreaterThan(payable.Balance) {
+			return errors.New("insufficient balance")
+		}
+		cash, err := platformAccount(tx, ledger.AccountPlatformCash, payout.Currency)
+		if err != nil {
+			return err
+		}
+
+		if err := post(tx, ledger.KindPayout, "payout_request", payout.ID, payout.Currency, "Payout",
+			leg{payable, ledger.Debit, payout.Amount},
+			leg{cash, ledger.Credit, payout.Amount},
+		); err != nil {
+			return err
+		}
+		var transaction ledger.LedgerTransaction
+		if err := tx.Select("id").
+			Where("posting_key = ?", ledger.PostingKey(ledger.KindPayout, "payout_request", payout.ID)).
+			First(&transaction).Error; err != nil {
+			return err
+		}
+
+		now := time.Now()
+		payout.Status = ledger.PayoutApproved
+		payout.ReviewedBy = &adminID
+		payout.ReviewedAt = &now
+		payout.TransactionID = &transaction.ID
+		return tx.Model(&payout).UpdateColumns(map[string]interface{}{
+			"status":         payout.Status,
+			"reviewed_by":    adminID,
+			"reviewed_at":    now,
+			"transaction_id": transaction.ID,
+			"updated_at":     now,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toPayoutDTO(&payout), nil
+}
+
+// RejectPayoutRequest declines a pending request, releasing the amount it held back
+func (s *ledgerService) RejectPayoutRequest(adminID, payoutID string, input dto.RejectPayoutInput) (*dto.PayoutResponse, error) {
+	if err := s.requireManagePayouts(adminID); err != nil {
+		return nil, err
+	}
+	if _, err := uuid.Parse(payoutID); err != nil {
+		return nil, errors.New("invalid payout request ID format")
+	}
+
+	var payout ledger.PayoutRequest
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockPendingPayout(tx, payoutID, &payout); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		payout.Status = ledger.PayoutRejected
+		payout.ReviewedBy = &adminID
+		payout.ReviewedAt = &now
+		payout.RejectionReason = input.Reason
+		return tx.Model(&payout).UpdateColumns(map[string]interface{}{
+			"status":           payout.Status,
+			"reviewed_by":      adminID,
+			"reviewed_at":      now,
+			"rejection_reason": input.Reason,
+			"updated_at":       now,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toPayoutDTO(&payout), nil
+}
+
+func (s *ledgerService) requireManagePayouts(userID string) error {
+	if _, err := uuid.Parse(userID); err != nil {
+		return errors.New("invalid user ID format")
+	}
+	hasPerm, err := s.authorizationService.HasPermission(userID, ManagePayoutsPermission)
+	if err != nil {
+		return err
+	}
+	if !hasPerm {
+		return errors.New("user lacks manage:payouts permission")
+	}
+	return nil
+}
+
+func lockPendingPayout(tx *gorm.DB, payoutID string, payout *ledger.PayoutRequest) error {
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", payoutID).First(payout).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("payout request not found")
+		}
+		return err
+	}
+	if payout.Status != ledger.PayoutPending {
+		return errors.New("payout request is not pending")
+	}
+	return nil
+}
+
+// pendingPayouts totals the organizer's requests still awaiting review
+func pendingPayouts(tx *gorm.DB, organizerID, currency string) (decimal.Decimal, error) {
+	var total decimal.Decimal
+	err := tx.Model(&ledger.PayoutRequest{}).
+		Select("COALESCE(SUM(amount), 0)").
+		Where("organizer_id = ? AND currency = ? AND status = ?", organizerID, currency, ledger.PayoutPending).
+		Scan(&total).Error
+	return total, err
+}
+
+func toPayoutDTO(payout *ledger.PayoutRequest) *dto.PayoutResponse {
+	response := &dto.PayoutResponse{
+		ID:              payout.ID,
+		OrganizerID:     payout.OrganizerID,
+		Amount:          payout.Amount,
+		Currency:        payout.Currency,
+		Status:          string(payout.Status),
+		Notes:           payout.Notes,
+		RequestedBy:     payout.RequestedBy,
+		ReviewedAt:      payout.ReviewedAt,
+		RejectionReason: payout.RejectionReason,
+		CreatedAt:       payout.CreatedAt,
+	}
+	if payout.ReviewedBy != nil {
+		response.ReviewedBy = *payout.ReviewedBy
+	}
+	if payout.TransactionID != nil {
+		response.TransactionID = *payout.TransactionID
+	}
+	return response
+}
+
+func toPayoutDTOs(payouts []ledger.PayoutRequest) []dto.PayoutResponse {
+	responses := make([]dto.PayoutResponse, len(payouts))
+	for i := range payouts {
+		responses[i] = *toPayoutDTO(&payouts[i])
+	}
+	return responses
+}
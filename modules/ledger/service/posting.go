@@ -0,0 +1,435 @@
+package ledger_service
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"ticket-zetu-api/modules/events/models/events"
+	ledger "ticket-zetu-api/modules/ledger/models"
+	organizers "ticket-zetu-api/modules/organizers/models"
+	"ticket-zetu-api/modules/tickets/models/tickets"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// leg is one side of a posting
+type leg struct {
+	account   *ledger.LedgerAccount
+	direction ledger.EntryDirection
+	amount    decimal.Decimal
+}
+
+// RecordTicketSale posts a paid order: the gross amount is owed to the event's
+// organizer, who then pays the platform its commission at the organizer's
+// current commission rate
+func RecordTicketSale(tx *gorm.DB, orderID, eventID string, gross decimal.Decimal, currency string) error {
+	amount := gross.Round(2)
+	if !amount.IsPositive() {
+		return nil
+	}
+	organizer, err := eventOrganizer(tx, eventID)
+	if err != nil {
+		return err
+	}
+	cash, err := platformAccount(tx, ledger.AccountPlatformCash, currency)
+	if err != nil {
+		return err
+	}
+	revenue, err := platformAccount(tx, ledger.AccountPlatformRevenue, currency)
+	if err != nil {
+		return err
+	}
+	payable, err := account(tx, ledger.AccountOrganizerPayable, organizer.ID, currency)
+	if err != nil {
+		return err
+	}
+
+	if err := post(tx, ledger.KindTicketSale, "order", orderID, currency, "Ticket sale",
+		leg{cash, ledger.Debit, amount},
+		leg{payable, ledger.Credit, amount},
+	); err != nil {
+		return err
+	}
+
+	commission := Commission(amount, organizer.CommissionRate)
+	return post(tx, ledger.KindCommission, "order", orderID, currency,
+		fmt.Sprintf("Platform commission at %s%%", decimal.NewFromFloat(organizer.CommissionRate).String()),
+		leg{payable, ledger.Debit, commission},
+		leg{revenue, ledger.Credit, commission},
+	)
+}
+
+// RecordRefund posts a completed refund against the organizer of the refunded
+// order. The platform returns the same share of its commission that it took
+// on the sale, so a full refund leaves both sides where they started.
+func RecordRefund(tx *gorm.DB, refundID, orderID, eventID string, refunded decimal.Decimal) error {
+	amount := refunded.Round(2)
+	if !amount.IsPositive() {
+		return nil
+	}
+
+	sale, err := postedLegs(tx, ledger.KindTicketSale, "order", orderID)
+	if err != nil {
+		return err
+	}
+	if len(sale) == 0 {
+		return recordPreLedgerRefund(tx, refundID, eventID, amount)
+	}
+	currency := sale[0].account.Currency
+	gross := sale[0].amount
+
+	commission := decimal.Zero
+	commissionLegs, err := postedLegs(tx, ledger.KindCommission, "order", orderID)
+	if err != nil {
+		return err
+	}
+	if len(commissionLegs) > 0 && gross.IsPositive() {
+		commission = commissionLegs[0].amount.Mul(amount).Div(gross).Round(2)
+	}
+
+	organizer, err := eventOrganizer(tx, eventID)
+	if err != nil {
+		return err
+	}
+	cash, err := platformAccount(tx, ledger.AccountPlatformCash, currency)
+	if err != nil {
+		return err
+	}
+	revenue, err := platformAccount(tx, ledger.AccountPlatformRevenue, currency)
+	if err != nil {
+		return err
+	}
+	payable, err := account(tx, ledger.AccountOrganizerPayable, organizer.ID, currency)
+	if err != nil {
+		return err
+	}
+
+	if err := post(tx, ledger.KindRefund, "refund", refundID, currency, "Ticket refund",
+		leg{payable, ledger.Debit, amount},
+		leg{cash, ledger.Credit, amount},
+	); err != nil {
+		return err
+	}
+	return post(tx, ledger.KindCommissionReversal, "refund", refundID, currency, "Commission returned on refund",
+		leg{revenue, ledger.Debit, commission},
+		leg{payable, ledger.Credit, commission},
+	)
+}
+
+// recordPreLedgerRefund posts a refund of an order paid before the ledger
+// existed. Its sale was credited to Organizer.Balance net of commission, and
+// that balance became the organizer's opening balance, so the organizer
+// returns the same net share and the platform the commission it kept.
+func recordPreLedgerRefund(tx *gorm.DB, refundID, eventID string, amount decimal.Decimal) error {
+	organizer, err := eventOrganizer(tx, eventID)
+	if err != nil {
+		return err
+	}
+	// Pre-ledger balances were only kept in the default currency
+	currency := tickets.DefaultCurrency
+	cash, err := platformAccount(tx, ledger.AccountPlatformCash, currency)
+	if err != nil {
+		return err
+	}
+	revenue, err := platformAccount(tx, ledger.AccountPlatformRevenue, currency)
+	if err != nil {
+		return err
+	}
+	payable, err := account(tx, ledger.AccountOrganizerPayable, organizer.ID, currency)
+	if err != nil {
+		return err
+	}
+
+	commission := Commission(amount, organizer.CommissionRate)
+	return post(tx, ledger.KindRefund, "refund", refundID, currency, "Refund of a sale paid before the ledger",
+		leg{payable, ledger.Debit, amount.Sub(commission)},
+		leg{revenue, ledger.Debit, commission},
+		leg{cash, ledger.Credit, amount},
+	)
+}
+
+// RecordResaleSale posts a completed resale: the price is owed to the seller,
+// less the platform's resale fee
+func RecordResaleSale(tx *gorm.DB, listingID, sellerID string, price, fee decimal.Decimal, currency string) error {
+	amount := price.Round(2)
+	if !amount.IsPositive() {
+		return nil
+	}
+	cash, err := platformAccount(tx, ledger.AccountPlatformCash, currency)
+	if err != nil {
+		return err
+	}
+	revenue, err := platformAccount(tx, ledger.AccountPlatformRevenue, currency)
+	if err != nil {
+		return err
+	}
+	payable, err := account(tx, ledger.AccountSellerPayable, sellerID, currency)
+	if err != nil {
+		return err
+	}
+
+	if err := post(tx, ledger.KindResaleSale, "resale", listingID, currency, "Ticket resale",
+		leg{cash, ledger.Debit, amount},
+		leg{payable, ledger.Credit, amount},
+	); err != nil {
+		return err
+	}
+	return post(tx, ledger.KindResaleFee, "resale", listingID, currency, "Resale platform fee",
+		leg{payable, ledger.Debit, fee.Round(2)},
+		leg{revenue, ledger.Credit, fee.Round(2)},
+	)
+}
+
+// RecordResaleRefund posts a resale refunded to its buyer in full: the seller
+// gives back what they were owed and the platform its fee. A seller who was
+// already paid is left owing the platform. Resales posted before the ledger
+// existed have nothing to reverse.
+func RecordResaleRefund(tx *gorm.DB, refundID, listingID string) error {
+	sale, err := postedLegs(tx, ledger.KindResaleSale, "resale", listingID)
+	if err != nil || len(sale) == 0 {
+		return err
+	}
+	price := sale[0].amount
+	fee := decimal.Zero
+	feeLegs, err := postedLegs(tx, ledger.KindResaleFee, "resale", listingID)
+	if err != nil {
+		return err
+	}
+	if len(feeLegs) > 0 {
+		fee = feeLegs[0].amount
+	}
+
+	var payable, cash *ledger.LedgerAccount
+	for _, l := range sale {
+		if l.direction == ledger.Credit {
+			payable = l.account
+		} else {
+			cash = l.account
+		}
+	}
+	revenue, err := platformAccount(tx, ledger.AccountPlatformRevenue, cash.Currency)
+	if err != nil {
+		return err
+	}
+
+	return post(tx, ledger.KindResaleRefund, "refund", refundID, cash.Currency, "Resale refunded to buyer",
+		leg{payable, ledger.Debit, price.Sub(fee)},
+		leg{revenue, ledger.Debit, fee},
+		leg{cash, ledger.Credit, price},
+	)
+}
+
+// post writes a balanced transaction and applies it to the cached balances of
+// its accounts. Legs with a zero amount are dropped. Posting the same kind of
+// transaction for the same reference again does nothing, so callers can
+// safely retry.
+func post(tx *gorm.DB, kind ledger.TransactionKind, referenceType, referenceID, currency, description string, legs ...leg) error {
+	debits, credits := decimal.Zero, decimal.Zero
+	kept := legs[:0]
+	for _, l := range legs {
+		if l.amount.IsNegative() {
+			return errors.New("ledger amounts cannot be negative")
+		}
+		if l.amount.IsZero() {
+			continue
+		}
+		if l.account.Currency != currency {
+			return errors.New("ledger accounts must share the transaction currency")
+		}
+		if l.direction == ledger.Debit {
+			debits = debits.Add(l.amount)
+		} else {
+			credits = credits.Add(l.amount)
+		}
+		kept = append(kept, l)
+	}
+	if !debits.Equal(credits) {
+		return errors.New("ledger transaction is not balanced")
+	}
+	if len(kept) == 0 {
+		return nil
+	}
+
+	transaction := ledger.LedgerTransaction{
+		Kind:          kind,
+		ReferenceType: referenceType,
+		ReferenceID:   referenceID,
+		Currency:      currency,
+		Description:   description,
+	}
+	result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&transaction)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil
+	}
+
+	entries := make([]ledger.LedgerEntry, len(kept))
+	for i, l := range kept {
+		entries[i] = ledger.LedgerEntry{
+			TransactionID: transaction.ID,
+			AccountID:     l.account.ID,
+			Direction:     l.direction,
+			Amount:        l.amount,
+		}
+	}
+	if err := tx.Create(&entries).Error; err != nil {
+		return err
+	}
+
+	// Accounts are updated in ID order so concurrent postings cannot deadlock
+	sort.SliceStable(kept, func(i, j int) bool { return kept[i].account.ID < kept[j].account.ID })
+	for _, l := range kept {
+		if err := applyLeg(tx, l); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyLeg moves an account's cached balance by one leg, and mirrors an
+// organizer's default-currency balance onto Organizer.Balance
+func applyLeg(tx *gorm.DB, l leg) error {
+	delta := l.amount
+	if (l.direction == ledger.Debit) != l.account.Type.DebitNormal() {
+		delta = delta.Neg()
+	}
+
+	var current ledger.LedgerAccount
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", l.account.ID).
+		First(&current).Error; err != nil {
+		return err
+	}
+	balance := current.Balance.Add(delta)
+	now := time.Now()
+	if err := tx.Model(&current).UpdateColumns(map[string]interface{}{
+		"balance":    balance,
+		"updated_at": now,
+	}).Error; err != nil {
+		return err
+	}
+	l.account.Balance = balance
+
+	if current.Type != ledger.AccountOrganizerPayable || current.Currency != tickets.DefaultCurrency || current.OwnerID == nil {
+		return nil
+	}
+	return tx.Model(&organizers.Organizer{}).
+		Where("id = ?", *current.OwnerID).
+		UpdateColumns(map[string]interface{}{
+			"balance":    balance,
+			"updated_at": now,
+		}).Error
+}
+
+// account loads, creating it on first use, the account of a type held by an
+// owner. An organizer's first account in the default currency opens with the
+// balance the organizer carried from before the ledger.
+func account(tx *gorm.DB, accountType ledger.AccountType, ownerID, currency string) (*ledger.LedgerAccount, error) {
+	key := ledger.AccountKey(accountType, ownerID, currency)
+	created := ledger.LedgerAccount{
+		Type:     accountType,
+		Key:      key,
+		Currency: currency,
+	}
+	if ownerID != "" {
+		created.OwnerID = &ownerID
+	}
+	result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&created)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	var acct ledger.LedgerAccount
+	if err := tx.Where("account_key = ?", key).First(&acct).Error; err != nil {
+		return nil, err
+	}
+
+	if result.RowsAffected == 1 && accountType == ledger.AccountOrganizerPayable && currency == tickets.DefaultCurrency {
+		if err := openOrganizerAccount(tx, &acct); err != nil {
+			return nil, err
+		}
+	}
+	return &acct, nil
+}
+
+func platformAccount(tx *gorm.DB, accountType ledger.AccountType, currency string) (*ledger.LedgerAccount, error) {
+	return account(tx, accountType, "", currency)
+}
+
+// openOrganizerAccount carries an organizer's pre-ledger balance into their
+// new account, so the cached balance and the ledger agree from the start
+func openOrganizerAccount(tx *gorm.DB, acct *ledger.LedgerAccount) error {
+	var organizer organizers.Organizer
+	if err := tx.Select("id", "balance").Where("id = ?", *acct.OwnerID).First(&organizer).Error; err != nil {
+		return err
+	}
+	opening := organizer.Balance.Round(2)
+	if opening.IsZero() {
+		return nil
+	}
+
+	equity, err := platformAccount(tx, ledger.AccountOpeningBalances, acct.Currency)
+	if err != nil {
+		return err
+	}
+	if opening.IsPositive() {
+		return post(tx, ledger.KindOpeningBalance, "organizer", organizer.ID, acct.Currency, "Opening balance",
+			leg{equity, ledger.Debit, opening},
+			leg{acct, ledger.Credit, opening},
+		)
+	}
+	return post(tx, ledger.KindOpeningBalance, "organizer", organizer.ID, acct.Currency, "Opening balance",
+		leg{acct, ledger.Debit, opening.Neg()},
+		leg{equity, ledger.Credit, opening.Neg()},
+	)
+}
+
+// postedLegs loads the legs of a posted transaction, largest first
+func postedLegs(tx *gorm.DB, kind ledger.TransactionKind, referenceType, referenceID string) ([]leg, error) {
+	var transaction ledger.LedgerTransaction
+	err := tx.Preload("Entries.Account").
+		Where("posting_key = ?", ledger.PostingKey(kind, referenceType, referenceID)).
+		First(&transaction).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	legs := make([]leg, 0, len(transaction.Entries))
+	for i := range transaction.Entries {
+		entry := &transaction.Entries[i]
+		legs = append(legs, leg{account: &entry.Account, direction: entry.Direction, amount: entry.Amount})
+	}
+	sort.SliceStable(legs, func(i, j int) bool { return legs[i].amount.GreaterThan(legs[j].amount) })
+	return legs, nil
+}
+
+func eventOrganizer(tx *gorm.DB, eventID string) (*organizers.Organizer, error) {
+	var event events.Event
+	if err := tx.Select("id", "organizer_id").Where("id = ?", eventID).First(&event).Error; err != nil {
+		return nil, err
+	}
+	var organizer organizers.Organizer
+	if err := tx.Select("id", "commission_rate").Where("id = ?", event.OrganizerID).First(&organizer).Error; err != nil {
+		return nil, err
+	}
+	return &organizer, nil
+}
+
+// Commission is the platform's commission on a gross sale at a percentage rate
+func Commission(gross decimal.Decimal, rate float64) decimal.Decimal {
+	return gross.Mul(decimal.NewFromFloat(rate)).Div(decimal.NewFromInt(100)).Round(2)
+}
+
+// Amount converts a money column of the ticketing tables, stored to the cent,
+// to an exact decimal
+func Amount(value float64) decimal.Decimal {
+	return decimal.NewFromFloatWithExponent(value, -2)
+}
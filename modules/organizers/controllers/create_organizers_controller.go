@@ -46,8 +46,8 @@ func (c *OrganizerController) CreateOrganizer(ctx *fiber.Ctx) error {
 	if input.CommissionRate < 0 || input.CommissionRate > 100 {
 		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, "Commission rate must be between 0 and 100"), fiber.StatusBadRequest)
 	}
-	if input.Balance < 0 {
-		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, "Balance cannot be negative"), fiber.StatusBadRequest)
+	if input.TaxRate < 0 || input.TaxRate > 100 {
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, "Tax rate must be between 0 and 100"), fiber.StatusBadRequest)
 	}
 
 	_, err := c.service.CreateOrganizer(
@@ -60,7 +60,7 @@ func (c *OrganizerController) CreateOrganizer(ctx *fiber.Ctx) error {
 		input.TaxID,
 		input.BankAccountInfo,
 		input.CommissionRate,
-		input.Balance,
+		input.TaxRate,
 		input.Notes,
 	)
 	if err != nil {
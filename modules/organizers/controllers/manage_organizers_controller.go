@@ -54,8 +54,8 @@ func (c *OrganizerController) UpdateOrganizer(ctx *fiber.Ctx) error {
 	if input.CommissionRate < 0 || input.CommissionRate > 100 {
 		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, "Commission rate must be between 0 and 100"), fiber.StatusBadRequest)
 	}
-	if input.Balance < 0 {
-		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, "Balance cannot be negative"), fiber.StatusBadRequest)
+	if input.TaxRate < 0 || input.TaxRate > 100 {
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, "Tax rate must be between 0 and 100"), fiber.StatusBadRequest)
 	}
 
 	// Update logic
@@ -63,7 +63,7 @@ func (c *OrganizerController) UpdateOrganizer(ctx *fiber.Ctx) error {
 		userID, id,
 		input.Name, input.ContactPerson, input.Email, input.Phone,
 		input.CompanyName, input.TaxID, input.BankAccountInfo,
-		input.CommissionRate, input.Balance, input.Notes, input.AllowSubscriptions,
+		input.CommissionRate, input.TaxRate, input.Notes, input.AllowSubscriptions,
 	)
 
 	if err != nil {
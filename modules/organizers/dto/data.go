@@ -10,6 +10,6 @@ type CreateOrganizerData struct {
 	BankAccountInfo string  `json:"bank_account_info,omitempty" example:"Bank: Equity Bank, Acc No: 1234567890"`
 	ImageURL        string  `json:"image_url,omitempty" example:"https://example.com/images/organizer-logo.png" validate:"max=255"`
 	CommissionRate  float64 `json:"commission_rate" example:"15.5" validate:"gte=0,lte=100"`
-	Balance         float64 `json:"balance" example:"10000.00" validate:"gte=0"`
+	TaxRate         float64 `json:"tax_rate" example:"16" validate:"gte=0,lte=100"`
 	Notes           string  `json:"notes,omitempty" example:"Preferred partner with high ticket volumes."`
 }
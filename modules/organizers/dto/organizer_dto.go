@@ -1,6 +1,10 @@
 package organizer_dto
 
-import "time"
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
 
 type OrganizerResponse struct {
 	ID                      string            `json:"id"`
@@ -13,7 +17,8 @@ type OrganizerResponse struct {
 	BankAccountInfo         string            `json:"bank_account_info,omitempty"`
 	ImageURL                string            `json:"image_url,omitempty"`
 	CommissionRate          float64           `json:"commission_rate"`
-	Balance                 float64           `json:"balance"`
+	TaxRate                 float64           `json:"tax_rate"`
+	Balance                 decimal.Decimal   `json:"balance"`
 	Status                  string            `json:"status"`
 	IsFlagged               bool              `json:"is_flagged"`
 	IsBanned                bool              `json:"is_banned"`
@@ -72,7 +77,7 @@ type CreateOrganizerRequest struct {
 	BankAccountInfo string  `json:"bank_account_info,omitempty"`
 	ImageURL        string  `json:"image_url,omitempty" validate:"max=255"`
 	CommissionRate  float64 `json:"commission_rate" validate:"gte=0,lte=100"`
-	Balance         float64 `json:"balance" validate:"gte=0"`
+	TaxRate         float64 `json:"tax_rate" validate:"gte=0,lte=100"`
 	Notes           string  `json:"notes,omitempty"`
 }
 
@@ -85,7 +90,7 @@ type UpdateOrganizerRequest struct {
 	TaxID              string  `json:"tax_id,omitempty" validate:"max=100"`
 	BankAccountInfo    string  `json:"bank_account_info,omitempty"`
 	CommissionRate     float64 `json:"commission_rate" validate:"gte=0,lte=100"`
-	Balance            float64 `json:"balance" validate:"gte=0"`
+	TaxRate            float64 `json:"tax_rate" validate:"gte=0,lte=100"`
 	Notes              string  `json:"notes,omitempty"`
 	AllowSubscriptions bool    `json:"allow_subscriptions" validate:"omitempty"`
 }
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
 	"gorm.io/gorm"
 )
 
@@ -19,11 +20,16 @@ type Organizer struct {
 	BankAccountInfo string  `gorm:"type:text" json:"bank_account_info,omitempty"`
 	ImageURL        string  `gorm:"type:varchar(255)" json:"image_url,omitempty"`
 	CommissionRate  float64 `gorm:"type:numeric(5,2);default:10.00" json:"commission_rate"`
-	Balance         float64 `gorm:"type:numeric(14,2);default:0.00" json:"balance"`
-	Status          string  `gorm:"type:varchar(20);default:'active';check:status IN ('active','inactive','suspended')" json:"status"`
-	IsFlagged       bool    `gorm:"default:false" json:"is_flagged"`
-	IsBanned        bool    `gorm:"default:false" json:"is_banned"`
-	Notes           string  `gorm:"type:text" json:"notes,omitempty"`
+	// TaxRate is the percentage of tax included in the organizer's ticket
+	// prices, shown on their receipts; zero when they are not tax registered
+	TaxRate float64 `gorm:"type:numeric(5,2);default:0.00" json:"tax_rate"`
+	// Balance caches the organizer's ledger balance in the default currency. It
+	// is read-only here and only changes when ledger entries are posted.
+	Balance   decimal.Decimal `gorm:"type:numeric(14,2);default:0.00;->" json:"balance"`
+	Status    string          `gorm:"type:varchar(20);default:'active';check:status IN ('active','inactive','suspended')" json:"status"`
+	IsFlagged bool            `gorm:"default:false" json:"is_flagged"`
+	IsBanned  bool            `gorm:"default:false" json:"is_banned"`
+	Notes     string          `gorm:"type:text" json:"notes,omitempty"`
 
 	AllowSubscriptions       bool  `gorm:"default:true" json:"allow_subscriptions"`
 	SubscriberCount          int64 `gorm:"default:0" json:"subscriber_count"`
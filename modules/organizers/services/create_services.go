@@ -9,7 +9,7 @@ import (
 	"gorm.io/gorm"
 )
 
-func (s *organizerService) CreateOrganizer(userID, name, contactPerson, email, phone, companyName, taxID, bankAccountInfo string, commissionRate, balance float64, notes string) (*organizer_dto.OrganizerResponse, error) {
+func (s *organizerService) CreateOrganizer(userID, name, contactPerson, email, phone, companyName, taxID, bankAccountInfo string, commissionRate, taxRate float64, notes string) (*organizer_dto.OrganizerResponse, error) {
 	var existingOrganizer organizers.Organizer
 	if err := s.db.Where("email = ? AND deleted_at IS NULL", email).First(&existingOrganizer).Error; err == nil {
 		return nil, errors.New("organizer email already exists")
@@ -24,7 +24,7 @@ func (s *organizerService) CreateOrganizer(userID, name, contactPerson, email, p
 		TaxID:           taxID,
 		BankAccountInfo: bankAccountInfo,
 		CommissionRate:  commissionRate,
-		Balance:         balance,
+		TaxRate:         taxRate,
 		Status:          "active",
 		IsFlagged:       false,
 		IsBanned:        false,
@@ -39,7 +39,7 @@ func (s *organizerService) CreateOrganizer(userID, name, contactPerson, email, p
 	return s.toOrganizerResponse(&dbOrganizer, userID), nil
 }
 
-func (s *organizerService) UpdateOrganizer(userID string, id, name, contactPerson, email, phone, companyName, taxID, bankAccountInfo string, commissionRate, balance float64, notes string, allowSubscriptions bool) (*organizer_dto.OrganizerResponse, error) {
+func (s *organizerService) UpdateOrganizer(userID string, id, name, contactPerson, email, phone, companyName, taxID, bankAccountInfo string, commissionRate, taxRate float64, notes string, allowSubscriptions bool) (*organizer_dto.OrganizerResponse, error) {
 	_, err := s.HasPermission(userID, "update:organizers")
 	if err != nil {
 		return nil, err
@@ -74,7 +74,7 @@ func (s *organizerService) UpdateOrganizer(userID string, id, name, contactPerso
 	dbOrganizer.TaxID = taxID
 	dbOrganizer.BankAccountInfo = bankAccountInfo
 	dbOrganizer.CommissionRate = commissionRate
-	dbOrganizer.Balance = balance
+	dbOrganizer.TaxRate = taxRate
 	dbOrganizer.Notes = notes
 	dbOrganizer.AllowSubscriptions = allowSubscriptions
 
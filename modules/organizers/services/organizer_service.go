@@ -13,8 +13,8 @@ import (
 )
 
 type OrganizerService interface {
-	CreateOrganizer(userID, name, contactPerson, email, phone, companyName, taxID, bankAccountInfo string, commissionRate, balance float64, notes string) (*organizer_dto.OrganizerResponse, error)
-	UpdateOrganizer(userID, id, name, contactPerson, email, phone, companyName, taxID, bankAccountInfo string, commissionRate, balance float64, notes string, allowSubscriptions bool) (*organizer_dto.OrganizerResponse, error)
+	CreateOrganizer(userID, name, contactPerson, email, phone, companyName, taxID, bankAccountInfo string, commissionRate, taxRate float64, notes string) (*organizer_dto.OrganizerResponse, error)
+	UpdateOrganizer(userID, id, name, contactPerson, email, phone, companyName, taxID, bankAccountInfo string, commissionRate, taxRate float64, notes string, allowSubscriptions bool) (*organizer_dto.OrganizerResponse, error)
 	DeleteOrganizer(userID, id string) error
 	DeactivateOrganizer(userID, id string) error
 	GetOrganizer(userID, id string) (*organizer_dto.OrganizerResponse, error)
@@ -154,6 +154,7 @@ func (s *organizerService) toOrganizerResponse(dbOrganizer *organizers.Organizer
 		BankAccountInfo:        dbOrganizer.BankAccountInfo,
 		ImageURL:               dbOrganizer.ImageURL,
 		CommissionRate:         dbOrganizer.CommissionRate,
+		TaxRate:                dbOrganizer.TaxRate,
 		Balance:                dbOrganizer.Balance,
 		Status:                 dbOrganizer.Status,
 		IsFlagged:              dbOrganizer.IsFlagged,
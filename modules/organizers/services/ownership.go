@@ -0,0 +1,44 @@
+package organizers_services
+
+import (
+	"errors"
+	"ticket-zetu-api/modules/events/models/events"
+	organizers "ticket-zetu-api/modules/organizers/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// GetUserOrganizer loads the organizer account the user created. Services
+// acting for an organizer use it to scope what the user can see and change.
+func GetUserOrganizer(db *gorm.DB, userID string) (*organizers.Organizer, error) {
+	var organizer organizers.Organizer
+	if err := db.Where("created_by = ? AND deleted_at IS NULL", userID).First(&organizer).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("organizer not found")
+		}
+		return nil, err
+	}
+	return &organizer, nil
+}
+
+// GetOwnedEvent loads an event belonging to the user's organizer
+func GetOwnedEvent(db *gorm.DB, userID, eventID string) (*events.Event, error) {
+	if _, err := uuid.Parse(eventID); err != nil {
+		return nil, errors.New("invalid event ID format")
+	}
+
+	organizer, err := GetUserOrganizer(db, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var event events.Event
+	if err := db.Where("id = ? AND organizer_id = ? AND deleted_at IS NULL", eventID, organizer.ID).First(&event).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("event not found")
+		}
+		return nil, err
+	}
+	return &event, nil
+}
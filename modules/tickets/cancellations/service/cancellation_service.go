@@ -0,0 +1,500 @@
+package cancellation_service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"ticket-zetu-api/modules/events/models/events"
+	seat_services "ticket-zetu-api/modules/events/seat_allocation/services"
+	notification_service "ticket-zetu-api/modules/notifications/service"
+	inventory_service "ticket-zetu-api/modules/tickets/inventory/service"
+	"ticket-zetu-api/modules/tickets/models/tickets"
+	order_service "ticket-zetu-api/modules/tickets/orders/service"
+	refund_service "ticket-zetu-api/modules/tickets/refunds/service"
+	mail_service "ticket-zetu-api/modules/users/authentication/mail"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CancellationService carries out the cascade that follows cancelling an
+// event: holds, seats and listings are released, unpaid orders cancelled,
+// paid ones refunded and every ticket holder told. The work is done a batch
+// at a time by a background job so events with tens of thousands of
+// attendees never hold up a request.
+type CancellationService interface {
+	ProcessCancellations(limit int) (int, error)
+}
+
+type cancellationService struct {
+	db                  *gorm.DB
+	inventoryService    inventory_service.InventoryService
+	orderService        order_service.OrderService
+	refundService       refund_service.RefundService
+	notificationService notification_service.NotificationService
+	emailService        mail_service.EmailService
+}
+
+func NewCancellationService(db *gorm.DB, inventoryService inventory_service.InventoryService, orderService order_service.OrderService, refundService refund_service.RefundService, notificationService notification_service.NotificationService, emailService mail_service.EmailService) CancellationService {
+	return &cancellationService{
+		db:                  db,
+		inventoryService:    inventoryService,
+		orderService:        orderService,
+		refundService:       refundService,
+		notificationService: notificationService,
+		emailService:        emailService,
+	}
+}
+
+// claimLease is how long a run has a cancellation to itself. It has to
+// outlast the slowest batch, which may wait on the payment provider for every
+// refund in it.
+const claimLease = 10 * time.Minute
+
+// nextStage is the stage a cancellation moves to once a stage has no work left
+var nextStage = map[events.CancellationStage]events.CancellationStage{
+	events.CancellationReleasing: events.CancellationRefunding,
+	events.CancellationRefunding: events.CancellationVoiding,
+	events.CancellationVoiding:   events.CancellationNotifying,
+	events.CancellationNotifying: events.CancellationCompleted,
+}
+
+// batchResult is what one batch of a stage did. Updates are the counters and
+// cursor to save with it.
+type batchResult struct {
+	handled int
+	done    bool
+	updates map[string]interface{}
+}
+
+// ProcessCancellations runs one batch of up to limit rows for every
+// cancellation still in progress and returns how many rows were handled.
+// Each cancellation is claimed before its batch runs, so a run that outlasts
+// the job's interval is never joined by the next one. A cancellation whose
+// batch fails is logged and tried again on the next run without holding up
+// the others.
+func (s *cancellationService) ProcessCancellations(limit int) (int, error) {
+	var inProgress []events.EventCancellation
+	if err := s.db.Preload("Event", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Where("stage <> ?", events.CancellationCompleted).
+		Where("claimed_until IS NULL OR claimed_until <= ?", time.Now()).
+		Order("created_at ASC").
+		Find(&inProgress).Error; err != nil {
+		return 0, err
+	}
+
+	handled := 0
+	for i := range inProgress {
+		cancellation := &inProgress[i]
+		claimed, err := s.claim(cancellation)
+		if err != nil {
+			return handled, err
+		}
+		if !claimed {
+			continue
+		}
+
+		result, err := s.runStage(cancellation, limit)
+		if err != nil {
+			log.Printf("Cancellation of event %s: %v", cancellation.EventID, err)
+			if err := s.saveProgress(cancellation, &batchResult{}); err != nil {
+				return handled, err
+			}
+			continue
+		}
+		if err := s.saveProgress(cancellation, result); err != nil {
+			return handled, err
+		}
+		handled += result.handled
+	}
+	return handled, nil
+}
+
+// claim takes the cancellation for this run unless another run holds it. It
+// reports false when the cancellation was claimed or moved on since it was
+// read.
+func (s *cancellationService) claim(cancellation *events.EventCancellation) (bool, error) {
+	now := time.Now()
+	result := s.db.Model(&events.EventCancellation{}).
+		Where("id = ? AND version = ?", cancellation.ID, cancellation.Version).
+		Where("claimed_until IS NULL OR claimed_until <= ?", now).
+		UpdateColumns(map[string]interface{}{
+			"claimed_until": now.Add(claimLease),
+			"version":       gorm.Expr("version + 1"),
+			"updated_at":    now,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+	cancellation.Version++
+	return true, nil
+}
+
+func (s *cancellationService) runStage(cancellation *events.EventCancellation, limit int) (*batchResult, error) {
+	switch cancellation.Stage {
+	case events.CancellationReleasing:
+		return s.release(cancellation, limit)
+	case events.CancellationRefunding:
+		return s.settleOrders(cancellation, limit)
+	case events.CancellationVoiding:
+		return s.voidTickets(cancellation, limit)
+	case events.CancellationNotifying:
+		return s.notifyHolders(cancellation, limit)
+	}
+	return nil, fmt.Errorf("unknown cancellation stage %q", cancellation.Stage)
+}
+
+// saveProgress records a batch, moves the cancellation on once its stage is
+// done and gives up the claim. The version guard catches a run that lost its
+// claim to another, whose batch must not be recorded twice.
+func (s *cancellationService) saveProgress(cancellation *events.EventCancellation, result *batchResult) error {
+	updates := result.updates
+	if updates == nil {
+		updates = map[string]interface{}{}
+	}
+	updates["claimed_until"] = nil
+	now := time.Now()
+	if result.done {
+		next := nextStage[cancellation.Stage]
+		updates["stage"] = next
+		updates["cursor"] = ""
+		if next == events.CancellationCompleted {
+			updates["completed_at"] = now
+		}
+	}
+	updates["version"] = gorm.Expr("version + 1")
+	updates["updated_at"] = now
+
+	saved := s.db.Model(&events.EventCancellation{}).
+		Where("id = ? AND version = ?", cancellation.ID, cancellation.Version).
+		UpdateColumns(updates)
+	if saved.Error != nil {
+		return saved.Error
+	}
+	if saved.RowsAffected == 0 {
+		return fmt.Errorf("cancellation of event %s was taken over by another run", cancellation.EventID)
+	}
+	return nil
+}
+
+// release frees what the event's tickets were not yet sold as: waitlist
+// places, ticket holds, seat reservations, resale listings, pending
+// transfers and unclaimed comps. Each step takes what is left of the batch.
+func (s *cancellationService) release(cancellation *events.EventCancellation, limit int) (*batchResult, error) {
+	steps := []func(eventID string, limit int) (int, error){
+		s.closeWaitlist,
+		s.releaseHolds,
+		s.releaseSeats,
+		s.closeListings,
+		s.cancelTransfers,
+		s.revokeClaims,
+	}
+
+	remaining := limit
+	for _, step := range steps {
+		if remaining == 0 {
+			break
+		}
+		released, err := step(cancellation.EventID, remaining)
+		if err != nil {
+			return nil, err
+		}
+		remaining -= released
+	}
+	handled := limit - remaining
+	return &batchResult{handled: handled, done: handled < limit}, nil
+}
+
+func (s *cancellationService) closeWaitlist(eventID string, limit int) (int, error) {
+	var ids []string
+	if err := s.db.Model(&tickets.WaitlistEntry{}).
+		Where("event_id = ? AND status IN ?", eventID, []tickets.WaitlistStatus{tickets.WaitlistWaiting, tickets.WaitlistOffered}).
+		Limit(limit).
+		Pluck("id", &ids).Error; err != nil {
+		return 0, err
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	// Offered places keep their hold, which the next step releases
+	return len(ids), s.db.Model(&tickets.WaitlistEntry{}).
+		Where("id IN ? AND status IN ?", ids, []tickets.WaitlistStatus{tickets.WaitlistWaiting, tickets.WaitlistOffered}).
+		UpdateColumns(map[string]interface{}{
+			"status":     tickets.WaitlistExpired,
+			"active_key": nil,
+			"updated_at": time.Now(),
+		}).Error
+}
+
+func (s *cancellationService) releaseHolds(eventID string, limit int) (int, error) {
+	var holds []tickets.TicketHold
+	if err := s.db.Joins("JOIN ticket_types ON ticket_types.id = ticket_holds.ticket_type_id").
+		Where("ticket_types.event_id = ?", eventID).
+		Limit(limit).
+		Find(&holds).Error; err != nil {
+		return 0, err
+	}
+
+	for i := range holds {
+		hold := holds[i]
+		err := s.db.Transaction(func(tx *gorm.DB) error {
+			// A sweep or the holder may have released it since it was read
+			result := tx.Delete(&hold)
+			if result.Error != nil || result.RowsAffected == 0 {
+				return result.Error
+			}
+			return s.inventoryService.MoveIfTracked(tx, hold.TicketTypeID, inventory_service.BucketHeld, inventory_service.BucketAvailable, hold.Quantity)
+		})
+		if err != nil {
+			return 0, err
+		}
+	}
+	return len(holds), nil
+}
+
+func (s *cancellationService) releaseSeats(eventID string, limit int) (int, error) {
+	released := 0
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		released, err = seat_services.ReleaseEventSeats(tx, eventID, limit)
+		return err
+	})
+	return released, err
+}
+
+// closeListings cancels open resale listings. A listing a buyer is paying
+// for is left until its reservation lapses.
+func (s *cancellationService) closeListings(eventID string, limit int) (int, error) {
+	now := time.Now()
+	var ids []string
+	if err := s.db.Model(&tickets.TicketResale{}).
+		Where("event_id = ? AND status IN ?", eventID, []tickets.ResaleStatus{tickets.ResaleListed, tickets.ResalePending}).
+		Where("status = ? OR reserved_until IS NULL OR reserved_until <= ?", tickets.ResaleListed, now).
+		Limit(limit).
+		Pluck("id", &ids).Error; err != nil {
+		return 0, err
+	}
+
+	for _, id := range ids {
+		err := s.db.Transaction(func(tx *gorm.DB) error {
+			var listing tickets.TicketResale
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+				Where("id = ?", id).
+				First(&listing).Error; err != nil {
+				return err
+			}
+			if listing.Status != tickets.ResaleListed &&
+				(listing.Status != tickets.ResalePending || (listing.ReservedUntil != nil && now.Before(*listing.ReservedUntil))) {
+				return nil
+			}
+			if err := tx.Model(&listing).UpdateColumns(map[string]interface{}{
+				"status":     tickets.ResaleCanceled,
+				"version":    gorm.Expr("version + 1"),
+				"updated_at": time.Now(),
+			}).Error; err != nil {
+				return err
+			}
+			return s.inventoryService.MoveIfTracked(tx, listing.TicketTypeID, inventory_service.BucketResale, inventory_service.BucketSold, 1)
+		})
+		if err != nil {
+			return 0, err
+		}
+	}
+	return len(ids), nil
+}
+
+func (s *cancellationService) cancelTransfers(eventID string, limit int) (int, error) {
+	var ids []string
+	if err := s.db.Model(&tickets.TicketTransfer{}).
+		Joins("JOIN tickets ON tickets.id = ticket_transfers.ticket_id").
+		Where("tickets.event_id = ? AND ticket_transfers.status = ?", eventID, tickets.TransferPending).
+		Limit(limit).
+		Pluck("ticket_transfers.id", &ids).Error; err != nil {
+		return 0, err
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	return len(ids), s.db.Model(&tickets.TicketTransfer{}).
+		Where("id IN ? AND status = ?", ids, tickets.TransferPending).
+		UpdateColumns(map[string]interface{}{
+			"status":       tickets.TransferCanceled,
+			"responded_at": time.Now(),
+			"updated_at":   time.Now(),
+		}).Error
+}
+
+func (s *cancellationService) revokeClaims(eventID string, limit int) (int, error) {
+	var grants []tickets.CompGrant
+	if err := s.db.Select("id").
+		Where("event_id = ? AND status = ?", eventID, tickets.CompPendingClaim).
+		Limit(limit).
+		Find(&grants).Error; err != nil {
+		return 0, err
+	}
+
+	for _, candidate := range grants {
+		err := s.db.Transaction(func(tx *gorm.DB) error {
+			var grant tickets.CompGrant
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+				Where("id = ? AND status = ?", candidate.ID, tickets.CompPendingClaim).
+				First(&grant).Error; err != nil {
+				// Claimed or expired since it was listed
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return nil
+				}
+				return err
+			}
+			if err := s.inventoryService.MoveIfTracked(tx, grant.TicketTypeID, inventory_service.BucketReserved, inventory_service.BucketAvailable, grant.Quantity); err != nil {
+				return err
+			}
+			return tx.Model(&tickets.CompGrant{}).Where("id = ?", grant.ID).
+				UpdateColumns(map[string]interface{}{
+					"status":           tickets.CompRevoked,
+					"claim_token_hash": nil,
+					"updated_at":       time.Now(),
+				}).Error
+		})
+		if err != nil {
+			return 0, err
+		}
+	}
+	return len(grants), nil
+}
+
+// settleOrders walks the event's orders: unpaid ones are cancelled and paid
+// ones refunded. A refund that fails is recorded on the cancellation so one
+// bad payment cannot stall the rest. Once every order has been walked, the
+// failed refunds are retried and the stage waits until they succeed or run
+// out of attempts.
+func (s *cancellationService) settleOrders(cancellation *events.EventCancellation, limit int) (*batchResult, error) {
+	var orders []tickets.Order
+	if err := s.db.Select("id", "status").
+		Where("event_id = ? AND status IN ? AND id > ?", cancellation.EventID,
+			[]tickets.OrderStatus{tickets.OrderPending, tickets.OrderConfirmed}, cancellation.Cursor).
+		Order("id ASC").
+		Limit(limit).
+		Find(&orders).Error; err != nil {
+		return nil, err
+	}
+
+	cancelled, refunded, failed, ticketsCancelled := 0, 0, 0, 0
+	reason := refundReason(cancellation)
+	for _, order := range orders {
+		if order.Status == tickets.OrderPending {
+			ok, err := s.orderService.CancelEventOrder(order.ID)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				cancelled++
+			}
+			continue
+		}
+
+		count, err := s.refundService.RefundCancelledOrder(order.ID, cancellation.RefundPercentage, reason)
+		ticketsCancelled += count
+		if err != nil {
+			if err := s.recordFailure(cancellation, events.CancellationFailedRefund, order.ID, err); err != nil {
+				return nil, err
+			}
+			failed++
+			continue
+		}
+		if count > 0 {
+			refunded++
+		}
+	}
+
+	handled, done := len(orders), len(orders) < limit
+	if done {
+		failures, err := s.dueFailures(cancellation, limit-len(orders), events.CancellationFailedRefund)
+		if err != nil {
+			return nil, err
+		}
+		var resolved []string
+		for _, failure := range failures {
+			count, err := s.refundService.RefundCancelledOrder(failure.ReferenceID, cancellation.RefundPercentage, reason)
+			ticketsCancelled += count
+			if err != nil {
+				if err := s.recordFailure(cancellation, events.CancellationFailedRefund, failure.ReferenceID, err); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			resolved = append(resolved, failure.ID)
+			failed--
+			if count > 0 {
+				refunded++
+			}
+		}
+		if err := s.resolveFailures(resolved); err != nil {
+			return nil, err
+		}
+		handled += len(failures)
+		if done, err = s.failuresSettled(cancellation, events.CancellationFailedRefund); err != nil {
+			return nil, err
+		}
+	}
+
+	result := &batchResult{
+		handled: handled,
+		done:    done,
+		updates: map[string]interface{}{
+			"orders_cancelled":  gorm.Expr("orders_cancelled + ?", cancelled),
+			"orders_refunded":   gorm.Expr("orders_refunded + ?", refunded),
+			"refunds_failed":    gorm.Expr("refunds_failed + ?", failed),
+			"tickets_cancelled": gorm.Expr("tickets_cancelled + ?", ticketsCancelled),
+		},
+	}
+	if len(orders) > 0 {
+		result.updates["cursor"] = orders[len(orders)-1].ID
+	}
+	return result, nil
+}
+
+// voidTickets cancels live tickets that were issued without an order, such
+// as comps. Their stock is left as it is; the event no longer sells.
+func (s *cancellationService) voidTickets(cancellation *events.EventCancellation, limit int) (*batchResult, error) {
+	liveStatuses := []tickets.TicketStatus{tickets.TicketValid, tickets.TicketPending}
+
+	var ids []string
+	if err := s.db.Model(&tickets.Ticket{}).
+		Where("event_id = ? AND status IN ? AND (order_id = '' OR order_id IS NULL) AND deleted_at IS NULL", cancellation.EventID, liveStatuses).
+		Limit(limit).
+		Pluck("id", &ids).Error; err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return &batchResult{done: true}, nil
+	}
+
+	result := s.db.Model(&tickets.Ticket{}).
+		Where("id IN ? AND status IN ?", ids, liveStatuses).
+		UpdateColumns(map[string]interface{}{
+			"status":     tickets.TicketCanceled,
+			"version":    gorm.Expr("version + 1"),
+			"updated_at": time.Now(),
+		})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &batchResult{
+		handled: len(ids),
+		done:    len(ids) < limit,
+		updates: map[string]interface{}{
+			"tickets_cancelled": gorm.Expr("tickets_cancelled + ?", result.RowsAffected),
+		},
+	}, nil
+}
+
+func refundReason(cancellation *events.EventCancellation) string {
+	if cancellation.Reason != "" {
+		return "event cancelled: " + cancellation.Reason
+	}
+	return "event cancelled"
+}
@@ -0,0 +1,78 @@
+package cancellation_service
+
+import (
+	"log"
+	"ticket-zetu-api/modules/events/models/events"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// maxFailureAttempts is how often an order or holder is tried before it
+	// is left for support
+	maxFailureAttempts = 5
+	// failureRetryDelay spaces out the attempts on one order or holder
+	failureRetryDelay = time.Minute
+)
+
+// recordFailure saves an order or holder the cancellation could not finish
+// with, counting another attempt when it has failed before
+func (s *cancellationService) recordFailure(cancellation *events.EventCancellation, kind events.CancellationFailureKind, referenceID string, cause error) error {
+	log.Printf("Cancellation of event %s: %s for %s failed: %v", cancellation.EventID, kind, referenceID, cause)
+
+	message := cause.Error()
+	if len(message) > 255 {
+		message = message[:255]
+	}
+	failure := events.EventCancellationFailure{
+		CancellationID: cancellation.ID,
+		Kind:           kind,
+		ReferenceID:    referenceID,
+		Error:          message,
+	}
+	return s.db.Clauses(clause.OnConflict{
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"attempts":    gorm.Expr("attempts + 1"),
+			"error":       message,
+			"resolved_at": nil,
+			"updated_at":  time.Now(),
+		}),
+	}).Create(&failure).Error
+}
+
+// dueFailures loads up to limit unresolved failures of the given kinds that
+// have attempts left and have waited out the retry delay
+func (s *cancellationService) dueFailures(cancellation *events.EventCancellation, limit int, kinds ...events.CancellationFailureKind) ([]events.EventCancellationFailure, error) {
+	var failures []events.EventCancellationFailure
+	err := s.db.Where("cancellation_id = ? AND kind IN ? AND resolved_at IS NULL AND attempts < ?", cancellation.ID, kinds, maxFailureAttempts).
+		Where("updated_at <= ?", time.Now().Add(-failureRetryDelay)).
+		Order("updated_at ASC").
+		Limit(limit).
+		Find(&failures).Error
+	return failures, err
+}
+
+// failuresSettled reports whether every failure of the given kinds has been
+// resolved or has run out of attempts, so the stage can move on
+func (s *cancellationService) failuresSettled(cancellation *events.EventCancellation, kinds ...events.CancellationFailureKind) (bool, error) {
+	var outstanding int64
+	err := s.db.Model(&events.EventCancellationFailure{}).
+		Where("cancellation_id = ? AND kind IN ? AND resolved_at IS NULL AND attempts < ?", cancellation.ID, kinds, maxFailureAttempts).
+		Count(&outstanding).Error
+	return outstanding == 0, err
+}
+
+func (s *cancellationService) resolveFailures(ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	now := time.Now()
+	return s.db.Model(&events.EventCancellationFailure{}).
+		Where("id IN ?", ids).
+		UpdateColumns(map[string]interface{}{
+			"resolved_at": now,
+			"updated_at":  now,
+		}).Error
+}
@@ -0,0 +1,182 @@
+package cancellation_service
+
+import (
+	"fmt"
+	"ticket-zetu-api/modules/events/models/events"
+	"ticket-zetu-api/modules/tickets/models/tickets"
+	"ticket-zetu-api/modules/users/models/members"
+
+	"gorm.io/gorm"
+)
+
+// errEmailQueueOverloaded is returned by the email service when it cannot
+// take more mail right now
+const errEmailQueueOverloaded = "email queue overloaded"
+
+// notifyHolders tells a batch of ticket holders, by notification and email,
+// that the event was cancelled. Holders are anyone with a live ticket or one
+// that changed after the cancellation, which covers everyone the earlier
+// stages refunded or voided. Each holder is told once however many tickets
+// they had. Holders who could not be told are recorded and tried again once
+// every holder has been walked.
+func (s *cancellationService) notifyHolders(cancellation *events.EventCancellation, limit int) (*batchResult, error) {
+	var userIDs []string
+	if err := s.db.Model(&tickets.Ticket{}).
+		Where("event_id = ? AND user_id > ? AND deleted_at IS NULL", cancellation.EventID, cancellation.Cursor).
+		Where("status IN ? OR updated_at >= ?", []tickets.TicketStatus{tickets.TicketValid, tickets.TicketPending}, cancellation.CreatedAt).
+		Distinct("user_id").
+		Order("user_id ASC").
+		Limit(limit).
+		Pluck("user_id", &userIDs).Error; err != nil {
+		return nil, err
+	}
+
+	usersByID, err := s.loadHolders(userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	notified := make([]string, 0, len(userIDs))
+	for _, userID := range userIDs {
+		if holder, ok := usersByID[userID]; ok && holder.Email != "" {
+			err := s.emailHolder(cancellation, &holder)
+			// A full queue stops the batch here; the rest are emailed on the next run
+			if err != nil && err.Error() == errEmailQueueOverloaded {
+				break
+			}
+			if err != nil {
+				if err := s.recordFailure(cancellation, events.CancellationFailedEmail, userID, err); err != nil {
+					return nil, err
+				}
+			}
+		}
+		notified = append(notified, userID)
+	}
+
+	result := &batchResult{
+		handled: len(notified),
+		done:    len(notified) == len(userIDs) && len(userIDs) < limit,
+	}
+	if len(notified) > 0 {
+		if err := s.notify(cancellation, notified); err != nil {
+			for _, userID := range notified {
+				if err := s.recordFailure(cancellation, events.CancellationFailedNotification, userID, err); err != nil {
+					return nil, err
+				}
+			}
+		}
+		result.updates = map[string]interface{}{
+			"holders_notified": gorm.Expr("holders_notified + ?", len(notified)),
+			"cursor":           notified[len(notified)-1],
+		}
+	}
+
+	if result.done {
+		retried, err := s.retryNotices(cancellation, limit-len(notified))
+		if err != nil {
+			return nil, err
+		}
+		result.handled += retried
+		if result.done, err = s.failuresSettled(cancellation, events.CancellationFailedEmail, events.CancellationFailedNotification); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// retryNotices emails and notifies again up to limit holders whose earlier
+// attempt failed, and returns how many it tried
+func (s *cancellationService) retryNotices(cancellation *events.EventCancellation, limit int) (int, error) {
+	failures, err := s.dueFailures(cancellation, limit, events.CancellationFailedEmail, events.CancellationFailedNotification)
+	if err != nil || len(failures) == 0 {
+		return 0, err
+	}
+
+	userIDs := make([]string, len(failures))
+	for i, failure := range failures {
+		userIDs[i] = failure.ReferenceID
+	}
+	usersByID, err := s.loadHolders(userIDs)
+	if err != nil {
+		return 0, err
+	}
+
+	var resolved, renotify, renotifyIDs []string
+	queueFull := false
+	for _, failure := range failures {
+		if failure.Kind == events.CancellationFailedNotification {
+			renotify = append(renotify, failure.ReferenceID)
+			renotifyIDs = append(renotifyIDs, failure.ID)
+			continue
+		}
+		if queueFull {
+			continue
+		}
+
+		// A holder who has since lost their email address has nothing to retry
+		holder, ok := usersByID[failure.ReferenceID]
+		if ok && holder.Email != "" {
+			err := s.emailHolder(cancellation, &holder)
+			if err != nil && err.Error() == errEmailQueueOverloaded {
+				queueFull = true
+				continue
+			}
+			if err != nil {
+				if err := s.recordFailure(cancellation, events.CancellationFailedEmail, failure.ReferenceID, err); err != nil {
+					return 0, err
+				}
+				continue
+			}
+		}
+		resolved = append(resolved, failure.ID)
+	}
+
+	if len(renotify) > 0 {
+		if err := s.notify(cancellation, renotify); err != nil {
+			for _, userID := range renotify {
+				if err := s.recordFailure(cancellation, events.CancellationFailedNotification, userID, err); err != nil {
+					return 0, err
+				}
+			}
+		} else {
+			resolved = append(resolved, renotifyIDs...)
+		}
+	}
+	return len(failures), s.resolveFailures(resolved)
+}
+
+func (s *cancellationService) loadHolders(userIDs []string) (map[string]members.User, error) {
+	usersByID := make(map[string]members.User, len(userIDs))
+	if len(userIDs) == 0 {
+		return usersByID, nil
+	}
+	var holders []members.User
+	if err := s.db.Select("id", "email", "username").Where("id IN ?", userIDs).Find(&holders).Error; err != nil {
+		return nil, err
+	}
+	for _, holder := range holders {
+		usersByID[holder.ID] = holder
+	}
+	return usersByID, nil
+}
+
+func (s *cancellationService) emailHolder(cancellation *events.EventCancellation, holder *members.User) error {
+	event := cancellation.Event
+	return s.emailService.SendEventCancelledEmail(nil, holder.Email, holder.Username, event.Title, event.StartTime, cancellation.Reason, cancellation.RefundPercentage)
+}
+
+// notify sends the in-app cancellation notice to the given holders
+func (s *cancellationService) notify(cancellation *events.EventCancellation, userIDs []string) error {
+	event := cancellation.Event
+	title := "Event cancelled"
+	content := fmt.Sprintf("%s on %s has been cancelled. Your tickets are no longer valid.", event.Title, event.StartTime.Format("2006-01-02 15:04"))
+	if cancellation.RefundPercentage > 0 {
+		content += fmt.Sprintf(" Paid tickets are refunded at %g%% to your original payment method.", cancellation.RefundPercentage)
+	}
+	metadata := map[string]interface{}{
+		"event_id":          event.ID,
+		"reason":            cancellation.Reason,
+		"refund_percentage": cancellation.RefundPercentage,
+	}
+	return s.notificationService.TriggerNotification("events", "event_cancelled", title, content, cancellation.CancelledBy, event.ID, userIDs, metadata)
+}
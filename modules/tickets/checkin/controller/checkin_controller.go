@@ -0,0 +1,266 @@
+package checkin_controller
+
+import (
+	"ticket-zetu-api/logs/handler"
+	"ticket-zetu-api/modules/tickets/checkin/dto"
+	"ticket-zetu-api/modules/tickets/checkin/service"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+type CheckInController struct {
+	service    checkin_service.CheckInService
+	logHandler *handler.LogHandler
+	validator  *validator.Validate
+}
+
+func NewCheckInController(service checkin_service.CheckInService, logHandler *handler.LogHandler) *CheckInController {
+	return &CheckInController{
+		service:    service,
+		logHandler: logHandler,
+		validator:  validator.New(),
+	}
+}
+
+// ScanTicket godoc
+// @Summary Scan a ticket at the door
+// @Description Verifies a ticket's signed QR credential and checks the ticket in. Duplicate, canceled and refunded tickets and tickets for other events are rejected. Requires the scan:tickets permission for the event.
+// @Tags Check-in
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param event_id path string true "Event ID"
+// @Param input body dto.ScanTicketInput true "Scanned credential"
+// @Success 200 {object} map[string]interface{} "Ticket checked in successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid request body or event ID"
+// @Failure 403 {object} map[string]interface{} "User lacks permission to scan tickets for this event"
+// @Failure 404 {object} map[string]interface{} "Ticket not found"
+// @Failure 409 {object} map[string]interface{} "Ticket already checked in"
+// @Failure 422 {object} map[string]interface{} "Ticket cannot be admitted"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /checkin/events/{event_id}/scan [post]
+func (c *CheckInController) ScanTicket(ctx *fiber.Ctx) error {
+	userID := ctx.Locals("user_id").(string)
+	eventID := ctx.Params("event_id")
+
+	var input dto.ScanTicketInput
+	if err := ctx.BodyParser(&input); err != nil {
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, "Invalid request body"), fiber.StatusBadRequest)
+	}
+
+	if err := c.validator.Struct(input); err != nil {
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, err.Error()), fiber.StatusBadRequest)
+	}
+
+	ticket, err := c.service.ScanTicket(userID, eventID, input)
+	if err != nil {
+		return c.handleError(ctx, err)
+	}
+
+	return c.logHandler.LogSuccess(ctx, ticket, "Ticket checked in successfully", true)
+}
+
+// UndoCheckIn godoc
+// @Summary Undo a ticket check-in
+// @Description Returns a checked-in ticket to valid so it can be scanned again. Requires the scan:tickets permission for the event.
+// @Tags Check-in
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param event_id path string true "Event ID"
+// @Param ticket_id path string true "Ticket ID"
+// @Param input body dto.UndoCheckInInput false "Reason for undoing the check-in"
+// @Success 200 {object} map[string]interface{} "Check-in undone successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid request body or ID"
+// @Failure 403 {object} map[string]interface{} "User lacks permission to scan tickets for this event"
+// @Failure 404 {object} map[string]interface{} "Ticket not found"
+// @Failure 409 {object} map[string]interface{} "Ticket is not checked in"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /checkin/events/{event_id}/tickets/{ticket_id}/undo [post]
+func (c *CheckInController) UndoCheckIn(ctx *fiber.Ctx) error {
+	userID := ctx.Locals("user_id").(string)
+	eventID := ctx.Params("event_id")
+	ticketID := ctx.Params("ticket_id")
+
+	var input dto.UndoCheckInInput
+	if len(ctx.Body()) > 0 {
+		if err := ctx.BodyParser(&input); err != nil {
+			return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, "Invalid request body"), fiber.StatusBadRequest)
+		}
+	}
+
+	if err := c.validator.Struct(input); err != nil {
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, err.Error()), fiber.StatusBadRequest)
+	}
+
+	ticket, err := c.service.UndoCheckIn(userID, eventID, ticketID, input)
+	if err != nil {
+		return c.handleError(ctx, err)
+	}
+
+	return c.logHandler.LogSuccess(ctx, ticket, "Check-in undone successfully", true)
+}
+
+// GetOfflineManifest godoc
+// @Summary Download an offline scanner bundle
+// @Description Exports a signed manifest of every admissible ticket for an event, with the public keys needed to verify ticket credentials without a connection. Requires the scan:tickets permission for the event.
+// @Tags Check-in
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param event_id path string true "Event ID"
+// @Success 200 {object} map[string]interface{} "Offline manifest generated successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid event ID"
+// @Failure 403 {object} map[string]interface{} "User lacks permission to scan tickets for this event"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /checkin/events/{event_id}/offline-manifest [get]
+func (c *CheckInController) GetOfflineManifest(ctx *fiber.Ctx) error {
+	userID := ctx.Locals("user_id").(string)
+	eventID := ctx.Params("event_id")
+
+	manifest, err := c.service.GetOfflineManifest(userID, eventID)
+	if err != nil {
+		return c.handleError(ctx, err)
+	}
+
+	return c.logHandler.LogSuccess(ctx, manifest, "Offline manifest generated successfully", false)
+}
+
+// SyncCheckIns godoc
+// @Summary Upload offline check-ins
+// @Description Reconciles check-ins recorded while offline. The earliest scan of a ticket wins; later scans are reported as duplicates and inadmissible tickets as rejected. Requires the scan:tickets permission for the event.
+// @Tags Check-in
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param event_id path string true "Event ID"
+// @Param input body dto.SyncCheckInsInput true "Offline scans"
+// @Success 200 {object} map[string]interface{} "Offline check-ins synced successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid request body or event ID"
+// @Failure 403 {object} map[string]interface{} "User lacks permission to scan tickets for this event"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /checkin/events/{event_id}/sync [post]
+func (c *CheckInController) SyncCheckIns(ctx *fiber.Ctx) error {
+	userID := ctx.Locals("user_id").(string)
+	eventID := ctx.Params("event_id")
+
+	var input dto.SyncCheckInsInput
+	if err := ctx.BodyParser(&input); err != nil {
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, "Invalid request body"), fiber.StatusBadRequest)
+	}
+
+	if err := c.validator.Struct(input); err != nil {
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, err.Error()), fiber.StatusBadRequest)
+	}
+
+	result, err := c.service.SyncCheckIns(userID, eventID, input)
+	if err != nil {
+		return c.handleError(ctx, err)
+	}
+
+	return c.logHandler.LogSuccess(ctx, result, "Offline check-ins synced successfully", true)
+}
+
+// GetScanners godoc
+// @Summary List an event's ticket scanners
+// @Description Lists the users granted permission to scan tickets at one of the organizer's events.
+// @Tags Check-in
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param event_id path string true "Event ID"
+// @Success 200 {object} map[string]interface{} "Scanners retrieved successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid event ID"
+// @Failure 404 {object} map[string]interface{} "Event or organizer not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /checkin/events/{event_id}/scanners [get]
+func (c *CheckInController) GetScanners(ctx *fiber.Ctx) error {
+	userID := ctx.Locals("user_id").(string)
+	eventID := ctx.Params("event_id")
+
+	scanners, err := c.service.GetScanners(userID, eventID)
+	if err != nil {
+		return c.handleError(ctx, err)
+	}
+
+	return c.logHandler.LogSuccess(ctx, scanners, "Scanners retrieved successfully", false)
+}
+
+// GrantScanner godoc
+// @Summary Allow a user to scan tickets at an event
+// @Description Grants a user the scan:tickets permission for one of the organizer's events.
+// @Tags Check-in
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param event_id path string true "Event ID"
+// @Param input body dto.GrantScannerInput true "Scanner user"
+// @Success 200 {object} map[string]interface{} "Scanner added successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid request body or event ID"
+// @Failure 404 {object} map[string]interface{} "Event, organizer or user not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /checkin/events/{event_id}/scanners [post]
+func (c *CheckInController) GrantScanner(ctx *fiber.Ctx) error {
+	userID := ctx.Locals("user_id").(string)
+	eventID := ctx.Params("event_id")
+
+	var input dto.GrantScannerInput
+	if err := ctx.BodyParser(&input); err != nil {
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, "Invalid request body"), fiber.StatusBadRequest)
+	}
+
+	if err := c.validator.Struct(input); err != nil {
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, err.Error()), fiber.StatusBadRequest)
+	}
+
+	if err := c.service.GrantScanner(userID, eventID, input); err != nil {
+		return c.handleError(ctx, err)
+	}
+
+	return c.logHandler.LogSuccess(ctx, nil, "Scanner added successfully", true)
+}
+
+// RevokeScanner godoc
+// @Summary Stop a user scanning tickets at an event
+// @Description Revokes a user's scan:tickets permission for one of the organizer's events.
+// @Tags Check-in
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param event_id path string true "Event ID"
+// @Param user_id path string true "Scanner user ID"
+// @Success 200 {object} map[string]interface{} "Scanner removed successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid ID"
+// @Failure 404 {object} map[string]interface{} "Event, organizer or scanner not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /checkin/events/{event_id}/scanners/{user_id} [delete]
+func (c *CheckInController) RevokeScanner(ctx *fiber.Ctx) error {
+	userID := ctx.Locals("user_id").(string)
+	eventID := ctx.Params("event_id")
+	scannerID := ctx.Params("user_id")
+
+	if err := c.service.RevokeScanner(userID, eventID, scannerID); err != nil {
+		return c.handleError(ctx, err)
+	}
+
+	return c.logHandler.LogSuccess(ctx, nil, "Scanner removed successfully", true)
+}
+
+func (c *CheckInController) handleError(ctx *fiber.Ctx, err error) error {
+	switch err.Error() {
+	case "invalid user ID format", "invalid event ID format", "invalid ticket ID format":
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, err.Error()), fiber.StatusBadRequest)
+	case "user lacks permission to scan tickets for this event":
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusForbidden, err.Error()), fiber.StatusForbidden)
+	case "ticket not found", "event not found", "organizer not found", "user not found", "scanner not found":
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusNotFound, err.Error()), fiber.StatusNotFound)
+	case "ticket already checked in", "ticket is not checked in":
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusConflict, err.Error()), fiber.StatusConflict)
+	case "invalid ticket credential", "ticket is for another event", "ticket credential has been replaced",
+		"ticket has been canceled", "ticket has been refunded", "ticket is not valid for entry":
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusUnprocessableEntity, err.Error()), fiber.StatusUnprocessableEntity)
+	default:
+		return c.logHandler.LogError(ctx, err, fiber.StatusInternalServerError)
+	}
+}
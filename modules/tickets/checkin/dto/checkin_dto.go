@@ -0,0 +1,97 @@
+package dto
+
+import "time"
+
+// ScanTicketInput defines the input structure for scanning a ticket at the door
+type ScanTicketInput struct {
+	Credential string `json:"credential" example:"TZ1.k1.AbCd...signature" validate:"required,max=512"`
+	DeviceID   string `json:"device_id,omitempty" example:"gate-a-scanner-2" validate:"omitempty,max=100"`
+}
+
+// UndoCheckInInput defines the input structure for reversing a mistaken check-in
+type UndoCheckInInput struct {
+	Reason string `json:"reason,omitempty" example:"Scanned the wrong ticket" validate:"omitempty,max=255"`
+}
+
+// GrantScannerInput defines the input structure for letting a user scan tickets at an event
+type GrantScannerInput struct {
+	UserID string `json:"user_id" example:"a1d3c4e6-89ab-44ce-8e65-123456789abc" validate:"required,uuid"`
+}
+
+// CheckInResponse defines the response structure for a scanned ticket
+type CheckInResponse struct {
+	TicketID       string     `json:"ticket_id"`
+	TicketNumber   string     `json:"ticket_number"`
+	EventID        string     `json:"event_id"`
+	TicketTypeID   string     `json:"ticket_type_id"`
+	TicketTypeName string     `json:"ticket_type_name,omitempty"`
+	HolderName     string     `json:"holder_name,omitempty"`
+	SeatNumber     string     `json:"seat_number,omitempty"`
+	SeatSection    string     `json:"seat_section,omitempty"`
+	Status         string     `json:"status"`
+	CheckedInAt    *time.Time `json:"checked_in_at,omitempty"`
+	CheckedInBy    string     `json:"checked_in_by,omitempty"`
+}
+
+// ScannerResponse defines a user allowed to scan tickets at an event
+type ScannerResponse struct {
+	UserID    string    `json:"user_id"`
+	Username  string    `json:"username,omitempty"`
+	GrantedBy string    `json:"granted_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// OfflineManifest is the signed document an offline scanner checks tickets
+// against. A scanner verifies a credential's signature with PublicKeys, then
+// looks up the first HashPrefixBytes of SHA-256(credential) in Valid or
+// CheckedIn. Credentials missing from both lists are not admissible.
+type OfflineManifest struct {
+	Version         int               `json:"version"`
+	EventID         string            `json:"event_id"`
+	GeneratedAt     time.Time         `json:"generated_at"`
+	PublicKeys      map[string]string `json:"public_keys"`
+	HashPrefixBytes int               `json:"hash_prefix_bytes"`
+	Valid           []string          `json:"valid"`
+	CheckedIn       []string          `json:"checked_in"`
+}
+
+// OfflineManifestResponse carries the manifest as base64url JSON so the
+// signature covers exactly the bytes the scanner decodes
+type OfflineManifestResponse struct {
+	EventID     string    `json:"event_id"`
+	GeneratedAt time.Time `json:"generated_at"`
+	TicketCount int       `json:"ticket_count"`
+	Payload     string    `json:"payload"`
+	KeyID       string    `json:"key_id"`
+	Signature   string    `json:"signature"`
+}
+
+// OfflineScan is one check-in recorded by a scanner while offline
+type OfflineScan struct {
+	Credential string    `json:"credential" validate:"required,max=512"`
+	ScannedAt  time.Time `json:"scanned_at" validate:"required"`
+}
+
+// SyncCheckInsInput defines the input structure for uploading offline check-ins
+type SyncCheckInsInput struct {
+	DeviceID string        `json:"device_id,omitempty" example:"gate-a-scanner-2" validate:"omitempty,max=100"`
+	Scans    []OfflineScan `json:"scans" validate:"required,min=1,max=1000,dive"`
+}
+
+// SyncResult reports how one uploaded scan was reconciled
+type SyncResult struct {
+	Index       int        `json:"index"`
+	TicketID    string     `json:"ticket_id,omitempty"`
+	Result      string     `json:"result"`
+	Reason      string     `json:"reason,omitempty"`
+	CheckedInAt *time.Time `json:"checked_in_at,omitempty"`
+	CheckedInBy string     `json:"checked_in_by,omitempty"`
+}
+
+// SyncCheckInsResponse summarises an offline check-in upload
+type SyncCheckInsResponse struct {
+	Admitted   int          `json:"admitted"`
+	Duplicates int          `json:"duplicates"`
+	Rejected   int          `json:"rejected"`
+	Results    []SyncResult `json:"results"`
+}
@@ -0,0 +1,223 @@
+package checkin_service
+
+import (
+	"errors"
+	"strings"
+	organizers_services "ticket-zetu-api/modules/organizers/services"
+	"ticket-zetu-api/modules/tickets/checkin/dto"
+	ticket_credentials "ticket-zetu-api/modules/tickets/credentials"
+	"ticket-zetu-api/modules/tickets/models/tickets"
+	"ticket-zetu-api/modules/users/authorization/service"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ScanPermission lets a user scan tickets. Held through a role it covers
+// every event; granted per event it covers only that event.
+const ScanPermission = "scan:tickets"
+
+type CheckInService interface {
+	ScanTicket(userID, eventID string, input dto.ScanTicketInput) (*dto.CheckInResponse, error)
+	UndoCheckIn(userID, eventID, ticketID string, input dto.UndoCheckInInput) (*dto.CheckInResponse, error)
+	GetScanners(userID, eventID string) ([]dto.ScannerResponse, error)
+	GrantScanner(userID, eventID string, input dto.GrantScannerInput) error
+	RevokeScanner(userID, eventID, scannerID string) error
+	GetOfflineManifest(userID, eventID string) (*dto.OfflineManifestResponse, error)
+	SyncCheckIns(userID, eventID string, input dto.SyncCheckInsInput) (*dto.SyncCheckInsResponse, error)
+}
+
+type checkInService struct {
+	db                   *gorm.DB
+	authorizationService authorization_service.PermissionService
+	keyRing              *ticket_credentials.KeyRing
+}
+
+func NewCheckInService(db *gorm.DB, authService authorization_service.PermissionService, keyRing *ticket_credentials.KeyRing) CheckInService {
+	return &checkInService{
+		db:                   db,
+		authorizationService: authService,
+		keyRing:              keyRing,
+	}
+}
+
+// ScanTicket admits the holder of a credential to an event. The ticket is
+// locked while it is checked, so two scanners presenting the same ticket at
+// once admit it only once. Every scan, admitted or not, is recorded.
+func (s *checkInService) ScanTicket(userID, eventID string, input dto.ScanTicketInput) (*dto.CheckInResponse, error) {
+	if err := s.requireScanner(userID, eventID); err != nil {
+		return nil, err
+	}
+
+	scan := tickets.TicketScan{
+		EventID:   eventID,
+		ScannedBy: userID,
+		DeviceID:  input.DeviceID,
+		ScannedAt: time.Now(),
+	}
+
+	var ticket tickets.Ticket
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		claims, err := s.keyRing.Verify(strings.TrimSpace(input.Credential))
+		if err != nil {
+			return errors.New("invalid ticket credential")
+		}
+		scan.TicketID = claims.TicketID
+		if claims.EventID != eventID {
+			return errors.New("ticket is for another event")
+		}
+
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Preload("TicketType").
+			Preload("User").
+			Where("id = ? AND event_id = ?", claims.TicketID, eventID).
+			First(&ticket).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("ticket not found")
+			}
+			return err
+		}
+		// A ticket that was re-signed, for example after a transfer, only admits with its newest credential
+		if ticket.QRCodeHash != ticket_credentials.Hash(strings.TrimSpace(input.Credential)) {
+			return errors.New("ticket credential has been replaced")
+		}
+		if err := admissible(&ticket); err != nil {
+			return err
+		}
+
+		ticket.Status = tickets.TicketUsed
+		ticket.CheckedInAt = &scan.ScannedAt
+		ticket.CheckedInBy = userID
+		return tx.Model(&ticket).UpdateColumns(map[string]interface{}{
+			"status":        tickets.TicketUsed,
+			"checked_in_at": scan.ScannedAt,
+			"checked_in_by": userID,
+			"version":       gorm.Expr("version + 1"),
+			"updated_at":    time.Now(),
+		}).Error
+	})
+
+	scan.Result = tickets.ScanAdmitted
+	if err != nil {
+		scan.Result = tickets.ScanRejected
+		scan.Reason = err.Error()
+	}
+	// The audit record is best effort; it must not turn an admitted scan into a failure
+	s.db.Create(&scan)
+
+	if err != nil {
+		return nil, err
+	}
+	return toDTO(&ticket), nil
+}
+
+// UndoCheckIn returns a checked-in ticket to valid, for scans made by mistake
+func (s *checkInService) UndoCheckIn(userID, eventID, ticketID string, input dto.UndoCheckInInput) (*dto.CheckInResponse, error) {
+	if _, err := uuid.Parse(ticketID); err != nil {
+		return nil, errors.New("invalid ticket ID format")
+	}
+	if err := s.requireScanner(userID, eventID); err != nil {
+		return nil, err
+	}
+
+	var ticket tickets.Ticket
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Preload("TicketType").
+			Preload("User").
+			Where("id = ? AND event_id = ?", ticketID, eventID).
+			First(&ticket).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("ticket not found")
+			}
+			return err
+		}
+		if ticket.Status != tickets.TicketUsed {
+			return errors.New("ticket is not checked in")
+		}
+
+		ticket.Status = tickets.TicketValid
+		ticket.CheckedInAt = nil
+		ticket.CheckedInBy = ""
+		if err := tx.Model(&ticket).UpdateColumns(map[string]interface{}{
+			"status":        tickets.TicketValid,
+			"checked_in_at": nil,
+			"checked_in_by": "",
+			"version":       gorm.Expr("version + 1"),
+			"updated_at":    time.Now(),
+		}).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(&tickets.TicketScan{
+			TicketID:  ticket.ID,
+			EventID:   eventID,
+			ScannedBy: userID,
+			Result:    tickets.ScanUndone,
+			Reason:    input.Reason,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toDTO(&ticket), nil
+}
+
+// requireScanner allows the event's organizer and anyone holding ScanPermission for the event
+func (s *checkInService) requireScanner(userID, eventID string) error {
+	if _, err := uuid.Parse(userID); err != nil {
+		return errors.New("invalid user ID format")
+	}
+	if _, err := uuid.Parse(eventID); err != nil {
+		return errors.New("invalid event ID format")
+	}
+
+	if _, err := organizers_services.GetOwnedEvent(s.db, userID, eventID); err == nil {
+		return nil
+	} else if err.Error() != "event not found" && err.Error() != "organizer not found" {
+		return err
+	}
+
+	hasPerm, err := s.authorizationService.HasEventPermission(userID, eventID, ScanPermission)
+	if err != nil {
+		return err
+	}
+	if !hasPerm {
+		return errors.New("user lacks permission to scan tickets for this event")
+	}
+	return nil
+}
+
+// admissible explains why a ticket cannot be admitted, or returns nil
+func admissible(ticket *tickets.Ticket) error {
+	switch ticket.Status {
+	case tickets.TicketValid:
+		return nil
+	case tickets.TicketUsed:
+		return errors.New("ticket already checked in")
+	case tickets.TicketCanceled:
+		return errors.New("ticket has been canceled")
+	case tickets.TicketRefunded:
+		return errors.New("ticket has been refunded")
+	default:
+		return errors.New("ticket is not valid for entry")
+	}
+}
+
+func toDTO(ticket *tickets.Ticket) *dto.CheckInResponse {
+	return &dto.CheckInResponse{
+		TicketID:       ticket.ID,
+		TicketNumber:   ticket.TicketNumber,
+		EventID:        ticket.EventID,
+		TicketTypeID:   ticket.TicketTypeID,
+		TicketTypeName: ticket.TicketType.Name,
+		HolderName:     strings.TrimSpace(ticket.User.FirstName + " " + ticket.User.LastName),
+		SeatNumber:     ticket.SeatNumber,
+		SeatSection:    ticket.SeatSection,
+		Status:         string(ticket.Status),
+		CheckedInAt:    ticket.CheckedInAt,
+		CheckedInBy:    ticket.CheckedInBy,
+	}
+}
@@ -0,0 +1,233 @@
+package checkin_service
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+	"ticket-zetu-api/modules/tickets/checkin/dto"
+	ticket_credentials "ticket-zetu-api/modules/tickets/credentials"
+	"ticket-zetu-api/modules/tickets/models/tickets"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	manifestVersion = 1
+	// manifestHashPrefixBytes keeps the manifest small; 96 bits makes an accidental match negligible
+	manifestHashPrefixBytes = 12
+	// maxClockSkew bounds how far in the future an offline scan time may be before it is treated as now
+	maxClockSkew = 5 * time.Minute
+)
+
+const (
+	syncAdmitted  = "admitted"
+	syncDuplicate = "duplicate"
+	syncRejected  = "rejected"
+)
+
+// GetOfflineManifest exports a signed list of every admissible ticket for an
+// event, so scanners can keep checking tickets in without a connection
+func (s *checkInService) GetOfflineManifest(userID, eventID string) (*dto.OfflineManifestResponse, error) {
+	if err := s.requireScanner(userID, eventID); err != nil {
+		return nil, err
+	}
+
+	var issued []tickets.Ticket
+	if err := s.db.Select("id", "qr_code_hash", "status").
+		Where("event_id = ? AND status IN ?", eventID, []tickets.TicketStatus{tickets.TicketValid, tickets.TicketUsed}).
+		Find(&issued).Error; err != nil {
+		return nil, err
+	}
+
+	manifest := dto.OfflineManifest{
+		Version:         manifestVersion,
+		EventID:         eventID,
+		GeneratedAt:     time.Now().UTC().Truncate(time.Second),
+		PublicKeys:      make(map[string]string),
+		HashPrefixBytes: manifestHashPrefixBytes,
+		Valid:           []string{},
+		CheckedIn:       []string{},
+	}
+	for id, key := range s.keyRing.PublicKeys() {
+		manifest.PublicKeys[id] = base64.RawURLEncoding.EncodeToString(key)
+	}
+	for _, ticket := range issued {
+		hash, err := hex.DecodeString(ticket.QRCodeHash)
+		if err != nil || len(hash) < manifestHashPrefixBytes {
+			// Tickets issued before signed credentials have no scannable hash yet
+			continue
+		}
+		prefix := base64.RawURLEncoding.EncodeToString(hash[:manifestHashPrefixBytes])
+		if ticket.Status == tickets.TicketUsed {
+			manifest.CheckedIn = append(manifest.CheckedIn, prefix)
+		} else {
+			manifest.Valid = append(manifest.Valid, prefix)
+		}
+	}
+	sort.Strings(manifest.Valid)
+	sort.Strings(manifest.CheckedIn)
+
+	payload, err := json.Marshal(manifest)
+	if err != nil {
+		return nil, err
+	}
+	keyID, signature, err := s.keyRing.SignDocument(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.OfflineManifestResponse{
+		EventID:     eventID,
+		GeneratedAt: manifest.GeneratedAt,
+		TicketCount: len(manifest.Valid) + len(manifest.CheckedIn),
+		Payload:     base64.RawURLEncoding.EncodeToString(payload),
+		KeyID:       keyID,
+		Signature:   base64.RawURLEncoding.EncodeToString(signature),
+	}, nil
+}
+
+// SyncCheckIns reconciles check-ins made offline. Scans are applied in the
+// order they happened and the earliest scan of a ticket wins, even over an
+// online scan that reached the server first; every later scan of the same
+// ticket is reported as a duplicate. Uploading the same batch twice is safe.
+func (s *checkInService) SyncCheckIns(userID, eventID string, input dto.SyncCheckInsInput) (*dto.SyncCheckInsResponse, error) {
+	if err := s.requireScanner(userID, eventID); err != nil {
+		return nil, err
+	}
+
+	order := make([]int, len(input.Scans))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return input.Scans[order[a]].ScannedAt.Before(input.Scans[order[b]].ScannedAt)
+	})
+
+	response := &dto.SyncCheckInsResponse{Results: make([]dto.SyncResult, len(input.Scans))}
+	now := time.Now()
+	for _, index := range order {
+		scan := input.Scans[index]
+		scannedAt := scan.ScannedAt.Truncate(time.Second)
+		if scannedAt.After(now.Add(maxClockSkew)) {
+			scannedAt = now.Truncate(time.Second)
+		}
+
+		result, err := s.syncScan(userID, eventID, input.DeviceID, strings.TrimSpace(scan.Credential), scannedAt)
+		if err != nil {
+			return nil, err
+		}
+		result.Index = index
+		response.Results[index] = *result
+
+		switch result.Result {
+		case syncAdmitted:
+			response.Admitted++
+		case syncDuplicate:
+			response.Duplicates++
+		default:
+			response.Rejected++
+		}
+	}
+	return response, nil
+}
+
+// syncScan applies one offline scan. Problems with the scan itself are
+// reported in the result; only database failures are returned as errors.
+func (s *checkInService) syncScan(userID, eventID, deviceID, credential string, scannedAt time.Time) (*dto.SyncResult, error) {
+	result := &dto.SyncResult{}
+	audit := tickets.TicketScan{
+		EventID:   eventID,
+		ScannedBy: userID,
+		DeviceID:  deviceID,
+		Offline:   true,
+		ScannedAt: scannedAt,
+	}
+
+	claims, err := s.keyRing.Verify(credential)
+	if err != nil {
+		return s.reject(result, &audit, "invalid ticket credential")
+	}
+	result.TicketID = claims.TicketID
+	audit.TicketID = claims.TicketID
+	if claims.EventID != eventID {
+		return s.reject(result, &audit, "ticket is for another event")
+	}
+
+	alreadySynced := false
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		var ticket tickets.Ticket
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND event_id = ?", claims.TicketID, eventID).
+			First(&ticket).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				result.Result, result.Reason = syncRejected, "ticket not found"
+				return nil
+			}
+			return err
+		}
+		if ticket.QRCodeHash != ticket_credentials.Hash(credential) {
+			result.Result, result.Reason = syncRejected, "ticket credential has been replaced"
+			return nil
+		}
+
+		switch ticket.Status {
+		case tickets.TicketValid:
+			// First scan of this ticket anywhere
+		case tickets.TicketUsed:
+			if ticket.CheckedInAt != nil && ticket.CheckedInAt.Equal(scannedAt) && ticket.CheckedInBy == userID {
+				alreadySynced = true
+				result.Result = syncAdmitted
+				result.CheckedInAt, result.CheckedInBy = ticket.CheckedInAt, ticket.CheckedInBy
+				return nil
+			}
+			if ticket.CheckedInAt != nil && !scannedAt.Before(*ticket.CheckedInAt) {
+				result.Result, result.Reason = syncDuplicate, "ticket already checked in"
+				result.CheckedInAt, result.CheckedInBy = ticket.CheckedInAt, ticket.CheckedInBy
+				return nil
+			}
+			// This offline scan happened before the recorded check-in, so it wins
+		default:
+			result.Result, result.Reason = syncRejected, admissible(&ticket).Error()
+			return nil
+		}
+
+		if err := tx.Model(&ticket).UpdateColumns(map[string]interface{}{
+			"status":        tickets.TicketUsed,
+			"checked_in_at": scannedAt,
+			"checked_in_by": userID,
+			"version":       gorm.Expr("version + 1"),
+			"updated_at":    time.Now(),
+		}).Error; err != nil {
+			return err
+		}
+		result.Result = syncAdmitted
+		result.CheckedInAt, result.CheckedInBy = &scannedAt, userID
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if alreadySynced {
+		return result, nil
+	}
+
+	audit.Result = tickets.ScanAdmitted
+	if result.Result != syncAdmitted {
+		audit.Result = tickets.ScanRejected
+		audit.Reason = result.Reason
+	}
+	s.db.Create(&audit)
+	return result, nil
+}
+
+func (s *checkInService) reject(result *dto.SyncResult, audit *tickets.TicketScan, reason string) (*dto.SyncResult, error) {
+	result.Result, result.Reason = syncRejected, reason
+	audit.Result, audit.Reason = tickets.ScanRejected, reason
+	s.db.Create(audit)
+	return result, nil
+}
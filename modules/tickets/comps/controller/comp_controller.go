@@ -0,0 +1,217 @@
+package comp_controller
+
+import (
+	"ticket-zetu-api/logs/handler"
+	"ticket-zetu-api/modules/tickets/comps/dto"
+	"ticket-zetu-api/modules/tickets/comps/service"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+type CompController struct {
+	service    comp_service.CompService
+	logHandler *handler.LogHandler
+	validator  *validator.Validate
+}
+
+func NewCompController(service comp_service.CompService, logHandler *handler.LogHandler) *CompController {
+	return &CompController{
+		service:    service,
+		logHandler: logHandler,
+		validator:  validator.New(),
+	}
+}
+
+// IssueComps godoc
+// @Summary Issue complimentary tickets
+// @Description Issues free tickets of one of the organizer's ticket types to a list of users or emails, counting against its stock. Registered users get their tickets straight away; other emails are sent a link to claim them, with the tickets reserved until the event ends. Each recipient is issued separately and gets its own result.
+// @Tags Comps
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param ticket_type_id path string true "Ticket Type ID"
+// @Param input body dto.IssueCompsInput true "Recipients and comp details"
+// @Success 200 {object} map[string]interface{} "Comps issued"
+// @Failure 400 {object} map[string]interface{} "Invalid request body or event has ended"
+// @Failure 404 {object} map[string]interface{} "Ticket type or organizer not found"
+// @Failure 409 {object} map[string]interface{} "Event cancelled or not enough tickets available"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /comps/ticket-types/{ticket_type_id} [post]
+func (c *CompController) IssueComps(ctx *fiber.Ctx) error {
+	userID := ctx.Locals("user_id").(string)
+	ticketTypeID := ctx.Params("ticket_type_id")
+
+	var input dto.IssueCompsInput
+	if err := ctx.BodyParser(&input); err != nil {
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, "Invalid request body"), fiber.StatusBadRequest)
+	}
+
+	if err := c.validator.Struct(input); err != nil {
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, err.Error()), fiber.StatusBadRequest)
+	}
+
+	response, err := c.service.IssueComps(userID, ticketTypeID, input)
+	if err != nil {
+		return c.handleError(ctx, err)
+	}
+
+	return c.logHandler.LogSuccess(ctx, response, "Comps issued", true)
+}
+
+// IssueCompsCSV godoc
+// @Summary Issue complimentary tickets from a CSV
+// @Description Issues free tickets to every row of an uploaded CSV of up to 500 rows. The header names the columns: email or user_id, and optionally quantity, category and note, which otherwise fall back to the form fields. Rows are issued one by one and reported with their own result.
+// @Tags Comps
+// @Accept multipart/form-data
+// @Produce json
+// @Security ApiKeyAuth
+// @Param ticket_type_id path string true "Ticket Type ID"
+// @Param file formData file true "Recipient CSV"
+// @Param quantity formData int true "Default tickets per recipient"
+// @Param category formData string true "Default category (guest_list, press, sponsor)"
+// @Param note formData string false "Default note"
+// @Param transferable formData bool false "Whether the tickets can be transferred"
+// @Success 200 {object} map[string]interface{} "Comps issued"
+// @Failure 400 {object} map[string]interface{} "Invalid CSV or form fields, or event has ended"
+// @Failure 404 {object} map[string]interface{} "Ticket type or organizer not found"
+// @Failure 409 {object} map[string]interface{} "Event has been cancelled"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /comps/ticket-types/{ticket_type_id}/csv [post]
+func (c *CompController) IssueCompsCSV(ctx *fiber.Ctx) error {
+	userID := ctx.Locals("user_id").(string)
+	ticketTypeID := ctx.Params("ticket_type_id")
+
+	var defaults dto.CompCSVDefaults
+	if err := ctx.BodyParser(&defaults); err != nil {
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, "Invalid request body"), fiber.StatusBadRequest)
+	}
+
+	if err := c.validator.Struct(defaults); err != nil {
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, err.Error()), fiber.StatusBadRequest)
+	}
+
+	file, err := ctx.FormFile("file")
+	if err != nil {
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, "Failed to parse file"), fiber.StatusBadRequest)
+	}
+
+	if file.Size > 1024*1024 { // Limit file size to 1MB
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, "File size exceeds 1MB limit"), fiber.StatusBadRequest)
+	}
+
+	f, err := file.Open()
+	if err != nil {
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusInternalServerError, "Failed to open file"), fiber.StatusInternalServerError)
+	}
+	defer f.Close()
+
+	response, err := c.service.IssueCompsCSV(userID, ticketTypeID, defaults, f)
+	if err != nil {
+		return c.handleError(ctx, err)
+	}
+
+	return c.logHandler.LogSuccess(ctx, response, "Comps issued", true)
+}
+
+// GetComps godoc
+// @Summary Get a ticket type's comps
+// @Description Lists the complimentary grants issued for one of the organizer's ticket types, newest first, with the tickets each produced.
+// @Tags Comps
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param ticket_type_id path string true "Ticket Type ID"
+// @Success 200 {object} map[string]interface{} "Comps retrieved successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid ticket type ID"
+// @Failure 404 {object} map[string]interface{} "Ticket type or organizer not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /comps/ticket-types/{ticket_type_id} [get]
+func (c *CompController) GetComps(ctx *fiber.Ctx) error {
+	userID := ctx.Locals("user_id").(string)
+	ticketTypeID := ctx.Params("ticket_type_id")
+
+	comps, err := c.service.GetComps(userID, ticketTypeID)
+	if err != nil {
+		return c.handleError(ctx, err)
+	}
+
+	return c.logHandler.LogSuccess(ctx, comps, "Comps retrieved successfully", false)
+}
+
+// RevokeComp godoc
+// @Summary Revoke an unclaimed comp
+// @Description Withdraws a comp whose claim link has not been used yet and returns its reserved tickets to stock.
+// @Tags Comps
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Comp ID"
+// @Success 200 {object} map[string]interface{} "Comp revoked successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid comp ID"
+// @Failure 404 {object} map[string]interface{} "Comp not found"
+// @Failure 409 {object} map[string]interface{} "Comp has already been claimed"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /comps/{id} [delete]
+func (c *CompController) RevokeComp(ctx *fiber.Ctx) error {
+	userID := ctx.Locals("user_id").(string)
+	grantID := ctx.Params("id")
+
+	if err := c.service.RevokeComp(userID, grantID); err != nil {
+		return c.handleError(ctx, err)
+	}
+
+	return c.logHandler.LogSuccess(ctx, nil, "Comp revoked successfully", true)
+}
+
+// ClaimComp godoc
+// @Summary Claim complimentary tickets
+// @Description Issues the tickets of an emailed comp to the signed-in user. Each claim link works once, until the event ends.
+// @Tags Comps
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param input body dto.ClaimCompInput true "Claim token from the email"
+// @Success 200 {object} map[string]interface{} "Comp claimed successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid request body"
+// @Failure 404 {object} map[string]interface{} "Comp not found"
+// @Failure 409 {object} map[string]interface{} "Comp already claimed"
+// @Failure 410 {object} map[string]interface{} "Comp claim has expired"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /comps/claim [post]
+func (c *CompController) ClaimComp(ctx *fiber.Ctx) error {
+	userID := ctx.Locals("user_id").(string)
+
+	var input dto.ClaimCompInput
+	if err := ctx.BodyParser(&input); err != nil {
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, "Invalid request body"), fiber.StatusBadRequest)
+	}
+
+	if err := c.validator.Struct(input); err != nil {
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, err.Error()), fiber.StatusBadRequest)
+	}
+
+	grant, err := c.service.ClaimComp(userID, input)
+	if err != nil {
+		return c.handleError(ctx, err)
+	}
+
+	return c.logHandler.LogSuccess(ctx, grant, "Comp claimed successfully", true)
+}
+
+func (c *CompController) handleError(ctx *fiber.Ctx, err error) error {
+	switch err.Error() {
+	case "invalid user ID format", "invalid ticket type ID format", "invalid comp ID format", "event has already ended",
+		"csv file is empty", "invalid csv file", "csv needs an email or user_id column", "csv has more than 500 rows", "csv has no rows":
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, err.Error()), fiber.StatusBadRequest)
+	case "ticket type not found", "organizer not found", "comp not found", "user not found":
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusNotFound, err.Error()), fiber.StatusNotFound)
+	case "comp already claimed", "only unclaimed comps can be revoked", "not enough tickets available",
+		"event has been cancelled":
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusConflict, err.Error()), fiber.StatusConflict)
+	case "comp claim has expired":
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusGone, err.Error()), fiber.StatusGone)
+	default:
+		return c.logHandler.LogError(ctx, err, fiber.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,73 @@
+package dto
+
+import "time"
+
+// CompRecipientInput names one recipient of complimentary tickets, either a
+// registered user or an email. Quantity overrides the issue's default.
+type CompRecipientInput struct {
+	Email    string `json:"email,omitempty" example:"guest@example.com" validate:"omitempty,email,max=255"`
+	UserID   string `json:"user_id,omitempty" example:"c3f5e6a8-01cd-46e0-a087-345678901cde" validate:"omitempty,uuid"`
+	Quantity int    `json:"quantity,omitempty" example:"2" validate:"omitempty,gte=1,lte=50"`
+}
+
+// IssueCompsInput defines the input structure for issuing complimentary tickets
+type IssueCompsInput struct {
+	Recipients   []CompRecipientInput `json:"recipients" validate:"required,min=1,max=500,dive"`
+	Quantity     int                  `json:"quantity" example:"1" validate:"required,gte=1,lte=50"`
+	Category     string               `json:"category" example:"guest_list" validate:"required,oneof=guest_list press sponsor"`
+	Note         string               `json:"note,omitempty" example:"Artist guest list" validate:"max=255"`
+	Transferable bool                 `json:"transferable" example:"false"`
+}
+
+// CompCSVDefaults defines the form fields sent with a CSV upload. They apply
+// to every row that does not set its own quantity, category or note.
+type CompCSVDefaults struct {
+	Quantity     int    `form:"quantity" example:"1" validate:"required,gte=1,lte=50"`
+	Category     string `form:"category" example:"press" validate:"required,oneof=guest_list press sponsor"`
+	Note         string `form:"note" validate:"max=255"`
+	Transferable bool   `form:"transferable"`
+}
+
+// CompRowResult reports what happened to one recipient of an issue
+type CompRowResult struct {
+	Row       int      `json:"row"`
+	Email     string   `json:"email,omitempty"`
+	UserID    string   `json:"user_id,omitempty"`
+	Quantity  int      `json:"quantity,omitempty"`
+	Status    string   `json:"status"`
+	GrantID   string   `json:"grant_id,omitempty"`
+	TicketIDs []string `json:"ticket_ids,omitempty"`
+	Error     string   `json:"error,omitempty"`
+}
+
+// IssueCompsResponse defines the response structure for an issue, with one result per recipient
+type IssueCompsResponse struct {
+	TicketTypeID  string          `json:"ticket_type_id"`
+	Issued        int             `json:"issued"`
+	PendingClaims int             `json:"pending_claims"`
+	Failed        int             `json:"failed"`
+	Results       []CompRowResult `json:"results"`
+}
+
+// ClaimCompInput defines the input structure for claiming complimentary tickets from an emailed link
+type ClaimCompInput struct {
+	Token string `json:"token" validate:"required,len=64,hexadecimal"`
+}
+
+// CompGrantResponse defines the response structure for a complimentary grant
+type CompGrantResponse struct {
+	ID             string     `json:"id"`
+	EventID        string     `json:"event_id"`
+	TicketTypeID   string     `json:"ticket_type_id"`
+	Category       string     `json:"category"`
+	Email          string     `json:"email,omitempty"`
+	UserID         string     `json:"user_id,omitempty"`
+	Quantity       int        `json:"quantity"`
+	Status         string     `json:"status"`
+	Note           string     `json:"note,omitempty"`
+	Transferable   bool       `json:"transferable"`
+	TicketIDs      []string   `json:"ticket_ids,omitempty"`
+	ClaimExpiresAt *time.Time `json:"claim_expires_at,omitempty"`
+	ClaimedAt      *time.Time `json:"claimed_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
@@ -0,0 +1,251 @@
+package comp_service
+
+import (
+	"errors"
+	"fmt"
+	"ticket-zetu-api/modules/tickets/comps/dto"
+	inventory_service "ticket-zetu-api/modules/tickets/inventory/service"
+	"ticket-zetu-api/modules/tickets/models/tickets"
+	"ticket-zetu-api/modules/users/models/members"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GetComps lists the grants issued for one of the organizer's ticket types,
+// newest first, with the tickets each one produced
+func (s *compService) GetComps(userID, ticketTypeID string) ([]dto.CompGrantResponse, error) {
+	target, err := s.loadTarget(userID, ticketTypeID)
+	if err != nil {
+		return nil, err
+	}
+
+	var grants []tickets.CompGrant
+	if err := s.db.Where("ticket_type_id = ?", target.ticketType.ID).
+		Order("created_at DESC").
+		Find(&grants).Error; err != nil {
+		return nil, err
+	}
+
+	grantIDs := make([]string, len(grants))
+	for i := range grants {
+		grantIDs[i] = grants[i].ID
+	}
+	ticketIDs := map[string][]string{}
+	if len(grantIDs) > 0 {
+		var issued []tickets.Ticket
+		if err := s.db.Select("id", "payment_reference").
+			Where("payment_method = ? AND payment_reference IN ?", tickets.CompPaymentMethod, grantIDs).
+			Find(&issued).Error; err != nil {
+			return nil, err
+		}
+		for _, ticket := range issued {
+			ticketIDs[ticket.PaymentReference] = append(ticketIDs[ticket.PaymentReference], ticket.ID)
+		}
+	}
+
+	responses := make([]dto.CompGrantResponse, len(grants))
+	for i := range grants {
+		responses[i] = toGrantDTO(&grants[i], ticketIDs[grants[i].ID])
+	}
+	return responses, nil
+}
+
+// RevokeComp withdraws a grant that has not been claimed yet and returns its
+// reserved units to sale. Tickets already issued are not taken back.
+func (s *compService) RevokeComp(userID, grantID string) error {
+	if _, err := uuid.Parse(grantID); err != nil {
+		return errors.New("invalid comp ID format")
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		var grant tickets.CompGrant
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", grantID).First(&grant).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("comp not found")
+			}
+			return err
+		}
+		if _, err := s.loadTarget(userID, grant.TicketTypeID); err != nil {
+			if err.Error() == "ticket type not found" {
+				return errors.New("comp not found")
+			}
+			return err
+		}
+		if grant.Status != tickets.CompPendingClaim {
+			return errors.New("only unclaimed comps can be revoked")
+		}
+
+		if err := s.inventoryService.MoveIfTracked(tx, grant.TicketTypeID, inventory_service.BucketReserved, inventory_service.BucketAvailable, grant.Quantity); err != nil {
+			return err
+		}
+		return tx.Model(&tickets.CompGrant{}).Where("id = ?", grant.ID).
+			UpdateColumns(map[string]interface{}{
+				"status":     tickets.CompRevoked,
+				"updated_at": time.Now(),
+			}).Error
+	})
+}
+
+// ClaimComp issues the tickets of an emailed grant to the user holding its
+// link. The link works once, until the claim expires.
+func (s *compService) ClaimComp(userID string, input dto.ClaimCompInput) (*dto.CompGrantResponse, error) {
+	if _, err := uuid.Parse(userID); err != nil {
+		return nil, errors.New("invalid user ID format")
+	}
+
+	var grant tickets.CompGrant
+	var issued []tickets.Ticket
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("claim_token_hash = ?", hashClaimToken(input.Token)).
+			First(&grant).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("comp not found")
+			}
+			return err
+		}
+		switch {
+		case grant.Status == tickets.CompClaimed:
+			return errors.New("comp already claimed")
+		case grant.Status == tickets.CompExpired,
+			grant.ClaimExpiresAt != nil && !time.Now().Before(*grant.ClaimExpiresAt):
+			return errors.New("comp claim has expired")
+		case grant.Status != tickets.CompPendingClaim:
+			return errors.New("comp not found")
+		}
+
+		var user members.User
+		if err := tx.Select("id", "email").Where("id = ? AND deleted_at IS NULL", userID).First(&user).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("user not found")
+			}
+			return err
+		}
+
+		var err error
+		if issued, err = s.issueTickets(tx, &grant, user.ID); err != nil {
+			return err
+		}
+		if err := s.inventoryService.MoveIfTracked(tx, grant.TicketTypeID, inventory_service.BucketReserved, inventory_service.BucketSold, grant.Quantity); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		grant.Status = tickets.CompClaimed
+		grant.UserID = &user.ID
+		grant.ClaimedAt = &now
+		return tx.Model(&tickets.CompGrant{}).Where("id = ?", grant.ID).
+			UpdateColumns(map[string]interface{}{
+				"status":     grant.Status,
+				"user_id":    user.ID,
+				"claimed_at": now,
+				"updated_at": now,
+			}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ticketIDs := make([]string, len(issued))
+	for i, ticket := range issued {
+		ticketIDs[i] = ticket.ID
+	}
+	response := toGrantDTO(&grant, ticketIDs)
+	return &response, nil
+}
+
+// ExpireClaims closes up to limit grants whose claim period has ended and
+// returns their reserved units to sale, reporting how many it expired
+func (s *compService) ExpireClaims(limit int) (int, error) {
+	var due []tickets.CompGrant
+	if err := s.db.Select("id").
+		Where("status = ? AND claim_expires_at <= ?", tickets.CompPendingClaim, time.Now()).
+		Order("claim_expires_at ASC").
+		Limit(limit).
+		Find(&due).Error; err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	for _, candidate := range due {
+		released := false
+		err := s.db.Transaction(func(tx *gorm.DB) error {
+			var grant tickets.CompGrant
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+				Where("id = ? AND status = ?", candidate.ID, tickets.CompPendingClaim).
+				First(&grant).Error; err != nil {
+				// Claimed or revoked since it was listed
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return nil
+				}
+				return err
+			}
+			if err := s.inventoryService.MoveIfTracked(tx, grant.TicketTypeID, inventory_service.BucketReserved, inventory_service.BucketAvailable, grant.Quantity); err != nil {
+				return err
+			}
+			released = true
+			return tx.Model(&tickets.CompGrant{}).Where("id = ?", grant.ID).
+				UpdateColumns(map[string]interface{}{
+					"status":     tickets.CompExpired,
+					"updated_at": time.Now(),
+				}).Error
+		})
+		if err != nil {
+			return expired, err
+		}
+		if released {
+			expired++
+		}
+	}
+	return expired, nil
+}
+
+// sendClaimLink is best effort; a grant whose email could not be queued can
+// be revoked and issued again
+func (s *compService) sendClaimLink(grant *tickets.CompGrant, target *compTarget, token string) {
+	if err := s.emailService.SendCompTicketEmail(nil, grant.Email, target.event.Title, target.ticketType.Name, grant.Quantity, token, *grant.ClaimExpiresAt); err != nil {
+		fmt.Printf("Failed to send comp ticket email: %v\n", err)
+	}
+}
+
+// notifyIssued tells a registered user about tickets they were comped, on
+// behalf of the organizer running the event
+func (s *compService) notifyIssued(grant *tickets.CompGrant, target *compTarget, recipientID string) {
+	title := "Complimentary tickets"
+	content := fmt.Sprintf("%s has given you %d %s ticket(s) for %s.", target.organizer.Name, grant.Quantity, target.ticketType.Name, target.event.Title)
+	metadata := map[string]interface{}{
+		"ticket_type_id": grant.TicketTypeID,
+		"event_id":       grant.EventID,
+		"quantity":       grant.Quantity,
+		"category":       grant.Category,
+	}
+	if err := s.notificationService.TriggerNotification("tickets", "comp_issued", title, content, target.organizer.CreatedBy, grant.ID, []string{recipientID}, metadata); err != nil {
+		fmt.Printf("Failed to send comp_issued notification: %v\n", err)
+	}
+}
+
+func toGrantDTO(grant *tickets.CompGrant, ticketIDs []string) dto.CompGrantResponse {
+	response := dto.CompGrantResponse{
+		ID:             grant.ID,
+		EventID:        grant.EventID,
+		TicketTypeID:   grant.TicketTypeID,
+		Category:       string(grant.Category),
+		Email:          grant.Email,
+		Quantity:       grant.Quantity,
+		Status:         string(grant.Status),
+		Note:           grant.Note,
+		Transferable:   grant.Transferable,
+		TicketIDs:      ticketIDs,
+		ClaimExpiresAt: grant.ClaimExpiresAt,
+		ClaimedAt:      grant.ClaimedAt,
+		CreatedAt:      grant.CreatedAt,
+	}
+	if grant.UserID != nil {
+		response.UserID = *grant.UserID
+	}
+	return response
+}
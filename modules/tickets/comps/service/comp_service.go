@@ -0,0 +1,381 @@
+package comp_service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"strings"
+	"ticket-zetu-api/modules/events/models/events"
+	notification_service "ticket-zetu-api/modules/notifications/service"
+	organizers "ticket-zetu-api/modules/organizers/models"
+	organizers_services "ticket-zetu-api/modules/organizers/services"
+	"ticket-zetu-api/modules/tickets/comps/dto"
+	ticket_credentials "ticket-zetu-api/modules/tickets/credentials"
+	inventory_service "ticket-zetu-api/modules/tickets/inventory/service"
+	"ticket-zetu-api/modules/tickets/models/tickets"
+	mail_service "ticket-zetu-api/modules/users/authentication/mail"
+	"ticket-zetu-api/modules/users/authorization/service"
+	"ticket-zetu-api/modules/users/models/members"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// maxCompQuantity caps the tickets a single recipient can be comped at once
+const maxCompQuantity = 50
+
+// Row statuses reported back for each recipient of an issue
+const (
+	rowIssued  = "issued"
+	rowPending = "pending_claim"
+	rowFailed  = "failed"
+)
+
+type CompService interface {
+	IssueComps(userID, ticketTypeID string, input dto.IssueCompsInput) (*dto.IssueCompsResponse, error)
+	IssueCompsCSV(userID, ticketTypeID string, defaults dto.CompCSVDefaults, file io.Reader) (*dto.IssueCompsResponse, error)
+	GetComps(userID, ticketTypeID string) ([]dto.CompGrantResponse, error)
+	RevokeComp(userID, grantID string) error
+	ClaimComp(userID string, input dto.ClaimCompInput) (*dto.CompGrantResponse, error)
+	ExpireClaims(limit int) (int, error)
+}
+
+type compService struct {
+	db                   *gorm.DB
+	authorizationService authorization_service.PermissionService
+	inventoryService     inventory_service.InventoryService
+	notificationService  notification_service.NotificationService
+	emailService         mail_service.EmailService
+	keyRing              *ticket_credentials.KeyRing
+}
+
+func NewCompService(db *gorm.DB, authService authorization_service.PermissionService, inventoryService inventory_service.InventoryService, notificationService notification_service.NotificationService, emailService mail_service.EmailService, keyRing *ticket_credentials.KeyRing) CompService {
+	return &compService{
+		db:                   db,
+		authorizationService: authService,
+		inventoryService:     inventoryService,
+		notificationService:  notificationService,
+		emailService:         emailService,
+		keyRing:              keyRing,
+	}
+}
+
+// compRow is one recipient to issue to, from a JSON request or a CSV line
+type compRow struct {
+	row          int
+	email        string
+	userID       string
+	quantity     int
+	category     tickets.CompCategory
+	note         string
+	transferable bool
+	// err is set when the row could not be read
+	err error
+}
+
+// compTarget is a ticket type being comped, with its event and organizer
+type compTarget struct {
+	ticketType *tickets.TicketType
+	event      *events.Event
+	organizer  *organizers.Organizer
+}
+
+// IssueComps issues complimentary tickets of one of the organizer's ticket
+// types to each recipient
+func (s *compService) IssueComps(userID, ticketTypeID string, input dto.IssueCompsInput) (*dto.IssueCompsResponse, error) {
+	target, err := s.getTarget(userID, ticketTypeID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]compRow, len(input.Recipients))
+	for i, recipient := range input.Recipients {
+		rows[i] = compRow{
+			row:          i + 1,
+			email:        strings.TrimSpace(recipient.Email),
+			userID:       recipient.UserID,
+			quantity:     input.Quantity,
+			category:     tickets.CompCategory(input.Category),
+			note:         input.Note,
+			transferable: input.Transferable,
+		}
+		if recipient.Quantity > 0 {
+			rows[i].quantity = recipient.Quantity
+		}
+	}
+	return s.issueRows(userID, target, rows), nil
+}
+
+// IssueCompsCSV issues complimentary tickets to every row of an uploaded CSV.
+// Each row is issued on its own, so a bad row or running out of stock fails
+// only that row; the rest are still tried and earlier rows are kept.
+func (s *compService) IssueCompsCSV(userID, ticketTypeID string, defaults dto.CompCSVDefaults, file io.Reader) (*dto.IssueCompsResponse, error) {
+	target, err := s.getTarget(userID, ticketTypeID)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := parseCompCSV(file, defaults)
+	if err != nil {
+		return nil, err
+	}
+	return s.issueRows(userID, target, rows), nil
+}
+
+func (s *compService) issueRows(issuerID string, target *compTarget, rows []compRow) *dto.IssueCompsResponse {
+	response := &dto.IssueCompsResponse{
+		TicketTypeID: target.ticketType.ID,
+		Results:      make([]dto.CompRowResult, len(rows)),
+	}
+	for i := range rows {
+		result := s.issueRow(issuerID, target, &rows[i])
+		switch result.Status {
+		case rowIssued:
+			response.Issued++
+		case rowPending:
+			response.PendingClaims++
+		default:
+			response.Failed++
+		}
+		response.Results[i] = result
+	}
+	return response
+}
+
+// issueRow issues one recipient's tickets in a transaction of its own.
+// Registered users get their tickets straight away; other emails get a link
+// to claim them, with the units reserved until then.
+func (s *compService) issueRow(issuerID string, target *compTarget, row *compRow) dto.CompRowResult {
+	result := dto.CompRowResult{
+		Row:      row.row,
+		Email:    row.email,
+		UserID:   row.userID,
+		Quantity: row.quantity,
+		Status:   rowFailed,
+	}
+	if row.err == nil {
+		row.err = validateRow(row)
+	}
+	if row.err != nil {
+		result.Error = row.err.Error()
+		return result
+	}
+
+	var grant tickets.CompGrant
+	var issued []tickets.Ticket
+	var token string
+	var recipient *members.User
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		if recipient, err = findRecipient(tx, row); err != nil {
+			return err
+		}
+
+		grant = tickets.CompGrant{
+			ID:           uuid.New().String(),
+			EventID:      target.event.ID,
+			TicketTypeID: target.ticketType.ID,
+			IssuedBy:     issuerID,
+			Category:     row.category,
+			Email:        row.email,
+			Quantity:     row.quantity,
+			Note:         row.note,
+			Transferable: row.transferable,
+		}
+
+		if recipient == nil {
+			if err := s.inventoryService.MoveIfTracked(tx, target.ticketType.ID, inventory_service.BucketAvailable, inventory_service.BucketReserved, row.quantity); err != nil {
+				return err
+			}
+			if token, err = newClaimToken(); err != nil {
+				return err
+			}
+			hash := hashClaimToken(token)
+			grant.Status = tickets.CompPendingClaim
+			grant.ClaimTokenHash = &hash
+			grant.ClaimExpiresAt = &target.event.EndTime
+			return tx.Create(&grant).Error
+		}
+
+		if err := s.inventoryService.MoveIfTracked(tx, target.ticketType.ID, inventory_service.BucketAvailable, inventory_service.BucketSold, row.quantity); err != nil {
+			return err
+		}
+		grant.Status = tickets.CompIssued
+		grant.UserID = &recipient.ID
+		grant.Email = recipient.Email
+		if err := tx.Create(&grant).Error; err != nil {
+			return err
+		}
+		issued, err = s.issueTickets(tx, &grant, recipient.ID)
+		return err
+	})
+	if errors.Is(err, inventory_service.ErrInsufficientStock) {
+		err = errors.New("not enough tickets available")
+	}
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	result.GrantID = grant.ID
+	result.Email = grant.Email
+	if recipient == nil {
+		result.Status = rowPending
+		s.sendClaimLink(&grant, target, token)
+		return result
+	}
+	result.Status = rowIssued
+	result.UserID = recipient.ID
+	for _, ticket := range issued {
+		result.TicketIDs = append(result.TicketIDs, ticket.ID)
+	}
+	s.notifyIssued(&grant, target, recipient.ID)
+	return result
+}
+
+// issueTickets creates a grant's tickets for their owner, free of charge and
+// paid for by the grant
+func (s *compService) issueTickets(tx *gorm.DB, grant *tickets.CompGrant, ownerID string) ([]tickets.Ticket, error) {
+	now := time.Now()
+	issued := make([]tickets.Ticket, 0, grant.Quantity)
+	for i := 0; i < grant.Quantity; i++ {
+		ticket := tickets.Ticket{
+			ID:               uuid.New().String(),
+			EventID:          grant.EventID,
+			UserID:           ownerID,
+			TicketTypeID:     grant.TicketTypeID,
+			Status:           tickets.TicketValid,
+			PaymentReference: grant.ID,
+			PaymentMethod:    tickets.CompPaymentMethod,
+			ActualPrice:      0,
+			PurchaseTime:     now,
+			Notes:            grant.Note,
+			IsTransferable:   grant.Transferable,
+		}
+		if _, err := s.keyRing.Sign(&ticket); err != nil {
+			return nil, err
+		}
+		issued = append(issued, ticket)
+	}
+	if err := tx.Create(&issued).Error; err != nil {
+		return nil, err
+	}
+	// is_transferable defaults to true, which gorm uses in place of a false
+	// value on insert, so untransferable comps are switched off afterwards
+	if !grant.Transferable {
+		if err := tx.Model(&tickets.Ticket{}).
+			Where("payment_method = ? AND payment_reference = ?", tickets.CompPaymentMethod, grant.ID).
+			UpdateColumn("is_transferable", false).Error; err != nil {
+			return nil, err
+		}
+	}
+	return issued, nil
+}
+
+// getTarget loads a ticket type to issue comps for, which has to belong to an
+// event that has not ended or been cancelled. It can be one that is not on
+// sale.
+func (s *compService) getTarget(userID, ticketTypeID string) (*compTarget, error) {
+	target, err := s.loadTarget(userID, ticketTypeID)
+	if err != nil {
+		return nil, err
+	}
+	if target.event.Status == events.EventCancelled {
+		return nil, errors.New("event has been cancelled")
+	}
+	if target.event.Status == events.EventCompleted || !time.Now().Before(target.event.EndTime) {
+		return nil, errors.New("event has already ended")
+	}
+	return target, nil
+}
+
+// loadTarget loads a ticket type of one of the user's organizer's events
+func (s *compService) loadTarget(userID, ticketTypeID string) (*compTarget, error) {
+	if _, err := uuid.Parse(userID); err != nil {
+		return nil, errors.New("invalid user ID format")
+	}
+	if _, err := uuid.Parse(ticketTypeID); err != nil {
+		return nil, errors.New("invalid ticket type ID format")
+	}
+
+	organizer, err := organizers_services.GetUserOrganizer(s.db, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var ticketType tickets.TicketType
+	if err := s.db.Where("id = ? AND deleted_at IS NULL", ticketTypeID).First(&ticketType).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("ticket type not found")
+		}
+		return nil, err
+	}
+	var event events.Event
+	if err := s.db.Where("id = ? AND organizer_id = ? AND deleted_at IS NULL", ticketType.EventID, organizer.ID).First(&event).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("ticket type not found")
+		}
+		return nil, err
+	}
+	return &compTarget{ticketType: &ticketType, event: &event, organizer: organizer}, nil
+}
+
+// findRecipient resolves a row to a registered user, or nil for an email
+// without an account
+func findRecipient(tx *gorm.DB, row *compRow) (*members.User, error) {
+	var user members.User
+	query := tx.Where("deleted_at IS NULL")
+	if row.userID != "" {
+		query = query.Where("id = ?", row.userID)
+	} else {
+		query = query.Where("LOWER(email) = ?", strings.ToLower(row.email))
+	}
+	err := query.First(&user).Error
+	switch {
+	case err == nil:
+		return &user, nil
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, err
+	case row.userID != "":
+		return nil, errors.New("user not found")
+	}
+	return nil, nil
+}
+
+func validateRow(row *compRow) error {
+	if row.email == "" && row.userID == "" {
+		return errors.New("email or user_id is required")
+	}
+	if row.userID != "" {
+		if _, err := uuid.Parse(row.userID); err != nil {
+			return errors.New("invalid user ID format")
+		}
+	}
+	if row.quantity <= 0 || row.quantity > maxCompQuantity {
+		return errors.New("quantity must be between 1 and 50")
+	}
+	switch row.category {
+	case tickets.CompGuestList, tickets.CompPress, tickets.CompSponsor:
+	default:
+		return errors.New("category must be one of guest_list, press, sponsor")
+	}
+	if len(row.note) > 255 {
+		return errors.New("note must be 255 characters or less")
+	}
+	return nil
+}
+
+func newClaimToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+func hashClaimToken(token string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(token)))
+	return hex.EncodeToString(sum[:])
+}
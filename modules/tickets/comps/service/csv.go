@@ -0,0 +1,104 @@
+package comp_service
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"ticket-zetu-api/modules/tickets/comps/dto"
+	"ticket-zetu-api/modules/tickets/models/tickets"
+)
+
+// maxCSVRows caps how many recipients one upload can issue to
+const maxCSVRows = 500
+
+// parseCompCSV reads an uploaded recipient list. The first line is a header
+// naming the columns; email or user_id is required and quantity, category
+// and note are optional, falling back to the upload's defaults. A row that
+// cannot be read is kept with its error so it shows up in the results.
+func parseCompCSV(file io.Reader, defaults dto.CompCSVDefaults) ([]compRow, error) {
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("csv file is empty")
+		}
+		return nil, errors.New("invalid csv file")
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		columns[name] = i
+	}
+	_, hasEmail := columns["email"]
+	_, hasUser := columns["user_id"]
+	if !hasEmail && !hasUser {
+		return nil, errors.New("csv needs an email or user_id column")
+	}
+
+	var rows []compRow
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err == nil && isBlank(record) {
+			continue
+		}
+		if len(rows) == maxCSVRows {
+			return nil, errors.New("csv has more than 500 rows")
+		}
+
+		row := compRow{
+			row:          line,
+			quantity:     defaults.Quantity,
+			category:     tickets.CompCategory(defaults.Category),
+			note:         defaults.Note,
+			transferable: defaults.Transferable,
+		}
+		if err != nil {
+			row.err = errors.New("row could not be read")
+			rows = append(rows, row)
+			continue
+		}
+
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		row.email = field("email")
+		row.userID = field("user_id")
+		if quantity := field("quantity"); quantity != "" {
+			if row.quantity, err = strconv.Atoi(quantity); err != nil {
+				row.err = errors.New("quantity must be a number")
+			}
+		}
+		if category := field("category"); category != "" {
+			row.category = tickets.CompCategory(strings.ToLower(category))
+		}
+		if note := field("note"); note != "" {
+			row.note = note
+		}
+		rows = append(rows, row)
+	}
+
+	if len(rows) == 0 {
+		return nil, errors.New("csv has no rows")
+	}
+	return rows, nil
+}
+
+func isBlank(record []string) bool {
+	for _, value := range record {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
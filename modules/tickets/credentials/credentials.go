@@ -0,0 +1,274 @@
+package ticket_credentials
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"ticket-zetu-api/modules/tickets/models/tickets"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// A credential is the text encoded in a ticket's QR code:
+//
+//	TZ1.<key id>.<payload>.<signature>
+//
+// The payload is the ticket ID, event ID and ticket type ID as raw 16-byte
+// UUIDs followed by the issue time as big-endian unix seconds. Payload and
+// signature are unpadded base64url. Ed25519 signatures are deterministic, so
+// only a hash of the credential needs to be stored on the ticket; the
+// credential itself can be rebuilt from the ticket whenever it is needed.
+const (
+	prefix      = "TZ1"
+	payloadSize = 16*3 + 8
+)
+
+var (
+	ErrInvalidCredential = errors.New("invalid ticket credential")
+	ErrUnknownKey        = errors.New("ticket credential signed with an unknown key")
+	ErrNotConfigured     = errors.New("ticket signing is not configured")
+	// ErrNotReproducible means no key in the ring rebuilds the ticket's stored
+	// credential, for example because its key was retired; re-sign the ticket.
+	ErrNotReproducible = errors.New("ticket credential cannot be reproduced")
+)
+
+// Claims are the facts a scanner learns from a verified credential
+type Claims struct {
+	KeyID        string
+	TicketID     string
+	EventID      string
+	TicketTypeID string
+	IssuedAt     time.Time
+}
+
+// KeyRing signs new credentials with its active key and verifies credentials
+// signed by any of its keys, so tickets keep working after a rotation as long
+// as the old key stays in the ring.
+type KeyRing struct {
+	activeID string
+	keys     map[string]ed25519.PrivateKey
+}
+
+var (
+	defaultRing     *KeyRing
+	defaultRingOnce sync.Once
+)
+
+// DefaultKeyRing returns the process-wide key ring loaded from the environment
+func DefaultKeyRing() *KeyRing {
+	defaultRingOnce.Do(func() {
+		ring, err := NewKeyRingFromEnv()
+		if err != nil {
+			log.Printf("ticket signing keys could not be loaded: %v", err)
+			ring = &KeyRing{keys: map[string]ed25519.PrivateKey{}}
+		}
+		defaultRing = ring
+	})
+	return defaultRing
+}
+
+// NewKeyRingFromEnv reads TICKET_SIGNING_KEYS, a comma-separated list of
+// "<key id>:<base64 32-byte seed>" entries. The first entry signs new tickets
+// unless TICKET_SIGNING_ACTIVE_KEY names another. To rotate, put the new key
+// first and keep the old one listed until its tickets have expired. Without
+// keys the ring fails to load, unless TICKET_SIGNING_DEV_KEY=true asks for a
+// fixed development key outside production.
+func NewKeyRingFromEnv() (*KeyRing, error) {
+	raw := strings.TrimSpace(os.Getenv("TICKET_SIGNING_KEYS"))
+	if raw == "" {
+		if os.Getenv("GO_ENV") == "production" || os.Getenv("TICKET_SIGNING_DEV_KEY") != "true" {
+			return nil, ErrNotConfigured
+		}
+		seed := sha256.Sum256([]byte("ticket-zetu development ticket signing key"))
+		return NewKeyRing("dev", map[string][]byte{"dev": seed[:]})
+	}
+
+	seeds := make(map[string][]byte)
+	activeID := os.Getenv("TICKET_SIGNING_ACTIVE_KEY")
+	for _, entry := range strings.Split(raw, ",") {
+		id, encoded, found := strings.Cut(strings.TrimSpace(entry), ":")
+		if !found || id == "" {
+			return nil, errors.New("TICKET_SIGNING_KEYS entries must look like <key id>:<base64 seed>")
+		}
+		seed, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("ticket signing key %q is not valid base64", id)
+		}
+		seeds[id] = seed
+		if activeID == "" {
+			activeID = id
+		}
+	}
+	return NewKeyRing(activeID, seeds)
+}
+
+// NewKeyRing builds a ring from 32-byte Ed25519 seeds keyed by key ID
+func NewKeyRing(activeID string, seeds map[string][]byte) (*KeyRing, error) {
+	ring := &KeyRing{activeID: activeID, keys: make(map[string]ed25519.PrivateKey)}
+	for id, seed := range seeds {
+		if strings.Contains(id, ".") {
+			return nil, fmt.Errorf("ticket signing key id %q cannot contain '.'", id)
+		}
+		if len(seed) != ed25519.SeedSize {
+			return nil, fmt.Errorf("ticket signing key %q must be a %d-byte seed", id, ed25519.SeedSize)
+		}
+		ring.keys[id] = ed25519.NewKeyFromSeed(seed)
+	}
+	if _, ok := ring.keys[activeID]; !ok {
+		return nil, fmt.Errorf("active ticket signing key %q is not in the key ring", activeID)
+	}
+	return ring, nil
+}
+
+// PublicKeys returns the verification key for every key in the ring
+func (r *KeyRing) PublicKeys() map[string]ed25519.PublicKey {
+	keys := make(map[string]ed25519.PublicKey, len(r.keys))
+	for id, key := range r.keys {
+		keys[id] = key.Public().(ed25519.PublicKey)
+	}
+	return keys
+}
+
+// Sign issues a new credential for the ticket with the active key, setting
+// CredentialIssuedAt and storing the credential's hash in QRCodeHash. Every
+// call yields a different credential, so re-signing a ticket, for example on
+// transfer, invalidates its previous QR code. The issue time is whole seconds
+// so that it survives the round trip through the database.
+func (r *KeyRing) Sign(ticket *tickets.Ticket) (string, error) {
+	issuedAt := time.Now().Truncate(time.Second)
+	if ticket.CredentialIssuedAt != nil && !issuedAt.After(*ticket.CredentialIssuedAt) {
+		issuedAt = ticket.CredentialIssuedAt.Truncate(time.Second).Add(time.Second)
+	}
+	previous := ticket.CredentialIssuedAt
+	ticket.CredentialIssuedAt = &issuedAt
+
+	credential, err := r.sign(r.activeID, ticket)
+	if err != nil {
+		ticket.CredentialIssuedAt = previous
+		return "", err
+	}
+	ticket.QRCodeHash = Hash(credential)
+	return credential, nil
+}
+
+// Credential rebuilds the credential an issued ticket was signed with
+func (r *KeyRing) Credential(ticket *tickets.Ticket) (string, error) {
+	if ticket.CredentialIssuedAt == nil {
+		return "", ErrNotReproducible
+	}
+	for id := range r.keys {
+		credential, err := r.sign(id, ticket)
+		if err != nil {
+			return "", err
+		}
+		if Hash(credential) == ticket.QRCodeHash {
+			return credential, nil
+		}
+	}
+	return "", ErrNotReproducible
+}
+
+// Verify checks a credential's signature and returns its claims. It does not
+// know whether the ticket is still valid; callers compare Hash(credential)
+// with the stored QRCodeHash and check the ticket status.
+func (r *KeyRing) Verify(credential string) (*Claims, error) {
+	parts := strings.Split(credential, ".")
+	if len(parts) != 4 || parts[0] != prefix {
+		return nil, ErrInvalidCredential
+	}
+	key, ok := r.keys[parts[1]]
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || len(payload) != payloadSize {
+		return nil, ErrInvalidCredential
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[3])
+	if err != nil {
+		return nil, ErrInvalidCredential
+	}
+	if !ed25519.Verify(key.Public().(ed25519.PublicKey), signedBytes(parts[1], payload), signature) {
+		return nil, ErrInvalidCredential
+	}
+
+	claims := &Claims{KeyID: parts[1], IssuedAt: time.Unix(int64(binary.BigEndian.Uint64(payload[48:])), 0)}
+	ids := []*string{&claims.TicketID, &claims.EventID, &claims.TicketTypeID}
+	for i, id := range ids {
+		parsed, err := uuid.FromBytes(payload[i*16 : (i+1)*16])
+		if err != nil {
+			return nil, ErrInvalidCredential
+		}
+		*id = parsed.String()
+	}
+	return claims, nil
+}
+
+func (r *KeyRing) sign(keyID string, ticket *tickets.Ticket) (string, error) {
+	key, ok := r.keys[keyID]
+	if !ok {
+		return "", ErrNotConfigured
+	}
+
+	payload := make([]byte, 0, payloadSize)
+	for _, id := range []string{ticket.ID, ticket.EventID, ticket.TicketTypeID} {
+		parsed, err := uuid.Parse(id)
+		if err != nil {
+			return "", errors.New("ticket IDs must be UUIDs to be signed")
+		}
+		payload = append(payload, parsed[:]...)
+	}
+	payload = binary.BigEndian.AppendUint64(payload, uint64(ticket.CredentialIssuedAt.Unix()))
+
+	signature := ed25519.Sign(key, signedBytes(keyID, payload))
+	return strings.Join([]string{
+		prefix,
+		keyID,
+		base64.RawURLEncoding.EncodeToString(payload),
+		base64.RawURLEncoding.EncodeToString(signature),
+	}, "."), nil
+}
+
+// signedBytes binds the key ID into the signature so a credential cannot be relabelled with another key
+func signedBytes(keyID string, payload []byte) []byte {
+	return append([]byte(prefix+"."+keyID+"."), payload...)
+}
+
+// Hash is the value stored in Ticket.QRCodeHash for a credential
+func Hash(credential string) string {
+	sum := sha256.Sum256([]byte(credential))
+	return hex.EncodeToString(sum[:])
+}
+
+// documentPrefix separates document signatures from credential signatures,
+// so a signed document can never be presented as a ticket
+const documentPrefix = "TZDOC1."
+
+// SignDocument signs an arbitrary document, such as an offline scanner
+// manifest, with the active key
+func (r *KeyRing) SignDocument(document []byte) (keyID string, signature []byte, err error) {
+	key, ok := r.keys[r.activeID]
+	if !ok {
+		return "", nil, ErrNotConfigured
+	}
+	return r.activeID, ed25519.Sign(key, append([]byte(documentPrefix+r.activeID+"."), document...)), nil
+}
+
+// VerifyDocument checks a signature made by SignDocument
+func (r *KeyRing) VerifyDocument(keyID string, document, signature []byte) bool {
+	key, ok := r.keys[keyID]
+	if !ok {
+		return false
+	}
+	return ed25519.Verify(key.Public().(ed25519.PublicKey), append([]byte(documentPrefix+keyID+"."), document...), signature)
+}
@@ -167,6 +167,7 @@ func (c *DiscountController) CancelDiscount(ctx *fiber.Ctx) error {
 // @Produce json
 // @Param code query string true "Discount code"
 // @Param event_id query string false "Event ID"
+// @Param ticket_type_id query string false "Ticket type ID"
 // @Param order_value query number false "Order value"
 // @Success 200 {object} map[string]interface{} "Discount is valid"
 // @Failure 400 {object} map[string]interface{} "Invalid discount code or validation failed"
@@ -179,9 +180,10 @@ func (c *DiscountController) ValidateDiscount(ctx *fiber.Ctx) error {
 	}
 
 	eventID := ctx.Query("event_id")
+	ticketTypeID := ctx.Query("ticket_type_id")
 	orderValue := ctx.QueryFloat("order_value", 0)
 
-	discount, err := c.service.ValidateDiscountCode(code, eventID, orderValue)
+	discount, err := c.service.ValidateDiscountCode(code, eventID, ticketTypeID, orderValue)
 	if err != nil {
 		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, err.Error()), fiber.StatusBadRequest)
 	}
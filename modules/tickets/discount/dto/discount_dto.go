@@ -20,6 +20,7 @@ type CreateDiscountCodeInput struct {
 	PromoterID    string                 `json:"promoter_id,omitempty" example:"22bb86ce-89af-4e2d-9a47-00aa12345678"`
 	MinOrderValue float64                `json:"min_order_value" binding:"gte=0" example:"500.00"`
 	IsSingleUse   bool                   `json:"is_single_use" binding:"required" example:"false"`
+	TicketTypeIDs []string               `json:"ticket_type_ids,omitempty" example:"a1d3c4e6-89ab-44ce-8e65-123456789abc"`
 }
 
 // UpdateDiscountCodeInput defines the input structure for updating a discount code
@@ -37,6 +38,7 @@ type UpdateDiscountCodeInput struct {
 	PromoterID    string                 `json:"promoter_id,omitempty"`
 	MinOrderValue float64                `json:"min_order_value" binding:"gte=0"`
 	IsSingleUse   bool                   `json:"is_single_use" binding:"required"`
+	TicketTypeIDs []string               `json:"ticket_type_ids,omitempty"`
 }
 
 // DiscountResponse defines the response structure for a single discount code
@@ -56,6 +58,7 @@ type DiscountResponse struct {
 	PromoterID    string                 `json:"promoter_id,omitempty"`
 	MinOrderValue float64                `json:"min_order_value"`
 	IsSingleUse   bool                   `json:"is_single_use"`
+	TicketTypeIDs []string               `json:"ticket_type_ids,omitempty"`
 	CreatedAt     time.Time              `json:"created_at"`
 	UpdatedAt     time.Time              `json:"updated_at"`
 	DeletedAt     *time.Time             `json:"deleted_at,omitempty"`
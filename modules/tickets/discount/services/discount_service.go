@@ -19,7 +19,7 @@ type DiscountService interface {
 	GetDiscounts(userID string) (*dto.GetDiscountsOutput, error)
 	UpdateDiscount(userID, id string, discount *dto.UpdateDiscountCodeInput) (*dto.DiscountResponse, error)
 	CancelDiscount(userID, id string) error
-	ValidateDiscountCode(code string, eventID string, orderValue float64) (*tickets.DiscountCode, error)
+	ValidateDiscountCode(code, eventID, ticketTypeID string, orderValue float64) (*tickets.DiscountCode, error)
 }
 
 type discountService struct {
@@ -106,7 +106,15 @@ func (s *discountService) CreateDiscount(userID string, input *dto.CreateDiscoun
 		IsSingleUse:   input.IsSingleUse,
 	}
 
-	if err := s.db.Create(discount).Error; err != nil {
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		ticketTypes, err := s.resolveTicketTypes(tx, organizer.ID, input.EventID, input.TicketTypeIDs)
+		if err != nil {
+			return err
+		}
+		discount.TicketTypes = ticketTypes
+		return tx.Omit("TicketTypes.*").Create(discount).Error
+	})
+	if err != nil {
 		return nil, err
 	}
 
@@ -132,7 +140,7 @@ func (s *discountService) GetDiscount(userID, id string) (*dto.DiscountResponse,
 	}
 
 	var discount tickets.DiscountCode
-	if err := s.db.Preload("Event").Preload("Organizer").
+	if err := s.db.Preload("Event").Preload("Organizer").Preload("TicketTypes").
 		Where("id = ? AND organizer_id = ? AND deleted_at IS NULL", id, organizer.ID).
 		First(&discount).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -159,7 +167,7 @@ func (s *discountService) GetDiscounts(userID string) (*dto.GetDiscountsOutput,
 	}
 
 	var discounts []tickets.DiscountCode
-	if err := s.db.Preload("Event").Preload("Organizer").
+	if err := s.db.Preload("Event").Preload("Organizer").Preload("TicketTypes").
 		Where("organizer_id = ? AND deleted_at IS NULL", organizer.ID).
 		Find(&discounts).Error; err != nil {
 		return nil, err
@@ -236,7 +244,19 @@ func (s *discountService) UpdateDiscount(userID, id string, input *dto.UpdateDis
 		IsSingleUse:   input.IsSingleUse,
 	}
 
-	if err := s.db.Save(discount).Error; err != nil {
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		ticketTypes, err := s.resolveTicketTypes(tx, organizer.ID, input.EventID, input.TicketTypeIDs)
+		if err != nil {
+			return err
+		}
+		// Uses taken by checkouts since the code was loaded must not be overwritten
+		if err := tx.Omit("current_uses", "TicketTypes").Save(discount).Error; err != nil {
+			return err
+		}
+		discount.TicketTypes = ticketTypes
+		return tx.Model(discount).Omit("TicketTypes.*").Association("TicketTypes").Replace(ticketTypes)
+	})
+	if err != nil {
 		return nil, err
 	}
 
@@ -269,69 +289,76 @@ func (s *discountService) CancelDiscount(userID, id string) error {
 		return err
 	}
 
-	discount.IsActive = false
-	if err := s.db.Save(&discount).Error; err != nil {
+	if err := s.db.Model(&discount).UpdateColumns(map[string]interface{}{
+		"is_active":  false,
+		"version":    gorm.Expr("version + 1"),
+		"updated_at": time.Now(),
+	}).Error; err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func (s *discountService) ValidateDiscountCode(code string, eventID string, orderValue float64) (*tickets.DiscountCode, error) {
-	tx := s.db.Begin()
-	defer func() {
-		if r := recover(); r != nil {
-			tx.Rollback()
-		}
-	}()
-
+// ValidateDiscountCode checks whether a code would apply to an order without
+// using it; uses are only taken when an order redeems the code
+func (s *discountService) ValidateDiscountCode(code, eventID, ticketTypeID string, orderValue float64) (*tickets.DiscountCode, error) {
 	var discount tickets.DiscountCode
-	if err := tx.Set("gorm:query_option", "FOR UPDATE").
+	if err := s.db.Preload("TicketTypes").
 		Where("code = ? AND is_active = true AND deleted_at IS NULL", code).
 		First(&discount).Error; err != nil {
-		tx.Rollback()
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errors.New("invalid or expired discount code")
 		}
 		return nil, err
 	}
 
-	now := time.Now().UTC()
-	if now.Before(discount.ValidFrom) {
-		tx.Rollback()
-		return nil, errors.New("discount code is not yet valid")
-	}
-	if now.After(discount.ValidUntil) {
-		tx.Rollback()
-		return nil, errors.New("discount code has expired")
-	}
-	if discount.MaxUses > 0 && discount.CurrentUses >= discount.MaxUses {
-		tx.Rollback()
-		return nil, errors.New("discount code has reached maximum uses")
+	if err := checkApplicable(&discount, eventID, ticketTypeID, orderValue, time.Now().UTC()); err != nil {
+		return nil, err
 	}
-	if discount.EventID != "" && discount.EventID != eventID {
-		tx.Rollback()
-		return nil, errors.New("discount code is not valid for this event")
+	return &discount, nil
+}
+
+// resolveTicketTypes loads the ticket types a code is scoped to, which must
+// belong to the organizer's events and to the code's event when it has one
+func (s *discountService) resolveTicketTypes(tx *gorm.DB, organizerID, eventID string, ticketTypeIDs []string) ([]tickets.TicketType, error) {
+	ticketTypes := []tickets.TicketType{}
+	if len(ticketTypeIDs) == 0 {
+		return ticketTypes, nil
 	}
-	if discount.MinOrderValue > 0 && orderValue < discount.MinOrderValue {
-		tx.Rollback()
-		return nil, errors.New("order value is below the minimum required for this discount")
+	for _, id := range ticketTypeIDs {
+		if _, err := uuid.Parse(id); err != nil {
+			return nil, errors.New("invalid ticket type ID format")
+		}
 	}
 
-	discount.CurrentUses++
-	if err := tx.Save(&discount).Error; err != nil {
-		tx.Rollback()
-		return nil, err
+	query := tx.Joins("JOIN events ON events.id = ticket_types.event_id").
+		Where("ticket_types.id IN ? AND ticket_types.deleted_at IS NULL AND events.organizer_id = ? AND events.deleted_at IS NULL", ticketTypeIDs, organizerID)
+	if eventID != "" {
+		query = query.Where("ticket_types.event_id = ?", eventID)
 	}
-
-	if err := tx.Commit().Error; err != nil {
+	if err := query.Find(&ticketTypes).Error; err != nil {
 		return nil, err
 	}
+	if len(ticketTypes) != len(uniqueIDs(ticketTypeIDs)) {
+		return nil, errors.New("ticket type not found or not part of the discount's event")
+	}
+	return ticketTypes, nil
+}
 
-	return &discount, nil
+func uniqueIDs(ids []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(ids))
+	for _, id := range ids {
+		set[id] = struct{}{}
+	}
+	return set
 }
 
 func (s *discountService) mapToDiscountResponse(discount *tickets.DiscountCode) *dto.DiscountResponse {
+	var ticketTypeIDs []string
+	for _, ticketType := range discount.TicketTypes {
+		ticketTypeIDs = append(ticketTypeIDs, ticketType.ID)
+	}
 	return &dto.DiscountResponse{
 		ID:            discount.ID,
 		OrganizerID:   discount.OrganizerID,
@@ -348,6 +375,7 @@ func (s *discountService) mapToDiscountResponse(discount *tickets.DiscountCode)
 		PromoterID:    discount.PromoterID,
 		MinOrderValue: discount.MinOrderValue,
 		IsSingleUse:   discount.IsSingleUse,
+		TicketTypeIDs: ticketTypeIDs,
 		CreatedAt:     discount.CreatedAt,
 		UpdatedAt:     discount.UpdatedAt,
 	}
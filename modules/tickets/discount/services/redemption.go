@@ -0,0 +1,189 @@
+package discount_service
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"ticket-zetu-api/modules/tickets/models/tickets"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var errMaxUsesReached = errors.New("discount code has reached maximum uses")
+
+// RedeemDiscount applies a discount code to a newly created order inside tx,
+// taking one use of the code and reducing the order's total
+func RedeemDiscount(tx *gorm.DB, code string, order *tickets.Order) error {
+	var discount tickets.DiscountCode
+	if err := tx.Preload("TicketTypes").
+		Where("code = ? AND is_active = true AND deleted_at IS NULL", strings.TrimSpace(code)).
+		First(&discount).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("invalid or expired discount code")
+		}
+		return err
+	}
+
+	subtotal := order.UnitPrice * float64(order.Quantity)
+	if err := checkApplicable(&discount, order.EventID, order.TicketTypeID, subtotal, time.Now().UTC()); err != nil {
+		return err
+	}
+	if err := takeUse(tx, discount.ID); err != nil {
+		return err
+	}
+
+	amount := discount.AmountOff(subtotal)
+	redemption := tickets.DiscountRedemption{
+		DiscountCodeID: discount.ID,
+		UserID:         order.UserID,
+		OrderID:        order.ID,
+		Amount:         amount,
+		Status:         tickets.RedemptionActive,
+	}
+	if discount.IsSingleUse {
+		key := singleUseKey(discount.ID, order.UserID)
+		redemption.SingleUseKey = &key
+	}
+	result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&redemption)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("discount code has already been used")
+	}
+
+	order.DiscountCode = discount.Code
+	order.DiscountAmount = amount
+	order.TotalAmount = math.Round((subtotal-amount)*100) / 100
+	return tx.Model(order).UpdateColumns(map[string]interface{}{
+		"discount_code":   order.DiscountCode,
+		"discount_amount": order.DiscountAmount,
+		"total_amount":    order.TotalAmount,
+	}).Error
+}
+
+// ReleaseRedemption gives back the use taken by an abandoned or refunded order.
+// Orders without an active redemption are left alone.
+func ReleaseRedemption(tx *gorm.DB, orderID string) error {
+	var redemption tickets.DiscountRedemption
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("order_id = ? AND status = ?", orderID, tickets.RedemptionActive).
+		First(&redemption).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	if err := tx.Model(&redemption).UpdateColumns(map[string]interface{}{
+		"status":         tickets.RedemptionReleased,
+		"single_use_key": nil,
+		"released_at":    time.Now(),
+		"updated_at":     time.Now(),
+	}).Error; err != nil {
+		return err
+	}
+	return tx.Model(&tickets.DiscountCode{}).
+		Where("id = ? AND current_uses > 0", redemption.DiscountCodeID).
+		UpdateColumns(map[string]interface{}{
+			"current_uses": gorm.Expr("current_uses - 1"),
+			"version":      gorm.Expr("version + 1"),
+			"updated_at":   time.Now(),
+		}).Error
+}
+
+// ReclaimRedemption takes the use back for an order that is paid after its
+// redemption was released, failing if the code has been used up meanwhile
+func ReclaimRedemption(tx *gorm.DB, orderID string) error {
+	var redemption tickets.DiscountRedemption
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Preload("DiscountCode").
+		Where("order_id = ? AND status = ?", orderID, tickets.RedemptionReleased).
+		First(&redemption).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	updates := map[string]interface{}{
+		"status":      tickets.RedemptionActive,
+		"released_at": nil,
+		"updated_at":  time.Now(),
+	}
+	if redemption.DiscountCode.IsSingleUse {
+		key := singleUseKey(redemption.DiscountCodeID, redemption.UserID)
+		var active int64
+		if err := tx.Model(&tickets.DiscountRedemption{}).
+			Where("single_use_key = ?", key).
+			Count(&active).Error; err != nil {
+			return err
+		}
+		if active > 0 {
+			return errors.New("discount code has already been used")
+		}
+		updates["single_use_key"] = key
+	}
+	if err := takeUse(tx, redemption.DiscountCodeID); err != nil {
+		return err
+	}
+	return tx.Model(&redemption).UpdateColumns(updates).Error
+}
+
+// takeUse increments the code's use count only while it is below MaxUses, so
+// concurrent checkouts can never redeem more uses than the code allows
+func takeUse(tx *gorm.DB, discountID string) error {
+	result := tx.Model(&tickets.DiscountCode{}).
+		Where("id = ? AND (max_uses = 0 OR current_uses < max_uses)", discountID).
+		UpdateColumns(map[string]interface{}{
+			"current_uses": gorm.Expr("current_uses + 1"),
+			"version":      gorm.Expr("version + 1"),
+			"updated_at":   time.Now(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errMaxUsesReached
+	}
+	return nil
+}
+
+// checkApplicable applies the code's validity window, usage, scope and minimum order rules
+func checkApplicable(discount *tickets.DiscountCode, eventID, ticketTypeID string, orderValue float64, now time.Time) error {
+	if now.Before(discount.ValidFrom) {
+		return errors.New("discount code is not yet valid")
+	}
+	if now.After(discount.ValidUntil) {
+		return errors.New("discount code has expired")
+	}
+	if discount.MaxUses > 0 && discount.CurrentUses >= discount.MaxUses {
+		return errMaxUsesReached
+	}
+	if discount.EventID != "" && discount.EventID != eventID {
+		return errors.New("discount code is not valid for this event")
+	}
+	// Without a ticket type, as when a code is checked before one is picked, the scope cannot apply yet
+	if ticketTypeID != "" && len(discount.TicketTypes) > 0 {
+		scoped := false
+		for _, ticketType := range discount.TicketTypes {
+			if ticketType.ID == ticketTypeID {
+				scoped = true
+				break
+			}
+		}
+		if !scoped {
+			return errors.New("discount code is not valid for this ticket type")
+		}
+	}
+	if discount.MinOrderValue > 0 && orderValue < discount.MinOrderValue {
+		return errors.New("order value is below the minimum required for this discount")
+	}
+	return nil
+}
+
+func singleUseKey(discountID, userID string) string {
+	return discountID + ":" + userID
+}
@@ -0,0 +1,241 @@
+package inventory_service
+
+import (
+	"errors"
+	"fmt"
+	"ticket-zetu-api/modules/tickets/models/tickets"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Bucket identifies one of the TicketStock counters that units move between.
+// BucketSold has no column of its own: sold units are whatever part of
+// total_stock is not available, reserved, held or listed for resale.
+type Bucket string
+
+const (
+	BucketAvailable Bucket = "available_stock"
+	BucketReserved  Bucket = "reserved_stock"
+	BucketHeld      Bucket = "held_stock"
+	BucketResale    Bucket = "resale_stock"
+	BucketSold      Bucket = "sold"
+)
+
+const soldExpr = "(total_stock - available_stock - reserved_stock - held_stock - resale_stock)"
+
+var (
+	ErrStockNotFound     = errors.New("ticket stock not found")
+	ErrInsufficientStock = errors.New("insufficient ticket stock")
+)
+
+// InventoryService moves ticket units between TicketStock counters. Every
+// movement is a single guarded UPDATE, so the row lock taken by the database
+// serialises concurrent buyers and a movement that would drive a counter
+// below zero simply matches no rows instead of overselling.
+//
+// Methods take the *gorm.DB to run on so callers can compose them with their
+// own transactions; pass nil to use the service's connection.
+type InventoryService interface {
+	CreateStock(tx *gorm.DB, ticketType *tickets.TicketType, total int) (*tickets.TicketStock, error)
+	GetStock(tx *gorm.DB, ticketTypeID string) (*tickets.TicketStock, error)
+	SetTotalStock(tx *gorm.DB, ticketTypeID string, total int) error
+	Move(tx *gorm.DB, ticketTypeID string, from, to Bucket, quantity int) error
+	MoveIfTracked(tx *gorm.DB, ticketTypeID string, from, to Bucket, quantity int) error
+
+	Hold(tx *gorm.DB, ticketTypeID string, quantity int) error
+	ReleaseHold(tx *gorm.DB, ticketTypeID string, quantity int) error
+	Reserve(tx *gorm.DB, ticketTypeID string, quantity int) error
+	ReserveHeld(tx *gorm.DB, ticketTypeID string, quantity int) error
+	ReleaseReservation(tx *gorm.DB, ticketTypeID string, quantity int) error
+	CommitReservation(tx *gorm.DB, ticketTypeID string, quantity int) error
+	ListForResale(tx *gorm.DB, ticketTypeID string, quantity int) error
+	WithdrawResale(tx *gorm.DB, ticketTypeID string, quantity int) error
+}
+
+type inventoryService struct {
+	db *gorm.DB
+}
+
+func NewInventoryService(db *gorm.DB) InventoryService {
+	return &inventoryService{
+		db: db,
+	}
+}
+
+func (s *inventoryService) conn(tx *gorm.DB) *gorm.DB {
+	if tx != nil {
+		return tx
+	}
+	return s.db
+}
+
+func (s *inventoryService) CreateStock(tx *gorm.DB, ticketType *tickets.TicketType, total int) (*tickets.TicketStock, error) {
+	if total < 0 {
+		return nil, errors.New("total stock cannot be negative")
+	}
+
+	stock := &tickets.TicketStock{
+		TicketTypeID:   ticketType.ID,
+		EventID:        ticketType.EventID,
+		TotalStock:     total,
+		AvailableStock: total,
+	}
+	if err := s.conn(tx).Create(stock).Error; err != nil {
+		return nil, err
+	}
+	return stock, nil
+}
+
+func (s *inventoryService) GetStock(tx *gorm.DB, ticketTypeID string) (*tickets.TicketStock, error) {
+	var stock tickets.TicketStock
+	if err := s.conn(tx).Where("ticket_type_id = ?", ticketTypeID).First(&stock).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrStockNotFound
+		}
+		return nil, err
+	}
+	return &stock, nil
+}
+
+// SetTotalStock changes the capacity of a ticket type. The difference is
+// applied to available stock, so capacity cannot drop below what is already
+// held, reserved, listed or sold.
+func (s *inventoryService) SetTotalStock(tx *gorm.DB, ticketTypeID string, total int) error {
+	if total < 0 {
+		return errors.New("total stock cannot be negative")
+	}
+
+	// GORM emits map assignments in key order, so available_stock is computed
+	// from the old total_stock before total_stock itself is overwritten
+	result := s.conn(tx).Model(&tickets.TicketStock{}).
+		Where("ticket_type_id = ? AND available_stock + (? - total_stock) >= 0", ticketTypeID, total).
+		UpdateColumns(map[string]interface{}{
+			"available_stock": gorm.Expr("available_stock + (? - total_stock)", total),
+			"total_stock":     total,
+			"version":         gorm.Expr("version + 1"),
+			"updated_at":      time.Now(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return s.missOrShortage(tx, ticketTypeID)
+	}
+	return nil
+}
+
+// Move transfers quantity units from one bucket to another in a single
+// statement. It fails with ErrInsufficientStock when the source bucket
+// does not hold enough units.
+func (s *inventoryService) Move(tx *gorm.DB, ticketTypeID string, from, to Bucket, quantity int) error {
+	if quantity <= 0 {
+		return errors.New("quantity must be greater than zero")
+	}
+	if from == to {
+		return errors.New("source and destination stock must differ")
+	}
+	if !from.valid() || !to.valid() {
+		return errors.New("invalid stock bucket")
+	}
+
+	// Moving into the sold bucket only needs the source decremented, and
+	// moving out of it only needs the destination incremented.
+	updates := map[string]interface{}{
+		"version":    gorm.Expr("version + 1"),
+		"updated_at": time.Now(),
+	}
+	if from != BucketSold {
+		updates[string(from)] = gorm.Expr(fmt.Sprintf("%s - ?", from), quantity)
+	}
+	if to != BucketSold {
+		updates[string(to)] = gorm.Expr(fmt.Sprintf("%s + ?", to), quantity)
+	}
+
+	source := string(from)
+	if from == BucketSold {
+		source = soldExpr
+	}
+
+	result := s.conn(tx).Model(&tickets.TicketStock{}).
+		Where(fmt.Sprintf("ticket_type_id = ? AND %s >= ?", source), ticketTypeID, quantity).
+		UpdateColumns(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return s.missOrShortage(tx, ticketTypeID)
+	}
+	return nil
+}
+
+// MoveIfTracked is Move for ticket types that may have no stock row. Those
+// have unlimited capacity and nothing to move, so ErrStockNotFound is not an
+// error here.
+func (s *inventoryService) MoveIfTracked(tx *gorm.DB, ticketTypeID string, from, to Bucket, quantity int) error {
+	if err := s.Move(tx, ticketTypeID, from, to, quantity); err != nil && !errors.Is(err, ErrStockNotFound) {
+		return err
+	}
+	return nil
+}
+
+// Hold sets units aside for a buyer who has not started paying yet
+func (s *inventoryService) Hold(tx *gorm.DB, ticketTypeID string, quantity int) error {
+	return s.Move(tx, ticketTypeID, BucketAvailable, BucketHeld, quantity)
+}
+
+func (s *inventoryService) ReleaseHold(tx *gorm.DB, ticketTypeID string, quantity int) error {
+	return s.Move(tx, ticketTypeID, BucketHeld, BucketAvailable, quantity)
+}
+
+// Reserve sets units aside for a pending order
+func (s *inventoryService) Reserve(tx *gorm.DB, ticketTypeID string, quantity int) error {
+	return s.Move(tx, ticketTypeID, BucketAvailable, BucketReserved, quantity)
+}
+
+// ReserveHeld turns held units into a reservation for a pending order
+func (s *inventoryService) ReserveHeld(tx *gorm.DB, ticketTypeID string, quantity int) error {
+	return s.Move(tx, ticketTypeID, BucketHeld, BucketReserved, quantity)
+}
+
+func (s *inventoryService) ReleaseReservation(tx *gorm.DB, ticketTypeID string, quantity int) error {
+	return s.Move(tx, ticketTypeID, BucketReserved, BucketAvailable, quantity)
+}
+
+// CommitReservation marks reserved units as sold once their order is paid
+func (s *inventoryService) CommitReservation(tx *gorm.DB, ticketTypeID string, quantity int) error {
+	return s.Move(tx, ticketTypeID, BucketReserved, BucketSold, quantity)
+}
+
+// ListForResale moves sold units into the resale pool
+func (s *inventoryService) ListForResale(tx *gorm.DB, ticketTypeID string, quantity int) error {
+	return s.Move(tx, ticketTypeID, BucketSold, BucketResale, quantity)
+}
+
+// WithdrawResale returns units from the resale pool to sold, either because
+// the listing was withdrawn or because the resale completed
+func (s *inventoryService) WithdrawResale(tx *gorm.DB, ticketTypeID string, quantity int) error {
+	return s.Move(tx, ticketTypeID, BucketResale, BucketSold, quantity)
+}
+
+// missOrShortage explains why a guarded update matched no rows
+func (s *inventoryService) missOrShortage(tx *gorm.DB, ticketTypeID string) error {
+	var count int64
+	if err := s.conn(tx).Model(&tickets.TicketStock{}).
+		Where("ticket_type_id = ?", ticketTypeID).
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return ErrStockNotFound
+	}
+	return ErrInsufficientStock
+}
+
+func (b Bucket) valid() bool {
+	switch b {
+	case BucketAvailable, BucketReserved, BucketHeld, BucketResale, BucketSold:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,124 @@
+package inventory_service
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"ticket-zetu-api/internal/testdb"
+	"ticket-zetu-api/modules/tickets/models/tickets"
+
+	"github.com/google/uuid"
+)
+
+// TestConcurrentMovesKeepCountersConsistent runs many buyers against one
+// stock row at once. Every buyer holds, reserves, sells and relists units;
+// shortages are expected, but no counter may go negative and every unit must
+// be accounted for at the end.
+func TestConcurrentMovesKeepCountersConsistent(t *testing.T) {
+	db := testdb.Open(t, &tickets.TicketStock{})
+	service := NewInventoryService(db)
+
+	const (
+		total   = 40
+		buyers  = 30
+		rounds  = 10
+		perHold = 2
+	)
+	stock := &tickets.TicketStock{
+		TicketTypeID:   uuid.New().String(),
+		EventID:        uuid.New().String(),
+		TotalStock:     total,
+		AvailableStock: total,
+	}
+	if err := db.Create(stock).Error; err != nil {
+		t.Fatalf("create stock: %v", err)
+	}
+	t.Cleanup(func() { db.Delete(stock) })
+	id := stock.TicketTypeID
+
+	var sold int64
+	var wg sync.WaitGroup
+	for b := 0; b < buyers; b++ {
+		wg.Add(1)
+		go func(b int) {
+			defer wg.Done()
+			for r := 0; r < rounds; r++ {
+				// A hold that is either converted into an order or released
+				if ok := step(t, service.Hold(nil, id, perHold)); ok {
+					if (b+r)%2 == 0 {
+						if step(t, service.ReserveHeld(nil, id, perHold)) {
+							step(t, service.ReleaseReservation(nil, id, perHold))
+						}
+					} else {
+						step(t, service.ReleaseHold(nil, id, perHold))
+					}
+				}
+
+				// A direct order that is paid or abandoned
+				if !step(t, service.Reserve(nil, id, 1)) {
+					continue
+				}
+				if r%3 == 0 {
+					step(t, service.ReleaseReservation(nil, id, 1))
+					continue
+				}
+				if !step(t, service.CommitReservation(nil, id, 1)) {
+					t.Errorf("commit of a reserved unit failed")
+					continue
+				}
+				atomic.AddInt64(&sold, 1)
+
+				// The buyer lists the ticket and withdraws it again
+				if step(t, service.Move(nil, id, BucketSold, BucketResale, 1)) {
+					if !step(t, service.WithdrawResale(nil, id, 1)) {
+						t.Errorf("withdrawal of a listed unit failed")
+					}
+				}
+			}
+		}(b)
+	}
+	wg.Wait()
+
+	final, err := service.GetStock(nil, id)
+	if err != nil {
+		t.Fatalf("load stock: %v", err)
+	}
+	for name, value := range map[string]int{
+		"available": final.AvailableStock,
+		"held":      final.HeldStock,
+		"reserved":  final.ReservedStock,
+		"resale":    final.ResaleStock,
+	} {
+		if value < 0 {
+			t.Errorf("%s stock went negative: %d", name, value)
+		}
+	}
+	if final.HeldStock != 0 || final.ReservedStock != 0 || final.ResaleStock != 0 {
+		t.Errorf("units left in flight: held %d, reserved %d, resale %d", final.HeldStock, final.ReservedStock, final.ResaleStock)
+	}
+
+	// Sold has no column: total = available + held + reserved + resale + sold
+	// holds only if it matches the units actually sold
+	if final.TotalStock != total {
+		t.Errorf("total stock changed to %d, want %d", final.TotalStock, total)
+	}
+	soldStock := final.TotalStock - final.AvailableStock - final.HeldStock - final.ReservedStock - final.ResaleStock
+	if soldStock != int(sold) {
+		t.Errorf("total %d != available %d + held %d + reserved %d + resale %d + sold %d",
+			final.TotalStock, final.AvailableStock, final.HeldStock, final.ReservedStock, final.ResaleStock, sold)
+	}
+}
+
+// step reports whether a movement succeeded, failing the test on anything
+// other than an expected shortage
+func step(t *testing.T, err error) bool {
+	if err == nil {
+		return true
+	}
+	if !errors.Is(err, ErrInsufficientStock) {
+		t.Errorf("unexpected error: %v", err)
+	}
+	return false
+}
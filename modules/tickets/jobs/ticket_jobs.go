@@ -0,0 +1,141 @@
+package ticket_jobs
+
+import (
+	"log"
+	invoice_service "ticket-zetu-api/modules/invoices/service"
+	notification_service "ticket-zetu-api/modules/notifications/service"
+	cancellation_service "ticket-zetu-api/modules/tickets/cancellations/service"
+	comp_service "ticket-zetu-api/modules/tickets/comps/service"
+	ticket_credentials "ticket-zetu-api/modules/tickets/credentials"
+	inventory_service "ticket-zetu-api/modules/tickets/inventory/service"
+	order_service "ticket-zetu-api/modules/tickets/orders/service"
+	payment_providers "ticket-zetu-api/modules/tickets/payments/providers"
+	payment_service "ticket-zetu-api/modules/tickets/payments/service"
+	refund_service "ticket-zetu-api/modules/tickets/refunds/service"
+	resale_service "ticket-zetu-api/modules/tickets/resale/service"
+	transfer_service "ticket-zetu-api/modules/tickets/transfers/service"
+	waitlist_service "ticket-zetu-api/modules/tickets/waitlist/service"
+	mail_service "ticket-zetu-api/modules/users/authentication/mail"
+	"ticket-zetu-api/modules/users/authorization/service"
+	"ticket-zetu-api/queue"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	sweepInterval  = 30 * time.Second
+	sweepBatchSize = 500
+	// Cancellations refund payments and send email for every row, so they
+	// take smaller batches than the other sweeps
+	cancellationBatchSize = 100
+	// Reconciling calls the payment provider once per payment
+	reconcileBatchSize = 50
+)
+
+// RegisterTicketJobs schedules the background jobs of the tickets module
+func RegisterTicketJobs(scheduler *queue.Scheduler, db *gorm.DB, emailService mail_service.EmailService) {
+	authService := authorization_service.NewPermissionService(db)
+	inventoryService := inventory_service.NewInventoryService(db)
+	holdService := order_service.NewHoldService(db, authService, inventoryService)
+	orderService := order_service.NewOrderService(db, authService, inventoryService, ticket_credentials.DefaultKeyRing())
+	notificationService := notification_service.NewNotificationService(db, authService)
+	transferService := transfer_service.NewTransferService(db, authService, notificationService, ticket_credentials.DefaultKeyRing())
+	resaleService := resale_service.NewResaleService(db, authService, inventoryService, transferService)
+	waitlistService := waitlist_service.NewWaitlistService(db, authService, inventoryService, notificationService, emailService)
+	invoiceService := invoice_service.NewInvoiceService(db, authService, emailService)
+	paymentService := payment_service.NewPaymentService(db, authService, payment_providers.NewRegistryFromEnv(), orderService)
+	refundService := refund_service.NewRefundService(db, authService, inventoryService, paymentService)
+	cancellationService := cancellation_service.NewCancellationService(db, inventoryService, orderService, refundService, notificationService, emailService)
+	compService := comp_service.NewCompService(db, authService, inventoryService, notificationService, emailService, ticket_credentials.DefaultKeyRing())
+
+	scheduler.Every("tickets:expire-holds", sweepInterval, func() {
+		released, err := holdService.SweepExpiredHolds(sweepBatchSize)
+		if err != nil {
+			log.Printf("Failed to sweep expired ticket holds: %v", err)
+		}
+		if released > 0 {
+			log.Printf("Released %d expired ticket holds", released)
+		}
+	})
+
+	scheduler.Every("tickets:expire-orders", sweepInterval, func() {
+		expired, err := orderService.ExpireStaleOrders(sweepBatchSize)
+		if err != nil {
+			log.Printf("Failed to expire stale orders: %v", err)
+		}
+		if expired > 0 {
+			log.Printf("Expired %d unpaid orders", expired)
+		}
+	})
+
+	scheduler.Every("tickets:expire-resale-listings", sweepInterval, func() {
+		expired, err := resaleService.ExpireListings(sweepBatchSize)
+		if err != nil {
+			log.Printf("Failed to expire resale listings: %v", err)
+		}
+		if expired > 0 {
+			log.Printf("Expired %d resale listings", expired)
+		}
+	})
+
+	scheduler.Every("tickets:waitlist-offers", sweepInterval, func() {
+		offered, err := waitlistService.ProcessOffers(sweepBatchSize)
+		if err != nil {
+			log.Printf("Failed to process waitlist offers: %v", err)
+		}
+		if offered > 0 {
+			log.Printf("Made %d waitlist offers", offered)
+		}
+	})
+
+	scheduler.Every("tickets:deliver-invoices", sweepInterval, func() {
+		delivered, err := invoiceService.DeliverPending(sweepBatchSize)
+		if err != nil {
+			log.Printf("Failed to deliver invoices: %v", err)
+		}
+		if delivered > 0 {
+			log.Printf("Emailed %d receipts and invoices", delivered)
+		}
+	})
+
+	scheduler.Every("tickets:expire-comp-claims", sweepInterval, func() {
+		expired, err := compService.ExpireClaims(sweepBatchSize)
+		if err != nil {
+			log.Printf("Failed to expire comp claims: %v", err)
+		}
+		if expired > 0 {
+			log.Printf("Expired %d unclaimed comps", expired)
+		}
+	})
+
+	scheduler.Every("tickets:send-refunds", sweepInterval, func() {
+		sent, err := paymentService.SendPendingRefunds(sweepBatchSize)
+		if err != nil {
+			log.Printf("Failed to send pending refunds: %v", err)
+		}
+		if sent > 0 {
+			log.Printf("Sent %d refunds to payment providers", sent)
+		}
+	})
+
+	scheduler.Every("tickets:reconcile-payments", sweepInterval, func() {
+		settled, err := paymentService.ReconcilePayments(reconcileBatchSize)
+		if err != nil {
+			log.Printf("Failed to reconcile pending payments: %v", err)
+		}
+		if settled > 0 {
+			log.Printf("Settled %d payments whose webhook never arrived", settled)
+		}
+	})
+
+	scheduler.Every("tickets:process-cancellations", sweepInterval, func() {
+		processed, err := cancellationService.ProcessCancellations(cancellationBatchSize)
+		if err != nil {
+			log.Printf("Failed to process event cancellations: %v", err)
+		}
+		if processed > 0 {
+			log.Printf("Processed %d rows of event cancellations", processed)
+		}
+	})
+}
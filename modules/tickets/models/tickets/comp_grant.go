@@ -0,0 +1,75 @@
+package tickets
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CompPaymentMethod marks tickets issued free of charge by an organizer
+const CompPaymentMethod = "comp"
+
+type CompCategory string
+
+const (
+	CompGuestList CompCategory = "guest_list"
+	CompPress     CompCategory = "press"
+	CompSponsor   CompCategory = "sponsor"
+)
+
+type CompStatus string
+
+const (
+	// CompIssued grants went straight to a registered user's tickets
+	CompIssued CompStatus = "issued"
+	// CompPendingClaim grants hold reserved units until the emailed link is claimed
+	CompPendingClaim CompStatus = "pending_claim"
+	CompClaimed      CompStatus = "claimed"
+	CompRevoked      CompStatus = "revoked"
+	CompExpired      CompStatus = "expired"
+)
+
+// CompGrant is one recipient's share of a complimentary issue. Grants to
+// emails without an account reserve their units and wait for someone to
+// claim them through the link sent to the email.
+type CompGrant struct {
+	ID           string       `gorm:"type:char(36);primaryKey" json:"id"`
+	EventID      string       `gorm:"type:char(36);not null;index" json:"event_id"`
+	TicketTypeID string       `gorm:"type:char(36);not null;index" json:"ticket_type_id"`
+	IssuedBy     string       `gorm:"type:char(36);not null" json:"issued_by"`
+	Category     CompCategory `gorm:"type:varchar(20);not null;check:category IN ('guest_list','press','sponsor')" json:"category"`
+	Email        string       `gorm:"type:varchar(255);index" json:"email,omitempty"`
+	UserID       *string      `gorm:"type:char(36);index" json:"user_id,omitempty"`
+	Quantity     int          `gorm:"not null;check:quantity > 0" json:"quantity"`
+	Status       CompStatus   `gorm:"type:varchar(20);not null;index;check:status IN ('issued','pending_claim','claimed','revoked','expired')" json:"status"`
+	Note         string       `gorm:"size:255" json:"note,omitempty"`
+	Transferable bool         `gorm:"default:false" json:"transferable"`
+	// ClaimTokenHash is the SHA-256 of the claim link's token; only pending claims have one
+	ClaimTokenHash *string    `gorm:"type:char(64);uniqueIndex" json:"-"`
+	ClaimExpiresAt *time.Time `gorm:"index" json:"claim_expires_at,omitempty"`
+	ClaimedAt      *time.Time `json:"claimed_at,omitempty"`
+	CreatedAt      time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (g *CompGrant) BeforeCreate(tx *gorm.DB) error {
+	if g.ID == "" {
+		g.ID = uuid.New().String()
+	}
+	if g.EventID == "" || g.TicketTypeID == "" || g.IssuedBy == "" {
+		return errors.New("event_id, ticket_type_id and issued_by cannot be empty")
+	}
+	if g.UserID == nil && g.Email == "" {
+		return errors.New("a comp needs a user or an email")
+	}
+	if g.Quantity <= 0 {
+		return errors.New("quantity must be greater than 0")
+	}
+	return nil
+}
+
+func (CompGrant) TableName() string {
+	return "comp_grants"
+}
@@ -2,6 +2,7 @@ package tickets
 
 import (
 	"errors"
+	"math"
 	"ticket-zetu-api/modules/events/models/events"
 	organizers "ticket-zetu-api/modules/organizers/models"
 	"ticket-zetu-api/modules/users/models/members"
@@ -52,6 +53,17 @@ type DiscountCode struct {
 	Tickets   []Ticket             `gorm:"foreignKey:DiscountCode;references:Code;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;" json:"tickets"`
 	Organizer organizers.Organizer `gorm:"foreignKey:OrganizerID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"organizer"`
 	Promoter  members.User         `gorm:"foreignKey:PromoterID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;" json:"promoter,omitempty"`
+	// TicketTypes limits the code to these ticket types; an empty list means every ticket type of the event
+	TicketTypes []TicketType `gorm:"many2many:discount_code_ticket_types;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"ticket_types,omitempty"`
+}
+
+// AmountOff returns the discount on a subtotal, never more than the subtotal itself
+func (dc *DiscountCode) AmountOff(subtotal float64) float64 {
+	amount := dc.DiscountValue
+	if dc.DiscountType == DiscountPercentage {
+		amount = subtotal * math.Min(dc.DiscountValue, 100) / 100
+	}
+	return math.Round(math.Min(amount, subtotal)*100) / 100
 }
 
 func (dc *DiscountCode) BeforeCreate(tx *gorm.DB) (err error) {
@@ -113,3 +125,48 @@ func (dc *DiscountCode) BeforeUpdate(tx *gorm.DB) (err error) {
 func (DiscountCode) TableName() string {
 	return "discount_codes"
 }
+
+type RedemptionStatus string
+
+const (
+	RedemptionActive   RedemptionStatus = "active"
+	RedemptionReleased RedemptionStatus = "released"
+)
+
+// DiscountRedemption records a discount code applied to an order. Redemptions
+// of abandoned or refunded orders are released and no longer count as uses.
+type DiscountRedemption struct {
+	ID             string           `gorm:"type:char(36);primaryKey" json:"id"`
+	DiscountCodeID string           `gorm:"type:char(36);not null;index" json:"discount_code_id"`
+	UserID         string           `gorm:"type:char(36);not null;index" json:"user_id"`
+	OrderID        string           `gorm:"type:char(36);not null;uniqueIndex" json:"order_id"`
+	Amount         float64          `gorm:"type:numeric(12,2);not null" json:"amount"`
+	Status         RedemptionStatus `gorm:"type:varchar(20);not null;default:'active';index;check:status IN ('active','released')" json:"status"`
+	// SingleUseKey is set while a single-use code's redemption is active, so
+	// the unique index allows each user one active redemption of such a code
+	SingleUseKey *string    `gorm:"size:80;uniqueIndex" json:"-"`
+	ReleasedAt   *time.Time `json:"released_at,omitempty"`
+	CreatedAt    time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt    time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+
+	// Relationships
+	DiscountCode DiscountCode `gorm:"foreignKey:DiscountCodeID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;" json:"-"`
+	Order        Order        `gorm:"foreignKey:OrderID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+}
+
+func (r *DiscountRedemption) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == "" {
+		r.ID = uuid.New().String()
+	}
+	if r.DiscountCodeID == "" || r.UserID == "" || r.OrderID == "" {
+		return errors.New("discount_code_id, user_id and order_id cannot be empty")
+	}
+	if r.Amount < 0 {
+		return errors.New("amount cannot be negative")
+	}
+	return nil
+}
+
+func (DiscountRedemption) TableName() string {
+	return "discount_redemptions"
+}
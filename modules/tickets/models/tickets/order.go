@@ -0,0 +1,95 @@
+package tickets
+
+import (
+	"errors"
+	"time"
+
+	"ticket-zetu-api/modules/events/models/events"
+	"ticket-zetu-api/modules/users/models/members"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type OrderStatus string
+
+const (
+	OrderPending   OrderStatus = "pending"
+	OrderConfirmed OrderStatus = "confirmed"
+	OrderCanceled  OrderStatus = "canceled"
+	OrderExpired   OrderStatus = "expired"
+	// OrderRefunded marks a confirmed order whose tickets have all been refunded
+	OrderRefunded OrderStatus = "refunded"
+)
+
+type Order struct {
+	ID               string         `gorm:"type:char(36);primaryKey" json:"id"`
+	OrderNumber      string         `gorm:"type:char(36);unique;not null" json:"order_number"`
+	UserID           string         `gorm:"type:char(36);not null;index" json:"user_id"`
+	EventID          string         `gorm:"type:char(36);not null;index" json:"event_id"`
+	TicketTypeID     string         `gorm:"type:char(36);not null;index" json:"ticket_type_id"`
+	Quantity         int            `gorm:"not null;check:quantity > 0" json:"quantity"`
+	UnitPrice        float64        `gorm:"type:numeric(10,2);not null" json:"unit_price"`
+	PriceTierID      *string        `gorm:"type:char(36);index" json:"price_tier_id,omitempty"`
+	PriceTierName    string         `gorm:"size:50" json:"price_tier_name,omitempty"`
+	DiscountCode     string         `gorm:"size:50" json:"discount_code,omitempty"`
+	DiscountAmount   float64        `gorm:"type:numeric(12,2);not null;default:0" json:"discount_amount"`
+	TotalAmount      float64        `gorm:"type:numeric(12,2);not null" json:"total_amount"`
+	Status           OrderStatus    `gorm:"type:varchar(20);not null;default:'pending';index" json:"status"`
+	PaymentReference string         `gorm:"size:255" json:"payment_reference,omitempty"`
+	PaymentMethod    string         `gorm:"size:50" json:"payment_method,omitempty"`
+	ExpiresAt        time.Time      `gorm:"not null;index" json:"expires_at"`
+	ConfirmedAt      *time.Time     `gorm:"" json:"confirmed_at,omitempty"`
+	CreatedAt        time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt        time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+	DeletedAt        gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
+	Version          int            `gorm:"default:1" json:"version"`
+
+	// Relationships
+	Event      events.Event `gorm:"foreignKey:EventID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;" json:"event"`
+	User       members.User `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;" json:"user"`
+	TicketType TicketType   `gorm:"foreignKey:TicketTypeID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;" json:"ticket_type"`
+}
+
+func (o *Order) BeforeCreate(tx *gorm.DB) (err error) {
+	if o.ID == "" {
+		o.ID = uuid.New().String()
+	}
+	if o.OrderNumber == "" {
+		o.OrderNumber = uuid.New().String()
+	}
+	return o.validate()
+}
+
+func (o *Order) BeforeUpdate(tx *gorm.DB) (err error) {
+	return o.validate()
+}
+
+func (o *Order) validate() error {
+	if o.UserID == "" {
+		return errors.New("user_id cannot be empty")
+	}
+	if o.EventID == "" {
+		return errors.New("event_id cannot be empty")
+	}
+	if o.TicketTypeID == "" {
+		return errors.New("ticket_type_id cannot be empty")
+	}
+	if o.Quantity <= 0 {
+		return errors.New("quantity must be greater than 0")
+	}
+	if o.UnitPrice < 0 || o.TotalAmount < 0 || o.DiscountAmount < 0 {
+		return errors.New("order amounts cannot be negative")
+	}
+	if o.ExpiresAt.IsZero() {
+		return errors.New("expires_at cannot be empty")
+	}
+	if o.Status != OrderPending && o.Status != OrderConfirmed && o.Status != OrderCanceled && o.Status != OrderExpired && o.Status != OrderRefunded {
+		return errors.New("status must be one of 'pending', 'confirmed', 'canceled', 'expired', 'refunded'")
+	}
+	return nil
+}
+
+func (Order) TableName() string {
+	return "orders"
+}
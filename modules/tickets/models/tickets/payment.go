@@ -0,0 +1,156 @@
+package tickets
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type PaymentStatus string
+
+const (
+	PaymentPending           PaymentStatus = "pending"
+	PaymentSucceeded         PaymentStatus = "succeeded"
+	PaymentFailed            PaymentStatus = "failed"
+	PaymentPartiallyRefunded PaymentStatus = "partially_refunded"
+	PaymentRefunded          PaymentStatus = "refunded"
+)
+
+// PaymentPurpose says what a payment pays for; ReferenceID points at that record
+type PaymentPurpose string
+
+const (
+	PaymentForOrder  PaymentPurpose = "order"
+	PaymentForResale PaymentPurpose = "resale"
+)
+
+const DefaultCurrency = "KES"
+
+type PaymentTransaction struct {
+	ID                string         `gorm:"type:char(36);primaryKey" json:"id"`
+	UserID            string         `gorm:"type:char(36);not null;index" json:"user_id"`
+	Provider          string         `gorm:"type:varchar(30);not null;index" json:"provider"`
+	Purpose           PaymentPurpose `gorm:"type:varchar(30);not null;index:idx_payment_reference" json:"purpose"`
+	ReferenceID       string         `gorm:"type:char(36);not null;index:idx_payment_reference" json:"reference_id"`
+	Amount            float64        `gorm:"type:numeric(12,2);not null;check:amount >= 0" json:"amount"`
+	RefundedAmount    float64        `gorm:"type:numeric(12,2);not null;default:0;check:refunded_amount >= 0" json:"refunded_amount"`
+	Currency          string         `gorm:"type:varchar(3);not null;default:'KES'" json:"currency"`
+	Status            PaymentStatus  `gorm:"type:varchar(20);not null;default:'pending';check:status IN ('pending','succeeded','failed','partially_refunded','refunded')" json:"status"`
+	ProviderReference string         `gorm:"type:varchar(255);index" json:"provider_reference,omitempty"`
+	Receipt           string         `gorm:"type:varchar(100)" json:"receipt,omitempty"`
+	FailureReason     string         `gorm:"type:varchar(255)" json:"failure_reason,omitempty"`
+	CompletedAt       *time.Time     `json:"completed_at,omitempty"`
+	CreatedAt         time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt         time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+	Version           int            `gorm:"default:1" json:"version"`
+}
+
+func (p *PaymentTransaction) BeforeCreate(tx *gorm.DB) error {
+	if p.ID == "" {
+		p.ID = uuid.New().String()
+	}
+	if p.Currency == "" {
+		p.Currency = DefaultCurrency
+	}
+	return p.validate()
+}
+
+func (p *PaymentTransaction) BeforeUpdate(tx *gorm.DB) error {
+	return p.validate()
+}
+
+func (p *PaymentTransaction) validate() error {
+	if p.Provider == "" {
+		return errors.New("provider cannot be empty")
+	}
+	if p.ReferenceID == "" {
+		return errors.New("reference_id cannot be empty")
+	}
+	if p.Amount < 0 {
+		return errors.New("amount cannot be negative")
+	}
+	if p.RefundedAmount > p.Amount {
+		return errors.New("refunded_amount cannot exceed amount")
+	}
+	return nil
+}
+
+func (PaymentTransaction) TableName() string {
+	return "payment_transactions"
+}
+
+// PaymentWebhookEvent records every accepted provider callback. The unique
+// (provider, event_id) pair is what makes webhook handling idempotent.
+type PaymentWebhookEvent struct {
+	ID        string    `gorm:"type:char(36);primaryKey" json:"id"`
+	Provider  string    `gorm:"type:varchar(30);not null;uniqueIndex:idx_provider_event" json:"provider"`
+	EventID   string    `gorm:"type:varchar(255);not null;uniqueIndex:idx_provider_event" json:"event_id"`
+	PaymentID string    `gorm:"type:char(36);index" json:"payment_id"`
+	Status    string    `gorm:"type:varchar(20)" json:"status"`
+	Payload   string    `gorm:"type:text" json:"payload"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (e *PaymentWebhookEvent) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == "" {
+		e.ID = uuid.New().String()
+	}
+	if e.Provider == "" || e.EventID == "" {
+		return errors.New("provider and event_id cannot be empty")
+	}
+	return nil
+}
+
+func (PaymentWebhookEvent) TableName() string {
+	return "payment_webhook_events"
+}
+
+type PaymentRefundStatus string
+
+const (
+	PaymentRefundPending PaymentRefundStatus = "pending"
+	PaymentRefundSent    PaymentRefundStatus = "sent"
+)
+
+// PaymentRefund is money owed back through a payment's provider. It is
+// recorded in the same transaction as the change that owes it and only sent
+// to the provider once that transaction has committed; a refund the
+// provider did not accept stays pending and is retried.
+type PaymentRefund struct {
+	ID                string              `gorm:"type:char(36);primaryKey" json:"id"`
+	PaymentID         string              `gorm:"type:char(36);not null;index" json:"payment_id"`
+	Amount            float64             `gorm:"type:numeric(12,2);not null;check:amount > 0" json:"amount"`
+	Currency          string              `gorm:"type:varchar(3);not null;default:'KES'" json:"currency"`
+	Reason            string              `gorm:"type:varchar(255)" json:"reason,omitempty"`
+	Status            PaymentRefundStatus `gorm:"type:varchar(20);not null;default:'pending';index;check:status IN ('pending','sent')" json:"status"`
+	ProviderReference string              `gorm:"type:varchar(255)" json:"provider_reference,omitempty"`
+	Attempts          int                 `gorm:"not null;default:0" json:"attempts"`
+	LastError         string              `gorm:"type:varchar(255)" json:"last_error,omitempty"`
+	SentAt            *time.Time          `json:"sent_at,omitempty"`
+	CreatedAt         time.Time           `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt         time.Time           `gorm:"autoUpdateTime" json:"updated_at"`
+
+	Payment PaymentTransaction `gorm:"foreignKey:PaymentID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;" json:"-"`
+}
+
+func (r *PaymentRefund) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == "" {
+		r.ID = uuid.New().String()
+	}
+	if r.PaymentID == "" {
+		return errors.New("payment_id cannot be empty")
+	}
+	if r.Amount <= 0 {
+		return errors.New("amount must be greater than zero")
+	}
+	if r.Status == "" {
+		r.Status = PaymentRefundPending
+	}
+	return nil
+}
+
+func (PaymentRefund) TableName() string {
+	return "payment_refunds"
+}
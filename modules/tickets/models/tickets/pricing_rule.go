@@ -0,0 +1,52 @@
+package tickets
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// PricingRule raises a ticket type's price once a share of its stock has
+// sold. When several rules are met, the one with the highest threshold applies.
+type PricingRule struct {
+	ID             string    `gorm:"type:char(36);primaryKey" json:"id"`
+	TicketTypeID   string    `gorm:"type:char(36);not null;index" json:"ticket_type_id"`
+	SoldPercentage float64   `gorm:"type:numeric(5,2);not null;check:sold_percentage > 0 AND sold_percentage <= 100" json:"sold_percentage"`
+	PriceModifier  float64   `gorm:"type:numeric(5,2);not null;check:price_modifier > 0" json:"price_modifier"`
+	IsActive       bool      `gorm:"not null" json:"is_active"`
+	CreatedAt      time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+
+	// Relationships
+	TicketType TicketType `gorm:"foreignKey:TicketTypeID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+}
+
+func (r *PricingRule) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == "" {
+		r.ID = uuid.New().String()
+	}
+	return r.validate()
+}
+
+func (r *PricingRule) BeforeUpdate(tx *gorm.DB) error {
+	return r.validate()
+}
+
+func (r *PricingRule) validate() error {
+	if r.TicketTypeID == "" {
+		return errors.New("ticket_type_id cannot be empty")
+	}
+	if r.SoldPercentage <= 0 || r.SoldPercentage > 100 {
+		return errors.New("sold_percentage must be greater than 0 and at most 100")
+	}
+	if r.PriceModifier <= 0 {
+		return errors.New("price_modifier must be greater than 0")
+	}
+	return nil
+}
+
+func (PricingRule) TableName() string {
+	return "pricing_rules"
+}
@@ -0,0 +1,151 @@
+package tickets
+
+import (
+	"errors"
+	"time"
+
+	"ticket-zetu-api/modules/events/models/events"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// RefundPolicy configures refunds for one event. Events without a policy do not allow refunds.
+type RefundPolicy struct {
+	ID           string `gorm:"type:char(36);primaryKey" json:"id"`
+	EventID      string `gorm:"type:char(36);not null;uniqueIndex" json:"event_id"`
+	AllowRefunds bool   `gorm:"not null" json:"allow_refunds"`
+	// DeadlineHours is how many hours before the event starts refund requests close
+	DeadlineHours    int       `gorm:"not null;check:deadline_hours >= 0" json:"deadline_hours"`
+	RefundPercentage float64   `gorm:"type:numeric(5,2);not null;check:refund_percentage >= 0 AND refund_percentage <= 100" json:"refund_percentage"`
+	AutoApprove      bool      `gorm:"not null" json:"auto_approve"`
+	CreatedAt        time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt        time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+
+	// CancellationRefundPercentage is how much holders get back when the
+	// organizer cancels the event. Without one they are refunded in full.
+	CancellationRefundPercentage *float64 `gorm:"type:numeric(5,2);check:cancellation_refund_percentage >= 0 AND cancellation_refund_percentage <= 100" json:"cancellation_refund_percentage,omitempty"`
+
+	// Relationships
+	Event events.Event `gorm:"foreignKey:EventID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+}
+
+func (p *RefundPolicy) BeforeCreate(tx *gorm.DB) error {
+	if p.ID == "" {
+		p.ID = uuid.New().String()
+	}
+	return p.validate()
+}
+
+func (p *RefundPolicy) BeforeUpdate(tx *gorm.DB) error {
+	return p.validate()
+}
+
+func (p *RefundPolicy) validate() error {
+	if p.EventID == "" {
+		return errors.New("event_id cannot be empty")
+	}
+	if p.DeadlineHours < 0 {
+		return errors.New("deadline_hours cannot be negative")
+	}
+	if p.RefundPercentage < 0 || p.RefundPercentage > 100 {
+		return errors.New("refund_percentage must be between 0 and 100")
+	}
+	if p.CancellationRefundPercentage != nil && (*p.CancellationRefundPercentage < 0 || *p.CancellationRefundPercentage > 100) {
+		return errors.New("cancellation_refund_percentage must be between 0 and 100")
+	}
+	return nil
+}
+
+// CancellationPercentage is the share of each ticket's price refunded when
+// the event is cancelled
+func (p *RefundPolicy) CancellationPercentage() float64 {
+	if p.CancellationRefundPercentage == nil {
+		return 100
+	}
+	return *p.CancellationRefundPercentage
+}
+
+// RefundDeadline is the last moment a refund can be requested for an event starting at start
+func (p *RefundPolicy) RefundDeadline(start time.Time) time.Time {
+	return start.Add(-time.Duration(p.DeadlineHours) * time.Hour)
+}
+
+func (RefundPolicy) TableName() string {
+	return "refund_policies"
+}
+
+type RefundStatus string
+
+const (
+	RefundPending   RefundStatus = "pending"
+	RefundRejected  RefundStatus = "rejected"
+	RefundCompleted RefundStatus = "completed"
+)
+
+// RefundRequest asks for some or all of the tickets in an order to be
+// refunded. UserID is whoever holds the tickets and asked; the money goes
+// back to PayerID, who paid for the order. A ticket bought on resale is
+// refunded on its own request with ResaleID set, from the resale payment.
+type RefundRequest struct {
+	ID              string       `gorm:"type:char(36);primaryKey" json:"id"`
+	OrderID         string       `gorm:"type:char(36);not null;index" json:"order_id"`
+	UserID          string       `gorm:"type:char(36);not null;index" json:"user_id"`
+	PayerID         string       `gorm:"type:char(36);index" json:"payer_id,omitempty"`
+	EventID         string       `gorm:"type:char(36);not null;index" json:"event_id"`
+	Status          RefundStatus `gorm:"type:varchar(20);not null;default:'pending';index;check:status IN ('pending','rejected','completed')" json:"status"`
+	Reason          string       `gorm:"type:text" json:"reason"`
+	Percentage      float64      `gorm:"type:numeric(5,2);not null" json:"percentage"`
+	Amount          float64      `gorm:"type:numeric(12,2);not null;check:amount >= 0" json:"amount"`
+	PaymentID       string       `gorm:"type:char(36)" json:"payment_id,omitempty"`
+	ResaleID        string       `gorm:"type:char(36);index" json:"resale_id,omitempty"`
+	ReviewedBy      string       `gorm:"type:char(36)" json:"reviewed_by,omitempty"`
+	ReviewedAt      *time.Time   `json:"reviewed_at,omitempty"`
+	RejectionReason string       `gorm:"type:text" json:"rejection_reason,omitempty"`
+	CreatedAt       time.Time    `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt       time.Time    `gorm:"autoUpdateTime" json:"updated_at"`
+	Version         int          `gorm:"default:1" json:"version"`
+
+	// Relationships
+	Items []RefundRequestItem `gorm:"foreignKey:RefundRequestID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"items"`
+}
+
+func (r *RefundRequest) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == "" {
+		r.ID = uuid.New().String()
+	}
+	if r.OrderID == "" || r.UserID == "" || r.EventID == "" {
+		return errors.New("order_id, user_id and event_id cannot be empty")
+	}
+	if r.Amount < 0 {
+		return errors.New("amount cannot be negative")
+	}
+	return nil
+}
+
+func (RefundRequest) TableName() string {
+	return "refund_requests"
+}
+
+// RefundRequestItem is one ticket covered by a refund request
+type RefundRequestItem struct {
+	ID              string  `gorm:"type:char(36);primaryKey" json:"id"`
+	RefundRequestID string  `gorm:"type:char(36);not null;index" json:"refund_request_id"`
+	TicketID        string  `gorm:"type:char(36);not null;index" json:"ticket_id"`
+	TicketTypeID    string  `gorm:"type:char(36);not null" json:"ticket_type_id"`
+	Amount          float64 `gorm:"type:numeric(10,2);not null" json:"amount"`
+}
+
+func (i *RefundRequestItem) BeforeCreate(tx *gorm.DB) error {
+	if i.ID == "" {
+		i.ID = uuid.New().String()
+	}
+	if i.TicketID == "" {
+		return errors.New("ticket_id cannot be empty")
+	}
+	return nil
+}
+
+func (RefundRequestItem) TableName() string {
+	return "refund_request_items"
+}
@@ -22,29 +22,33 @@ const (
 )
 
 type Ticket struct {
-	ID               string         `gorm:"type:char(36);primaryKey" json:"id"`
-	TicketNumber     string         `gorm:"type:char(36);unique;not null" json:"ticket_number"`
-	EventID          string         `gorm:"type:char(36);not null;index" json:"event_id"`
-	UserID           string         `gorm:"type:char(36);not null;index" json:"user_id"`
-	TicketTypeID     string         `gorm:"type:char(36);not null;index" json:"ticket_type_id"`
-	SeatID           string         `gorm:"type:char(36);index" json:"seat_id,omitempty"`
-	SeatNumber       string         `gorm:"size:10" json:"seat_number"`
-	SeatSection      string         `gorm:"size:50" json:"seat_section"`
-	PurchaseTime     time.Time      `gorm:"autoCreateTime" json:"purchase_time"`
-	Status           TicketStatus   `gorm:"size:20;not null;default:'valid'" json:"status"`
-	QRCodeHash       string         `gorm:"type:text;not null" json:"qr_code_hash"`
-	PaymentReference string         `gorm:"size:255;not null" json:"payment_reference"`
-	PaymentMethod    string         `gorm:"size:50" json:"payment_method"`
-	ActualPrice      float64        `gorm:"type:numeric(10,2);not null" json:"actual_price"`
-	DiscountCode     string         `gorm:"size:50" json:"discount_code"`
-	CheckedInAt      time.Time      `gorm:"" json:"checked_in_at"`
-	CheckedInBy      string         `gorm:"type:char(36)" json:"checked_in_by"`
-	Notes            string         `gorm:"type:text" json:"notes"`
-	IsTransferable   bool           `gorm:"default:true" json:"is_transferable"`
-	CreatedAt        time.Time      `gorm:"autoCreateTime" json:"created_at"`
-	UpdatedAt        time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
-	DeletedAt        gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
-	Version          int            `gorm:"default:1" json:"version"`
+	ID           string       `gorm:"type:char(36);primaryKey" json:"id"`
+	TicketNumber string       `gorm:"type:char(36);unique;not null" json:"ticket_number"`
+	OrderID      string       `gorm:"type:char(36);index" json:"order_id"`
+	EventID      string       `gorm:"type:char(36);not null;index" json:"event_id"`
+	UserID       string       `gorm:"type:char(36);not null;index" json:"user_id"`
+	TicketTypeID string       `gorm:"type:char(36);not null;index" json:"ticket_type_id"`
+	SeatID       string       `gorm:"type:char(36);index" json:"seat_id,omitempty"`
+	SeatNumber   string       `gorm:"size:10" json:"seat_number"`
+	SeatSection  string       `gorm:"size:50" json:"seat_section"`
+	PurchaseTime time.Time    `gorm:"autoCreateTime" json:"purchase_time"`
+	Status       TicketStatus `gorm:"size:20;not null;default:'valid'" json:"status"`
+	QRCodeHash   string       `gorm:"type:text;not null" json:"qr_code_hash"`
+	// CredentialIssuedAt is when the ticket's current QR credential was signed
+	CredentialIssuedAt *time.Time     `json:"credential_issued_at,omitempty"`
+	PaymentReference   string         `gorm:"size:255;not null" json:"payment_reference"`
+	PaymentMethod      string         `gorm:"size:50" json:"payment_method"`
+	ActualPrice        float64        `gorm:"type:numeric(10,2);not null" json:"actual_price"`
+	DiscountCode       *string        `gorm:"size:50" json:"discount_code,omitempty"`
+	CheckedInAt        *time.Time     `gorm:"" json:"checked_in_at,omitempty"`
+	CheckedInBy        string         `gorm:"type:char(36)" json:"checked_in_by"`
+	Notes              string         `gorm:"type:text" json:"notes"`
+	IsTransferable     bool           `gorm:"default:true" json:"is_transferable"`
+	TransferCount      int            `gorm:"default:0" json:"transfer_count"`
+	CreatedAt          time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt          time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+	DeletedAt          gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
+	Version            int            `gorm:"default:1" json:"version"`
 
 	// Relationships
 	Event      events.Event `gorm:"foreignKey:EventID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;" json:"event"`
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type TicketHold struct {
@@ -13,14 +14,16 @@ type TicketHold struct {
 	UserID       string    `gorm:"type:char(36);not null;index" json:"user_id"`
 	SessionID    string    `gorm:"type:char(36);not null;index" json:"session_id"`
 	Quantity     int       `gorm:"not null;default:1" json:"quantity"`
-	HeldUntil    time.Time `gorm:"not null" json:"held_until"`
+	HeldUntil    time.Time `gorm:"not null;index" json:"held_until"`
+	Extended     bool      `gorm:"default:false" json:"extended"`
 	CreatedAt    time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt    time.Time `gorm:"autoUpdateTime" json:"updated_at"`
 
-	// Indexes for faster cleanup
-	Indexes []struct{} `gorm:"index:,expression:EXTRACT(EPOCH FROM (held_until - NOW())) WHERE held_until > NOW()"`
+	// Relationships
+	TicketType *TicketType `gorm:"foreignKey:TicketTypeID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"ticket_type,omitempty"`
 }
 
-func (th *TicketHold) BeforeCreate() error {
+func (th *TicketHold) BeforeCreate(tx *gorm.DB) error {
 	if th.ID == "" {
 		th.ID = uuid.New().String()
 	}
@@ -42,8 +45,8 @@ func (th *TicketHold) BeforeCreate() error {
 	return nil
 }
 
-func (th *TicketHold) BeforeUpdate() error {
-	return th.BeforeCreate()
+func (th *TicketHold) BeforeUpdate(tx *gorm.DB) error {
+	return th.BeforeCreate(tx)
 }
 
 func (TicketHold) TableName() string {
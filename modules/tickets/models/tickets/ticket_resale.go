@@ -4,13 +4,17 @@ import (
 	"errors"
 	"time"
 
+	"ticket-zetu-api/modules/events/models/events"
+
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type ResaleStatus string
 
 const (
-	ResaleListed    ResaleStatus = "listed"
+	ResaleListed ResaleStatus = "listed"
+	// ResalePending marks a listing reserved by a buyer who is paying for it
 	ResalePending   ResaleStatus = "pending"
 	ResaleCompleted ResaleStatus = "completed"
 	ResaleCanceled  ResaleStatus = "canceled"
@@ -20,6 +24,8 @@ const (
 type TicketResale struct {
 	ID             string  `gorm:"type:char(36);primaryKey" json:"id"`
 	TicketID       string  `gorm:"type:char(36);not null;index" json:"ticket_id"`
+	EventID        string  `gorm:"type:char(36);not null;index" json:"event_id"`
+	TicketTypeID   string  `gorm:"type:char(36);not null;index" json:"ticket_type_id"`
 	OriginalUserID string  `gorm:"type:char(36);not null;index" json:"original_user_id"`
 	NewUserID      *string `gorm:"type:char(36);index" json:"new_user_id"`
 
@@ -31,25 +37,42 @@ type TicketResale struct {
 	// Time controls
 	ListedAt  time.Time  `gorm:"autoCreateTime" json:"listed_at"`
 	SoldAt    *time.Time `gorm:"index" json:"sold_at"`
-	ExpiresAt time.Time  `gorm:"not null" json:"expires_at"`
+	ExpiresAt time.Time  `gorm:"not null;index" json:"expires_at"`
+	// ReservedUntil is when a pending buyer's claim on the listing lapses
+	ReservedUntil *time.Time `json:"reserved_until,omitempty"`
 
 	// Status
-	Status ResaleStatus `gorm:"type:varchar(20);default:'listed'" json:"status"`
+	Status ResaleStatus `gorm:"type:varchar(20);not null;default:'listed';index;check:status IN ('listed','pending','completed','canceled','expired')" json:"status"`
 
-	// Anti-flipping rules
+	// Anti-flipping rules, copied from the event's resale policy when listed
 	MinHoldDuration time.Duration `gorm:"-" json:"min_hold_duration"`
-	MinHoldDays     int           `gorm:"default:1" json:"min_hold_days"`
+	MinHoldDays     int           `gorm:"not null;check:min_hold_days >= 0" json:"min_hold_days"`
 
 	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+	Version   int       `gorm:"default:1" json:"version"`
 
 	// Relationships
 	Ticket Ticket `gorm:"foreignKey:TicketID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"ticket"`
 }
 
-func (tr *TicketResale) BeforeCreate() error {
+func (tr *TicketResale) BeforeCreate(tx *gorm.DB) error {
 	if tr.ID == "" {
 		tr.ID = uuid.New().String()
 	}
+	return tr.validate()
+}
+
+func (tr *TicketResale) BeforeUpdate(tx *gorm.DB) error {
+	return tr.validate()
+}
+
+func (tr *TicketResale) AfterFind(tx *gorm.DB) error {
+	tr.MinHoldDuration = time.Duration(tr.MinHoldDays) * 24 * time.Hour
+	return nil
+}
+
+func (tr *TicketResale) validate() error {
 	if tr.TicketID == "" {
 		return errors.New("ticket_id cannot be empty")
 	}
@@ -62,16 +85,116 @@ func (tr *TicketResale) BeforeCreate() error {
 	if tr.PlatformFee < 0 {
 		return errors.New("platform_fee cannot be negative")
 	}
+	if tr.PlatformFee > tr.ResalePrice {
+		return errors.New("platform_fee cannot exceed resale_price")
+	}
 	if tr.ExpiresAt.IsZero() {
 		return errors.New("expires_at cannot be empty")
 	}
 	return nil
 }
 
-func (tr *TicketResale) BeforeUpdate() error {
-	return tr.BeforeCreate()
+// SellerProceeds is what the seller is owed once the listing sells
+func (tr *TicketResale) SellerProceeds() float64 {
+	return tr.ResalePrice - tr.PlatformFee
 }
 
 func (TicketResale) TableName() string {
 	return "ticket_resales"
 }
+
+// ResalePolicy configures fan-to-fan resale for one event. Events without a policy do not allow resale.
+type ResalePolicy struct {
+	ID          string `gorm:"type:char(36);primaryKey" json:"id"`
+	EventID     string `gorm:"type:char(36);not null;uniqueIndex" json:"event_id"`
+	AllowResale bool   `gorm:"not null" json:"allow_resale"`
+	// MaxPricePercentage caps the resale price as a percentage of the price originally paid
+	MaxPricePercentage float64 `gorm:"type:numeric(6,2);not null;check:max_price_percentage >= 0" json:"max_price_percentage"`
+	// MinHoldDays is how long a ticket must be held before it can be listed
+	MinHoldDays int       `gorm:"not null;check:min_hold_days >= 0" json:"min_hold_days"`
+	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+
+	// Relationships
+	Event events.Event `gorm:"foreignKey:EventID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+}
+
+func (p *ResalePolicy) BeforeCreate(tx *gorm.DB) error {
+	if p.ID == "" {
+		p.ID = uuid.New().String()
+	}
+	return p.validate()
+}
+
+func (p *ResalePolicy) BeforeUpdate(tx *gorm.DB) error {
+	return p.validate()
+}
+
+func (p *ResalePolicy) validate() error {
+	if p.EventID == "" {
+		return errors.New("event_id cannot be empty")
+	}
+	if p.MaxPricePercentage < 0 {
+		return errors.New("max_price_percentage cannot be negative")
+	}
+	if p.MinHoldDays < 0 {
+		return errors.New("min_hold_days cannot be negative")
+	}
+	return nil
+}
+
+// PriceCap is the highest resale price allowed for a ticket bought at originalPrice
+func (p *ResalePolicy) PriceCap(originalPrice float64) float64 {
+	return originalPrice * p.MaxPricePercentage / 100
+}
+
+func (ResalePolicy) TableName() string {
+	return "resale_policies"
+}
+
+type PayoutStatus string
+
+const (
+	PayoutPending  PayoutStatus = "pending"
+	PayoutPaid     PayoutStatus = "paid"
+	PayoutCanceled PayoutStatus = "canceled" // the resale was refunded to its buyer
+)
+
+// ResalePayout is what the platform owes a seller for a completed resale
+type ResalePayout struct {
+	ID          string       `gorm:"type:char(36);primaryKey" json:"id"`
+	ResaleID    string       `gorm:"type:char(36);not null;uniqueIndex" json:"resale_id"`
+	SellerID    string       `gorm:"type:char(36);not null;index" json:"seller_id"`
+	PaymentID   string       `gorm:"type:char(36);not null;index" json:"payment_id"`
+	GrossAmount float64      `gorm:"type:numeric(10,2);not null" json:"gross_amount"`
+	PlatformFee float64      `gorm:"type:numeric(10,2);not null" json:"platform_fee"`
+	NetAmount   float64      `gorm:"type:numeric(10,2);not null;check:net_amount >= 0" json:"net_amount"`
+	Currency    string       `gorm:"type:varchar(3);not null;default:'KES'" json:"currency"`
+	Status      PayoutStatus `gorm:"type:varchar(20);not null;default:'pending';index;check:status IN ('pending','paid','canceled')" json:"status"`
+	PaidAt      *time.Time   `json:"paid_at,omitempty"`
+	CreatedAt   time.Time    `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time    `gorm:"autoUpdateTime" json:"updated_at"`
+
+	// Relationships
+	Resale TicketResale `gorm:"foreignKey:ResaleID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;" json:"-"`
+}
+
+func (p *ResalePayout) BeforeCreate(tx *gorm.DB) error {
+	if p.ID == "" {
+		p.ID = uuid.New().String()
+	}
+	if p.Currency == "" {
+		p.Currency = DefaultCurrency
+	}
+	if p.ResaleID == "" || p.SellerID == "" || p.PaymentID == "" {
+		return errors.New("resale_id, seller_id and payment_id cannot be empty")
+	}
+	if p.NetAmount < 0 {
+		return errors.New("net_amount cannot be negative")
+	}
+	return nil
+}
+
+func (ResalePayout) TableName() string {
+	return "resale_payouts"
+}
@@ -0,0 +1,50 @@
+package tickets
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ScanResult string
+
+const (
+	ScanAdmitted ScanResult = "admitted"
+	ScanRejected ScanResult = "rejected"
+	ScanUndone   ScanResult = "undone"
+)
+
+// TicketScan is an audit record of every door scan, including rejected
+// scans and undone check-ins
+type TicketScan struct {
+	ID        string     `gorm:"type:char(36);primaryKey" json:"id"`
+	TicketID  string     `gorm:"type:char(36);index" json:"ticket_id,omitempty"`
+	EventID   string     `gorm:"type:char(36);not null;index" json:"event_id"`
+	ScannedBy string     `gorm:"type:char(36);not null;index" json:"scanned_by"`
+	Result    ScanResult `gorm:"type:varchar(20);not null" json:"result"`
+	Reason    string     `gorm:"size:255" json:"reason,omitempty"`
+	DeviceID  string     `gorm:"size:100" json:"device_id,omitempty"`
+	// Offline scans were made without a connection and uploaded later
+	Offline   bool      `gorm:"default:false" json:"offline"`
+	ScannedAt time.Time `gorm:"not null;index" json:"scanned_at"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (s *TicketScan) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == "" {
+		s.ID = uuid.New().String()
+	}
+	if s.EventID == "" || s.ScannedBy == "" {
+		return errors.New("event_id and scanned_by cannot be empty")
+	}
+	if s.ScannedAt.IsZero() {
+		s.ScannedAt = time.Now()
+	}
+	return nil
+}
+
+func (TicketScan) TableName() string {
+	return "ticket_scans"
+}
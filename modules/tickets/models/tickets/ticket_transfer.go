@@ -0,0 +1,57 @@
+package tickets
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type TransferStatus string
+
+const (
+	TransferPending  TransferStatus = "pending"
+	TransferAccepted TransferStatus = "accepted"
+	TransferDeclined TransferStatus = "declined"
+	TransferCanceled TransferStatus = "canceled"
+	TransferExpired  TransferStatus = "expired"
+)
+
+// TicketTransfer is an offer to hand a ticket to another user. Accepted
+// transfers form the ticket's ownership history.
+type TicketTransfer struct {
+	ID          string         `gorm:"type:char(36);primaryKey" json:"id"`
+	TicketID    string         `gorm:"type:char(36);not null;index" json:"ticket_id"`
+	FromUserID  string         `gorm:"type:char(36);not null;index" json:"from_user_id"`
+	ToUserID    string         `gorm:"type:char(36);not null;index" json:"to_user_id"`
+	Status      TransferStatus `gorm:"type:varchar(20);not null;default:'pending';index;check:status IN ('pending','accepted','declined','canceled','expired')" json:"status"`
+	Message     string         `gorm:"size:255" json:"message,omitempty"`
+	ExpiresAt   time.Time      `gorm:"not null;index" json:"expires_at"`
+	RespondedAt *time.Time     `json:"responded_at,omitempty"`
+	CreatedAt   time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+
+	// Relationships
+	Ticket Ticket `gorm:"foreignKey:TicketID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+}
+
+func (t *TicketTransfer) BeforeCreate(tx *gorm.DB) error {
+	if t.ID == "" {
+		t.ID = uuid.New().String()
+	}
+	if t.TicketID == "" || t.FromUserID == "" || t.ToUserID == "" {
+		return errors.New("ticket_id, from_user_id and to_user_id cannot be empty")
+	}
+	if t.FromUserID == t.ToUserID {
+		return errors.New("cannot transfer a ticket to its owner")
+	}
+	if t.ExpiresAt.IsZero() {
+		return errors.New("expires_at cannot be empty")
+	}
+	return nil
+}
+
+func (TicketTransfer) TableName() string {
+	return "ticket_transfers"
+}
@@ -30,6 +30,8 @@ type TicketType struct {
 	MaxTicketsPerUser int              `gorm:"default:4;check:max_tickets_per_user >= 1 AND max_tickets_per_user >= min_tickets_per_user" json:"max_tickets_per_user"`
 	Status            TicketTypeStatus `gorm:"type:varchar(20);default:'active';check:status IN ('active','inactive','archived')" json:"status"`
 	IsDefault         bool             `gorm:"default:false" json:"is_default"`
+	TransfersDisabled bool             `gorm:"default:false" json:"transfers_disabled"`
+	MaxTransfers      int              `gorm:"default:0;check:max_transfers >= 0" json:"max_transfers"` // 0 means unlimited
 	SalesStart        time.Time        `gorm:"type:timestamp" json:"sales_start"`
 	SalesEnd          *time.Time       `gorm:"type:timestamp" json:"sales_end,omitempty"`
 	Version           int              `gorm:"default:1" json:"version"`
@@ -73,7 +75,9 @@ func (tt *TicketType) validate() error {
 	return nil
 }
 
-// CurrentPrice calculates the final price after modifiers
+// CurrentPrice calculates the ticket type's own price after its modifier. Sale
+// prices also depend on price tiers and demand rules; the pricing engine
+// resolves those.
 func (tt *TicketType) CurrentPrice() float64 {
 	return tt.BasePrice * tt.PriceModifier
 }
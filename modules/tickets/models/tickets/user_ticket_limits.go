@@ -10,17 +10,21 @@ import (
 	"gorm.io/gorm"
 )
 
+// UserTicketLimits tracks one user's purchases for one event. Its row is
+// locked while an order or hold is checked against the event's limits, so
+// concurrent checkouts by the same user cannot both slip under the cap.
 type UserTicketLimits struct {
 	ID      string `gorm:"type:char(36);primaryKey" json:"id"`
-	UserID  string `gorm:"type:char(36);primaryKey" json:"user_id"`
-	EventID string `gorm:"type:char(36);primaryKey" json:"event_id"`
+	UserID  string `gorm:"type:char(36);not null;uniqueIndex:idx_user_event_limit" json:"user_id"`
+	EventID string `gorm:"type:char(36);not null;uniqueIndex:idx_user_event_limit;index" json:"event_id"`
 
-	// Limits
-	MaxTickets    int `gorm:"not null;default:4" json:"max_tickets"`
+	// Limits. MaxTickets is an organizer override of the event's per-user
+	// cap; 0 means the event's cap applies.
+	MaxTickets    int `gorm:"not null;default:0;check:max_tickets >= 0" json:"max_tickets"`
 	TicketsBought int `gorm:"not null;default:0" json:"tickets_bought"`
 	TicketsResold int `gorm:"not null;default:0" json:"tickets_resold"`
 
-	// Dynamic pricing adjustments
+	// Purchase history, used for the cooldown between purchases
 	LastPurchaseAt *time.Time `gorm:"index" json:"last_purchase_at"`
 	PurchaseCount  int        `gorm:"default:0" json:"purchase_count"`
 
@@ -35,14 +39,22 @@ func (u *UserTicketLimits) BeforeCreate(tx *gorm.DB) error {
 	if u.ID == "" {
 		u.ID = uuid.New().String()
 	}
+	return u.validate()
+}
+
+func (u *UserTicketLimits) BeforeUpdate(tx *gorm.DB) error {
+	return u.validate()
+}
+
+func (u *UserTicketLimits) validate() error {
 	if u.UserID == "" {
 		return errors.New("user_id cannot be empty")
 	}
 	if u.EventID == "" {
 		return errors.New("event_id cannot be empty")
 	}
-	if u.MaxTickets <= 0 {
-		return errors.New("max_tickets must be greater than 0")
+	if u.MaxTickets < 0 {
+		return errors.New("max_tickets cannot be negative")
 	}
 	if u.TicketsBought < 0 {
 		return errors.New("tickets_bought cannot be negative")
@@ -52,24 +64,56 @@ func (u *UserTicketLimits) BeforeCreate(tx *gorm.DB) error {
 	}
 	return nil
 }
-func (u *UserTicketLimits) BeforeUpdate(tx *gorm.DB) error {
-	if u.UserID == "" {
-		return errors.New("user_id cannot be empty")
+
+func (UserTicketLimits) TableName() string {
+	return "user_ticket_limits"
+}
+
+// EventPurchaseLimit configures purchase limits that apply across all ticket
+// types of an event. Events without one only have their per-type limits.
+type EventPurchaseLimit struct {
+	ID      string `gorm:"type:char(36);primaryKey" json:"id"`
+	EventID string `gorm:"type:char(36);not null;uniqueIndex" json:"event_id"`
+	// MaxTicketsPerUser caps the tickets one user may hold for the event; 0 means no cap
+	MaxTicketsPerUser int `gorm:"not null;check:max_tickets_per_user >= 0" json:"max_tickets_per_user"`
+	// CooldownSeconds is the minimum time between one user's orders for the event
+	CooldownSeconds int       `gorm:"not null;check:cooldown_seconds >= 0" json:"cooldown_seconds"`
+	CreatedAt       time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt       time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+
+	// Relationships
+	Event events.Event `gorm:"foreignKey:EventID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+}
+
+func (l *EventPurchaseLimit) BeforeCreate(tx *gorm.DB) error {
+	if l.ID == "" {
+		l.ID = uuid.New().String()
 	}
-	if u.EventID == "" {
+	return l.validate()
+}
+
+func (l *EventPurchaseLimit) BeforeUpdate(tx *gorm.DB) error {
+	return l.validate()
+}
+
+func (l *EventPurchaseLimit) validate() error {
+	if l.EventID == "" {
 		return errors.New("event_id cannot be empty")
 	}
-	if u.MaxTickets <= 0 {
-		return errors.New("max_tickets must be greater than 0")
-	}
-	if u.TicketsBought < 0 {
-		return errors.New("tickets_bought cannot be negative")
+	if l.MaxTicketsPerUser < 0 {
+		return errors.New("max_tickets_per_user cannot be negative")
 	}
-	if u.TicketsResold < 0 {
-		return errors.New("tickets_resold cannot be negative")
+	if l.CooldownSeconds < 0 {
+		return errors.New("cooldown_seconds cannot be negative")
 	}
 	return nil
 }
-func (UserTicketLimits) TableName() string {
-	return "user_ticket_limits"
+
+// Cooldown is the minimum time between one user's orders for the event
+func (l *EventPurchaseLimit) Cooldown() time.Duration {
+	return time.Duration(l.CooldownSeconds) * time.Second
+}
+
+func (EventPurchaseLimit) TableName() string {
+	return "event_purchase_limits"
 }
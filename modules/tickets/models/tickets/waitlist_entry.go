@@ -0,0 +1,87 @@
+package tickets
+
+import (
+	"errors"
+	"time"
+
+	"ticket-zetu-api/modules/events/models/events"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type WaitlistStatus string
+
+const (
+	WaitlistWaiting WaitlistStatus = "waiting"
+	// WaitlistOffered marks an entry whose units are held for the user to buy
+	WaitlistOffered WaitlistStatus = "offered"
+	WaitlistClaimed WaitlistStatus = "claimed"
+	WaitlistExpired WaitlistStatus = "expired"
+	WaitlistLeft    WaitlistStatus = "left"
+)
+
+// WaitlistEntry is a user's place in line for a sold-out ticket type, or for
+// any ticket type of an event when TicketTypeID is nil. Offers are made as
+// ticket holds the user converts into an order.
+type WaitlistEntry struct {
+	ID           string         `gorm:"type:char(36);primaryKey" json:"id"`
+	EventID      string         `gorm:"type:char(36);not null;index" json:"event_id"`
+	TicketTypeID *string        `gorm:"type:char(36);index" json:"ticket_type_id,omitempty"`
+	UserID       string         `gorm:"type:char(36);not null;index" json:"user_id"`
+	Quantity     int            `gorm:"not null;check:quantity > 0" json:"quantity"`
+	Status       WaitlistStatus `gorm:"type:varchar(20);not null;default:'waiting';index;check:status IN ('waiting','offered','claimed','expired','left')" json:"status"`
+	// ActiveKey is set only while the entry is waiting or offered, so a user
+	// holds at most one place in each line
+	ActiveKey *string `gorm:"type:varchar(80);uniqueIndex" json:"-"`
+
+	// Offer
+	OfferedTicketTypeID *string    `gorm:"type:char(36);index" json:"offered_ticket_type_id,omitempty"`
+	HoldID              *string    `gorm:"type:char(36);index" json:"hold_id,omitempty"`
+	OfferedAt           *time.Time `json:"offered_at,omitempty"`
+	OfferExpiresAt      *time.Time `gorm:"index" json:"offer_expires_at,omitempty"`
+	OrderID             *string    `gorm:"type:char(36);index" json:"order_id,omitempty"`
+
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+
+	// Relationships
+	Event      events.Event `gorm:"foreignKey:EventID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+	TicketType *TicketType  `gorm:"foreignKey:TicketTypeID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"ticket_type,omitempty"`
+}
+
+// WaitlistKey identifies the line an entry stands in for one user
+func WaitlistKey(userID, eventID string, ticketTypeID *string) string {
+	if ticketTypeID != nil {
+		return userID + ":type:" + *ticketTypeID
+	}
+	return userID + ":event:" + eventID
+}
+
+func (we *WaitlistEntry) BeforeCreate(tx *gorm.DB) error {
+	if we.ID == "" {
+		we.ID = uuid.New().String()
+	}
+	return we.validate()
+}
+
+func (we *WaitlistEntry) BeforeUpdate(tx *gorm.DB) error {
+	return we.validate()
+}
+
+func (we *WaitlistEntry) validate() error {
+	if we.EventID == "" {
+		return errors.New("event_id cannot be empty")
+	}
+	if we.UserID == "" {
+		return errors.New("user_id cannot be empty")
+	}
+	if we.Quantity <= 0 {
+		return errors.New("quantity must be greater than 0")
+	}
+	return nil
+}
+
+func (WaitlistEntry) TableName() string {
+	return "waitlist_entries"
+}
@@ -0,0 +1,152 @@
+package order_controller
+
+import (
+	"ticket-zetu-api/logs/handler"
+	"ticket-zetu-api/modules/tickets/orders/dto"
+	"ticket-zetu-api/modules/tickets/orders/service"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+type HoldController struct {
+	service    order_service.HoldService
+	logHandler *handler.LogHandler
+	validator  *validator.Validate
+}
+
+func NewHoldController(service order_service.HoldService, logHandler *handler.LogHandler) *HoldController {
+	return &HoldController{
+		service:    service,
+		logHandler: logHandler,
+		validator:  validator.New(),
+	}
+}
+
+// CreateHold godoc
+// @Summary Hold tickets during checkout
+// @Description Sets tickets aside for the current user for a limited time. Pass the hold ID when creating the order to keep the held tickets.
+// @Tags Holds
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param input body dto.CreateHoldInput true "Hold details"
+// @Success 200 {object} map[string]interface{} "Tickets held successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid request body or ticket type not on sale"
+// @Failure 404 {object} map[string]interface{} "Ticket type or event not found"
+// @Failure 409 {object} map[string]interface{} "Not enough tickets available"
+// @Failure 422 {object} map[string]interface{} "Quantity violates per-user limits"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /holds [post]
+func (c *HoldController) CreateHold(ctx *fiber.Ctx) error {
+	userID := ctx.Locals("user_id").(string)
+
+	var input dto.CreateHoldInput
+	if err := ctx.BodyParser(&input); err != nil {
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, "Invalid request body"), fiber.StatusBadRequest)
+	}
+
+	if err := c.validator.Struct(input); err != nil {
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, err.Error()), fiber.StatusBadRequest)
+	}
+
+	hold, err := c.service.CreateHold(userID, input)
+	if err != nil {
+		return c.handleError(ctx, err)
+	}
+
+	return c.logHandler.LogSuccess(ctx, hold, "Tickets held successfully", true)
+}
+
+// GetHold godoc
+// @Summary Get a ticket hold
+// @Description Retrieves an active hold with the seconds remaining before it expires.
+// @Tags Holds
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Hold ID"
+// @Success 200 {object} map[string]interface{} "Hold retrieved successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid hold ID"
+// @Failure 404 {object} map[string]interface{} "Hold not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /holds/{id} [get]
+func (c *HoldController) GetHold(ctx *fiber.Ctx) error {
+	userID := ctx.Locals("user_id").(string)
+	id := ctx.Params("id")
+
+	hold, err := c.service.GetHold(userID, id)
+	if err != nil {
+		return c.handleError(ctx, err)
+	}
+
+	return c.logHandler.LogSuccess(ctx, hold, "Hold retrieved successfully", false)
+}
+
+// ExtendHold godoc
+// @Summary Extend a ticket hold
+// @Description Extends an active hold by one more hold period. A hold can only be extended once, and waitlist offers cannot be extended.
+// @Tags Holds
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Hold ID"
+// @Success 200 {object} map[string]interface{} "Hold extended successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid hold ID"
+// @Failure 404 {object} map[string]interface{} "Hold not found"
+// @Failure 409 {object} map[string]interface{} "Hold has already been extended or is a waitlist offer"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /holds/{id}/extend [post]
+func (c *HoldController) ExtendHold(ctx *fiber.Ctx) error {
+	userID := ctx.Locals("user_id").(string)
+	id := ctx.Params("id")
+
+	hold, err := c.service.ExtendHold(userID, id)
+	if err != nil {
+		return c.handleError(ctx, err)
+	}
+
+	return c.logHandler.LogSuccess(ctx, hold, "Hold extended successfully", true)
+}
+
+// ReleaseHold godoc
+// @Summary Release a ticket hold
+// @Description Releases an active hold and returns its tickets to stock.
+// @Tags Holds
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Hold ID"
+// @Success 200 {object} map[string]interface{} "Hold released successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid hold ID"
+// @Failure 404 {object} map[string]interface{} "Hold not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /holds/{id} [delete]
+func (c *HoldController) ReleaseHold(ctx *fiber.Ctx) error {
+	userID := ctx.Locals("user_id").(string)
+	id := ctx.Params("id")
+
+	if err := c.service.ReleaseHold(userID, id); err != nil {
+		return c.handleError(ctx, err)
+	}
+
+	return c.logHandler.LogSuccess(ctx, nil, "Hold released successfully", true)
+}
+
+func (c *HoldController) handleError(ctx *fiber.Ctx, err error) error {
+	switch err.Error() {
+	case "invalid user ID format", "invalid hold ID format", "invalid ticket type ID format",
+		"event is not open for ticket sales", "ticket type is not on sale",
+		"ticket sales have not started", "ticket sales have ended":
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, err.Error()), fiber.StatusBadRequest)
+	case "hold not found", "ticket type not found", "event not found":
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusNotFound, err.Error()), fiber.StatusNotFound)
+	case "hold has already been extended", "waitlist offers cannot be extended", "not enough tickets available":
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusConflict, err.Error()), fiber.StatusConflict)
+	case "quantity is below the minimum tickets per user", "quantity exceeds the maximum tickets per user",
+		"quantity exceeds the event's ticket limit per user":
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusUnprocessableEntity, err.Error()), fiber.StatusUnprocessableEntity)
+	default:
+		return c.logHandler.LogError(ctx, err, fiber.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,157 @@
+package order_controller
+
+import (
+	"ticket-zetu-api/logs/handler"
+	"ticket-zetu-api/modules/tickets/orders/dto"
+	"ticket-zetu-api/modules/tickets/orders/service"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+type OrderController struct {
+	service    order_service.OrderService
+	logHandler *handler.LogHandler
+	validator  *validator.Validate
+}
+
+func NewOrderController(service order_service.OrderService, logHandler *handler.LogHandler) *OrderController {
+	return &OrderController{
+		service:    service,
+		logHandler: logHandler,
+		validator:  validator.New(),
+	}
+}
+
+// CreateOrder godoc
+// @Summary Create a ticket order
+// @Description Starts checkout for a ticket type, optionally converting an active hold and applying a discount code. For reserved seating, pass the buyer's held seat reservations, one per ticket; each seat is priced from its own price tier and becomes the ticket's seat once paid. The order stays pending until its payment succeeds or it expires.
+// @Tags Orders
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param input body dto.CreateOrderInput true "Order details"
+// @Success 200 {object} map[string]interface{} "Order created successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid request body or ticket type not on sale"
+// @Failure 404 {object} map[string]interface{} "Ticket type, event or seat reservation not found"
+// @Failure 409 {object} map[string]interface{} "Not enough tickets available or seat no longer held"
+// @Failure 422 {object} map[string]interface{} "Quantity violates per-user limits or the discount code cannot be applied"
+// @Failure 429 {object} map[string]interface{} "Ordered too soon after the previous order for this event"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /orders [post]
+func (c *OrderController) CreateOrder(ctx *fiber.Ctx) error {
+	userID := ctx.Locals("user_id").(string)
+
+	var input dto.CreateOrderInput
+	if err := ctx.BodyParser(&input); err != nil {
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, "Invalid request body"), fiber.StatusBadRequest)
+	}
+
+	if err := c.validator.Struct(input); err != nil {
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, err.Error()), fiber.StatusBadRequest)
+	}
+
+	order, err := c.service.CreateOrder(userID, input)
+	if err != nil {
+		return c.handleError(ctx, err)
+	}
+
+	return c.logHandler.LogSuccess(ctx, order, "Order created successfully", true)
+}
+
+// CancelOrder godoc
+// @Summary Cancel a pending order
+// @Description Cancels an order that has not been confirmed yet.
+// @Tags Orders
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Order ID"
+// @Success 200 {object} map[string]interface{} "Order canceled successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid order ID"
+// @Failure 404 {object} map[string]interface{} "Order not found"
+// @Failure 409 {object} map[string]interface{} "Order is not pending"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /orders/{id}/cancel [patch]
+func (c *OrderController) CancelOrder(ctx *fiber.Ctx) error {
+	userID := ctx.Locals("user_id").(string)
+	id := ctx.Params("id")
+
+	if err := c.service.CancelOrder(userID, id); err != nil {
+		return c.handleError(ctx, err)
+	}
+
+	return c.logHandler.LogSuccess(ctx, nil, "Order canceled successfully", true)
+}
+
+// GetOrder godoc
+// @Summary Get an order
+// @Description Retrieves one of the current user's orders with any issued tickets.
+// @Tags Orders
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Order ID"
+// @Success 200 {object} map[string]interface{} "Order retrieved successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid order ID"
+// @Failure 404 {object} map[string]interface{} "Order not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /orders/{id} [get]
+func (c *OrderController) GetOrder(ctx *fiber.Ctx) error {
+	userID := ctx.Locals("user_id").(string)
+	id := ctx.Params("id")
+
+	order, err := c.service.GetOrder(userID, id)
+	if err != nil {
+		return c.handleError(ctx, err)
+	}
+
+	return c.logHandler.LogSuccess(ctx, order, "Order retrieved successfully", false)
+}
+
+// GetUserOrders godoc
+// @Summary Get the current user's orders
+// @Description Retrieves all orders placed by the current user, newest first.
+// @Tags Orders
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} map[string]interface{} "Orders retrieved successfully"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /orders [get]
+func (c *OrderController) GetUserOrders(ctx *fiber.Ctx) error {
+	userID := ctx.Locals("user_id").(string)
+
+	orders, err := c.service.GetUserOrders(userID)
+	if err != nil {
+		return c.handleError(ctx, err)
+	}
+
+	return c.logHandler.LogSuccess(ctx, orders, "Orders retrieved successfully", false)
+}
+
+func (c *OrderController) handleError(ctx *fiber.Ctx, err error) error {
+	switch err.Error() {
+	case "invalid user ID format", "invalid order ID format", "invalid ticket type ID format", "order does not match hold",
+		"event is not open for ticket sales", "ticket type is not on sale",
+		"ticket sales have not started", "ticket sales have ended",
+		"seat reservations do not match quantity", "invalid seat reservation ID format", "duplicate seat reservation":
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, err.Error()), fiber.StatusBadRequest)
+	case "order not found", "hold not found", "ticket type not found", "event not found", "seat reservation not found":
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusNotFound, err.Error()), fiber.StatusNotFound)
+	case "order is not pending", "not enough tickets available",
+		"seat reservation is no longer held", "seat reservation is already part of an order":
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusConflict, err.Error()), fiber.StatusConflict)
+	case "quantity is below the minimum tickets per user", "quantity exceeds the maximum tickets per user",
+		"quantity exceeds the event's ticket limit per user",
+		"invalid or expired discount code", "discount code is not yet valid", "discount code has expired",
+		"discount code has reached maximum uses", "discount code is not valid for this event",
+		"discount code is not valid for this ticket type", "order value is below the minimum required for this discount",
+		"discount code has already been used":
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusUnprocessableEntity, err.Error()), fiber.StatusUnprocessableEntity)
+	case "please wait before placing another order for this event":
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusTooManyRequests, err.Error()), fiber.StatusTooManyRequests)
+	default:
+		return c.logHandler.LogError(ctx, err, fiber.StatusInternalServerError)
+	}
+}
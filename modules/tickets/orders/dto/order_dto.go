@@ -0,0 +1,71 @@
+package dto
+
+import "time"
+
+// CreateOrderInput defines the input structure for starting a ticket order
+type CreateOrderInput struct {
+	TicketTypeID string `json:"ticket_type_id" example:"a1d3c4e6-89ab-44ce-8e65-123456789abc" validate:"required,uuid"`
+	Quantity     int    `json:"quantity" example:"2" validate:"required,gte=1"`
+	HoldID       string `json:"hold_id,omitempty" example:"c2f4e6a8-0b1d-4c3e-9f5a-7b9d1e3f5a7c" validate:"omitempty,uuid"`
+	DiscountCode string `json:"discount_code,omitempty" example:"SUMMER2025" validate:"omitempty,max=50"`
+	// SeatReservationIDs are the buyer's held seats, one per ticket, for events with reserved seating
+	SeatReservationIDs []string `json:"seat_reservation_ids,omitempty" validate:"omitempty,dive,uuid"`
+}
+
+// CreateHoldInput defines the input structure for holding tickets while the buyer checks out
+type CreateHoldInput struct {
+	TicketTypeID string `json:"ticket_type_id" example:"a1d3c4e6-89ab-44ce-8e65-123456789abc" validate:"required,uuid"`
+	Quantity     int    `json:"quantity" example:"2" validate:"required,gte=1"`
+	SessionID    string `json:"session_id,omitempty" example:"5e7a9c1b-3d5f-4a7b-9c1d-3e5f7a9b1c3d" validate:"omitempty,uuid"`
+}
+
+// HoldResponse defines the response structure for a ticket hold
+type HoldResponse struct {
+	ID               string    `json:"id"`
+	TicketTypeID     string    `json:"ticket_type_id"`
+	SessionID        string    `json:"session_id"`
+	Quantity         int       `json:"quantity"`
+	HeldUntil        time.Time `json:"held_until"`
+	SecondsRemaining int64     `json:"seconds_remaining"`
+	CanExtend        bool      `json:"can_extend"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+// IssuedTicketResponse defines a ticket produced by a confirmed order
+type IssuedTicketResponse struct {
+	ID           string    `json:"id"`
+	TicketNumber string    `json:"ticket_number"`
+	TicketTypeID string    `json:"ticket_type_id"`
+	EventID      string    `json:"event_id"`
+	Status       string    `json:"status"`
+	ActualPrice  float64   `json:"actual_price"`
+	PurchaseTime time.Time `json:"purchase_time"`
+	SeatID       string    `json:"seat_id,omitempty"`
+	SeatNumber   string    `json:"seat_number,omitempty"`
+	SeatSection  string    `json:"seat_section,omitempty"`
+}
+
+// OrderResponse defines the response structure for an order
+type OrderResponse struct {
+	ID               string                 `json:"id"`
+	OrderNumber      string                 `json:"order_number"`
+	UserID           string                 `json:"user_id"`
+	EventID          string                 `json:"event_id"`
+	TicketTypeID     string                 `json:"ticket_type_id"`
+	TicketTypeName   string                 `json:"ticket_type_name,omitempty"`
+	Quantity         int                    `json:"quantity"`
+	UnitPrice        float64                `json:"unit_price"`
+	PriceTierID      *string                `json:"price_tier_id,omitempty"`
+	PriceTierName    string                 `json:"price_tier_name,omitempty"`
+	DiscountCode     string                 `json:"discount_code,omitempty"`
+	DiscountAmount   float64                `json:"discount_amount"`
+	TotalAmount      float64                `json:"total_amount"`
+	Status           string                 `json:"status"`
+	PaymentReference string                 `json:"payment_reference,omitempty"`
+	PaymentMethod    string                 `json:"payment_method,omitempty"`
+	ExpiresAt        time.Time              `json:"expires_at"`
+	ConfirmedAt      *time.Time             `json:"confirmed_at,omitempty"`
+	CreatedAt        time.Time              `json:"created_at"`
+	UpdatedAt        time.Time              `json:"updated_at"`
+	Tickets          []IssuedTicketResponse `json:"tickets,omitempty"`
+}
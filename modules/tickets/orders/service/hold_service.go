@@ -0,0 +1,260 @@
+package order_service
+
+import (
+	"errors"
+	inventory_service "ticket-zetu-api/modules/tickets/inventory/service"
+	"ticket-zetu-api/modules/tickets/models/tickets"
+	"ticket-zetu-api/modules/tickets/orders/dto"
+	waitlist_service "ticket-zetu-api/modules/tickets/waitlist/service"
+	"ticket-zetu-api/modules/users/authorization/service"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// defaultHoldDuration applies to ticket types that have no stock row to read HoldSeconds from
+const defaultHoldDuration = 15 * time.Minute
+
+type HoldService interface {
+	CreateHold(userID string, input dto.CreateHoldInput) (*dto.HoldResponse, error)
+	GetHold(userID, holdID string) (*dto.HoldResponse, error)
+	ExtendHold(userID, holdID string) (*dto.HoldResponse, error)
+	ReleaseHold(userID, holdID string) error
+	SweepExpiredHolds(limit int) (int, error)
+}
+
+type holdService struct {
+	db                   *gorm.DB
+	authorizationService authorization_service.PermissionService
+	inventoryService     inventory_service.InventoryService
+}
+
+func NewHoldService(db *gorm.DB, authService authorization_service.PermissionService, inventoryService inventory_service.InventoryService) HoldService {
+	return &holdService{
+		db:                   db,
+		authorizationService: authService,
+		inventoryService:     inventoryService,
+	}
+}
+
+func (s *holdService) CreateHold(userID string, input dto.CreateHoldInput) (*dto.HoldResponse, error) {
+	if _, err := uuid.Parse(userID); err != nil {
+		return nil, errors.New("invalid user ID format")
+	}
+	if _, err := uuid.Parse(input.TicketTypeID); err != nil {
+		return nil, errors.New("invalid ticket type ID format")
+	}
+
+	sessionID := input.SessionID
+	if sessionID == "" {
+		sessionID = uuid.New().String()
+	}
+
+	var hold tickets.TicketHold
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		ticketType, _, err := getSellableTicketType(tx, input.TicketTypeID, time.Now())
+		if err != nil {
+			return err
+		}
+		if err := checkUserLimits(tx, userID, ticketType, input.Quantity, limitExclusions{}); err != nil {
+			return err
+		}
+		if err := checkWaitlist(tx, ticketType); err != nil {
+			return err
+		}
+
+		duration, err := s.holdDuration(tx, ticketType.ID)
+		if err != nil {
+			return err
+		}
+
+		if err := s.inventoryService.MoveIfTracked(tx, ticketType.ID, inventory_service.BucketAvailable, inventory_service.BucketHeld, input.Quantity); err != nil {
+			return shortage(err)
+		}
+
+		hold = tickets.TicketHold{
+			TicketTypeID: ticketType.ID,
+			UserID:       userID,
+			SessionID:    sessionID,
+			Quantity:     input.Quantity,
+			HeldUntil:    time.Now().Add(duration),
+		}
+		return tx.Create(&hold).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toHoldDTO(&hold), nil
+}
+
+func (s *holdService) GetHold(userID, holdID string) (*dto.HoldResponse, error) {
+	if _, err := uuid.Parse(holdID); err != nil {
+		return nil, errors.New("invalid hold ID format")
+	}
+
+	var hold tickets.TicketHold
+	if err := s.db.Where("id = ? AND user_id = ? AND held_until > ?", holdID, userID, time.Now()).
+		First(&hold).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("hold not found")
+		}
+		return nil, err
+	}
+
+	return toHoldDTO(&hold), nil
+}
+
+// ExtendHold pushes the expiry of an active hold out by one more hold
+// duration. Each hold can be extended once; waitlist offers keep their
+// original window so the next person in line is not kept waiting.
+func (s *holdService) ExtendHold(userID, holdID string) (*dto.HoldResponse, error) {
+	if _, err := uuid.Parse(holdID); err != nil {
+		return nil, errors.New("invalid hold ID format")
+	}
+
+	var hold *tickets.TicketHold
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		if hold, err = lockActiveHold(tx, userID, holdID); err != nil {
+			return err
+		}
+		if hold.Extended {
+			return errors.New("hold has already been extended")
+		}
+		offer, err := waitlist_service.IsOffer(tx, hold.ID)
+		if err != nil {
+			return err
+		}
+		if offer {
+			return errors.New("waitlist offers cannot be extended")
+		}
+
+		duration, err := s.holdDuration(tx, hold.TicketTypeID)
+		if err != nil {
+			return err
+		}
+
+		hold.HeldUntil = hold.HeldUntil.Add(duration)
+		hold.Extended = true
+		return tx.Save(hold).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toHoldDTO(hold), nil
+}
+
+func (s *holdService) ReleaseHold(userID, holdID string) error {
+	if _, err := uuid.Parse(holdID); err != nil {
+		return errors.New("invalid hold ID format")
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		hold, err := lockActiveHold(tx, userID, holdID)
+		if err != nil {
+			return err
+		}
+		return s.deleteAndRelease(tx, hold)
+	})
+}
+
+// SweepExpiredHolds returns up to limit expired holds to available stock and
+// reports how many it released. Each hold is deleted with a guarded DELETE
+// before its units are released, so concurrent sweepers on other instances
+// can never release the same hold twice.
+func (s *holdService) SweepExpiredHolds(limit int) (int, error) {
+	var expired []tickets.TicketHold
+	if err := s.db.Where("held_until <= ?", time.Now()).
+		Order("held_until ASC").
+		Limit(limit).
+		Find(&expired).Error; err != nil {
+		return 0, err
+	}
+
+	released := 0
+	for i := range expired {
+		hold := expired[i]
+		freed := false
+		err := s.db.Transaction(func(tx *gorm.DB) error {
+			result := tx.Where("held_until <= ?", time.Now()).Delete(&hold)
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return nil
+			}
+			freed = true
+			return s.releaseStock(tx, &hold)
+		})
+		if err != nil {
+			return released, err
+		}
+		if freed {
+			released++
+		}
+	}
+
+	return released, nil
+}
+
+func (s *holdService) deleteAndRelease(tx *gorm.DB, hold *tickets.TicketHold) error {
+	if err := tx.Delete(hold).Error; err != nil {
+		return err
+	}
+	return s.releaseStock(tx, hold)
+}
+
+func (s *holdService) releaseStock(tx *gorm.DB, hold *tickets.TicketHold) error {
+	return s.inventoryService.MoveIfTracked(tx, hold.TicketTypeID, inventory_service.BucketHeld, inventory_service.BucketAvailable, hold.Quantity)
+}
+
+// holdDuration reads the hold window configured on the ticket type's stock
+func (s *holdService) holdDuration(tx *gorm.DB, ticketTypeID string) (time.Duration, error) {
+	stock, err := s.inventoryService.GetStock(tx, ticketTypeID)
+	if errors.Is(err, inventory_service.ErrStockNotFound) {
+		return defaultHoldDuration, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	if stock.HoldSeconds <= 0 {
+		return defaultHoldDuration, nil
+	}
+	return time.Duration(stock.HoldSeconds) * time.Second, nil
+}
+
+// lockActiveHold loads an unexpired hold owned by the user and locks it for the rest of the transaction
+func lockActiveHold(tx *gorm.DB, userID, holdID string) (*tickets.TicketHold, error) {
+	var hold tickets.TicketHold
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND user_id = ? AND held_until > ?", holdID, userID, time.Now()).
+		First(&hold).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("hold not found")
+		}
+		return nil, err
+	}
+	return &hold, nil
+}
+
+func toHoldDTO(hold *tickets.TicketHold) *dto.HoldResponse {
+	remaining := int64(time.Until(hold.HeldUntil).Seconds())
+	if remaining < 0 {
+		remaining = 0
+	}
+
+	return &dto.HoldResponse{
+		ID:               hold.ID,
+		TicketTypeID:     hold.TicketTypeID,
+		SessionID:        hold.SessionID,
+		Quantity:         hold.Quantity,
+		HeldUntil:        hold.HeldUntil,
+		SecondsRemaining: remaining,
+		CanExtend:        !hold.Extended,
+		CreatedAt:        hold.CreatedAt,
+	}
+}
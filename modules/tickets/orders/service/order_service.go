@@ -0,0 +1,600 @@
+package order_service
+
+import (
+	"errors"
+	"math"
+	"ticket-zetu-api/modules/events/models/events"
+	"ticket-zetu-api/modules/events/models/seats"
+	seat_services "ticket-zetu-api/modules/events/seat_allocation/services"
+	ticket_credentials "ticket-zetu-api/modules/tickets/credentials"
+	discount_service "ticket-zetu-api/modules/tickets/discount/services"
+	inventory_service "ticket-zetu-api/modules/tickets/inventory/service"
+	"ticket-zetu-api/modules/tickets/models/tickets"
+	"ticket-zetu-api/modules/tickets/orders/dto"
+	pricing_service "ticket-zetu-api/modules/tickets/pricing/service"
+	purchase_limit_service "ticket-zetu-api/modules/tickets/purchase_limits/service"
+	waitlist_service "ticket-zetu-api/modules/tickets/waitlist/service"
+	"ticket-zetu-api/modules/users/authorization/service"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// orderPaymentWindow is how long a pending order stays payable before it expires
+const orderPaymentWindow = 15 * time.Minute
+
+type OrderService interface {
+	CreateOrder(userID string, input dto.CreateOrderInput) (*dto.OrderResponse, error)
+	FulfillOrder(tx *gorm.DB, orderID, paymentReference, paymentMethod string) (*tickets.Order, error)
+	CancelOrder(userID, orderID string) error
+	CancelEventOrder(orderID string) (bool, error)
+	GetOrder(userID, orderID string) (*dto.OrderResponse, error)
+	GetUserOrders(userID string) ([]dto.OrderResponse, error)
+	ExpireStaleOrders(limit int) (int, error)
+}
+
+type orderService struct {
+	db                   *gorm.DB
+	authorizationService authorization_service.PermissionService
+	inventoryService     inventory_service.InventoryService
+	keyRing              *ticket_credentials.KeyRing
+}
+
+func NewOrderService(db *gorm.DB, authService authorization_service.PermissionService, inventoryService inventory_service.InventoryService, keyRing *ticket_credentials.KeyRing) OrderService {
+	return &orderService{
+		db:                   db,
+		authorizationService: authService,
+		inventoryService:     inventoryService,
+		keyRing:              keyRing,
+	}
+}
+
+func (s *orderService) CreateOrder(userID string, input dto.CreateOrderInput) (*dto.OrderResponse, error) {
+	if _, err := uuid.Parse(userID); err != nil {
+		return nil, errors.New("invalid user ID format")
+	}
+	if _, err := uuid.Parse(input.TicketTypeID); err != nil {
+		return nil, errors.New("invalid ticket type ID format")
+	}
+
+	var order tickets.Order
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		ticketType, event, err := getSellableTicketType(tx, input.TicketTypeID, time.Now())
+		if err != nil {
+			return err
+		}
+
+		var hold *tickets.TicketHold
+		if input.HoldID != "" {
+			if hold, err = lockActiveHold(tx, userID, input.HoldID); err != nil {
+				return err
+			}
+			if hold.TicketTypeID != ticketType.ID || hold.Quantity != input.Quantity {
+				return errors.New("order does not match hold")
+			}
+		} else if err := checkWaitlist(tx, ticketType); err != nil {
+			return err
+		}
+
+		exclude := limitExclusions{}
+		if hold != nil {
+			exclude.holdID = hold.ID
+		}
+		if err := checkUserLimits(tx, userID, ticketType, input.Quantity, exclude); err != nil {
+			return err
+		}
+		if err := purchase_limit_service.CheckCooldown(tx, userID, event.ID); err != nil {
+			return err
+		}
+
+		ownUnits := 0
+		if hold != nil {
+			ownUnits = hold.Quantity
+		}
+		quote, err := pricing_service.Resolve(tx, ticketType, time.Now(), ownUnits)
+		if err != nil {
+			return err
+		}
+		unitPrice := quote.UnitPrice
+		totalAmount := math.Round(quote.UnitPrice*float64(input.Quantity)*100) / 100
+
+		// Reserved seats are bought one ticket each, priced by their own tiers
+		var reservedSeats []seats.SeatReservation
+		var seatPrices []float64
+		if len(input.SeatReservationIDs) > 0 {
+			if len(input.SeatReservationIDs) != input.Quantity {
+				return errors.New("seat reservations do not match quantity")
+			}
+			if reservedSeats, err = seat_services.LockHeldSeats(tx, userID, event.ID, input.SeatReservationIDs); err != nil {
+				return err
+			}
+			seatTotal := 0.0
+			for _, reservation := range reservedSeats {
+				price, err := pricing_service.SeatPrice(tx, quote, reservation.Seat.PriceTierID)
+				if err != nil {
+					return err
+				}
+				seatPrices = append(seatPrices, price)
+				seatTotal += price
+			}
+			totalAmount = math.Round(seatTotal*100) / 100
+			unitPrice = math.Round(totalAmount/float64(input.Quantity)*100) / 100
+		}
+
+		order = tickets.Order{
+			UserID:        userID,
+			EventID:       event.ID,
+			TicketTypeID:  ticketType.ID,
+			Quantity:      input.Quantity,
+			UnitPrice:     unitPrice,
+			PriceTierName: quote.PriceTierName,
+			TotalAmount:   totalAmount,
+			Status:        tickets.OrderPending,
+			ExpiresAt:     time.Now().Add(orderPaymentWindow),
+		}
+		if quote.PriceTierID != "" {
+			order.PriceTierID = &quote.PriceTierID
+		}
+
+		if err := tx.Create(&order).Error; err != nil {
+			return err
+		}
+		if len(reservedSeats) > 0 {
+			if err := seat_services.AttachSeatsToOrder(tx, reservedSeats, order.ID, seatPrices, order.ExpiresAt); err != nil {
+				return err
+			}
+		}
+
+		// A converted hold's units become the order's reservation
+		if hold != nil {
+			if err := tx.Delete(hold).Error; err != nil {
+				return err
+			}
+			if err := waitlist_service.ClaimOffer(tx, hold.ID, order.ID); err != nil {
+				return err
+			}
+			if err := s.inventoryService.MoveIfTracked(tx, ticketType.ID, inventory_service.BucketHeld, inventory_service.BucketReserved, input.Quantity); err != nil {
+				return err
+			}
+		} else if err := s.inventoryService.MoveIfTracked(tx, ticketType.ID, inventory_service.BucketAvailable, inventory_service.BucketReserved, input.Quantity); err != nil {
+			return err
+		}
+
+		if input.DiscountCode != "" {
+			return discount_service.RedeemDiscount(tx, input.DiscountCode, &order)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, shortage(err)
+	}
+
+	return s.toDTO(&order, nil), nil
+}
+
+// FulfillOrder issues the tickets for a paid order inside the caller's
+// transaction. Orders that expired or were canceled while the payment was in
+// flight are honoured if their units can still be reserved. Nothing is issued
+// once the event has left sale, such as when it was cancelled after the
+// payment started, so the payment is refunded instead.
+func (s *orderService) FulfillOrder(tx *gorm.DB, orderID, paymentReference, paymentMethod string) (*tickets.Order, error) {
+	var order tickets.Order
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", orderID).
+		First(&order).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("order not found")
+		}
+		return nil, err
+	}
+
+	var event events.Event
+	if err := tx.Select("id", "status").Where("id = ?", order.EventID).First(&event).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("event not found")
+		}
+		return nil, err
+	}
+	if !event.Status.IsOnSale() {
+		return nil, errors.New("event is not open for ticket sales")
+	}
+
+	switch order.Status {
+	case tickets.OrderPending:
+		// Units were reserved when the order was created
+	case tickets.OrderExpired, tickets.OrderCanceled:
+		if err := s.inventoryService.MoveIfTracked(tx, order.TicketTypeID, inventory_service.BucketAvailable, inventory_service.BucketReserved, order.Quantity); err != nil {
+			return nil, shortage(err)
+		}
+		if err := discount_service.ReclaimRedemption(tx, order.ID); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errors.New("order is not pending")
+	}
+
+	// Orders for reserved seats book them and issue one ticket per seat
+	reservedSeats, err := seat_services.BookOrderSeats(tx, order.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(reservedSeats) > 0 && len(reservedSeats) != order.Quantity {
+		return nil, errors.New("order seats do not match quantity")
+	}
+
+	now := time.Now()
+	prices := splitAmount(order.TotalAmount, order.Quantity)
+	if len(reservedSeats) > 0 {
+		seatPrices := make([]float64, len(reservedSeats))
+		for i, reservation := range reservedSeats {
+			seatPrices[i] = reservation.Price
+		}
+		prices = splitByWeight(order.TotalAmount, seatPrices)
+	}
+	var discountCode *string
+	if order.DiscountCode != "" {
+		discountCode = &order.DiscountCode
+	}
+	issued := make([]tickets.Ticket, 0, order.Quantity)
+	for i := 0; i < order.Quantity; i++ {
+		ticket := tickets.Ticket{
+			ID:               uuid.New().String(),
+			OrderID:          order.ID,
+			EventID:          order.EventID,
+			UserID:           order.UserID,
+			TicketTypeID:     order.TicketTypeID,
+			Status:           tickets.TicketValid,
+			PaymentReference: paymentReference,
+			PaymentMethod:    paymentMethod,
+			DiscountCode:     discountCode,
+			ActualPrice:      prices[i],
+			PurchaseTime:     now,
+		}
+		if len(reservedSeats) > 0 {
+			seat := reservedSeats[i].Seat
+			ticket.SeatID = seat.ID
+			ticket.SeatNumber = seat.SeatNumber
+			ticket.SeatSection = seat.SeatSection
+		}
+		if _, err := s.keyRing.Sign(&ticket); err != nil {
+			return nil, err
+		}
+		issued = append(issued, ticket)
+	}
+	if err := tx.Create(&issued).Error; err != nil {
+		return nil, err
+	}
+	if err := s.inventoryService.MoveIfTracked(tx, order.TicketTypeID, inventory_service.BucketReserved, inventory_service.BucketSold, order.Quantity); err != nil {
+		return nil, shortage(err)
+	}
+	if err := purchase_limit_service.RecordPurchase(tx, order.UserID, order.EventID, order.Quantity); err != nil {
+		return nil, err
+	}
+
+	order.Status = tickets.OrderConfirmed
+	order.PaymentReference = paymentReference
+	order.PaymentMethod = paymentMethod
+	order.ConfirmedAt = &now
+	order.Version++
+	if err := tx.Save(&order).Error; err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+func (s *orderService) CancelOrder(userID, orderID string) error {
+	if _, err := uuid.Parse(orderID); err != nil {
+		return errors.New("invalid order ID format")
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		var order tickets.Order
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND user_id = ?", orderID, userID).
+			First(&order).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("order not found")
+			}
+			return err
+		}
+
+		if order.Status != tickets.OrderPending {
+			return errors.New("order is not pending")
+		}
+
+		order.Status = tickets.OrderCanceled
+		order.Version++
+		if err := tx.Save(&order).Error; err != nil {
+			return err
+		}
+		if err := discount_service.ReleaseRedemption(tx, order.ID); err != nil {
+			return err
+		}
+		if err := seat_services.ReleaseOrderSeats(tx, order.ID); err != nil {
+			return err
+		}
+		return s.inventoryService.MoveIfTracked(tx, order.TicketTypeID, inventory_service.BucketReserved, inventory_service.BucketAvailable, order.Quantity)
+	})
+}
+
+// CancelEventOrder cancels a pending order for an event that has been
+// cancelled, whoever placed it, and returns its reserved units and seats. It
+// reports whether the order was still pending.
+func (s *orderService) CancelEventOrder(orderID string) (bool, error) {
+	cancelled := false
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var order tickets.Order
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND status = ?", orderID, tickets.OrderPending).
+			First(&order).Error; err != nil {
+			// Paid, cancelled or expired since it was listed
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			return err
+		}
+
+		if err := tx.Model(&order).UpdateColumns(map[string]interface{}{
+			"status":     tickets.OrderCanceled,
+			"version":    gorm.Expr("version + 1"),
+			"updated_at": time.Now(),
+		}).Error; err != nil {
+			return err
+		}
+		if err := discount_service.ReleaseRedemption(tx, order.ID); err != nil {
+			return err
+		}
+		if err := seat_services.ReleaseOrderSeats(tx, order.ID); err != nil {
+			return err
+		}
+		cancelled = true
+		return s.inventoryService.MoveIfTracked(tx, order.TicketTypeID, inventory_service.BucketReserved, inventory_service.BucketAvailable, order.Quantity)
+	})
+	if err != nil {
+		return false, err
+	}
+	return cancelled, nil
+}
+
+// ExpireStaleOrders expires up to limit pending orders whose payment window
+// has passed, returning their reserved units to stock
+func (s *orderService) ExpireStaleOrders(limit int) (int, error) {
+	var ids []string
+	if err := s.db.Model(&tickets.Order{}).
+		Where("status = ? AND expires_at <= ?", tickets.OrderPending, time.Now()).
+		Order("expires_at ASC").
+		Limit(limit).
+		Pluck("id", &ids).Error; err != nil {
+		return 0, err
+	}
+
+	for i, id := range ids {
+		if err := s.expireOrder(id); err != nil {
+			return i, err
+		}
+	}
+	return len(ids), nil
+}
+
+// expireOrder marks a pending order expired and returns its reserved units to stock
+func (s *orderService) expireOrder(orderID string) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		var order tickets.Order
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND status = ?", orderID, tickets.OrderPending).
+			First(&order).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			return err
+		}
+
+		if err := tx.Model(&order).UpdateColumns(map[string]interface{}{
+			"status":     tickets.OrderExpired,
+			"version":    gorm.Expr("version + 1"),
+			"updated_at": time.Now(),
+		}).Error; err != nil {
+			return err
+		}
+		if err := discount_service.ReleaseRedemption(tx, order.ID); err != nil {
+			return err
+		}
+		if err := seat_services.ReleaseOrderSeats(tx, order.ID); err != nil {
+			return err
+		}
+		return s.inventoryService.MoveIfTracked(tx, order.TicketTypeID, inventory_service.BucketReserved, inventory_service.BucketAvailable, order.Quantity)
+	})
+}
+
+// shortage reports a stock shortage in checkout terms
+func shortage(err error) error {
+	if errors.Is(err, inventory_service.ErrInsufficientStock) {
+		return errors.New("not enough tickets available")
+	}
+	return err
+}
+
+// splitAmount divides an order total across its tickets in whole cents, giving
+// any remainder to the first tickets so the prices add up to the total
+func splitAmount(total float64, quantity int) []float64 {
+	cents := int64(math.Round(total * 100))
+	base, remainder := cents/int64(quantity), cents%int64(quantity)
+	prices := make([]float64, quantity)
+	for i := range prices {
+		share := base
+		if int64(i) < remainder {
+			share++
+		}
+		prices[i] = float64(share) / 100
+	}
+	return prices
+}
+
+// splitByWeight divides an order total across its tickets in proportion to
+// their weights, such as seat prices, in whole cents that add up to the total.
+// Without any weight the total is split evenly.
+func splitByWeight(total float64, weights []float64) []float64 {
+	sum := 0.0
+	for _, weight := range weights {
+		sum += weight
+	}
+	if sum <= 0 {
+		return splitAmount(total, len(weights))
+	}
+
+	cents := int64(math.Round(total * 100))
+	prices := make([]float64, len(weights))
+	assigned := int64(0)
+	for i, weight := range weights {
+		share := int64(math.Floor(float64(cents) * weight / sum))
+		prices[i] = float64(share) / 100
+		assigned += share
+	}
+	for i := 0; assigned < cents; i = (i + 1) % len(prices) {
+		prices[i] = math.Round((prices[i]+0.01)*100) / 100
+		assigned++
+	}
+	return prices
+}
+
+// getSellableTicketType loads a ticket type and its event and checks that it can be sold at the given time
+func getSellableTicketType(tx *gorm.DB, ticketTypeID string, at time.Time) (*tickets.TicketType, *events.Event, error) {
+	var ticketType tickets.TicketType
+	if err := tx.Where("id = ? AND deleted_at IS NULL", ticketTypeID).First(&ticketType).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, errors.New("ticket type not found")
+		}
+		return nil, nil, err
+	}
+
+	var event events.Event
+	if err := tx.Where("id = ? AND deleted_at IS NULL", ticketType.EventID).First(&event).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, errors.New("event not found")
+		}
+		return nil, nil, err
+	}
+
+	if !event.Status.IsOnSale() || !event.HasTickets || at.After(event.EndTime) {
+		return nil, nil, errors.New("event is not open for ticket sales")
+	}
+	if ticketType.Status != tickets.TicketTypeActive {
+		return nil, nil, errors.New("ticket type is not on sale")
+	}
+	if at.Before(ticketType.SalesStart) {
+		return nil, nil, errors.New("ticket sales have not started")
+	}
+	if ticketType.SalesEnd != nil && at.After(*ticketType.SalesEnd) {
+		return nil, nil, errors.New("ticket sales have ended")
+	}
+
+	return &ticketType, &event, nil
+}
+
+// limitExclusions names the order or hold being converted so that its own
+// units are not counted twice against the per-user limit
+type limitExclusions struct {
+	orderID string
+	holdID  string
+}
+
+// checkWaitlist turns buyers away from units that are owed to the waitlist.
+// Converting a hold is exempt, as its units were taken out of stock earlier.
+func checkWaitlist(tx *gorm.DB, ticketType *tickets.TicketType) error {
+	first, err := waitlist_service.WaitlistFirst(tx, ticketType)
+	if err != nil {
+		return err
+	}
+	if first {
+		return errors.New("not enough tickets available")
+	}
+	return nil
+}
+
+// checkUserLimits enforces MinTicketsPerUser/MaxTicketsPerUser against tickets the user
+// already holds, other orders still awaiting payment and active holds, then the
+// event-wide cap across all of the event's ticket types
+func checkUserLimits(tx *gorm.DB, userID string, ticketType *tickets.TicketType, quantity int, exclude limitExclusions) error {
+	if quantity < ticketType.MinTicketsPerUser {
+		return errors.New("quantity is below the minimum tickets per user")
+	}
+
+	now := time.Now()
+
+	var owned int64
+	if err := tx.Model(&tickets.Ticket{}).
+		Where("user_id = ? AND ticket_type_id = ? AND status IN ?", userID, ticketType.ID,
+			[]tickets.TicketStatus{tickets.TicketValid, tickets.TicketUsed, tickets.TicketPending}).
+		Count(&owned).Error; err != nil {
+		return err
+	}
+
+	var pending int64
+	orderQuery := tx.Model(&tickets.Order{}).
+		Where("user_id = ? AND ticket_type_id = ? AND status = ? AND expires_at > ?", userID, ticketType.ID, tickets.OrderPending, now)
+	if exclude.orderID != "" {
+		orderQuery = orderQuery.Where("id <> ?", exclude.orderID)
+	}
+	if err := orderQuery.Select("COALESCE(SUM(quantity), 0)").Scan(&pending).Error; err != nil {
+		return err
+	}
+
+	var held int64
+	holdQuery := tx.Model(&tickets.TicketHold{}).
+		Where("user_id = ? AND ticket_type_id = ? AND held_until > ?", userID, ticketType.ID, now)
+	if exclude.holdID != "" {
+		holdQuery = holdQuery.Where("id <> ?", exclude.holdID)
+	}
+	if err := holdQuery.Select("COALESCE(SUM(quantity), 0)").Scan(&held).Error; err != nil {
+		return err
+	}
+
+	if int(owned+pending+held)+quantity > ticketType.MaxTicketsPerUser {
+		return errors.New("quantity exceeds the maximum tickets per user")
+	}
+	return purchase_limit_service.CheckEventLimit(tx, userID, ticketType.EventID, quantity, exclude.orderID, exclude.holdID)
+}
+
+func (s *orderService) toDTO(order *tickets.Order, issued []tickets.Ticket) *dto.OrderResponse {
+	var ticketResponses []dto.IssuedTicketResponse
+	for _, t := range issued {
+		ticketResponses = append(ticketResponses, dto.IssuedTicketResponse{
+			ID:           t.ID,
+			TicketNumber: t.TicketNumber,
+			TicketTypeID: t.TicketTypeID,
+			EventID:      t.EventID,
+			Status:       string(t.Status),
+			ActualPrice:  t.ActualPrice,
+			PurchaseTime: t.PurchaseTime,
+			SeatID:       t.SeatID,
+			SeatNumber:   t.SeatNumber,
+			SeatSection:  t.SeatSection,
+		})
+	}
+
+	return &dto.OrderResponse{
+		ID:               order.ID,
+		OrderNumber:      order.OrderNumber,
+		UserID:           order.UserID,
+		EventID:          order.EventID,
+		TicketTypeID:     order.TicketTypeID,
+		TicketTypeName:   order.TicketType.Name,
+		Quantity:         order.Quantity,
+		UnitPrice:        order.UnitPrice,
+		PriceTierID:      order.PriceTierID,
+		PriceTierName:    order.PriceTierName,
+		DiscountCode:     order.DiscountCode,
+		DiscountAmount:   order.DiscountAmount,
+		TotalAmount:      order.TotalAmount,
+		Status:           string(order.Status),
+		PaymentReference: order.PaymentReference,
+		PaymentMethod:    order.PaymentMethod,
+		ExpiresAt:        order.ExpiresAt,
+		ConfirmedAt:      order.ConfirmedAt,
+		CreatedAt:        order.CreatedAt,
+		UpdatedAt:        order.UpdatedAt,
+		Tickets:          ticketResponses,
+	}
+}
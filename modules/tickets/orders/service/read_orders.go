@@ -0,0 +1,53 @@
+package order_service
+
+import (
+	"errors"
+	"ticket-zetu-api/modules/tickets/models/tickets"
+	"ticket-zetu-api/modules/tickets/orders/dto"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func (s *orderService) GetOrder(userID, orderID string) (*dto.OrderResponse, error) {
+	if _, err := uuid.Parse(orderID); err != nil {
+		return nil, errors.New("invalid order ID format")
+	}
+
+	var order tickets.Order
+	if err := s.db.Preload("TicketType").
+		Where("id = ? AND user_id = ?", orderID, userID).
+		First(&order).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("order not found")
+		}
+		return nil, err
+	}
+
+	var issued []tickets.Ticket
+	if err := s.db.Where("order_id = ?", order.ID).Find(&issued).Error; err != nil {
+		return nil, err
+	}
+
+	return s.toDTO(&order, issued), nil
+}
+
+func (s *orderService) GetUserOrders(userID string) ([]dto.OrderResponse, error) {
+	if _, err := uuid.Parse(userID); err != nil {
+		return nil, errors.New("invalid user ID format")
+	}
+
+	var orders []tickets.Order
+	if err := s.db.Preload("TicketType").
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&orders).Error; err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.OrderResponse, 0, len(orders))
+	for _, order := range orders {
+		responses = append(responses, *s.toDTO(&order, nil))
+	}
+	return responses, nil
+}
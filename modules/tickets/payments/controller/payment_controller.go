@@ -0,0 +1,162 @@
+package payment_controller
+
+import (
+	"errors"
+	"ticket-zetu-api/logs/handler"
+	"ticket-zetu-api/modules/tickets/payments/dto"
+	"ticket-zetu-api/modules/tickets/payments/providers"
+	"ticket-zetu-api/modules/tickets/payments/service"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+type PaymentController struct {
+	service    payment_service.PaymentService
+	logHandler *handler.LogHandler
+	validator  *validator.Validate
+}
+
+func NewPaymentController(service payment_service.PaymentService, logHandler *handler.LogHandler) *PaymentController {
+	return &PaymentController{
+		service:    service,
+		logHandler: logHandler,
+		validator:  validator.New(),
+	}
+}
+
+// InitiatePayment godoc
+// @Summary Start a payment
+// @Description Starts a payment for a pending order, or for a reserved resale listing with purpose "resale", with the chosen provider. M-Pesa sends a prompt to the given phone; card payments return a redirect URL.
+// @Tags Payments
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param input body dto.InitiatePaymentInput true "Payment details"
+// @Success 200 {object} map[string]interface{} "Payment initiated successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid request body or order or listing cannot be paid"
+// @Failure 404 {object} map[string]interface{} "Order, listing or provider not found"
+// @Failure 502 {object} map[string]interface{} "Payment provider rejected the payment"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /payments [post]
+func (c *PaymentController) InitiatePayment(ctx *fiber.Ctx) error {
+	userID := ctx.Locals("user_id").(string)
+
+	var input dto.InitiatePaymentInput
+	if err := ctx.BodyParser(&input); err != nil {
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, "Invalid request body"), fiber.StatusBadRequest)
+	}
+
+	if err := c.validator.Struct(input); err != nil {
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, err.Error()), fiber.StatusBadRequest)
+	}
+
+	payment, err := c.service.InitiatePayment(userID, input)
+	if err != nil {
+		return c.handleError(ctx, err)
+	}
+
+	return c.logHandler.LogSuccess(ctx, payment, "Payment initiated successfully", true)
+}
+
+// GetPayment godoc
+// @Summary Get a payment
+// @Description Retrieves one of the current user's payments.
+// @Tags Payments
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Payment ID"
+// @Success 200 {object} map[string]interface{} "Payment retrieved successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid payment ID"
+// @Failure 404 {object} map[string]interface{} "Payment not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /payments/{id} [get]
+func (c *PaymentController) GetPayment(ctx *fiber.Ctx) error {
+	userID := ctx.Locals("user_id").(string)
+	id := ctx.Params("id")
+
+	payment, err := c.service.GetPayment(userID, id)
+	if err != nil {
+		return c.handleError(ctx, err)
+	}
+
+	return c.logHandler.LogSuccess(ctx, payment, "Payment retrieved successfully", false)
+}
+
+// VerifyPayment godoc
+// @Summary Verify a payment with its provider
+// @Description Polls the provider for the outcome of a pending payment and applies it, issuing tickets if the payment succeeded.
+// @Tags Payments
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Payment ID"
+// @Success 200 {object} map[string]interface{} "Payment verified successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid payment ID"
+// @Failure 404 {object} map[string]interface{} "Payment not found"
+// @Failure 502 {object} map[string]interface{} "Could not verify payment with provider"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /payments/{id}/verify [post]
+func (c *PaymentController) VerifyPayment(ctx *fiber.Ctx) error {
+	userID := ctx.Locals("user_id").(string)
+	id := ctx.Params("id")
+
+	payment, err := c.service.VerifyPayment(userID, id)
+	if err != nil {
+		return c.handleError(ctx, err)
+	}
+
+	return c.logHandler.LogSuccess(ctx, payment, "Payment verified successfully", false)
+}
+
+// HandleWebhook godoc
+// @Summary Receive a payment provider webhook
+// @Description Endpoint for provider callbacks. The signature is checked per provider and repeated deliveries are ignored.
+// @Tags Payments
+// @Accept json
+// @Produce json
+// @Param provider path string true "Provider name" Enums(mpesa, card, fake)
+// @Success 200 {object} map[string]interface{} "Webhook processed successfully"
+// @Failure 401 {object} map[string]interface{} "Invalid webhook signature"
+// @Failure 404 {object} map[string]interface{} "Payment or provider not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /payments/webhooks/{provider} [post]
+func (c *PaymentController) HandleWebhook(ctx *fiber.Ctx) error {
+	headers := make(map[string]string)
+	for key, values := range ctx.GetReqHeaders() {
+		if len(values) > 0 {
+			headers[key] = values[0]
+		}
+	}
+
+	req := payment_providers.WebhookRequest{
+		Headers: headers,
+		Query:   ctx.Queries(),
+		Body:    append([]byte(nil), ctx.Body()...),
+	}
+
+	if err := c.service.HandleWebhook(ctx.Params("provider"), req); err != nil {
+		if errors.Is(err, payment_providers.ErrInvalidSignature) {
+			return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusUnauthorized, err.Error()), fiber.StatusUnauthorized)
+		}
+		return c.handleError(ctx, err)
+	}
+
+	return c.logHandler.LogSuccess(ctx, nil, "Webhook processed successfully", false)
+}
+
+func (c *PaymentController) handleError(ctx *fiber.Ctx, err error) error {
+	switch err.Error() {
+	case "invalid user ID format", "invalid payment ID format", "invalid order ID format", "invalid listing ID format",
+		"unsupported payment purpose", "order is not pending", "order has expired", "listing is not reserved for you",
+		"phone number is required for mpesa payments", "invalid webhook payload":
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, err.Error()), fiber.StatusBadRequest)
+	case "payment not found", "order not found", "listing not found", payment_providers.ErrProviderNotFound.Error():
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusNotFound, err.Error()), fiber.StatusNotFound)
+	case "payment provider rejected the payment", "could not verify payment with provider":
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadGateway, err.Error()), fiber.StatusBadGateway)
+	default:
+		return c.logHandler.LogError(ctx, err, fiber.StatusInternalServerError)
+	}
+}
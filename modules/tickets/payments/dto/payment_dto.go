@@ -0,0 +1,32 @@
+package dto
+
+import "time"
+
+// InitiatePaymentInput defines the input structure for starting a payment
+type InitiatePaymentInput struct {
+	Purpose     string `json:"purpose,omitempty" example:"order" validate:"omitempty,oneof=order resale"`
+	ReferenceID string `json:"reference_id" example:"a1d3c4e6-89ab-44ce-8e65-123456789abc" validate:"required,uuid"`
+	Provider    string `json:"provider" example:"mpesa" validate:"required,oneof=mpesa card fake"`
+	Phone       string `json:"phone,omitempty" example:"254712345678" validate:"omitempty,numeric,min=10,max=15"`
+	ReturnURL   string `json:"return_url,omitempty" example:"https://ticketzetu.com/checkout/complete" validate:"omitempty,url"`
+}
+
+// PaymentResponse defines the response structure for a payment
+type PaymentResponse struct {
+	ID                string     `json:"id"`
+	Provider          string     `json:"provider"`
+	Purpose           string     `json:"purpose"`
+	ReferenceID       string     `json:"reference_id"`
+	Amount            float64    `json:"amount"`
+	RefundedAmount    float64    `json:"refunded_amount"`
+	Currency          string     `json:"currency"`
+	Status            string     `json:"status"`
+	ProviderReference string     `json:"provider_reference,omitempty"`
+	Receipt           string     `json:"receipt,omitempty"`
+	FailureReason     string     `json:"failure_reason,omitempty"`
+	RedirectURL       string     `json:"redirect_url,omitempty"`
+	Message           string     `json:"message,omitempty"`
+	CompletedAt       *time.Time `json:"completed_at,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+}
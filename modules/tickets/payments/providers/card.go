@@ -0,0 +1,236 @@
+package payment_providers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// webhookTolerance bounds how old a signed card webhook may be, which stops captured callbacks being replayed later
+const webhookTolerance = 5 * time.Minute
+
+// CardConfig holds credentials for a hosted card checkout gateway
+type CardConfig struct {
+	BaseURL       string
+	SecretKey     string
+	WebhookSecret string
+}
+
+// CardProvider redirects the buyer to a hosted checkout page. Webhooks carry
+// an X-Signature header of the form "t=<unix>,v1=<hex hmac>", where the HMAC
+// covers "<unix>.<body>".
+type CardProvider struct {
+	config CardConfig
+	client *http.Client
+}
+
+type cardSession struct {
+	ID            string `json:"id"`
+	URL           string `json:"url"`
+	Status        string `json:"status"`
+	Amount        int64  `json:"amount"`
+	FailureReason string `json:"failure_reason"`
+}
+
+type cardWebhookPayload struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+	Data struct {
+		Reference     string `json:"reference"`
+		SessionID     string `json:"session_id"`
+		Status        string `json:"status"`
+		Amount        int64  `json:"amount"`
+		FailureReason string `json:"failure_reason"`
+	} `json:"data"`
+}
+
+// NewCardProviderFromEnv returns nil when the card gateway is not configured
+func NewCardProviderFromEnv() *CardProvider {
+	config := CardConfig{
+		BaseURL:       os.Getenv("CARD_GATEWAY_BASE_URL"),
+		SecretKey:     os.Getenv("CARD_GATEWAY_SECRET_KEY"),
+		WebhookSecret: os.Getenv("CARD_GATEWAY_WEBHOOK_SECRET"),
+	}
+	if config.BaseURL == "" || config.SecretKey == "" || config.WebhookSecret == "" {
+		return nil
+	}
+	return NewCardProvider(config)
+}
+
+func NewCardProvider(config CardConfig) *CardProvider {
+	return &CardProvider{
+		config: config,
+		client: &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+func (p *CardProvider) Name() string {
+	return "card"
+}
+
+func (p *CardProvider) Initiate(req InitiateRequest) (*InitiateResult, error) {
+	payload := map[string]interface{}{
+		"reference":      req.Reference,
+		"amount":         toMinorUnits(req.Amount),
+		"currency":       req.Currency,
+		"description":    req.Description,
+		"customer_email": req.Email,
+		"return_url":     req.ReturnURL,
+	}
+
+	var session cardSession
+	if err := p.do(http.MethodPost, "/v1/checkout/sessions", payload, &session); err != nil {
+		return nil, err
+	}
+
+	return &InitiateResult{
+		ProviderReference: session.ID,
+		Status:            StatusPending,
+		RedirectURL:       session.URL,
+	}, nil
+}
+
+func (p *CardProvider) Verify(providerReference string) (*VerifyResult, error) {
+	var session cardSession
+	if err := p.do(http.MethodGet, "/v1/checkout/sessions/"+providerReference, nil, &session); err != nil {
+		return nil, err
+	}
+
+	return &VerifyResult{
+		ProviderReference: session.ID,
+		Status:            cardStatus(session.Status),
+		Amount:            float64(session.Amount) / 100,
+		FailureReason:     session.FailureReason,
+	}, nil
+}
+
+func (p *CardProvider) Refund(req RefundRequest) (*RefundResult, error) {
+	payload := map[string]interface{}{
+		"session_id": req.ProviderReference,
+		"amount":     toMinorUnits(req.Amount),
+		"reason":     req.Reason,
+		"reference":  req.Reference,
+	}
+
+	var refund struct {
+		ID     string `json:"id"`
+		Status string `json:"status"`
+	}
+	if err := p.do(http.MethodPost, "/v1/refunds", payload, &refund); err != nil {
+		return nil, err
+	}
+
+	return &RefundResult{
+		ProviderReference: refund.ID,
+		Status:            cardStatus(refund.Status),
+	}, nil
+}
+
+func (p *CardProvider) ParseWebhook(req WebhookRequest) (*WebhookEvent, error) {
+	timestamp, signature := parseCardSignature(lookup(req.Headers, "X-Signature"))
+	if timestamp == "" {
+		return nil, ErrInvalidSignature
+	}
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return nil, ErrInvalidSignature
+	}
+	age := time.Since(time.Unix(unix, 0))
+	if age > webhookTolerance || age < -webhookTolerance {
+		return nil, ErrInvalidSignature
+	}
+
+	signed := append([]byte(timestamp+"."), req.Body...)
+	if !verifySignature(p.config.WebhookSecret, signed, signature) {
+		return nil, ErrInvalidSignature
+	}
+
+	var payload cardWebhookPayload
+	if err := json.Unmarshal(req.Body, &payload); err != nil {
+		return nil, errors.New("invalid webhook payload")
+	}
+	if payload.ID == "" || payload.Data.Reference == "" {
+		return nil, errors.New("invalid webhook payload")
+	}
+
+	return &WebhookEvent{
+		EventID:           payload.ID,
+		Reference:         payload.Data.Reference,
+		ProviderReference: payload.Data.SessionID,
+		Status:            cardStatus(payload.Data.Status),
+		Amount:            float64(payload.Data.Amount) / 100,
+		FailureReason:     payload.Data.FailureReason,
+	}, nil
+}
+
+func (p *CardProvider) do(method, path string, payload interface{}, out interface{}) error {
+	var body io.Reader
+	if payload != nil {
+		raw, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(raw)
+	}
+
+	req, err := http.NewRequest(method, p.config.BaseURL+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+p.config.SecretKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("card gateway request failed with status %d: %s", resp.StatusCode, strings.TrimSpace(string(raw)))
+	}
+	return json.Unmarshal(raw, out)
+}
+
+func parseCardSignature(value string) (timestamp, signature string) {
+	for _, part := range strings.Split(value, ",") {
+		key, val, found := strings.Cut(strings.TrimSpace(part), "=")
+		if !found {
+			continue
+		}
+		switch key {
+		case "t":
+			timestamp = val
+		case "v1":
+			signature = val
+		}
+	}
+	return timestamp, signature
+}
+
+func cardStatus(status string) Status {
+	switch status {
+	case "succeeded", "paid", "completed":
+		return StatusSucceeded
+	case "failed", "canceled", "expired":
+		return StatusFailed
+	default:
+		return StatusPending
+	}
+}
+
+func toMinorUnits(amount float64) int64 {
+	return int64(math.Round(amount * 100))
+}
@@ -0,0 +1,120 @@
+package payment_providers
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"os"
+	"sync"
+)
+
+// FakeProvider is a deterministic in-memory provider for local development.
+// Payments succeed unless the amount ends in .99, which always fails, so both
+// paths can be exercised without a real gateway. Webhooks are only accepted
+// when signed with the configured secret.
+type FakeProvider struct {
+	webhookSecret string
+	payments      sync.Map
+}
+
+type fakeWebhookPayload struct {
+	EventID           string  `json:"event_id"`
+	Reference         string  `json:"reference"`
+	ProviderReference string  `json:"provider_reference"`
+	Status            string  `json:"status"`
+	Amount            float64 `json:"amount"`
+}
+
+// NewFakeProviderFromEnv returns nil unless the fake provider has been
+// switched on with ENABLE_FAKE_PAYMENTS=true and given a webhook secret. It
+// is never enabled in production.
+func NewFakeProviderFromEnv() *FakeProvider {
+	if os.Getenv("GO_ENV") == "production" || os.Getenv("ENABLE_FAKE_PAYMENTS") != "true" {
+		return nil
+	}
+	webhookSecret := os.Getenv("FAKE_PAYMENT_WEBHOOK_SECRET")
+	if webhookSecret == "" {
+		return nil
+	}
+	return NewFakeProvider(webhookSecret)
+}
+
+func NewFakeProvider(webhookSecret string) *FakeProvider {
+	return &FakeProvider{webhookSecret: webhookSecret}
+}
+
+func (p *FakeProvider) Name() string {
+	return "fake"
+}
+
+func (p *FakeProvider) Initiate(req InitiateRequest) (*InitiateResult, error) {
+	if req.Reference == "" {
+		return nil, errors.New("payment reference is required")
+	}
+
+	status := StatusSucceeded
+	cents := int(math.Round(req.Amount*100)) % 100
+	if cents == 99 {
+		status = StatusFailed
+	}
+
+	providerRef := "fake_" + req.Reference
+	p.payments.Store(providerRef, &VerifyResult{
+		ProviderReference: providerRef,
+		Status:            status,
+		Amount:            req.Amount,
+	})
+
+	return &InitiateResult{
+		ProviderReference: providerRef,
+		Status:            StatusPending,
+		Message:           "Send a signed webhook to /payments/webhooks/fake or verify the payment to complete it",
+	}, nil
+}
+
+func (p *FakeProvider) Verify(providerReference string) (*VerifyResult, error) {
+	stored, ok := p.payments.Load(providerReference)
+	if !ok {
+		return &VerifyResult{ProviderReference: providerReference, Status: StatusPending}, nil
+	}
+	result := *stored.(*VerifyResult)
+	if result.Status == StatusFailed {
+		result.FailureReason = "declined by fake provider"
+	}
+	return &result, nil
+}
+
+func (p *FakeProvider) Refund(req RefundRequest) (*RefundResult, error) {
+	return &RefundResult{
+		ProviderReference: "fake_refund_" + req.Reference,
+		Status:            StatusSucceeded,
+	}, nil
+}
+
+// ParseWebhook expects the JSON body to be signed in the X-Fake-Signature header
+func (p *FakeProvider) ParseWebhook(req WebhookRequest) (*WebhookEvent, error) {
+	if !verifySignature(p.webhookSecret, req.Body, lookup(req.Headers, "X-Fake-Signature")) {
+		return nil, ErrInvalidSignature
+	}
+
+	var payload fakeWebhookPayload
+	if err := json.Unmarshal(req.Body, &payload); err != nil {
+		return nil, errors.New("invalid webhook payload")
+	}
+	if payload.EventID == "" || payload.Reference == "" || !Status(payload.Status).Valid() {
+		return nil, errors.New("invalid webhook payload")
+	}
+
+	return &WebhookEvent{
+		EventID:           payload.EventID,
+		Reference:         payload.Reference,
+		ProviderReference: payload.ProviderReference,
+		Status:            Status(payload.Status),
+		Amount:            payload.Amount,
+	}, nil
+}
+
+// Sign produces the X-Fake-Signature header value for a webhook body
+func (p *FakeProvider) Sign(body []byte) string {
+	return sign(p.webhookSecret, body)
+}
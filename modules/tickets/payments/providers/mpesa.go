@@ -0,0 +1,352 @@
+package payment_providers
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MpesaConfig holds Daraja API credentials for STK push payments
+type MpesaConfig struct {
+	BaseURL            string
+	ConsumerKey        string
+	ConsumerSecret     string
+	ShortCode          string
+	Passkey            string
+	CallbackURL        string
+	WebhookSecret      string
+	Initiator          string
+	SecurityCredential string
+	ResultURL          string
+	TimeoutURL         string
+}
+
+// MpesaProvider pushes a payment prompt to the buyer's phone. Daraja does not
+// sign its callbacks, so every payment gets its own callback URL carrying an
+// HMAC of the payment reference; callbacks without a valid token are rejected.
+type MpesaProvider struct {
+	config MpesaConfig
+	client *http.Client
+
+	tokenMutex  sync.Mutex
+	accessToken string
+	tokenExpiry time.Time
+}
+
+type mpesaCallback struct {
+	Body struct {
+		StkCallback struct {
+			MerchantRequestID string `json:"MerchantRequestID"`
+			CheckoutRequestID string `json:"CheckoutRequestID"`
+			ResultCode        int    `json:"ResultCode"`
+			ResultDesc        string `json:"ResultDesc"`
+			CallbackMetadata  struct {
+				Item []struct {
+					Name  string      `json:"Name"`
+					Value interface{} `json:"Value"`
+				} `json:"Item"`
+			} `json:"CallbackMetadata"`
+		} `json:"stkCallback"`
+	} `json:"Body"`
+}
+
+// NewMpesaProviderFromEnv returns nil when M-Pesa is not configured
+func NewMpesaProviderFromEnv() *MpesaProvider {
+	config := MpesaConfig{
+		BaseURL:            os.Getenv("MPESA_BASE_URL"),
+		ConsumerKey:        os.Getenv("MPESA_CONSUMER_KEY"),
+		ConsumerSecret:     os.Getenv("MPESA_CONSUMER_SECRET"),
+		ShortCode:          os.Getenv("MPESA_SHORTCODE"),
+		Passkey:            os.Getenv("MPESA_PASSKEY"),
+		CallbackURL:        os.Getenv("MPESA_CALLBACK_URL"),
+		WebhookSecret:      os.Getenv("MPESA_WEBHOOK_SECRET"),
+		Initiator:          os.Getenv("MPESA_INITIATOR"),
+		SecurityCredential: os.Getenv("MPESA_SECURITY_CREDENTIAL"),
+		ResultURL:          os.Getenv("MPESA_RESULT_URL"),
+		TimeoutURL:         os.Getenv("MPESA_TIMEOUT_URL"),
+	}
+	if config.ConsumerKey == "" || config.ShortCode == "" || config.WebhookSecret == "" {
+		return nil
+	}
+	if config.BaseURL == "" {
+		config.BaseURL = "https://sandbox.safaricom.co.ke"
+	}
+	return NewMpesaProvider(config)
+}
+
+func NewMpesaProvider(config MpesaConfig) *MpesaProvider {
+	return &MpesaProvider{
+		config: config,
+		client: &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+func (p *MpesaProvider) Name() string {
+	return "mpesa"
+}
+
+func (p *MpesaProvider) Initiate(req InitiateRequest) (*InitiateResult, error) {
+	if req.Phone == "" {
+		return nil, errors.New("phone number is required for mpesa payments")
+	}
+
+	timestamp, password := p.password()
+	callbackURL, err := p.callbackURL(req.Reference)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := map[string]interface{}{
+		"BusinessShortCode": p.config.ShortCode,
+		"Password":          password,
+		"Timestamp":         timestamp,
+		"TransactionType":   "CustomerPayBillOnline",
+		"Amount":            int64(math.Ceil(req.Amount)),
+		"PartyA":            req.Phone,
+		"PartyB":            p.config.ShortCode,
+		"PhoneNumber":       req.Phone,
+		"CallBackURL":       callbackURL,
+		"AccountReference":  truncate(req.Reference, 12),
+		"TransactionDesc":   truncate(req.Description, 13),
+	}
+
+	var response struct {
+		CheckoutRequestID string `json:"CheckoutRequestID"`
+		ResponseCode      string `json:"ResponseCode"`
+		ResponseDesc      string `json:"ResponseDescription"`
+		CustomerMessage   string `json:"CustomerMessage"`
+	}
+	if err := p.post("/mpesa/stkpush/v1/processrequest", payload, &response); err != nil {
+		return nil, err
+	}
+	if response.ResponseCode != "0" {
+		return nil, fmt.Errorf("mpesa rejected payment request: %s", response.ResponseDesc)
+	}
+
+	return &InitiateResult{
+		ProviderReference: response.CheckoutRequestID,
+		Status:            StatusPending,
+		Message:           response.CustomerMessage,
+	}, nil
+}
+
+func (p *MpesaProvider) Verify(providerReference string) (*VerifyResult, error) {
+	timestamp, password := p.password()
+	payload := map[string]interface{}{
+		"BusinessShortCode": p.config.ShortCode,
+		"Password":          password,
+		"Timestamp":         timestamp,
+		"CheckoutRequestID": providerReference,
+	}
+
+	var response struct {
+		ResultCode string `json:"ResultCode"`
+		ResultDesc string `json:"ResultDesc"`
+	}
+	if err := p.post("/mpesa/stkpushquery/v1/query", payload, &response); err != nil {
+		return nil, err
+	}
+
+	result := &VerifyResult{ProviderReference: providerReference}
+	switch response.ResultCode {
+	case "":
+		result.Status = StatusPending
+	case "0":
+		result.Status = StatusSucceeded
+	default:
+		result.Status = StatusFailed
+		result.FailureReason = response.ResultDesc
+	}
+	return result, nil
+}
+
+// Refund requests a reversal of the original M-Pesa transaction. Reversals
+// are asynchronous, so the refund is reported as pending.
+func (p *MpesaProvider) Refund(req RefundRequest) (*RefundResult, error) {
+	if req.Receipt == "" {
+		return nil, errors.New("mpesa receipt number is required for refunds")
+	}
+	if p.config.Initiator == "" || p.config.SecurityCredential == "" {
+		return nil, errors.New("mpesa reversals are not configured")
+	}
+
+	payload := map[string]interface{}{
+		"Initiator":              p.config.Initiator,
+		"SecurityCredential":     p.config.SecurityCredential,
+		"CommandID":              "TransactionReversal",
+		"TransactionID":          req.Receipt,
+		"Amount":                 int64(math.Ceil(req.Amount)),
+		"ReceiverParty":          p.config.ShortCode,
+		"RecieverIdentifierType": "11",
+		"ResultURL":              p.config.ResultURL,
+		"QueueTimeOutURL":        p.config.TimeoutURL,
+		"Remarks":                truncate(req.Reason, 100),
+	}
+
+	var response struct {
+		ConversationID string `json:"ConversationID"`
+		ResponseCode   string `json:"ResponseCode"`
+		ResponseDesc   string `json:"ResponseDescription"`
+	}
+	if err := p.post("/mpesa/reversal/v1/request", payload, &response); err != nil {
+		return nil, err
+	}
+	if response.ResponseCode != "0" {
+		return nil, fmt.Errorf("mpesa rejected reversal: %s", response.ResponseDesc)
+	}
+
+	return &RefundResult{
+		ProviderReference: response.ConversationID,
+		Status:            StatusPending,
+	}, nil
+}
+
+func (p *MpesaProvider) ParseWebhook(req WebhookRequest) (*WebhookEvent, error) {
+	reference := lookup(req.Query, "ref")
+	if !verifySignature(p.config.WebhookSecret, []byte(reference), lookup(req.Query, "token")) {
+		return nil, ErrInvalidSignature
+	}
+
+	var callback mpesaCallback
+	if err := json.Unmarshal(req.Body, &callback); err != nil {
+		return nil, errors.New("invalid webhook payload")
+	}
+	stk := callback.Body.StkCallback
+	if stk.CheckoutRequestID == "" {
+		return nil, errors.New("invalid webhook payload")
+	}
+
+	event := &WebhookEvent{
+		EventID:           stk.CheckoutRequestID,
+		Reference:         reference,
+		ProviderReference: stk.CheckoutRequestID,
+	}
+	if stk.ResultCode != 0 {
+		event.Status = StatusFailed
+		event.FailureReason = stk.ResultDesc
+		return event, nil
+	}
+
+	event.Status = StatusSucceeded
+	for _, item := range stk.CallbackMetadata.Item {
+		switch item.Name {
+		case "Amount":
+			if amount, ok := item.Value.(float64); ok {
+				event.Amount = amount
+			}
+		case "MpesaReceiptNumber":
+			if receipt, ok := item.Value.(string); ok {
+				event.Receipt = receipt
+			}
+		}
+	}
+	return event, nil
+}
+
+// callbackURL signs the payment reference into the callback so the webhook can be authenticated
+func (p *MpesaProvider) callbackURL(reference string) (string, error) {
+	u, err := url.Parse(p.config.CallbackURL)
+	if err != nil || p.config.CallbackURL == "" {
+		return "", errors.New("mpesa callback URL is not configured")
+	}
+	q := u.Query()
+	q.Set("ref", reference)
+	q.Set("token", sign(p.config.WebhookSecret, []byte(reference)))
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+func (p *MpesaProvider) password() (string, string) {
+	timestamp := time.Now().Format("20060102150405")
+	raw := p.config.ShortCode + p.config.Passkey + timestamp
+	return timestamp, base64.StdEncoding.EncodeToString([]byte(raw))
+}
+
+func (p *MpesaProvider) token() (string, error) {
+	p.tokenMutex.Lock()
+	defer p.tokenMutex.Unlock()
+
+	if p.accessToken != "" && time.Now().Before(p.tokenExpiry) {
+		return p.accessToken, nil
+	}
+
+	req, err := http.NewRequest(http.MethodGet, p.config.BaseURL+"/oauth/v1/generate?grant_type=client_credentials", nil)
+	if err != nil {
+		return "", err
+	}
+	req.SetBasicAuth(p.config.ConsumerKey, p.config.ConsumerSecret)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("mpesa authentication failed with status %d", resp.StatusCode)
+	}
+
+	var body struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   string `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", err
+	}
+
+	p.accessToken = body.AccessToken
+	// Refresh a minute early; Daraja tokens last an hour
+	p.tokenExpiry = time.Now().Add(59 * time.Minute)
+	return p.accessToken, nil
+}
+
+func (p *MpesaProvider) post(path string, payload interface{}, out interface{}) error {
+	token, err := p.token()
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, p.config.BaseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("mpesa request failed with status %d: %s", resp.StatusCode, strings.TrimSpace(string(raw)))
+	}
+	return json.Unmarshal(raw, out)
+}
+
+func truncate(value string, max int) string {
+	if len(value) <= max {
+		return value
+	}
+	return value[:max]
+}
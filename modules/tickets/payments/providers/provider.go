@@ -0,0 +1,174 @@
+package payment_providers
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+// Status is the provider-neutral outcome of a payment or refund
+type Status string
+
+const (
+	StatusPending   Status = "pending"
+	StatusSucceeded Status = "succeeded"
+	StatusFailed    Status = "failed"
+)
+
+// Valid reports whether s is one of the known outcomes
+func (s Status) Valid() bool {
+	switch s {
+	case StatusPending, StatusSucceeded, StatusFailed:
+		return true
+	}
+	return false
+}
+
+var (
+	ErrProviderNotFound = errors.New("payment provider not found")
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+)
+
+// InitiateRequest describes a payment to start with a provider
+type InitiateRequest struct {
+	// Reference is our payment transaction ID. Providers echo it back in
+	// webhooks so the callback can be matched to the transaction.
+	Reference   string
+	Amount      float64
+	Currency    string
+	Description string
+	Phone       string
+	Email       string
+	ReturnURL   string
+}
+
+// InitiateResult is what the client needs to complete the payment
+type InitiateResult struct {
+	ProviderReference string
+	Status            Status
+	RedirectURL       string
+	Message           string
+}
+
+// VerifyResult is the provider's current view of a payment
+type VerifyResult struct {
+	ProviderReference string
+	Status            Status
+	Amount            float64
+	Receipt           string
+	FailureReason     string
+}
+
+// RefundRequest describes a full or partial refund of a completed payment
+type RefundRequest struct {
+	// Reference is our refund ID. It stays the same when a refund is
+	// retried, so providers can discard the repeat.
+	Reference         string
+	ProviderReference string
+	Receipt           string
+	Amount            float64
+	Currency          string
+	Reason            string
+}
+
+type RefundResult struct {
+	ProviderReference string
+	Status            Status
+}
+
+// WebhookRequest carries the raw parts of an incoming provider callback
+type WebhookRequest struct {
+	Headers map[string]string
+	Query   map[string]string
+	Body    []byte
+}
+
+// WebhookEvent is a verified provider callback
+type WebhookEvent struct {
+	// EventID uniquely identifies the callback at the provider and is used
+	// to discard duplicate deliveries
+	EventID           string
+	Reference         string
+	ProviderReference string
+	Status            Status
+	Amount            float64
+	Receipt           string
+	FailureReason     string
+}
+
+// PaymentProvider is implemented by every payment gateway adapter
+type PaymentProvider interface {
+	Name() string
+	Initiate(req InitiateRequest) (*InitiateResult, error)
+	Verify(providerReference string) (*VerifyResult, error)
+	Refund(req RefundRequest) (*RefundResult, error)
+	// ParseWebhook checks the callback signature and decodes the event
+	ParseWebhook(req WebhookRequest) (*WebhookEvent, error)
+}
+
+// Registry looks up configured providers by name
+type Registry struct {
+	providers map[string]PaymentProvider
+}
+
+func NewRegistry(providers ...PaymentProvider) *Registry {
+	r := &Registry{providers: make(map[string]PaymentProvider)}
+	for _, p := range providers {
+		r.providers[p.Name()] = p
+	}
+	return r
+}
+
+// NewRegistryFromEnv registers every provider whose credentials are present
+// in the environment. The fake provider must be switched on explicitly with
+// ENABLE_FAKE_PAYMENTS=true and a FAKE_PAYMENT_WEBHOOK_SECRET, and is never
+// available in production.
+func NewRegistryFromEnv() *Registry {
+	var providers []PaymentProvider
+	if p := NewMpesaProviderFromEnv(); p != nil {
+		providers = append(providers, p)
+	}
+	if p := NewCardProviderFromEnv(); p != nil {
+		providers = append(providers, p)
+	}
+	if p := NewFakeProviderFromEnv(); p != nil {
+		providers = append(providers, p)
+	}
+	return NewRegistry(providers...)
+}
+
+func (r *Registry) Get(name string) (PaymentProvider, error) {
+	p, ok := r.providers[name]
+	if !ok {
+		return nil, ErrProviderNotFound
+	}
+	return p, nil
+}
+
+// sign returns the hex HMAC-SHA256 of payload under secret
+func sign(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// verifySignature compares a received hex signature in constant time
+func verifySignature(secret string, payload []byte, signature string) bool {
+	if secret == "" || signature == "" {
+		return false
+	}
+	expected := sign(secret, payload)
+	return hmac.Equal([]byte(expected), []byte(strings.ToLower(strings.TrimSpace(signature))))
+}
+
+// lookup reads a header or query value case-insensitively
+func lookup(values map[string]string, name string) string {
+	for k, v := range values {
+		if strings.EqualFold(k, name) {
+			return v
+		}
+	}
+	return ""
+}
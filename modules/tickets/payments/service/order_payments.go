@@ -0,0 +1,50 @@
+package payment_service
+
+import (
+	"errors"
+	"fmt"
+	invoice_service "ticket-zetu-api/modules/invoices/service"
+	ledger_service "ticket-zetu-api/modules/ledger/service"
+	"ticket-zetu-api/modules/tickets/models/tickets"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// quoteOrder returns the amount due for one of the user's pending orders
+func (s *paymentService) quoteOrder(tx *gorm.DB, userID, orderID string) (float64, string, error) {
+	if _, err := uuid.Parse(orderID); err != nil {
+		return 0, "", errors.New("invalid order ID format")
+	}
+
+	var order tickets.Order
+	if err := tx.Where("id = ? AND user_id = ?", orderID, userID).First(&order).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, "", errors.New("order not found")
+		}
+		return 0, "", err
+	}
+
+	if order.Status != tickets.OrderPending {
+		return 0, "", errors.New("order is not pending")
+	}
+	if time.Now().After(order.ExpiresAt) {
+		return 0, "", errors.New("order has expired")
+	}
+
+	return order.TotalAmount, fmt.Sprintf("Order %s", order.OrderNumber), nil
+}
+
+// fulfillOrder issues the order's tickets, posts the sale to the ledger and
+// numbers its receipt and commission invoice
+func (s *paymentService) fulfillOrder(tx *gorm.DB, payment *tickets.PaymentTransaction) error {
+	order, err := s.orderService.FulfillOrder(tx, payment.ReferenceID, payment.ID, payment.Provider)
+	if err != nil {
+		return err
+	}
+	if err := ledger_service.RecordTicketSale(tx, order.ID, order.EventID, ledger_service.Amount(order.TotalAmount), payment.Currency); err != nil {
+		return err
+	}
+	return invoice_service.IssueOrderInvoices(tx, order.ID, payment.Currency)
+}
@@ -0,0 +1,616 @@
+package payment_service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"ticket-zetu-api/modules/tickets/models/tickets"
+	order_service "ticket-zetu-api/modules/tickets/orders/service"
+	"ticket-zetu-api/modules/tickets/payments/dto"
+	"ticket-zetu-api/modules/tickets/payments/providers"
+	"ticket-zetu-api/modules/users/authorization/service"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// reconcileAfter is how long a payment waits for its webhook before
+	// ReconcilePayments asks the provider about it
+	reconcileAfter = 15 * time.Minute
+	// abandonAfter is how long a payment may stay pending with the provider
+	// before it is failed
+	abandonAfter = 24 * time.Hour
+)
+
+// PurposeHandler connects payments to the thing being paid for
+type PurposeHandler struct {
+	// Quote checks that the user may pay for the referenced record and returns the amount due
+	Quote func(tx *gorm.DB, userID, referenceID string) (amount float64, description string, err error)
+	// Fulfill completes the referenced record once its payment has succeeded
+	Fulfill func(tx *gorm.DB, payment *tickets.PaymentTransaction) error
+}
+
+type PaymentService interface {
+	InitiatePayment(userID string, input dto.InitiatePaymentInput) (*dto.PaymentResponse, error)
+	GetPayment(userID, paymentID string) (*dto.PaymentResponse, error)
+	VerifyPayment(userID, paymentID string) (*dto.PaymentResponse, error)
+	HandleWebhook(providerName string, req payment_providers.WebhookRequest) error
+	RefundPayment(tx *gorm.DB, paymentID string, amount float64, reason string) (*tickets.PaymentRefund, error)
+	SendRefund(refundID string) error
+	SendPendingRefunds(limit int) (int, error)
+	ReconcilePayments(limit int) (int, error)
+	RegisterPurpose(purpose tickets.PaymentPurpose, handler PurposeHandler)
+}
+
+type paymentService struct {
+	db                   *gorm.DB
+	authorizationService authorization_service.PermissionService
+	providers            *payment_providers.Registry
+	orderService         order_service.OrderService
+	purposes             map[tickets.PaymentPurpose]PurposeHandler
+}
+
+func NewPaymentService(db *gorm.DB, authService authorization_service.PermissionService, providers *payment_providers.Registry, orderService order_service.OrderService) PaymentService {
+	s := &paymentService{
+		db:                   db,
+		authorizationService: authService,
+		providers:            providers,
+		orderService:         orderService,
+		purposes:             make(map[tickets.PaymentPurpose]PurposeHandler),
+	}
+	s.RegisterPurpose(tickets.PaymentForOrder, PurposeHandler{
+		Quote:   s.quoteOrder,
+		Fulfill: s.fulfillOrder,
+	})
+	return s
+}
+
+func (s *paymentService) RegisterPurpose(purpose tickets.PaymentPurpose, handler PurposeHandler) {
+	s.purposes[purpose] = handler
+}
+
+func (s *paymentService) InitiatePayment(userID string, input dto.InitiatePaymentInput) (*dto.PaymentResponse, error) {
+	if _, err := uuid.Parse(userID); err != nil {
+		return nil, errors.New("invalid user ID format")
+	}
+
+	purpose := tickets.PaymentPurpose(input.Purpose)
+	if purpose == "" {
+		purpose = tickets.PaymentForOrder
+	}
+	handler, ok := s.purposes[purpose]
+	if !ok {
+		return nil, errors.New("unsupported payment purpose")
+	}
+
+	provider, err := s.providers.Get(input.Provider)
+	if err != nil {
+		return nil, err
+	}
+
+	amount, description, err := handler.Quote(s.db, userID, input.ReferenceID)
+	if err != nil {
+		return nil, err
+	}
+
+	payment := tickets.PaymentTransaction{
+		UserID:      userID,
+		Provider:    provider.Name(),
+		Purpose:     purpose,
+		ReferenceID: input.ReferenceID,
+		Amount:      amount,
+		Currency:    tickets.DefaultCurrency,
+		Status:      tickets.PaymentPending,
+	}
+	if err := s.db.Create(&payment).Error; err != nil {
+		return nil, err
+	}
+
+	// Free orders have nothing to collect and are fulfilled straight away
+	if amount == 0 {
+		if err := s.apply(provider.Name(), "free:"+payment.ID, payment.ID, payment_providers.StatusSucceeded, 0, "", "", ""); err != nil {
+			return nil, err
+		}
+		return s.GetPayment(userID, payment.ID)
+	}
+
+	result, err := provider.Initiate(payment_providers.InitiateRequest{
+		Reference:   payment.ID,
+		Amount:      amount,
+		Currency:    payment.Currency,
+		Description: description,
+		Phone:       input.Phone,
+		ReturnURL:   input.ReturnURL,
+	})
+	if err != nil {
+		s.db.Model(&payment).UpdateColumns(map[string]interface{}{
+			"status":         tickets.PaymentFailed,
+			"failure_reason": truncate(err.Error(), 255),
+			"updated_at":     time.Now(),
+		})
+		return nil, errors.New("payment provider rejected the payment")
+	}
+
+	if err := s.db.Model(&payment).UpdateColumns(map[string]interface{}{
+		"provider_reference": result.ProviderReference,
+		"updated_at":         time.Now(),
+	}).Error; err != nil {
+		return nil, err
+	}
+	payment.ProviderReference = result.ProviderReference
+
+	response := toDTO(&payment)
+	response.RedirectURL = result.RedirectURL
+	response.Message = result.Message
+	return response, nil
+}
+
+func (s *paymentService) GetPayment(userID, paymentID string) (*dto.PaymentResponse, error) {
+	payment, err := s.findUserPayment(userID, paymentID)
+	if err != nil {
+		return nil, err
+	}
+	return toDTO(payment), nil
+}
+
+// VerifyPayment asks the provider for the payment outcome, for clients that
+// do not want to wait for the webhook
+func (s *paymentService) VerifyPayment(userID, paymentID string) (*dto.PaymentResponse, error) {
+	payment, err := s.findUserPayment(userID, paymentID)
+	if err != nil {
+		return nil, err
+	}
+	if payment.Status != tickets.PaymentPending || payment.ProviderReference == "" {
+		return toDTO(payment), nil
+	}
+
+	provider, err := s.providers.Get(payment.Provider)
+	if err != nil {
+		return nil, err
+	}
+	result, err := provider.Verify(payment.ProviderReference)
+	if err != nil {
+		return nil, errors.New("could not verify payment with provider")
+	}
+
+	// Some providers only report the amount in their webhook; a success
+	// without one is left for the webhook, or for ReconcilePayments if the
+	// webhook never arrives
+	if result.Status == payment_providers.StatusSucceeded && result.Amount <= 0 {
+		return toDTO(payment), nil
+	}
+	if result.Status != payment_providers.StatusPending {
+		eventID := fmt.Sprintf("verify:%s:%s", payment.ID, result.Status)
+		if err := s.apply(payment.Provider, eventID, payment.ID, result.Status, result.Amount, result.Receipt, result.FailureReason, ""); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.GetPayment(userID, paymentID)
+}
+
+// HandleWebhook verifies and applies a provider callback. Deliveries are
+// recorded by (provider, event ID), so a repeated callback is a no-op.
+func (s *paymentService) HandleWebhook(providerName string, req payment_providers.WebhookRequest) error {
+	provider, err := s.providers.Get(providerName)
+	if err != nil {
+		return err
+	}
+
+	event, err := provider.ParseWebhook(req)
+	if err != nil {
+		return err
+	}
+
+	var payment tickets.PaymentTransaction
+	if err := s.db.Where("id = ? AND provider = ?", event.Reference, provider.Name()).First(&payment).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("payment not found")
+		}
+		return err
+	}
+
+	return s.apply(provider.Name(), event.EventID, payment.ID, event.Status, event.Amount, event.Receipt, event.FailureReason, string(req.Body))
+}
+
+// apply moves a pending payment to its final status and, on success, fulfils
+// whatever it paid for in the same transaction. A payment that succeeds but
+// cannot be fulfilled, for example because the tickets sold out after its
+// order expired, is refunded in full.
+func (s *paymentService) apply(provider, eventID, paymentID string, status payment_providers.Status, amount float64, receipt, failureReason, payload string) error {
+	// A success that does not say how much was paid cannot be checked
+	// against the amount due, so the provider is asked again
+	if status == payment_providers.StatusSucceeded && amount <= 0 {
+		var err error
+		status, amount, failureReason, err = s.verifiedAmount(paymentID)
+		if err != nil {
+			return err
+		}
+	}
+	// Anything else is treated as a success further down, so it is refused here
+	if !status.Valid() {
+		return errors.New("unknown payment status")
+	}
+	if status == payment_providers.StatusPending {
+		return nil
+	}
+	return s.record(provider, eventID, paymentID, status, amount, receipt, failureReason, payload)
+}
+
+// record stores the final outcome of a pending payment. A success whose
+// amount could not be verified is kept, since the customer has paid, but
+// nothing is fulfilled and the payment is refunded in full.
+func (s *paymentService) record(provider, eventID, paymentID string, status payment_providers.Status, amount float64, receipt, failureReason, payload string) error {
+	var refund *tickets.PaymentRefund
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		record := tickets.PaymentWebhookEvent{
+			Provider:  provider,
+			EventID:   eventID,
+			PaymentID: paymentID,
+			Status:    string(status),
+			Payload:   payload,
+		}
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&record)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		var payment tickets.PaymentTransaction
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", paymentID).
+			First(&payment).Error; err != nil {
+			return err
+		}
+		if payment.Status != tickets.PaymentPending {
+			return nil
+		}
+
+		// A success without an amount is refunded further down
+		now := time.Now()
+		if status == payment_providers.StatusSucceeded && amount > 0 && amount+0.005 < payment.Amount {
+			status = payment_providers.StatusFailed
+			failureReason = "amount paid is less than amount due"
+		}
+
+		if status == payment_providers.StatusFailed {
+			return tx.Model(&payment).UpdateColumns(map[string]interface{}{
+				"status":         tickets.PaymentFailed,
+				"failure_reason": truncate(failureReason, 255),
+				"completed_at":   now,
+				"version":        gorm.Expr("version + 1"),
+				"updated_at":     now,
+			}).Error
+		}
+
+		payment.Status = tickets.PaymentSucceeded
+		payment.Receipt = receipt
+		payment.CompletedAt = &now
+		if err := tx.Model(&payment).UpdateColumns(map[string]interface{}{
+			"status":       payment.Status,
+			"receipt":      receipt,
+			"completed_at": now,
+			"version":      gorm.Expr("version + 1"),
+			"updated_at":   now,
+		}).Error; err != nil {
+			return err
+		}
+
+		if payment.Amount > 0 && amount <= 0 {
+			if err := tx.Model(&payment).UpdateColumns(map[string]interface{}{
+				"failure_reason": "amount paid could not be verified",
+				"updated_at":     now,
+			}).Error; err != nil {
+				return err
+			}
+			var err error
+			refund, err = s.RefundPayment(tx, payment.ID, payment.Amount, "amount paid could not be verified")
+			return err
+		}
+
+		handler, ok := s.purposes[payment.Purpose]
+		if !ok {
+			return errors.New("unsupported payment purpose")
+		}
+
+		// Fulfil inside a savepoint so a failed fulfilment still records the payment
+		if err := tx.Transaction(func(ftx *gorm.DB) error {
+			return handler.Fulfill(ftx, &payment)
+		}); err != nil {
+			if err := tx.Model(&payment).UpdateColumns(map[string]interface{}{
+				"failure_reason": truncate("could not be fulfilled: "+err.Error(), 255),
+				"updated_at":     now,
+			}).Error; err != nil {
+				return err
+			}
+			refund, err = s.RefundPayment(tx, payment.ID, payment.Amount, "payment could not be fulfilled")
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// The refund is already queued, so a provider failure is retried later
+	if refund != nil {
+		if err := s.SendRefund(refund.ID); err != nil {
+			log.Printf("Failed to refund unfulfilled payment %s: %v", paymentID, err)
+		}
+	}
+	return nil
+}
+
+// verifiedAmount asks the provider for the outcome and amount of a payment
+// whose success notice carried no amount. Free payments have nothing to
+// check. A success the provider still cannot put a figure on comes back
+// without an amount, and record refunds it.
+func (s *paymentService) verifiedAmount(paymentID string) (payment_providers.Status, float64, string, error) {
+	var payment tickets.PaymentTransaction
+	if err := s.db.Where("id = ?", paymentID).First(&payment).Error; err != nil {
+		return "", 0, "", err
+	}
+	if payment.Status != tickets.PaymentPending {
+		return payment_providers.StatusPending, 0, "", nil
+	}
+	if payment.Amount == 0 {
+		return payment_providers.StatusSucceeded, 0, "", nil
+	}
+	if payment.ProviderReference == "" {
+		return payment_providers.StatusFailed, 0, "amount paid could not be verified", nil
+	}
+
+	provider, err := s.providers.Get(payment.Provider)
+	if err != nil {
+		return "", 0, "", err
+	}
+	result, err := provider.Verify(payment.ProviderReference)
+	if err != nil {
+		return "", 0, "", errors.New("could not verify payment with provider")
+	}
+	return result.Status, result.Amount, result.FailureReason, nil
+}
+
+// RefundPayment books a refund of part or all of a completed payment and
+// queues it for the provider. Pass a transaction to make the refund part of
+// a larger change. The provider is never called here: once the transaction
+// has committed, hand the returned refund to SendRefund. Payments that never
+// went through a provider return no refund.
+func (s *paymentService) RefundPayment(tx *gorm.DB, paymentID string, amount float64, reason string) (*tickets.PaymentRefund, error) {
+	if amount <= 0 {
+		return nil, errors.New("refund amount must be greater than zero")
+	}
+
+	var queued *tickets.PaymentRefund
+	refund := func(tx *gorm.DB) error {
+		var payment tickets.PaymentTransaction
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", paymentID).
+			First(&payment).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("payment not found")
+			}
+			return err
+		}
+
+		if payment.Status != tickets.PaymentSucceeded && payment.Status != tickets.PaymentPartiallyRefunded {
+			return errors.New("payment cannot be refunded")
+		}
+		if amount > payment.Amount-payment.RefundedAmount+0.005 {
+			return errors.New("refund exceeds the amount paid")
+		}
+
+		refunded := payment.RefundedAmount + amount
+		status := tickets.PaymentPartiallyRefunded
+		if refunded+0.005 >= payment.Amount {
+			refunded = payment.Amount
+			status = tickets.PaymentRefunded
+		}
+
+		if err := tx.Model(&payment).UpdateColumns(map[string]interface{}{
+			"refunded_amount": refunded,
+			"status":          status,
+			"version":         gorm.Expr("version + 1"),
+			"updated_at":      time.Now(),
+		}).Error; err != nil {
+			return err
+		}
+
+		// Zero-value payments never went through a provider
+		if payment.Amount == 0 || payment.ProviderReference == "" {
+			return nil
+		}
+
+		queued = &tickets.PaymentRefund{
+			PaymentID: payment.ID,
+			Amount:    amount,
+			Currency:  payment.Currency,
+			Reason:    truncate(reason, 255),
+			Status:    tickets.PaymentRefundPending,
+		}
+		return tx.Create(queued).Error
+	}
+
+	var err error
+	if tx != nil {
+		err = refund(tx)
+	} else {
+		err = s.db.Transaction(refund)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return queued, nil
+}
+
+// SendRefund asks the provider to return a queued refund. A refund that has
+// already been sent is left alone; a failure is recorded on the refund and
+// it stays pending for SendPendingRefunds.
+func (s *paymentService) SendRefund(refundID string) error {
+	var refund tickets.PaymentRefund
+	if err := s.db.Preload("Payment").
+		Where("id = ? AND status = ?", refundID, tickets.PaymentRefundPending).
+		First(&refund).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	now := time.Now()
+	provider, err := s.providers.Get(refund.Payment.Provider)
+	if err == nil {
+		var result *payment_providers.RefundResult
+		result, err = provider.Refund(payment_providers.RefundRequest{
+			Reference:         refund.ID,
+			ProviderReference: refund.Payment.ProviderReference,
+			Receipt:           refund.Payment.Receipt,
+			Amount:            refund.Amount,
+			Currency:          refund.Currency,
+			Reason:            refund.Reason,
+		})
+		if err == nil {
+			return s.db.Model(&refund).
+				Where("status = ?", tickets.PaymentRefundPending).
+				UpdateColumns(map[string]interface{}{
+					"status":             tickets.PaymentRefundSent,
+					"provider_reference": result.ProviderReference,
+					"attempts":           gorm.Expr("attempts + 1"),
+					"last_error":         "",
+					"sent_at":            now,
+					"updated_at":         now,
+				}).Error
+		}
+	}
+
+	if updateErr := s.db.Model(&refund).UpdateColumns(map[string]interface{}{
+		"attempts":   gorm.Expr("attempts + 1"),
+		"last_error": truncate(err.Error(), 255),
+		"updated_at": now,
+	}).Error; updateErr != nil {
+		return updateErr
+	}
+	return fmt.Errorf("payment provider refund failed: %w", err)
+}
+
+// SendPendingRefunds retries refunds the provider has not yet accepted,
+// oldest first, and returns how many were sent
+func (s *paymentService) SendPendingRefunds(limit int) (int, error) {
+	var refunds []tickets.PaymentRefund
+	if err := s.db.Select("id").
+		Where("status = ?", tickets.PaymentRefundPending).
+		Order("created_at ASC").
+		Limit(limit).
+		Find(&refunds).Error; err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, refund := range refunds {
+		if err := s.SendRefund(refund.ID); err != nil {
+			log.Printf("Failed to send refund %s: %v", refund.ID, err)
+			continue
+		}
+		sent++
+	}
+	return sent, nil
+}
+
+// ReconcilePayments settles payments still pending well after they were
+// started, for when the provider's webhook was lost. Each is checked with
+// the provider: an outcome is recorded as if the webhook had delivered it,
+// a success without an amount is refunded, and a payment the provider
+// still reports as pending is failed once it is too old to complete.
+// It returns how many payments were settled.
+func (s *paymentService) ReconcilePayments(limit int) (int, error) {
+	now := time.Now()
+	var payments []tickets.PaymentTransaction
+	if err := s.db.Where("status = ? AND created_at <= ?", tickets.PaymentPending, now.Add(-reconcileAfter)).
+		Order("created_at ASC").
+		Limit(limit).
+		Find(&payments).Error; err != nil {
+		return 0, err
+	}
+
+	settled := 0
+	for _, payment := range payments {
+		result := &payment_providers.VerifyResult{Status: payment_providers.StatusPending}
+		if payment.ProviderReference != "" {
+			provider, err := s.providers.Get(payment.Provider)
+			if err != nil {
+				log.Printf("Failed to reconcile payment %s: %v", payment.ID, err)
+				continue
+			}
+			if result, err = provider.Verify(payment.ProviderReference); err != nil {
+				log.Printf("Failed to verify payment %s with provider: %v", payment.ID, err)
+				continue
+			}
+		}
+
+		if result.Status == payment_providers.StatusPending {
+			if payment.CreatedAt.After(now.Add(-abandonAfter)) {
+				continue
+			}
+			result.Status = payment_providers.StatusFailed
+			result.FailureReason = "payment was not completed in time"
+		}
+		if !result.Status.Valid() {
+			log.Printf("Failed to reconcile payment %s: unknown payment status %q", payment.ID, result.Status)
+			continue
+		}
+
+		eventID := fmt.Sprintf("reconcile:%s:%s", payment.ID, result.Status)
+		if err := s.record(payment.Provider, eventID, payment.ID, result.Status, result.Amount, result.Receipt, result.FailureReason, ""); err != nil {
+			log.Printf("Failed to reconcile payment %s: %v", payment.ID, err)
+			continue
+		}
+		settled++
+	}
+	return settled, nil
+}
+
+func (s *paymentService) findUserPayment(userID, paymentID string) (*tickets.PaymentTransaction, error) {
+	if _, err := uuid.Parse(paymentID); err != nil {
+		return nil, errors.New("invalid payment ID format")
+	}
+
+	var payment tickets.PaymentTransaction
+	if err := s.db.Where("id = ? AND user_id = ?", paymentID, userID).First(&payment).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("payment not found")
+		}
+		return nil, err
+	}
+	return &payment, nil
+}
+
+func toDTO(payment *tickets.PaymentTransaction) *dto.PaymentResponse {
+	return &dto.PaymentResponse{
+		ID:                payment.ID,
+		Provider:          payment.Provider,
+		Purpose:           string(payment.Purpose),
+		ReferenceID:       payment.ReferenceID,
+		Amount:            payment.Amount,
+		RefundedAmount:    payment.RefundedAmount,
+		Currency:          payment.Currency,
+		Status:            string(payment.Status),
+		ProviderReference: payment.ProviderReference,
+		Receipt:           payment.Receipt,
+		FailureReason:     payment.FailureReason,
+		CompletedAt:       payment.CompletedAt,
+		CreatedAt:         payment.CreatedAt,
+		UpdatedAt:         payment.UpdatedAt,
+	}
+}
+
+func truncate(value string, max int) string {
+	if len(value) <= max {
+		return value
+	}
+	return value[:max]
+}
@@ -0,0 +1,180 @@
+package pricing_controller
+
+import (
+	"ticket-zetu-api/logs/handler"
+	"ticket-zetu-api/modules/tickets/pricing/dto"
+	"ticket-zetu-api/modules/tickets/pricing/service"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+type PricingController struct {
+	service    pricing_service.PricingService
+	logHandler *handler.LogHandler
+	validator  *validator.Validate
+}
+
+func NewPricingController(service pricing_service.PricingService, logHandler *handler.LogHandler) *PricingController {
+	return &PricingController{
+		service:    service,
+		logHandler: logHandler,
+		validator:  validator.New(),
+	}
+}
+
+// GetQuote godoc
+// @Summary Get a price quote for a ticket type
+// @Description Prices a quantity of tickets as checkout would right now, naming the price tier and demand rule that produced the price. Only events on sale can be quoted.
+// @Tags Pricing
+// @Accept json
+// @Produce json
+// @Param ticket_type_id path string true "Ticket type ID"
+// @Param quantity query int false "Number of tickets" default(1)
+// @Success 200 {object} map[string]interface{} "Quote retrieved successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid ticket type ID or quantity, or event not on sale"
+// @Failure 404 {object} map[string]interface{} "Ticket type or event not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /pricing/ticket-types/{ticket_type_id}/quote [get]
+func (c *PricingController) GetQuote(ctx *fiber.Ctx) error {
+	ticketTypeID := ctx.Params("ticket_type_id")
+	quantity := ctx.QueryInt("quantity", 1)
+
+	quote, err := c.service.GetQuote(ticketTypeID, quantity)
+	if err != nil {
+		return c.handleError(ctx, err)
+	}
+
+	return c.logHandler.LogSuccess(ctx, quote, "Quote retrieved successfully", false)
+}
+
+// GetRules godoc
+// @Summary Get a ticket type's demand pricing rules
+// @Description Retrieves the demand rules of one of the organizer's ticket types, lowest threshold first.
+// @Tags Pricing
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param ticket_type_id path string true "Ticket type ID"
+// @Success 200 {object} map[string]interface{} "Pricing rules retrieved successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid ticket type ID"
+// @Failure 404 {object} map[string]interface{} "Ticket type or organizer not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /pricing/ticket-types/{ticket_type_id}/rules [get]
+func (c *PricingController) GetRules(ctx *fiber.Ctx) error {
+	userID := ctx.Locals("user_id").(string)
+	ticketTypeID := ctx.Params("ticket_type_id")
+
+	rules, err := c.service.GetRules(userID, ticketTypeID)
+	if err != nil {
+		return c.handleError(ctx, err)
+	}
+
+	return c.logHandler.LogSuccess(ctx, rules, "Pricing rules retrieved successfully", false)
+}
+
+// CreateRule godoc
+// @Summary Add a demand pricing rule
+// @Description Scales a ticket type's price by price_modifier once sold_percentage of its stock is sold. The rule with the highest threshold reached applies.
+// @Tags Pricing
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param ticket_type_id path string true "Ticket type ID"
+// @Param input body dto.PricingRuleInput true "Pricing rule"
+// @Success 200 {object} map[string]interface{} "Pricing rule created successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid request body or ticket type ID"
+// @Failure 404 {object} map[string]interface{} "Ticket type or organizer not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /pricing/ticket-types/{ticket_type_id}/rules [post]
+func (c *PricingController) CreateRule(ctx *fiber.Ctx) error {
+	userID := ctx.Locals("user_id").(string)
+	ticketTypeID := ctx.Params("ticket_type_id")
+
+	var input dto.PricingRuleInput
+	if err := ctx.BodyParser(&input); err != nil {
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, "Invalid request body"), fiber.StatusBadRequest)
+	}
+
+	if err := c.validator.Struct(input); err != nil {
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, err.Error()), fiber.StatusBadRequest)
+	}
+
+	rule, err := c.service.CreateRule(userID, ticketTypeID, input)
+	if err != nil {
+		return c.handleError(ctx, err)
+	}
+
+	return c.logHandler.LogSuccess(ctx, rule, "Pricing rule created successfully", true)
+}
+
+// UpdateRule godoc
+// @Summary Update a demand pricing rule
+// @Description Replaces the threshold and modifier of a demand rule, and optionally activates or deactivates it.
+// @Tags Pricing
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Pricing rule ID"
+// @Param input body dto.PricingRuleInput true "Pricing rule"
+// @Success 200 {object} map[string]interface{} "Pricing rule updated successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid request body or rule ID"
+// @Failure 404 {object} map[string]interface{} "Pricing rule or organizer not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /pricing/rules/{id} [put]
+func (c *PricingController) UpdateRule(ctx *fiber.Ctx) error {
+	userID := ctx.Locals("user_id").(string)
+	ruleID := ctx.Params("id")
+
+	var input dto.PricingRuleInput
+	if err := ctx.BodyParser(&input); err != nil {
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, "Invalid request body"), fiber.StatusBadRequest)
+	}
+
+	if err := c.validator.Struct(input); err != nil {
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, err.Error()), fiber.StatusBadRequest)
+	}
+
+	rule, err := c.service.UpdateRule(userID, ruleID, input)
+	if err != nil {
+		return c.handleError(ctx, err)
+	}
+
+	return c.logHandler.LogSuccess(ctx, rule, "Pricing rule updated successfully", true)
+}
+
+// DeleteRule godoc
+// @Summary Delete a demand pricing rule
+// @Description Removes a demand rule from one of the organizer's ticket types.
+// @Tags Pricing
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Pricing rule ID"
+// @Success 200 {object} map[string]interface{} "Pricing rule deleted successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid rule ID"
+// @Failure 404 {object} map[string]interface{} "Pricing rule or organizer not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /pricing/rules/{id} [delete]
+func (c *PricingController) DeleteRule(ctx *fiber.Ctx) error {
+	userID := ctx.Locals("user_id").(string)
+	ruleID := ctx.Params("id")
+
+	if err := c.service.DeleteRule(userID, ruleID); err != nil {
+		return c.handleError(ctx, err)
+	}
+
+	return c.logHandler.LogSuccess(ctx, nil, "Pricing rule deleted successfully", true)
+}
+
+func (c *PricingController) handleError(ctx *fiber.Ctx, err error) error {
+	switch err.Error() {
+	case "invalid ticket type ID format", "invalid pricing rule ID format", "quantity must be at least 1",
+		"event is not open for ticket sales":
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, err.Error()), fiber.StatusBadRequest)
+	case "ticket type not found", "event not found", "pricing rule not found", "organizer not found":
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusNotFound, err.Error()), fiber.StatusNotFound)
+	default:
+		return c.logHandler.LogError(ctx, err, fiber.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,43 @@
+package dto
+
+import "time"
+
+// PriceQuote defines how a ticket type's unit price was produced: the base
+// price of the active price tier (or of the ticket type when no tier applies),
+// times the ticket type's modifier, times the modifier of any demand rule met
+type PriceQuote struct {
+	TicketTypeID   string    `json:"ticket_type_id"`
+	UnitPrice      float64   `json:"unit_price"`
+	BasePrice      float64   `json:"base_price"`
+	PriceModifier  float64   `json:"price_modifier"`
+	DemandModifier float64   `json:"demand_modifier"`
+	PriceTierID    string    `json:"price_tier_id,omitempty"`
+	PriceTierName  string    `json:"price_tier_name,omitempty"`
+	PricingRuleID  string    `json:"pricing_rule_id,omitempty"`
+	ResolvedAt     time.Time `json:"resolved_at"`
+}
+
+// QuoteResponse defines the response structure for a price quote on a quantity of tickets
+type QuoteResponse struct {
+	PriceQuote
+	Quantity int     `json:"quantity"`
+	Subtotal float64 `json:"subtotal"`
+}
+
+// PricingRuleInput defines the input structure for a demand pricing rule
+type PricingRuleInput struct {
+	SoldPercentage float64 `json:"sold_percentage" example:"75" validate:"gt=0,lte=100"`
+	PriceModifier  float64 `json:"price_modifier" example:"1.25" validate:"gt=0,lte=100"`
+	IsActive       *bool   `json:"is_active,omitempty" example:"true"`
+}
+
+// PricingRuleResponse defines the response structure for a demand pricing rule
+type PricingRuleResponse struct {
+	ID             string    `json:"id"`
+	TicketTypeID   string    `json:"ticket_type_id"`
+	SoldPercentage float64   `json:"sold_percentage"`
+	PriceModifier  float64   `json:"price_modifier"`
+	IsActive       bool      `json:"is_active"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
@@ -0,0 +1,259 @@
+package pricing_service
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"ticket-zetu-api/modules/tickets/models/tickets"
+	"ticket-zetu-api/modules/tickets/pricing/dto"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Resolve prices one unit of a ticket type at the given time. The active price
+// tier is the one whose effective window contains the time and whose ticket
+// range contains the number already sold; default tiers only apply when no
+// other tier does. Demand rules then scale the price by the share of stock sold.
+// ownUnits are stocked units the buyer already holds, which are not counted as sold.
+// A whole order is priced at the tier active when it is placed.
+func Resolve(tx *gorm.DB, ticketType *tickets.TicketType, at time.Time, ownUnits int) (*dto.PriceQuote, error) {
+	sold, total, err := soldCount(tx, ticketType.ID)
+	if err != nil {
+		return nil, err
+	}
+	if total > 0 {
+		sold = max(sold-ownUnits, 0)
+	}
+
+	var tiers []tickets.PriceTier
+	if err := tx.Model(ticketType).
+		Where("status = ?", tickets.PriceTierActive).
+		Association("PriceTiers").
+		Find(&tiers); err != nil {
+		return nil, err
+	}
+
+	var rules []tickets.PricingRule
+	if err := tx.Where("ticket_type_id = ? AND is_active = ?", ticketType.ID, true).
+		Find(&rules).Error; err != nil {
+		return nil, err
+	}
+
+	return quoteAt(ticketType, at, sold, total, tiers, rules), nil
+}
+
+// ResolveAll prices one unit of each ticket type as Resolve does for a buyer
+// holding nothing, loading stock, tiers and demand rules for the whole list
+// at once. Quotes are keyed by ticket type ID.
+func ResolveAll(tx *gorm.DB, ticketTypes []tickets.TicketType, at time.Time) (map[string]*dto.PriceQuote, error) {
+	quotes := make(map[string]*dto.PriceQuote, len(ticketTypes))
+	if len(ticketTypes) == 0 {
+		return quotes, nil
+	}
+	ids := make([]string, len(ticketTypes))
+	for i := range ticketTypes {
+		ids[i] = ticketTypes[i].ID
+	}
+
+	var stocks []tickets.TicketStock
+	if err := tx.Where("ticket_type_id IN ?", ids).Find(&stocks).Error; err != nil {
+		return nil, err
+	}
+	stockByType := make(map[string]tickets.TicketStock, len(stocks))
+	for _, stock := range stocks {
+		stockByType[stock.TicketTypeID] = stock
+	}
+
+	// Ticket types without a stock row count their issued tickets instead
+	var issued []struct {
+		TicketTypeID string
+		Issued       int
+	}
+	if err := tx.Model(&tickets.Ticket{}).
+		Select("ticket_type_id, COUNT(*) AS issued").
+		Where("ticket_type_id IN ? AND status IN ?", ids,
+			[]tickets.TicketStatus{tickets.TicketValid, tickets.TicketUsed, tickets.TicketPending}).
+		Group("ticket_type_id").
+		Scan(&issued).Error; err != nil {
+		return nil, err
+	}
+	issuedByType := make(map[string]int, len(issued))
+	for _, row := range issued {
+		issuedByType[row.TicketTypeID] = row.Issued
+	}
+
+	var links []struct {
+		TicketTypeID string
+		PriceTierID  string
+	}
+	if err := tx.Table("ticket_type_price_tiers").
+		Where("ticket_type_id IN ?", ids).
+		Scan(&links).Error; err != nil {
+		return nil, err
+	}
+	tierIDs := make([]string, 0, len(links))
+	for _, link := range links {
+		tierIDs = append(tierIDs, link.PriceTierID)
+	}
+	tierByID := make(map[string]tickets.PriceTier)
+	if len(tierIDs) > 0 {
+		var tiers []tickets.PriceTier
+		if err := tx.Where("id IN ? AND status = ?", tierIDs, tickets.PriceTierActive).
+			Find(&tiers).Error; err != nil {
+			return nil, err
+		}
+		for _, tier := range tiers {
+			tierByID[tier.ID] = tier
+		}
+	}
+	tiersByType := make(map[string][]tickets.PriceTier)
+	for _, link := range links {
+		if tier, ok := tierByID[link.PriceTierID]; ok {
+			tiersByType[link.TicketTypeID] = append(tiersByType[link.TicketTypeID], tier)
+		}
+	}
+
+	var rules []tickets.PricingRule
+	if err := tx.Where("ticket_type_id IN ? AND is_active = ?", ids, true).
+		Find(&rules).Error; err != nil {
+		return nil, err
+	}
+	rulesByType := make(map[string][]tickets.PricingRule)
+	for _, rule := range rules {
+		rulesByType[rule.TicketTypeID] = append(rulesByType[rule.TicketTypeID], rule)
+	}
+
+	for i := range ticketTypes {
+		ticketType := &ticketTypes[i]
+		sold, total := issuedByType[ticketType.ID], 0
+		if stock, ok := stockByType[ticketType.ID]; ok {
+			sold, total = stock.TotalStock-stock.AvailableStock, stock.TotalStock
+		}
+		quotes[ticketType.ID] = quoteAt(ticketType, at, sold, total, tiersByType[ticketType.ID], rulesByType[ticketType.ID])
+	}
+	return quotes, nil
+}
+
+// quoteAt prices one unit from the ticket type's sold count, stock size,
+// active tiers and active demand rules
+func quoteAt(ticketType *tickets.TicketType, at time.Time, sold, total int, tiers []tickets.PriceTier, rules []tickets.PricingRule) *dto.PriceQuote {
+	quote := &dto.PriceQuote{
+		TicketTypeID:   ticketType.ID,
+		BasePrice:      ticketType.BasePrice,
+		PriceModifier:  ticketType.PriceModifier,
+		DemandModifier: 1,
+		ResolvedAt:     at,
+	}
+	if tier := activeTier(tiers, at, sold); tier != nil {
+		quote.BasePrice = tier.BasePrice
+		quote.PriceTierID = tier.ID
+		quote.PriceTierName = tier.Name
+	}
+
+	// Demand rules need a stock size to measure against
+	if total > 0 {
+		soldPercentage := float64(sold) * 100 / float64(total)
+		if rule := demandRule(rules, soldPercentage); rule != nil {
+			quote.DemandModifier = rule.PriceModifier
+			quote.PricingRuleID = rule.ID
+		}
+	}
+
+	quote.UnitPrice = roundAmount(quote.BasePrice * quote.PriceModifier * quote.DemandModifier)
+	return quote
+}
+
+// demandRule picks the rule with the highest threshold the sold share has
+// reached, breaking ties by ID
+func demandRule(rules []tickets.PricingRule, soldPercentage float64) *tickets.PricingRule {
+	var best *tickets.PricingRule
+	for i := range rules {
+		rule := &rules[i]
+		if rule.SoldPercentage > soldPercentage {
+			continue
+		}
+		if best == nil || rule.SoldPercentage > best.SoldPercentage ||
+			(rule.SoldPercentage == best.SoldPercentage && rule.ID < best.ID) {
+			best = rule
+		}
+	}
+	return best
+}
+
+// SeatPrice prices a reserved seat from the seat's own price tier, keeping the
+// ticket type's and demand modifiers of the quote. Seats without an active
+// tier cost the quoted unit price.
+func SeatPrice(tx *gorm.DB, quote *dto.PriceQuote, priceTierID string) (float64, error) {
+	if priceTierID == "" {
+		return quote.UnitPrice, nil
+	}
+	var tier tickets.PriceTier
+	err := tx.Where("id = ? AND status = ? AND deleted_at IS NULL", priceTierID, tickets.PriceTierActive).First(&tier).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return quote.UnitPrice, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return roundAmount(tier.BasePrice * quote.PriceModifier * quote.DemandModifier), nil
+}
+
+// activeTier picks the tier that applies at the given time and sold count,
+// preferring non-default tiers, then the most recently started window, then
+// the tier for the most tickets sold
+func activeTier(tiers []tickets.PriceTier, at time.Time, sold int) *tickets.PriceTier {
+	candidates := make([]tickets.PriceTier, 0, len(tiers))
+	for _, tier := range tiers {
+		if at.Before(tier.EffectiveFrom) || (tier.EffectiveTo != nil && !at.Before(*tier.EffectiveTo)) {
+			continue
+		}
+		if sold < tier.MinTickets || (tier.MaxTickets != nil && sold >= *tier.MaxTickets) {
+			continue
+		}
+		candidates = append(candidates, tier)
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.IsDefault != b.IsDefault {
+			return !a.IsDefault
+		}
+		if !a.EffectiveFrom.Equal(b.EffectiveFrom) {
+			return a.EffectiveFrom.After(b.EffectiveFrom)
+		}
+		return a.MinTickets > b.MinTickets
+	})
+	return &candidates[0]
+}
+
+// soldCount reports how many units of a ticket type are taken and its total
+// stock. Units held or reserved for checkouts count as taken, so concurrent
+// buyers cannot all claim the last units of a cheaper tier. Ticket types
+// without a stock row have no total, and their issued tickets are counted.
+func soldCount(tx *gorm.DB, ticketTypeID string) (int, int, error) {
+	var stock tickets.TicketStock
+	err := tx.Where("ticket_type_id = ?", ticketTypeID).First(&stock).Error
+	if err == nil {
+		return stock.TotalStock - stock.AvailableStock, stock.TotalStock, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, 0, err
+	}
+
+	var issued int64
+	if err := tx.Model(&tickets.Ticket{}).
+		Where("ticket_type_id = ? AND status IN ?", ticketTypeID,
+			[]tickets.TicketStatus{tickets.TicketValid, tickets.TicketUsed, tickets.TicketPending}).
+		Count(&issued).Error; err != nil {
+		return 0, 0, err
+	}
+	return int(issued), 0, nil
+}
+
+func roundAmount(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
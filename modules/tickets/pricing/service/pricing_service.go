@@ -0,0 +1,192 @@
+package pricing_service
+
+import (
+	"errors"
+	"ticket-zetu-api/modules/events/models/events"
+	organizers_services "ticket-zetu-api/modules/organizers/services"
+	"ticket-zetu-api/modules/tickets/models/tickets"
+	"ticket-zetu-api/modules/tickets/pricing/dto"
+	"ticket-zetu-api/modules/users/authorization/service"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type PricingService interface {
+	GetQuote(ticketTypeID string, quantity int) (*dto.QuoteResponse, error)
+	GetRules(userID, ticketTypeID string) ([]dto.PricingRuleResponse, error)
+	CreateRule(userID, ticketTypeID string, input dto.PricingRuleInput) (*dto.PricingRuleResponse, error)
+	UpdateRule(userID, ruleID string, input dto.PricingRuleInput) (*dto.PricingRuleResponse, error)
+	DeleteRule(userID, ruleID string) error
+}
+
+type pricingService struct {
+	db                   *gorm.DB
+	authorizationService authorization_service.PermissionService
+}
+
+func NewPricingService(db *gorm.DB, authService authorization_service.PermissionService) PricingService {
+	return &pricingService{
+		db:                   db,
+		authorizationService: authService,
+	}
+}
+
+// GetQuote prices a quantity of tickets as checkout would right now. Only
+// ticket types of events that are on sale can be quoted.
+func (s *pricingService) GetQuote(ticketTypeID string, quantity int) (*dto.QuoteResponse, error) {
+	if _, err := uuid.Parse(ticketTypeID); err != nil {
+		return nil, errors.New("invalid ticket type ID format")
+	}
+	if quantity < 1 {
+		return nil, errors.New("quantity must be at least 1")
+	}
+
+	var ticketType tickets.TicketType
+	if err := s.db.Where("id = ? AND status = ? AND deleted_at IS NULL", ticketTypeID, tickets.TicketTypeActive).
+		First(&ticketType).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("ticket type not found")
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	var event events.Event
+	if err := s.db.Where("id = ? AND deleted_at IS NULL", ticketType.EventID).First(&event).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("event not found")
+		}
+		return nil, err
+	}
+	if !event.Status.IsOnSale() || !event.HasTickets || now.After(event.EndTime) {
+		return nil, errors.New("event is not open for ticket sales")
+	}
+
+	quote, err := Resolve(s.db, &ticketType, now, 0)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.QuoteResponse{
+		PriceQuote: *quote,
+		Quantity:   quantity,
+		Subtotal:   roundAmount(quote.UnitPrice * float64(quantity)),
+	}, nil
+}
+
+func (s *pricingService) GetRules(userID, ticketTypeID string) ([]dto.PricingRuleResponse, error) {
+	if _, err := s.getOwnedTicketType(userID, ticketTypeID); err != nil {
+		return nil, err
+	}
+
+	var rules []tickets.PricingRule
+	if err := s.db.Where("ticket_type_id = ?", ticketTypeID).
+		Order("sold_percentage ASC").
+		Find(&rules).Error; err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.PricingRuleResponse, len(rules))
+	for i := range rules {
+		responses[i] = *toRuleDTO(&rules[i])
+	}
+	return responses, nil
+}
+
+func (s *pricingService) CreateRule(userID, ticketTypeID string, input dto.PricingRuleInput) (*dto.PricingRuleResponse, error) {
+	if _, err := s.getOwnedTicketType(userID, ticketTypeID); err != nil {
+		return nil, err
+	}
+
+	rule := tickets.PricingRule{
+		TicketTypeID:   ticketTypeID,
+		SoldPercentage: input.SoldPercentage,
+		PriceModifier:  input.PriceModifier,
+		IsActive:       input.IsActive == nil || *input.IsActive,
+	}
+	if err := s.db.Create(&rule).Error; err != nil {
+		return nil, err
+	}
+	return toRuleDTO(&rule), nil
+}
+
+func (s *pricingService) UpdateRule(userID, ruleID string, input dto.PricingRuleInput) (*dto.PricingRuleResponse, error) {
+	rule, err := s.getOwnedRule(userID, ruleID)
+	if err != nil {
+		return nil, err
+	}
+
+	rule.SoldPercentage = input.SoldPercentage
+	rule.PriceModifier = input.PriceModifier
+	if input.IsActive != nil {
+		rule.IsActive = *input.IsActive
+	}
+	if err := s.db.Save(rule).Error; err != nil {
+		return nil, err
+	}
+	return toRuleDTO(rule), nil
+}
+
+func (s *pricingService) DeleteRule(userID, ruleID string) error {
+	rule, err := s.getOwnedRule(userID, ruleID)
+	if err != nil {
+		return err
+	}
+	return s.db.Delete(rule).Error
+}
+
+// getOwnedTicketType loads a ticket type belonging to the user's organizer
+func (s *pricingService) getOwnedTicketType(userID, ticketTypeID string) (*tickets.TicketType, error) {
+	if _, err := uuid.Parse(ticketTypeID); err != nil {
+		return nil, errors.New("invalid ticket type ID format")
+	}
+
+	organizer, err := organizers_services.GetUserOrganizer(s.db, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var ticketType tickets.TicketType
+	if err := s.db.Where("id = ? AND organizer_id = ? AND deleted_at IS NULL", ticketTypeID, organizer.ID).
+		First(&ticketType).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("ticket type not found")
+		}
+		return nil, err
+	}
+	return &ticketType, nil
+}
+
+func (s *pricingService) getOwnedRule(userID, ruleID string) (*tickets.PricingRule, error) {
+	if _, err := uuid.Parse(ruleID); err != nil {
+		return nil, errors.New("invalid pricing rule ID format")
+	}
+
+	var rule tickets.PricingRule
+	if err := s.db.Where("id = ?", ruleID).First(&rule).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("pricing rule not found")
+		}
+		return nil, err
+	}
+	if _, err := s.getOwnedTicketType(userID, rule.TicketTypeID); err != nil {
+		if err.Error() == "ticket type not found" {
+			return nil, errors.New("pricing rule not found")
+		}
+		return nil, err
+	}
+	return &rule, nil
+}
+
+func toRuleDTO(rule *tickets.PricingRule) *dto.PricingRuleResponse {
+	return &dto.PricingRuleResponse{
+		ID:             rule.ID,
+		TicketTypeID:   rule.TicketTypeID,
+		SoldPercentage: rule.SoldPercentage,
+		PriceModifier:  rule.PriceModifier,
+		IsActive:       rule.IsActive,
+		CreatedAt:      rule.CreatedAt,
+		UpdatedAt:      rule.UpdatedAt,
+	}
+}
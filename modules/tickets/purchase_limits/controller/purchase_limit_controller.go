@@ -0,0 +1,180 @@
+package purchase_limit_controller
+
+import (
+	"ticket-zetu-api/logs/handler"
+	"ticket-zetu-api/modules/tickets/purchase_limits/dto"
+	"ticket-zetu-api/modules/tickets/purchase_limits/service"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+type PurchaseLimitController struct {
+	service    purchase_limit_service.PurchaseLimitService
+	logHandler *handler.LogHandler
+	validator  *validator.Validate
+}
+
+func NewPurchaseLimitController(service purchase_limit_service.PurchaseLimitService, logHandler *handler.LogHandler) *PurchaseLimitController {
+	return &PurchaseLimitController{
+		service:    service,
+		logHandler: logHandler,
+		validator:  validator.New(),
+	}
+}
+
+// GetEventLimit godoc
+// @Summary Get an event's purchase limits
+// @Description Retrieves the per-user ticket cap across all ticket types of an event and the cooldown between orders.
+// @Tags Purchase Limits
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param event_id path string true "Event ID"
+// @Success 200 {object} map[string]interface{} "Purchase limits retrieved successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid event ID"
+// @Failure 404 {object} map[string]interface{} "Event not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /purchase-limits/events/{event_id} [get]
+func (c *PurchaseLimitController) GetEventLimit(ctx *fiber.Ctx) error {
+	eventID := ctx.Params("event_id")
+
+	limit, err := c.service.GetEventLimit(eventID)
+	if err != nil {
+		return c.handleError(ctx, err)
+	}
+
+	return c.logHandler.LogSuccess(ctx, limit, "Purchase limits retrieved successfully", false)
+}
+
+// SetEventLimit godoc
+// @Summary Set an event's purchase limits
+// @Description Creates or replaces the purchase limits for one of the organizer's events. A cap of 0 removes the event-wide cap; a cooldown of 0 removes the cooldown.
+// @Tags Purchase Limits
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param event_id path string true "Event ID"
+// @Param input body dto.EventLimitInput true "Purchase limits"
+// @Success 200 {object} map[string]interface{} "Purchase limits updated successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid request body or event ID"
+// @Failure 404 {object} map[string]interface{} "Event or organizer not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /purchase-limits/events/{event_id} [put]
+func (c *PurchaseLimitController) SetEventLimit(ctx *fiber.Ctx) error {
+	userID := ctx.Locals("user_id").(string)
+	eventID := ctx.Params("event_id")
+
+	var input dto.EventLimitInput
+	if err := ctx.BodyParser(&input); err != nil {
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, "Invalid request body"), fiber.StatusBadRequest)
+	}
+
+	if err := c.validator.Struct(input); err != nil {
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, err.Error()), fiber.StatusBadRequest)
+	}
+
+	limit, err := c.service.SetEventLimit(userID, eventID, input)
+	if err != nil {
+		return c.handleError(ctx, err)
+	}
+
+	return c.logHandler.LogSuccess(ctx, limit, "Purchase limits updated successfully", true)
+}
+
+// GetAllowance godoc
+// @Summary Get the current user's purchase allowance for an event
+// @Description Retrieves how many more tickets the current user may buy for an event and when the cooldown allows their next order.
+// @Tags Purchase Limits
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param event_id path string true "Event ID"
+// @Success 200 {object} map[string]interface{} "Allowance retrieved successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid event ID"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /purchase-limits/events/{event_id}/me [get]
+func (c *PurchaseLimitController) GetAllowance(ctx *fiber.Ctx) error {
+	userID := ctx.Locals("user_id").(string)
+	eventID := ctx.Params("event_id")
+
+	allowance, err := c.service.GetAllowance(userID, eventID)
+	if err != nil {
+		return c.handleError(ctx, err)
+	}
+
+	return c.logHandler.LogSuccess(ctx, allowance, "Allowance retrieved successfully", false)
+}
+
+// GetUserLimits godoc
+// @Summary Get buyers' purchase records for an event
+// @Description Retrieves each buyer's purchase counters and cap override for one of the organizer's events.
+// @Tags Purchase Limits
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param event_id path string true "Event ID"
+// @Success 200 {object} map[string]interface{} "User limits retrieved successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid event ID"
+// @Failure 404 {object} map[string]interface{} "Event or organizer not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /purchase-limits/events/{event_id}/users [get]
+func (c *PurchaseLimitController) GetUserLimits(ctx *fiber.Ctx) error {
+	userID := ctx.Locals("user_id").(string)
+	eventID := ctx.Params("event_id")
+
+	limits, err := c.service.GetUserLimits(userID, eventID)
+	if err != nil {
+		return c.handleError(ctx, err)
+	}
+
+	return c.logHandler.LogSuccess(ctx, limits, "User limits retrieved successfully", false)
+}
+
+// SetUserOverride godoc
+// @Summary Override one user's cap for an event
+// @Description Grants a user a different ticket cap for one of the organizer's events. A cap of 0 removes the override.
+// @Tags Purchase Limits
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param event_id path string true "Event ID"
+// @Param user_id path string true "User ID"
+// @Param input body dto.UserOverrideInput true "Cap override"
+// @Success 200 {object} map[string]interface{} "User limit updated successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid request body, event or user ID"
+// @Failure 404 {object} map[string]interface{} "Event, organizer or user not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /purchase-limits/events/{event_id}/users/{user_id} [put]
+func (c *PurchaseLimitController) SetUserOverride(ctx *fiber.Ctx) error {
+	userID := ctx.Locals("user_id").(string)
+	eventID := ctx.Params("event_id")
+	targetUserID := ctx.Params("user_id")
+
+	var input dto.UserOverrideInput
+	if err := ctx.BodyParser(&input); err != nil {
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, "Invalid request body"), fiber.StatusBadRequest)
+	}
+
+	if err := c.validator.Struct(input); err != nil {
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, err.Error()), fiber.StatusBadRequest)
+	}
+
+	limit, err := c.service.SetUserOverride(userID, eventID, targetUserID, input)
+	if err != nil {
+		return c.handleError(ctx, err)
+	}
+
+	return c.logHandler.LogSuccess(ctx, limit, "User limit updated successfully", true)
+}
+
+func (c *PurchaseLimitController) handleError(ctx *fiber.Ctx, err error) error {
+	switch err.Error() {
+	case "invalid user ID format", "invalid event ID format":
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, err.Error()), fiber.StatusBadRequest)
+	case "event not found", "organizer not found", "user not found":
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusNotFound, err.Error()), fiber.StatusNotFound)
+	default:
+		return c.logHandler.LogError(ctx, err, fiber.StatusInternalServerError)
+	}
+}
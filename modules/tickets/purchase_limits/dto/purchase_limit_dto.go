@@ -0,0 +1,46 @@
+package dto
+
+import "time"
+
+// EventLimitInput defines the input structure for an event's purchase limits
+type EventLimitInput struct {
+	MaxTicketsPerUser int `json:"max_tickets_per_user" example:"6" validate:"gte=0,lte=1000"`
+	CooldownSeconds   int `json:"cooldown_seconds" example:"300" validate:"gte=0,lte=86400"`
+}
+
+// EventLimitResponse defines the response structure for an event's purchase limits.
+// A max_tickets_per_user of 0 means the event has no cap across ticket types.
+type EventLimitResponse struct {
+	EventID           string `json:"event_id"`
+	MaxTicketsPerUser int    `json:"max_tickets_per_user"`
+	CooldownSeconds   int    `json:"cooldown_seconds"`
+}
+
+// UserOverrideInput defines the input structure for overriding one user's cap for an event.
+// A max_tickets of 0 removes the override.
+type UserOverrideInput struct {
+	MaxTickets int `json:"max_tickets" example:"20" validate:"gte=0,lte=10000"`
+}
+
+// UserLimitResponse defines the response structure for one user's purchase record for an event
+type UserLimitResponse struct {
+	UserID         string     `json:"user_id"`
+	Username       string     `json:"username,omitempty"`
+	EventID        string     `json:"event_id"`
+	MaxTickets     int        `json:"max_tickets"`
+	TicketsBought  int        `json:"tickets_bought"`
+	TicketsResold  int        `json:"tickets_resold"`
+	PurchaseCount  int        `json:"purchase_count"`
+	LastPurchaseAt *time.Time `json:"last_purchase_at,omitempty"`
+}
+
+// AllowanceResponse defines how many more tickets the current user may buy for an event.
+// Limit and remaining are omitted when the event has no cap.
+type AllowanceResponse struct {
+	EventID         string     `json:"event_id"`
+	Limit           int        `json:"limit,omitempty"`
+	Counted         int        `json:"counted"`
+	Remaining       *int       `json:"remaining,omitempty"`
+	NextPurchaseAt  *time.Time `json:"next_purchase_at,omitempty"`
+	CooldownSeconds int        `json:"cooldown_seconds"`
+}
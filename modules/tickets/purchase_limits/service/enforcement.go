@@ -0,0 +1,175 @@
+package purchase_limit_service
+
+import (
+	"errors"
+	"ticket-zetu-api/modules/tickets/models/tickets"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CheckEventLimit enforces the event-wide per-user cap for a purchase of
+// quantity tickets. Tickets the user holds for the event, their unpaid orders
+// and active holds all count; the order or hold being converted is excluded
+// by ID so it is not counted twice. The user's limits row stays locked until
+// tx ends, serialising the user's concurrent checkouts for the event.
+func CheckEventLimit(tx *gorm.DB, userID, eventID string, quantity int, excludeOrderID, excludeHoldID string) error {
+	limits, err := lockUserLimits(tx, userID, eventID)
+	if err != nil {
+		return err
+	}
+	eventLimit, err := getEventLimit(tx, eventID)
+	if err != nil {
+		return err
+	}
+
+	limit := effectiveLimit(limits, eventLimit)
+	if limit == 0 {
+		return nil
+	}
+
+	counted, err := countTowardsLimit(tx, userID, eventID, excludeOrderID, excludeHoldID)
+	if err != nil {
+		return err
+	}
+	if counted+quantity > limit {
+		return errors.New("quantity exceeds the event's ticket limit per user")
+	}
+	return nil
+}
+
+// CheckCooldown rejects a new order placed too soon after the user's last
+// order for the event. Call it after CheckEventLimit so the user's row is locked.
+func CheckCooldown(tx *gorm.DB, userID, eventID string) error {
+	eventLimit, err := getEventLimit(tx, eventID)
+	if err != nil {
+		return err
+	}
+	if eventLimit.CooldownSeconds == 0 {
+		return nil
+	}
+
+	last, err := lastOrderAt(tx, userID, eventID)
+	if err != nil {
+		return err
+	}
+	if last != nil && time.Since(*last) < eventLimit.Cooldown() {
+		return errors.New("please wait before placing another order for this event")
+	}
+	return nil
+}
+
+// RecordPurchase adds a completed purchase to the user's record for the event
+func RecordPurchase(tx *gorm.DB, userID, eventID string, quantity int) error {
+	if _, err := lockUserLimits(tx, userID, eventID); err != nil {
+		return err
+	}
+	now := time.Now()
+	return tx.Model(&tickets.UserTicketLimits{}).
+		Where("user_id = ? AND event_id = ?", userID, eventID).
+		UpdateColumns(map[string]interface{}{
+			"tickets_bought":   gorm.Expr("tickets_bought + ?", quantity),
+			"purchase_count":   gorm.Expr("purchase_count + 1"),
+			"last_purchase_at": now,
+			"updated_at":       now,
+		}).Error
+}
+
+// RecordResale adds a ticket resold by the user to their record for the event
+func RecordResale(tx *gorm.DB, userID, eventID string) error {
+	if _, err := lockUserLimits(tx, userID, eventID); err != nil {
+		return err
+	}
+	return tx.Model(&tickets.UserTicketLimits{}).
+		Where("user_id = ? AND event_id = ?", userID, eventID).
+		UpdateColumns(map[string]interface{}{
+			"tickets_resold": gorm.Expr("tickets_resold + 1"),
+			"updated_at":     time.Now(),
+		}).Error
+}
+
+// lockUserLimits creates the user's row for the event if needed and locks it
+func lockUserLimits(tx *gorm.DB, userID, eventID string) (*tickets.UserTicketLimits, error) {
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&tickets.UserTicketLimits{UserID: userID, EventID: eventID}).Error; err != nil {
+		return nil, err
+	}
+
+	var limits tickets.UserTicketLimits
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("user_id = ? AND event_id = ?", userID, eventID).
+		First(&limits).Error; err != nil {
+		return nil, err
+	}
+	return &limits, nil
+}
+
+// getEventLimit returns the event's limits; events without any have no cap and no cooldown
+func getEventLimit(tx *gorm.DB, eventID string) (*tickets.EventPurchaseLimit, error) {
+	eventLimit := tickets.EventPurchaseLimit{EventID: eventID}
+	if err := tx.Where("event_id = ?", eventID).First(&eventLimit).Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	return &eventLimit, nil
+}
+
+// effectiveLimit is the user's override if they have one, otherwise the event's cap
+func effectiveLimit(limits *tickets.UserTicketLimits, eventLimit *tickets.EventPurchaseLimit) int {
+	if limits != nil && limits.MaxTickets > 0 {
+		return limits.MaxTickets
+	}
+	return eventLimit.MaxTicketsPerUser
+}
+
+func countTowardsLimit(tx *gorm.DB, userID, eventID, excludeOrderID, excludeHoldID string) (int, error) {
+	now := time.Now()
+
+	var owned int64
+	if err := tx.Model(&tickets.Ticket{}).
+		Where("user_id = ? AND event_id = ? AND status IN ?", userID, eventID,
+			[]tickets.TicketStatus{tickets.TicketValid, tickets.TicketUsed, tickets.TicketPending}).
+		Count(&owned).Error; err != nil {
+		return 0, err
+	}
+
+	var pending int64
+	orderQuery := tx.Model(&tickets.Order{}).
+		Where("user_id = ? AND event_id = ? AND status = ? AND expires_at > ?", userID, eventID, tickets.OrderPending, now)
+	if excludeOrderID != "" {
+		orderQuery = orderQuery.Where("id <> ?", excludeOrderID)
+	}
+	if err := orderQuery.Select("COALESCE(SUM(quantity), 0)").Scan(&pending).Error; err != nil {
+		return 0, err
+	}
+
+	var held int64
+	holdQuery := tx.Model(&tickets.TicketHold{}).
+		Joins("JOIN ticket_types ON ticket_types.id = ticket_holds.ticket_type_id").
+		Where("ticket_holds.user_id = ? AND ticket_types.event_id = ? AND ticket_holds.held_until > ?", userID, eventID, now)
+	if excludeHoldID != "" {
+		holdQuery = holdQuery.Where("ticket_holds.id <> ?", excludeHoldID)
+	}
+	if err := holdQuery.Select("COALESCE(SUM(ticket_holds.quantity), 0)").Scan(&held).Error; err != nil {
+		return 0, err
+	}
+
+	return int(owned + pending + held), nil
+}
+
+// lastOrderAt is when the user last placed an order for the event that was not abandoned
+func lastOrderAt(tx *gorm.DB, userID, eventID string) (*time.Time, error) {
+	var order tickets.Order
+	err := tx.Select("created_at").
+		Where("user_id = ? AND event_id = ? AND status IN ?", userID, eventID,
+			[]tickets.OrderStatus{tickets.OrderPending, tickets.OrderConfirmed, tickets.OrderRefunded}).
+		Order("created_at DESC").
+		First(&order).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &order.CreatedAt, nil
+}
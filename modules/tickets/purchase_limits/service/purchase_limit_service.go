@@ -0,0 +1,203 @@
+package purchase_limit_service
+
+import (
+	"errors"
+	"ticket-zetu-api/modules/events/models/events"
+	organizers_services "ticket-zetu-api/modules/organizers/services"
+	"ticket-zetu-api/modules/tickets/models/tickets"
+	"ticket-zetu-api/modules/tickets/purchase_limits/dto"
+	"ticket-zetu-api/modules/users/authorization/service"
+	"ticket-zetu-api/modules/users/models/members"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PurchaseLimitService interface {
+	GetEventLimit(eventID string) (*dto.EventLimitResponse, error)
+	SetEventLimit(userID, eventID string, input dto.EventLimitInput) (*dto.EventLimitResponse, error)
+	GetUserLimits(userID, eventID string) ([]dto.UserLimitResponse, error)
+	SetUserOverride(userID, eventID, targetUserID string, input dto.UserOverrideInput) (*dto.UserLimitResponse, error)
+	GetAllowance(userID, eventID string) (*dto.AllowanceResponse, error)
+}
+
+type purchaseLimitService struct {
+	db                   *gorm.DB
+	authorizationService authorization_service.PermissionService
+}
+
+func NewPurchaseLimitService(db *gorm.DB, authService authorization_service.PermissionService) PurchaseLimitService {
+	return &purchaseLimitService{
+		db:                   db,
+		authorizationService: authService,
+	}
+}
+
+func (s *purchaseLimitService) GetEventLimit(eventID string) (*dto.EventLimitResponse, error) {
+	if _, err := uuid.Parse(eventID); err != nil {
+		return nil, errors.New("invalid event ID format")
+	}
+
+	var count int64
+	if err := s.db.Model(&events.Event{}).Where("id = ? AND deleted_at IS NULL", eventID).Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return nil, errors.New("event not found")
+	}
+
+	eventLimit, err := getEventLimit(s.db, eventID)
+	if err != nil {
+		return nil, err
+	}
+	return toEventLimitDTO(eventLimit), nil
+}
+
+// SetEventLimit creates or replaces the purchase limits for one of the organizer's events
+func (s *purchaseLimitService) SetEventLimit(userID, eventID string, input dto.EventLimitInput) (*dto.EventLimitResponse, error) {
+	if _, err := organizers_services.GetOwnedEvent(s.db, userID, eventID); err != nil {
+		return nil, err
+	}
+
+	eventLimit := tickets.EventPurchaseLimit{
+		EventID:           eventID,
+		MaxTicketsPerUser: input.MaxTicketsPerUser,
+		CooldownSeconds:   input.CooldownSeconds,
+	}
+	if err := s.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "event_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"max_tickets_per_user", "cooldown_seconds", "updated_at"}),
+	}).Create(&eventLimit).Error; err != nil {
+		return nil, err
+	}
+	return toEventLimitDTO(&eventLimit), nil
+}
+
+// GetUserLimits lists the purchase records and overrides of every user who has bought or tried to buy for the event
+func (s *purchaseLimitService) GetUserLimits(userID, eventID string) ([]dto.UserLimitResponse, error) {
+	if _, err := organizers_services.GetOwnedEvent(s.db, userID, eventID); err != nil {
+		return nil, err
+	}
+
+	var limits []tickets.UserTicketLimits
+	if err := s.db.Preload("User").
+		Where("event_id = ?", eventID).
+		Order("tickets_bought DESC").
+		Find(&limits).Error; err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.UserLimitResponse, len(limits))
+	for i := range limits {
+		responses[i] = *toUserLimitDTO(&limits[i])
+	}
+	return responses, nil
+}
+
+// SetUserOverride replaces the event's cap for one user; a cap of 0 removes the override
+func (s *purchaseLimitService) SetUserOverride(userID, eventID, targetUserID string, input dto.UserOverrideInput) (*dto.UserLimitResponse, error) {
+	if _, err := uuid.Parse(targetUserID); err != nil {
+		return nil, errors.New("invalid user ID format")
+	}
+	if _, err := organizers_services.GetOwnedEvent(s.db, userID, eventID); err != nil {
+		return nil, err
+	}
+
+	var user members.User
+	if err := s.db.Select("id", "username").Where("id = ?", targetUserID).First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("user not found")
+		}
+		return nil, err
+	}
+
+	var limits *tickets.UserTicketLimits
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		if limits, err = lockUserLimits(tx, targetUserID, eventID); err != nil {
+			return err
+		}
+		limits.MaxTickets = input.MaxTickets
+		return tx.Model(limits).UpdateColumn("max_tickets", input.MaxTickets).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	limits.User = user
+	return toUserLimitDTO(limits), nil
+}
+
+// GetAllowance tells the user how many more tickets they may buy for the
+// event and when the cooldown lets them order again
+func (s *purchaseLimitService) GetAllowance(userID, eventID string) (*dto.AllowanceResponse, error) {
+	if _, err := uuid.Parse(userID); err != nil {
+		return nil, errors.New("invalid user ID format")
+	}
+	if _, err := uuid.Parse(eventID); err != nil {
+		return nil, errors.New("invalid event ID format")
+	}
+
+	eventLimit, err := getEventLimit(s.db, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	var limits tickets.UserTicketLimits
+	if err := s.db.Where("user_id = ? AND event_id = ?", userID, eventID).First(&limits).Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	counted, err := countTowardsLimit(s.db, userID, eventID, "", "")
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.AllowanceResponse{
+		EventID:         eventID,
+		Counted:         counted,
+		CooldownSeconds: eventLimit.CooldownSeconds,
+	}
+	if limit := effectiveLimit(&limits, eventLimit); limit > 0 {
+		remaining := limit - counted
+		if remaining < 0 {
+			remaining = 0
+		}
+		response.Limit = limit
+		response.Remaining = &remaining
+	}
+
+	if eventLimit.CooldownSeconds > 0 {
+		last, err := lastOrderAt(s.db, userID, eventID)
+		if err != nil {
+			return nil, err
+		}
+		if last != nil {
+			next := last.Add(eventLimit.Cooldown())
+			response.NextPurchaseAt = &next
+		}
+	}
+	return response, nil
+}
+
+func toEventLimitDTO(eventLimit *tickets.EventPurchaseLimit) *dto.EventLimitResponse {
+	return &dto.EventLimitResponse{
+		EventID:           eventLimit.EventID,
+		MaxTicketsPerUser: eventLimit.MaxTicketsPerUser,
+		CooldownSeconds:   eventLimit.CooldownSeconds,
+	}
+}
+
+func toUserLimitDTO(limits *tickets.UserTicketLimits) *dto.UserLimitResponse {
+	return &dto.UserLimitResponse{
+		UserID:         limits.UserID,
+		Username:       limits.User.Username,
+		EventID:        limits.EventID,
+		MaxTickets:     limits.MaxTickets,
+		TicketsBought:  limits.TicketsBought,
+		TicketsResold:  limits.TicketsResold,
+		PurchaseCount:  limits.PurchaseCount,
+		LastPurchaseAt: limits.LastPurchaseAt,
+	}
+}
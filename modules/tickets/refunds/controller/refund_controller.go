@@ -0,0 +1,268 @@
+package refund_controller
+
+import (
+	"ticket-zetu-api/logs/handler"
+	"ticket-zetu-api/modules/tickets/refunds/dto"
+	"ticket-zetu-api/modules/tickets/refunds/service"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+type RefundController struct {
+	service    refund_service.RefundService
+	logHandler *handler.LogHandler
+	validator  *validator.Validate
+}
+
+func NewRefundController(service refund_service.RefundService, logHandler *handler.LogHandler) *RefundController {
+	return &RefundController{
+		service:    service,
+		logHandler: logHandler,
+		validator:  validator.New(),
+	}
+}
+
+// RequestRefund godoc
+// @Summary Request a ticket refund
+// @Description Requests a refund for some or all valid tickets the user holds in a confirmed order, under the event's refund policy. Transferred tickets are refunded to whoever paid for the order. Events with automatic approval are refunded immediately.
+// @Tags Refunds
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param input body dto.CreateRefundInput true "Refund details"
+// @Success 200 {object} map[string]interface{} "Refund requested successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid request body or tickets not refundable"
+// @Failure 404 {object} map[string]interface{} "Order not found"
+// @Failure 409 {object} map[string]interface{} "A refund is already pending for these tickets"
+// @Failure 422 {object} map[string]interface{} "Refunds not allowed, deadline passed or tickets bought on resale"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /refunds [post]
+func (c *RefundController) RequestRefund(ctx *fiber.Ctx) error {
+	userID := ctx.Locals("user_id").(string)
+
+	var input dto.CreateRefundInput
+	if err := ctx.BodyParser(&input); err != nil {
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, "Invalid request body"), fiber.StatusBadRequest)
+	}
+
+	if err := c.validator.Struct(input); err != nil {
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, err.Error()), fiber.StatusBadRequest)
+	}
+
+	refund, err := c.service.RequestRefund(userID, input)
+	if err != nil {
+		return c.handleError(ctx, err)
+	}
+
+	return c.logHandler.LogSuccess(ctx, refund, "Refund requested successfully", true)
+}
+
+// GetRefund godoc
+// @Summary Get a refund request
+// @Description Retrieves a refund request the current user made or is being paid.
+// @Tags Refunds
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Refund ID"
+// @Success 200 {object} map[string]interface{} "Refund retrieved successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid refund ID"
+// @Failure 404 {object} map[string]interface{} "Refund not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /refunds/{id} [get]
+func (c *RefundController) GetRefund(ctx *fiber.Ctx) error {
+	userID := ctx.Locals("user_id").(string)
+	id := ctx.Params("id")
+
+	refund, err := c.service.GetRefund(userID, id)
+	if err != nil {
+		return c.handleError(ctx, err)
+	}
+
+	return c.logHandler.LogSuccess(ctx, refund, "Refund retrieved successfully", false)
+}
+
+// GetUserRefunds godoc
+// @Summary Get the current user's refund requests
+// @Description Retrieves all refund requests the current user made or is being paid, newest first.
+// @Tags Refunds
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} map[string]interface{} "Refunds retrieved successfully"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /refunds [get]
+func (c *RefundController) GetUserRefunds(ctx *fiber.Ctx) error {
+	userID := ctx.Locals("user_id").(string)
+
+	refunds, err := c.service.GetUserRefunds(userID)
+	if err != nil {
+		return c.handleError(ctx, err)
+	}
+
+	return c.logHandler.LogSuccess(ctx, refunds, "Refunds retrieved successfully", false)
+}
+
+// GetOrganizerRefunds godoc
+// @Summary Get refund requests for the organizer's events
+// @Description Retrieves refund requests for events owned by the current user's organizer, optionally filtered by status.
+// @Tags Refunds
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param status query string false "Refund status (pending, rejected, completed)"
+// @Success 200 {object} map[string]interface{} "Refunds retrieved successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid refund status"
+// @Failure 404 {object} map[string]interface{} "Organizer not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /refunds/organizer [get]
+func (c *RefundController) GetOrganizerRefunds(ctx *fiber.Ctx) error {
+	userID := ctx.Locals("user_id").(string)
+
+	refunds, err := c.service.GetOrganizerRefunds(userID, ctx.Query("status"))
+	if err != nil {
+		return c.handleError(ctx, err)
+	}
+
+	return c.logHandler.LogSuccess(ctx, refunds, "Refunds retrieved successfully", false)
+}
+
+// ApproveRefund godoc
+// @Summary Approve a refund request
+// @Description Refunds the requested tickets, returns them to stock, debits the organizer balance and returns the money through the payment provider.
+// @Tags Refunds
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Refund ID"
+// @Success 200 {object} map[string]interface{} "Refund approved successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid refund ID or tickets not refundable"
+// @Failure 404 {object} map[string]interface{} "Refund not found"
+// @Failure 409 {object} map[string]interface{} "Refund is not pending"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /refunds/{id}/approve [post]
+func (c *RefundController) ApproveRefund(ctx *fiber.Ctx) error {
+	userID := ctx.Locals("user_id").(string)
+	id := ctx.Params("id")
+
+	refund, err := c.service.ApproveRefund(userID, id)
+	if err != nil {
+		return c.handleError(ctx, err)
+	}
+
+	return c.logHandler.LogSuccess(ctx, refund, "Refund approved successfully", true)
+}
+
+// RejectRefund godoc
+// @Summary Reject a refund request
+// @Description Rejects a pending refund request for one of the organizer's events.
+// @Tags Refunds
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Refund ID"
+// @Param input body dto.RejectRefundInput true "Rejection reason"
+// @Success 200 {object} map[string]interface{} "Refund rejected successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid request body or refund ID"
+// @Failure 404 {object} map[string]interface{} "Refund not found"
+// @Failure 409 {object} map[string]interface{} "Refund is not pending"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /refunds/{id}/reject [post]
+func (c *RefundController) RejectRefund(ctx *fiber.Ctx) error {
+	userID := ctx.Locals("user_id").(string)
+	id := ctx.Params("id")
+
+	var input dto.RejectRefundInput
+	if err := ctx.BodyParser(&input); err != nil {
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, "Invalid request body"), fiber.StatusBadRequest)
+	}
+
+	if err := c.validator.Struct(input); err != nil {
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, err.Error()), fiber.StatusBadRequest)
+	}
+
+	refund, err := c.service.RejectRefund(userID, id, input)
+	if err != nil {
+		return c.handleError(ctx, err)
+	}
+
+	return c.logHandler.LogSuccess(ctx, refund, "Refund rejected successfully", true)
+}
+
+// GetRefundPolicy godoc
+// @Summary Get an event's refund policy
+// @Description Retrieves the refund policy for an event, including the refund deadline.
+// @Tags Refunds
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param event_id path string true "Event ID"
+// @Success 200 {object} map[string]interface{} "Refund policy retrieved successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid event ID"
+// @Failure 404 {object} map[string]interface{} "Event not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /refunds/policy/event/{event_id} [get]
+func (c *RefundController) GetRefundPolicy(ctx *fiber.Ctx) error {
+	eventID := ctx.Params("event_id")
+
+	policy, err := c.service.GetRefundPolicy(eventID)
+	if err != nil {
+		return c.handleError(ctx, err)
+	}
+
+	return c.logHandler.LogSuccess(ctx, policy, "Refund policy retrieved successfully", false)
+}
+
+// SetRefundPolicy godoc
+// @Summary Set an event's refund policy
+// @Description Creates or replaces the refund policy for one of the organizer's events.
+// @Tags Refunds
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param event_id path string true "Event ID"
+// @Param input body dto.RefundPolicyInput true "Refund policy"
+// @Success 200 {object} map[string]interface{} "Refund policy updated successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid request body or event ID"
+// @Failure 404 {object} map[string]interface{} "Event or organizer not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /refunds/policy/event/{event_id} [put]
+func (c *RefundController) SetRefundPolicy(ctx *fiber.Ctx) error {
+	userID := ctx.Locals("user_id").(string)
+	eventID := ctx.Params("event_id")
+
+	var input dto.RefundPolicyInput
+	if err := ctx.BodyParser(&input); err != nil {
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, "Invalid request body"), fiber.StatusBadRequest)
+	}
+
+	if err := c.validator.Struct(input); err != nil {
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, err.Error()), fiber.StatusBadRequest)
+	}
+
+	policy, err := c.service.SetRefundPolicy(userID, eventID, input)
+	if err != nil {
+		return c.handleError(ctx, err)
+	}
+
+	return c.logHandler.LogSuccess(ctx, policy, "Refund policy updated successfully", true)
+}
+
+func (c *RefundController) handleError(ctx *fiber.Ctx, err error) error {
+	switch err.Error() {
+	case "invalid user ID format", "invalid order ID format", "invalid refund ID format", "invalid event ID format",
+		"invalid refund status", "tickets are not refundable":
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, err.Error()), fiber.StatusBadRequest)
+	case "order not found", "refund not found", "event not found", "organizer not found":
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusNotFound, err.Error()), fiber.StatusNotFound)
+	case "order is not confirmed", "refund is not pending", "a refund is already pending for these tickets",
+		"tickets are listed for resale":
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusConflict, err.Error()), fiber.StatusConflict)
+	case "refunds are not allowed for this event", "refund deadline has passed",
+		"payment cannot be refunded", "refund exceeds the amount paid", "tickets bought on resale cannot be refunded":
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusUnprocessableEntity, err.Error()), fiber.StatusUnprocessableEntity)
+	default:
+		return c.logHandler.LogError(ctx, err, fiber.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,67 @@
+package dto
+
+import "time"
+
+// CreateRefundInput defines the input structure for requesting a refund.
+// Leaving ticket_ids empty requests a refund for every valid ticket in the order.
+type CreateRefundInput struct {
+	OrderID   string   `json:"order_id" example:"a1d3c4e6-89ab-44ce-8e65-123456789abc" validate:"required,uuid"`
+	TicketIDs []string `json:"ticket_ids,omitempty" validate:"omitempty,dive,uuid"`
+	Reason    string   `json:"reason,omitempty" example:"I can no longer attend" validate:"omitempty,max=500"`
+}
+
+// RejectRefundInput defines the input structure for rejecting a refund request
+type RejectRefundInput struct {
+	Reason string `json:"reason" example:"Refund window has closed" validate:"required,max=500"`
+}
+
+// RefundPolicyInput defines the input structure for an event's refund policy
+type RefundPolicyInput struct {
+	AllowRefunds     bool    `json:"allow_refunds" example:"true"`
+	DeadlineHours    int     `json:"deadline_hours" example:"48" validate:"gte=0"`
+	RefundPercentage float64 `json:"refund_percentage" example:"80" validate:"gte=0,lte=100"`
+	AutoApprove      bool    `json:"auto_approve" example:"false"`
+	// CancellationRefundPercentage applies if the organizer cancels the
+	// event; leave it out to refund cancelled events in full
+	CancellationRefundPercentage *float64 `json:"cancellation_refund_percentage,omitempty" example:"100" validate:"omitempty,gte=0,lte=100"`
+}
+
+// RefundPolicyResponse defines the response structure for an event's refund policy
+type RefundPolicyResponse struct {
+	EventID          string    `json:"event_id"`
+	AllowRefunds     bool      `json:"allow_refunds"`
+	DeadlineHours    int       `json:"deadline_hours"`
+	RefundPercentage float64   `json:"refund_percentage"`
+	AutoApprove      bool      `json:"auto_approve"`
+	RefundDeadline   time.Time `json:"refund_deadline"`
+	// CancellationRefundPercentage is what holders get back if the event is cancelled
+	CancellationRefundPercentage float64 `json:"cancellation_refund_percentage"`
+}
+
+// RefundItemResponse defines one ticket covered by a refund request
+type RefundItemResponse struct {
+	TicketID     string  `json:"ticket_id"`
+	TicketTypeID string  `json:"ticket_type_id"`
+	Amount       float64 `json:"amount"`
+}
+
+// RefundResponse defines the response structure for a refund request
+type RefundResponse struct {
+	ID              string               `json:"id"`
+	OrderID         string               `json:"order_id"`
+	UserID          string               `json:"user_id"`
+	PayerID         string               `json:"payer_id,omitempty"`
+	EventID         string               `json:"event_id"`
+	Status          string               `json:"status"`
+	Reason          string               `json:"reason,omitempty"`
+	Percentage      float64              `json:"percentage"`
+	Amount          float64              `json:"amount"`
+	PaymentID       string               `json:"payment_id,omitempty"`
+	ResaleID        string               `json:"resale_id,omitempty"`
+	ReviewedBy      string               `json:"reviewed_by,omitempty"`
+	ReviewedAt      *time.Time           `json:"reviewed_at,omitempty"`
+	RejectionReason string               `json:"rejection_reason,omitempty"`
+	CreatedAt       time.Time            `json:"created_at"`
+	UpdatedAt       time.Time            `json:"updated_at"`
+	Items           []RefundItemResponse `json:"items"`
+}
@@ -0,0 +1,135 @@
+package refund_service
+
+import (
+	"errors"
+	"ticket-zetu-api/modules/tickets/models/tickets"
+	resale_service "ticket-zetu-api/modules/tickets/resale/service"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RefundCancelledOrder refunds the valid tickets left in a confirmed order
+// for an event that has been cancelled, at the percentage fixed when it was
+// cancelled and whatever the event's refund deadline. Refund requests still
+// pending for the order are rejected in its favour. A ticket sold on since is
+// refunded in full to whoever bought it on resale, and its seller's payout is
+// taken back, rather than refunded to the order's buyer. It returns how many
+// tickets were refunded, which is zero when the order has nothing left.
+func (s *refundService) RefundCancelledOrder(orderID string, percentage float64, reason string) (int, error) {
+	var refunds []*tickets.RefundRequest
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var order tickets.Order
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND status = ?", orderID, tickets.OrderConfirmed).
+			First(&order).Error; err != nil {
+			// Unpaid or already fully refunded
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			return err
+		}
+
+		// This includes a cancellation refund whose payment failed on an
+		// earlier attempt
+		now := time.Now()
+		if err := tx.Model(&tickets.RefundRequest{}).
+			Where("order_id = ? AND status = ?", order.ID, tickets.RefundPending).
+			UpdateColumns(map[string]interface{}{
+				"status":           tickets.RefundRejected,
+				"reviewed_at":      now,
+				"rejection_reason": "replaced by the refund for the event's cancellation",
+				"version":          gorm.Expr("version + 1"),
+				"updated_at":       now,
+			}).Error; err != nil {
+			return err
+		}
+
+		var refundable []tickets.Ticket
+		if err := tx.Where("order_id = ? AND status = ?", order.ID, tickets.TicketValid).
+			Find(&refundable).Error; err != nil {
+			return err
+		}
+		if len(refundable) == 0 {
+			return nil
+		}
+
+		ticketIDs := make([]string, len(refundable))
+		for i, ticket := range refundable {
+			ticketIDs[i] = ticket.ID
+		}
+		resales, err := resale_service.LastResale(tx, ticketIDs)
+		if err != nil {
+			return err
+		}
+
+		buyerRefund := &tickets.RefundRequest{
+			OrderID:    order.ID,
+			UserID:     order.UserID,
+			PayerID:    order.UserID,
+			EventID:    order.EventID,
+			Status:     tickets.RefundPending,
+			Reason:     reason,
+			Percentage: percentage,
+			PaymentID:  order.PaymentReference,
+		}
+		for _, ticket := range refundable {
+			resale, resold := resales[ticket.ID]
+			if !resold {
+				amount := roundAmount(ticket.ActualPrice * percentage / 100)
+				buyerRefund.Amount += amount
+				buyerRefund.Items = append(buyerRefund.Items, tickets.RefundRequestItem{
+					TicketID:     ticket.ID,
+					TicketTypeID: ticket.TicketTypeID,
+					Amount:       amount,
+				})
+				continue
+			}
+
+			paymentID, err := resale_service.ResalePayment(tx, resale.ID)
+			if err != nil {
+				return err
+			}
+			refunds = append(refunds, &tickets.RefundRequest{
+				OrderID:    order.ID,
+				UserID:     ticket.UserID,
+				PayerID:    *resale.NewUserID,
+				EventID:    order.EventID,
+				Status:     tickets.RefundPending,
+				Reason:     reason,
+				Percentage: 100,
+				Amount:     resale.ResalePrice,
+				PaymentID:  paymentID,
+				ResaleID:   resale.ID,
+				Items: []tickets.RefundRequestItem{{
+					TicketID:     ticket.ID,
+					TicketTypeID: ticket.TicketTypeID,
+					Amount:       resale.ResalePrice,
+				}},
+			})
+		}
+		if len(buyerRefund.Items) > 0 {
+			buyerRefund.Amount = roundAmount(buyerRefund.Amount)
+			refunds = append(refunds, buyerRefund)
+		}
+		for _, refund := range refunds {
+			if err := tx.Create(refund).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	refunded := 0
+	for _, refund := range refunds {
+		if err := s.process(refund.ID, ""); err != nil {
+			return refunded, err
+		}
+		refunded += len(refund.Items)
+	}
+	return refunded, nil
+}
@@ -0,0 +1,119 @@
+package refund_service
+
+import (
+	"errors"
+	organizers_services "ticket-zetu-api/modules/organizers/services"
+	"ticket-zetu-api/modules/tickets/models/tickets"
+	"ticket-zetu-api/modules/tickets/refunds/dto"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func (s *refundService) GetRefund(userID, refundID string) (*dto.RefundResponse, error) {
+	if _, err := uuid.Parse(refundID); err != nil {
+		return nil, errors.New("invalid refund ID format")
+	}
+
+	var refund tickets.RefundRequest
+	if err := s.db.Preload("Items").
+		Where("id = ? AND (user_id = ? OR payer_id = ?)", refundID, userID, userID).
+		First(&refund).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("refund not found")
+		}
+		return nil, err
+	}
+	return toDTO(&refund), nil
+}
+
+func (s *refundService) GetUserRefunds(userID string) ([]dto.RefundResponse, error) {
+	if _, err := uuid.Parse(userID); err != nil {
+		return nil, errors.New("invalid user ID format")
+	}
+
+	var refunds []tickets.RefundRequest
+	if err := s.db.Preload("Items").
+		Where("user_id = ? OR payer_id = ?", userID, userID).
+		Order("created_at DESC").
+		Find(&refunds).Error; err != nil {
+		return nil, err
+	}
+	return toDTOs(refunds), nil
+}
+
+// GetOrganizerRefunds lists refund requests for the organizer's events, optionally filtered by status
+func (s *refundService) GetOrganizerRefunds(userID, status string) ([]dto.RefundResponse, error) {
+	organizer, err := organizers_services.GetUserOrganizer(s.db, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	query := s.db.Preload("Items").
+		Joins("JOIN events ON events.id = refund_requests.event_id").
+		Where("events.organizer_id = ?", organizer.ID)
+	if status != "" {
+		switch tickets.RefundStatus(status) {
+		case tickets.RefundPending, tickets.RefundRejected, tickets.RefundCompleted:
+			query = query.Where("refund_requests.status = ?", status)
+		default:
+			return nil, errors.New("invalid refund status")
+		}
+	}
+
+	var refunds []tickets.RefundRequest
+	if err := query.Order("refund_requests.created_at DESC").Find(&refunds).Error; err != nil {
+		return nil, err
+	}
+	return toDTOs(refunds), nil
+}
+
+func (s *refundService) getRefund(refundID string) (*dto.RefundResponse, error) {
+	var refund tickets.RefundRequest
+	if err := s.db.Preload("Items").Where("id = ?", refundID).First(&refund).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("refund not found")
+		}
+		return nil, err
+	}
+	return toDTO(&refund), nil
+}
+
+func toDTOs(refunds []tickets.RefundRequest) []dto.RefundResponse {
+	responses := make([]dto.RefundResponse, len(refunds))
+	for i := range refunds {
+		responses[i] = *toDTO(&refunds[i])
+	}
+	return responses
+}
+
+func toDTO(refund *tickets.RefundRequest) *dto.RefundResponse {
+	items := make([]dto.RefundItemResponse, len(refund.Items))
+	for i, item := range refund.Items {
+		items[i] = dto.RefundItemResponse{
+			TicketID:     item.TicketID,
+			TicketTypeID: item.TicketTypeID,
+			Amount:       item.Amount,
+		}
+	}
+
+	return &dto.RefundResponse{
+		ID:              refund.ID,
+		OrderID:         refund.OrderID,
+		UserID:          refund.UserID,
+		PayerID:         refund.PayerID,
+		EventID:         refund.EventID,
+		Status:          string(refund.Status),
+		Reason:          refund.Reason,
+		Percentage:      refund.Percentage,
+		Amount:          refund.Amount,
+		PaymentID:       refund.PaymentID,
+		ResaleID:        refund.ResaleID,
+		ReviewedBy:      refund.ReviewedBy,
+		ReviewedAt:      refund.ReviewedAt,
+		RejectionReason: refund.RejectionReason,
+		CreatedAt:       refund.CreatedAt,
+		UpdatedAt:       refund.UpdatedAt,
+		Items:           items,
+	}
+}
@@ -0,0 +1,82 @@
+package refund_service
+
+import (
+	"errors"
+	"ticket-zetu-api/modules/events/models/events"
+	organizers_services "ticket-zetu-api/modules/organizers/services"
+	"ticket-zetu-api/modules/tickets/models/tickets"
+	"ticket-zetu-api/modules/tickets/refunds/dto"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GetRefundPolicy returns the refund policy for an event. Events without a
+// policy report refunds as not allowed.
+func (s *refundService) GetRefundPolicy(eventID string) (*dto.RefundPolicyResponse, error) {
+	if _, err := uuid.Parse(eventID); err != nil {
+		return nil, errors.New("invalid event ID format")
+	}
+
+	var event events.Event
+	if err := s.db.Where("id = ? AND deleted_at IS NULL", eventID).First(&event).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("event not found")
+		}
+		return nil, err
+	}
+
+	policy := tickets.RefundPolicy{EventID: eventID}
+	if err := s.db.Where("event_id = ?", eventID).First(&policy).Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	return toPolicyDTO(&policy, &event), nil
+}
+
+// SetRefundPolicy creates or replaces the refund policy for one of the organizer's events
+func (s *refundService) SetRefundPolicy(userID, eventID string, input dto.RefundPolicyInput) (*dto.RefundPolicyResponse, error) {
+	if _, err := uuid.Parse(eventID); err != nil {
+		return nil, errors.New("invalid event ID format")
+	}
+	organizer, err := organizers_services.GetUserOrganizer(s.db, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var event events.Event
+	if err := s.db.Where("id = ? AND organizer_id = ? AND deleted_at IS NULL", eventID, organizer.ID).First(&event).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("event not found")
+		}
+		return nil, err
+	}
+
+	policy := tickets.RefundPolicy{
+		EventID:                      eventID,
+		AllowRefunds:                 input.AllowRefunds,
+		DeadlineHours:                input.DeadlineHours,
+		RefundPercentage:             input.RefundPercentage,
+		AutoApprove:                  input.AutoApprove,
+		CancellationRefundPercentage: input.CancellationRefundPercentage,
+	}
+	if err := s.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "event_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"allow_refunds", "deadline_hours", "refund_percentage", "auto_approve", "cancellation_refund_percentage", "updated_at"}),
+	}).Create(&policy).Error; err != nil {
+		return nil, err
+	}
+	return toPolicyDTO(&policy, &event), nil
+}
+
+func toPolicyDTO(policy *tickets.RefundPolicy, event *events.Event) *dto.RefundPolicyResponse {
+	return &dto.RefundPolicyResponse{
+		EventID:                      policy.EventID,
+		AllowRefunds:                 policy.AllowRefunds,
+		DeadlineHours:                policy.DeadlineHours,
+		RefundPercentage:             policy.RefundPercentage,
+		AutoApprove:                  policy.AutoApprove,
+		RefundDeadline:               policy.RefundDeadline(event.StartTime),
+		CancellationRefundPercentage: policy.CancellationPercentage(),
+	}
+}
@@ -0,0 +1,409 @@
+package refund_service
+
+import (
+	"errors"
+	"log"
+	"math"
+	"ticket-zetu-api/modules/events/models/events"
+	seat_services "ticket-zetu-api/modules/events/seat_allocation/services"
+	ledger_service "ticket-zetu-api/modules/ledger/service"
+	organizers_services "ticket-zetu-api/modules/organizers/services"
+	discount_service "ticket-zetu-api/modules/tickets/discount/services"
+	inventory_service "ticket-zetu-api/modules/tickets/inventory/service"
+	"ticket-zetu-api/modules/tickets/models/tickets"
+	payment_service "ticket-zetu-api/modules/tickets/payments/service"
+	"ticket-zetu-api/modules/tickets/refunds/dto"
+	resale_service "ticket-zetu-api/modules/tickets/resale/service"
+	"ticket-zetu-api/modules/users/authorization/service"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RefundService interface {
+	RequestRefund(userID string, input dto.CreateRefundInput) (*dto.RefundResponse, error)
+	GetRefund(userID, refundID string) (*dto.RefundResponse, error)
+	GetUserRefunds(userID string) ([]dto.RefundResponse, error)
+	GetOrganizerRefunds(userID, status string) ([]dto.RefundResponse, error)
+	ApproveRefund(userID, refundID string) (*dto.RefundResponse, error)
+	RejectRefund(userID, refundID string, input dto.RejectRefundInput) (*dto.RefundResponse, error)
+	GetRefundPolicy(eventID string) (*dto.RefundPolicyResponse, error)
+	SetRefundPolicy(userID, eventID string, input dto.RefundPolicyInput) (*dto.RefundPolicyResponse, error)
+	RefundCancelledOrder(orderID string, percentage float64, reason string) (int, error)
+}
+
+type refundService struct {
+	db                   *gorm.DB
+	authorizationService authorization_service.PermissionService
+	inventoryService     inventory_service.InventoryService
+	paymentService       payment_service.PaymentService
+}
+
+func NewRefundService(db *gorm.DB, authService authorization_service.PermissionService, inventoryService inventory_service.InventoryService, paymentService payment_service.PaymentService) RefundService {
+	return &refundService{
+		db:                   db,
+		authorizationService: authService,
+		inventoryService:     inventoryService,
+		paymentService:       paymentService,
+	}
+}
+
+// RequestRefund records a refund request for some or all of the valid tickets
+// the user holds in a confirmed order. Tickets transferred to the user can be
+// refunded too, with the money going back to whoever paid for the order, but
+// tickets bought on resale cannot. The amount is fixed from the event's
+// refund policy at request time. Events with auto-approval are refunded
+// immediately.
+func (s *refundService) RequestRefund(userID string, input dto.CreateRefundInput) (*dto.RefundResponse, error) {
+	if _, err := uuid.Parse(userID); err != nil {
+		return nil, errors.New("invalid user ID format")
+	}
+	if _, err := uuid.Parse(input.OrderID); err != nil {
+		return nil, errors.New("invalid order ID format")
+	}
+
+	var refund tickets.RefundRequest
+	autoApprove := false
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		// Locking the order serialises concurrent requests for the same tickets
+		var order tickets.Order
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", input.OrderID).
+			First(&order).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("order not found")
+			}
+			return err
+		}
+
+		ticketQuery := tx.Where("order_id = ? AND user_id = ? AND status = ?", order.ID, userID, tickets.TicketValid)
+		if len(input.TicketIDs) > 0 {
+			ticketQuery = ticketQuery.Where("id IN ?", input.TicketIDs)
+		}
+		var refundable []tickets.Ticket
+		if err := ticketQuery.Find(&refundable).Error; err != nil {
+			return err
+		}
+		// Only the buyer and current holders may see that the order exists
+		if len(refundable) == 0 && order.UserID != userID {
+			return errors.New("order not found")
+		}
+
+		if order.Status != tickets.OrderConfirmed {
+			return errors.New("order is not confirmed")
+		}
+
+		policy, event, err := s.activePolicy(tx, order.EventID)
+		if err != nil {
+			return err
+		}
+		if time.Now().After(policy.RefundDeadline(event.StartTime)) {
+			return errors.New("refund deadline has passed")
+		}
+		if len(refundable) == 0 || (len(input.TicketIDs) > 0 && len(refundable) != len(uniqueIDs(input.TicketIDs))) {
+			return errors.New("tickets are not refundable")
+		}
+
+		ticketIDs := make([]string, len(refundable))
+		for i, ticket := range refundable {
+			ticketIDs[i] = ticket.ID
+		}
+		var pending int64
+		if err := tx.Model(&tickets.RefundRequestItem{}).
+			Joins("JOIN refund_requests ON refund_requests.id = refund_request_items.refund_request_id").
+			Where("refund_request_items.ticket_id IN ? AND refund_requests.status = ?", ticketIDs, tickets.RefundPending).
+			Count(&pending).Error; err != nil {
+			return err
+		}
+		if pending > 0 {
+			return errors.New("a refund is already pending for these tickets")
+		}
+
+		var listed int64
+		if err := tx.Model(&tickets.TicketResale{}).
+			Where("ticket_id IN ? AND status IN ?", ticketIDs, []tickets.ResaleStatus{tickets.ResaleListed, tickets.ResalePending}).
+			Count(&listed).Error; err != nil {
+			return err
+		}
+		if listed > 0 {
+			return errors.New("tickets are listed for resale")
+		}
+
+		// The order's payment did not pay for a resold ticket, and the
+		// resale price went to the seller
+		resold, err := resale_service.LastResale(tx, ticketIDs)
+		if err != nil {
+			return err
+		}
+		if len(resold) > 0 {
+			return errors.New("tickets bought on resale cannot be refunded")
+		}
+
+		refund = tickets.RefundRequest{
+			OrderID:    order.ID,
+			UserID:     userID,
+			PayerID:    order.UserID,
+			EventID:    order.EventID,
+			Status:     tickets.RefundPending,
+			Reason:     input.Reason,
+			Percentage: policy.RefundPercentage,
+			PaymentID:  order.PaymentReference,
+		}
+		for _, ticket := range refundable {
+			amount := roundAmount(ticket.ActualPrice * policy.RefundPercentage / 100)
+			refund.Amount += amount
+			refund.Items = append(refund.Items, tickets.RefundRequestItem{
+				TicketID:     ticket.ID,
+				TicketTypeID: ticket.TicketTypeID,
+				Amount:       amount,
+			})
+		}
+		refund.Amount = roundAmount(refund.Amount)
+		autoApprove = policy.AutoApprove
+		return tx.Create(&refund).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if autoApprove {
+		if err := s.process(refund.ID, ""); err != nil {
+			return nil, err
+		}
+	}
+	return s.GetRefund(userID, refund.ID)
+}
+
+func (s *refundService) ApproveRefund(userID, refundID string) (*dto.RefundResponse, error) {
+	if _, err := s.reviewableRefund(userID, refundID); err != nil {
+		return nil, err
+	}
+	if err := s.process(refundID, userID); err != nil {
+		return nil, err
+	}
+	return s.getRefund(refundID)
+}
+
+func (s *refundService) RejectRefund(userID, refundID string, input dto.RejectRefundInput) (*dto.RefundResponse, error) {
+	if _, err := s.reviewableRefund(userID, refundID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	result := s.db.Model(&tickets.RefundRequest{}).
+		Where("id = ? AND status = ?", refundID, tickets.RefundPending).
+		UpdateColumns(map[string]interface{}{
+			"status":           tickets.RefundRejected,
+			"reviewed_by":      userID,
+			"reviewed_at":      now,
+			"rejection_reason": input.Reason,
+			"version":          gorm.Expr("version + 1"),
+			"updated_at":       now,
+		})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, errors.New("refund is not pending")
+	}
+	return s.getRefund(refundID)
+}
+
+// process completes a pending refund in one transaction: the tickets are
+// refunded, their units and any seats go back on sale, the organizer's
+// balance is debited and a refund of the payment is queued. If any step fails
+// nothing changes and the request stays pending. The provider is only asked to return the
+// money once the transaction has committed.
+func (s *refundService) process(refundID, reviewerID string) error {
+	var paymentRefund *tickets.PaymentRefund
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var refund tickets.RefundRequest
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Preload("Items").
+			Where("id = ?", refundID).
+			First(&refund).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("refund not found")
+			}
+			return err
+		}
+		if refund.Status != tickets.RefundPending {
+			return errors.New("refund is not pending")
+		}
+
+		// A zero-percentage policy cancels the tickets without returning money
+		ticketStatus := tickets.TicketRefunded
+		if refund.Amount == 0 {
+			ticketStatus = tickets.TicketCanceled
+		}
+
+		now := time.Now()
+		perType := make(map[string]int)
+		ticketIDs := make([]string, len(refund.Items))
+		for i, item := range refund.Items {
+			ticketIDs[i] = item.TicketID
+			perType[item.TicketTypeID]++
+		}
+
+		result := tx.Model(&tickets.Ticket{}).
+			Where("id IN ? AND status = ?", ticketIDs, tickets.TicketValid).
+			UpdateColumns(map[string]interface{}{
+				"status":     ticketStatus,
+				"version":    gorm.Expr("version + 1"),
+				"updated_at": now,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if int(result.RowsAffected) != len(ticketIDs) {
+			return errors.New("tickets are not refundable")
+		}
+
+		for ticketTypeID, quantity := range perType {
+			if err := s.inventoryService.MoveIfTracked(tx, ticketTypeID, inventory_service.BucketSold, inventory_service.BucketAvailable, quantity); err != nil {
+				return err
+			}
+		}
+
+		// Seated tickets give their seat back along with the unit
+		var seatIDs []string
+		if err := tx.Model(&tickets.Ticket{}).
+			Where("id IN ? AND seat_id <> ''", ticketIDs).
+			Pluck("seat_id", &seatIDs).Error; err != nil {
+			return err
+		}
+		if err := seat_services.ReleaseTicketSeats(tx, refund.OrderID, seatIDs); err != nil {
+			return err
+		}
+
+		var remaining int64
+		if err := tx.Model(&tickets.Ticket{}).
+			Where("order_id = ? AND status IN ?", refund.OrderID, []tickets.TicketStatus{tickets.TicketValid, tickets.TicketUsed}).
+			Count(&remaining).Error; err != nil {
+			return err
+		}
+		if remaining == 0 {
+			if err := tx.Model(&tickets.Order{}).
+				Where("id = ?", refund.OrderID).
+				UpdateColumns(map[string]interface{}{
+					"status":     tickets.OrderRefunded,
+					"version":    gorm.Expr("version + 1"),
+					"updated_at": now,
+				}).Error; err != nil {
+				return err
+			}
+			if err := discount_service.ReleaseRedemption(tx, refund.OrderID); err != nil {
+				return err
+			}
+		}
+
+		updates := map[string]interface{}{
+			"status":     tickets.RefundCompleted,
+			"version":    gorm.Expr("version + 1"),
+			"updated_at": now,
+		}
+		if reviewerID != "" {
+			updates["reviewed_by"] = reviewerID
+			updates["reviewed_at"] = now
+		}
+		if err := tx.Model(&refund).UpdateColumns(updates).Error; err != nil {
+			return err
+		}
+
+		if refund.Amount == 0 {
+			return nil
+		}
+		// A resale refund is paid from what the seller was owed, not by the organizer
+		if refund.ResaleID != "" {
+			if err := resale_service.ReverseResale(tx, refund.ResaleID, refund.ID); err != nil {
+				return err
+			}
+		} else if err := ledger_service.RecordRefund(tx, refund.ID, refund.OrderID, refund.EventID, ledger_service.Amount(refund.Amount)); err != nil {
+			return err
+		}
+		var err error
+		paymentRefund, err = s.paymentService.RefundPayment(tx, refund.PaymentID, refund.Amount, refundReason(&refund))
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	// A refund the provider turns down stays queued and is retried
+	if paymentRefund != nil {
+		if err := s.paymentService.SendRefund(paymentRefund.ID); err != nil {
+			log.Printf("Failed to send refund for request %s: %v", refundID, err)
+		}
+	}
+	return nil
+}
+
+// reviewableRefund loads a refund for an event owned by the user's organizer
+func (s *refundService) reviewableRefund(userID, refundID string) (*tickets.RefundRequest, error) {
+	if _, err := uuid.Parse(refundID); err != nil {
+		return nil, errors.New("invalid refund ID format")
+	}
+	organizer, err := organizers_services.GetUserOrganizer(s.db, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var refund tickets.RefundRequest
+	if err := s.db.Joins("JOIN events ON events.id = refund_requests.event_id").
+		Where("refund_requests.id = ? AND events.organizer_id = ?", refundID, organizer.ID).
+		First(&refund).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("refund not found")
+		}
+		return nil, err
+	}
+	return &refund, nil
+}
+
+// activePolicy returns the event's refund policy, failing when refunds are not allowed
+func (s *refundService) activePolicy(tx *gorm.DB, eventID string) (*tickets.RefundPolicy, *events.Event, error) {
+	var event events.Event
+	if err := tx.Where("id = ?", eventID).First(&event).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, errors.New("event not found")
+		}
+		return nil, nil, err
+	}
+
+	var policy tickets.RefundPolicy
+	if err := tx.Where("event_id = ?", eventID).First(&policy).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, errors.New("refunds are not allowed for this event")
+		}
+		return nil, nil, err
+	}
+	if !policy.AllowRefunds {
+		return nil, nil, errors.New("refunds are not allowed for this event")
+	}
+	return &policy, &event, nil
+}
+
+func refundReason(refund *tickets.RefundRequest) string {
+	if refund.Reason != "" {
+		return refund.Reason
+	}
+	return "ticket refund"
+}
+
+func roundAmount(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+func uniqueIDs(ids []string) []string {
+	seen := make(map[string]bool, len(ids))
+	unique := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,282 @@
+package resale_controller
+
+import (
+	"ticket-zetu-api/logs/handler"
+	"ticket-zetu-api/modules/tickets/resale/dto"
+	"ticket-zetu-api/modules/tickets/resale/service"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+type ResaleController struct {
+	service    resale_service.ResaleService
+	logHandler *handler.LogHandler
+	validator  *validator.Validate
+}
+
+func NewResaleController(service resale_service.ResaleService, logHandler *handler.LogHandler) *ResaleController {
+	return &ResaleController{
+		service:    service,
+		logHandler: logHandler,
+		validator:  validator.New(),
+	}
+}
+
+// ListTicket godoc
+// @Summary List a ticket for resale
+// @Description Lists one of the current user's valid tickets on the resale marketplace. The price may not exceed the event's cap, and the ticket must have been held for the event's minimum hold period. Listings close when the event starts.
+// @Tags Resale
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param input body dto.CreateListingInput true "Listing details"
+// @Success 200 {object} map[string]interface{} "Ticket listed successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid request body or ticket not transferable"
+// @Failure 404 {object} map[string]interface{} "Ticket not found"
+// @Failure 409 {object} map[string]interface{} "Ticket already listed, being transferred or awaiting a refund"
+// @Failure 422 {object} map[string]interface{} "Resale not allowed, price above cap or hold period not met"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /resale/listings [post]
+func (c *ResaleController) ListTicket(ctx *fiber.Ctx) error {
+	userID := ctx.Locals("user_id").(string)
+
+	var input dto.CreateListingInput
+	if err := ctx.BodyParser(&input); err != nil {
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, "Invalid request body"), fiber.StatusBadRequest)
+	}
+
+	if err := c.validator.Struct(input); err != nil {
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, err.Error()), fiber.StatusBadRequest)
+	}
+
+	listing, err := c.service.ListTicket(userID, input)
+	if err != nil {
+		return c.handleError(ctx, err)
+	}
+
+	return c.logHandler.LogSuccess(ctx, listing, "Ticket listed successfully", true)
+}
+
+// GetEventListings godoc
+// @Summary Browse resale tickets for an event
+// @Description Retrieves the resale listings currently available for an event, cheapest first.
+// @Tags Resale
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param event_id path string true "Event ID"
+// @Param ticket_type_id query string false "Ticket type ID"
+// @Success 200 {object} map[string]interface{} "Listings retrieved successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid event or ticket type ID"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /resale/events/{event_id}/listings [get]
+func (c *ResaleController) GetEventListings(ctx *fiber.Ctx) error {
+	eventID := ctx.Params("event_id")
+
+	listings, err := c.service.GetEventListings(eventID, ctx.Query("ticket_type_id"))
+	if err != nil {
+		return c.handleError(ctx, err)
+	}
+
+	return c.logHandler.LogSuccess(ctx, listings, "Listings retrieved successfully", false)
+}
+
+// GetUserListings godoc
+// @Summary Get the current user's resale listings
+// @Description Retrieves every resale listing created by the current user, newest first.
+// @Tags Resale
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} map[string]interface{} "Listings retrieved successfully"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /resale/listings [get]
+func (c *ResaleController) GetUserListings(ctx *fiber.Ctx) error {
+	userID := ctx.Locals("user_id").(string)
+
+	listings, err := c.service.GetUserListings(userID)
+	if err != nil {
+		return c.handleError(ctx, err)
+	}
+
+	return c.logHandler.LogSuccess(ctx, listings, "Listings retrieved successfully", false)
+}
+
+// GetListing godoc
+// @Summary Get a resale listing
+// @Description Retrieves a single resale listing.
+// @Tags Resale
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Listing ID"
+// @Success 200 {object} map[string]interface{} "Listing retrieved successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid listing ID"
+// @Failure 404 {object} map[string]interface{} "Listing not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /resale/listings/{id} [get]
+func (c *ResaleController) GetListing(ctx *fiber.Ctx) error {
+	id := ctx.Params("id")
+
+	listing, err := c.service.GetListing(id)
+	if err != nil {
+		return c.handleError(ctx, err)
+	}
+
+	return c.logHandler.LogSuccess(ctx, listing, "Listing retrieved successfully", false)
+}
+
+// ReserveListing godoc
+// @Summary Reserve a resale listing for purchase
+// @Description Reserves a listing for the current user for 15 minutes. Pay for it with POST /payments using purpose "resale" and the listing ID as reference_id; the ticket is transferred once the payment succeeds.
+// @Tags Resale
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Listing ID"
+// @Success 200 {object} map[string]interface{} "Listing reserved successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid listing ID or own listing"
+// @Failure 404 {object} map[string]interface{} "Listing not found"
+// @Failure 409 {object} map[string]interface{} "Listing is not available"
+// @Failure 410 {object} map[string]interface{} "Listing has expired"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /resale/listings/{id}/reserve [post]
+func (c *ResaleController) ReserveListing(ctx *fiber.Ctx) error {
+	userID := ctx.Locals("user_id").(string)
+	id := ctx.Params("id")
+
+	listing, err := c.service.ReserveListing(userID, id)
+	if err != nil {
+		return c.handleError(ctx, err)
+	}
+
+	return c.logHandler.LogSuccess(ctx, listing, "Listing reserved successfully", true)
+}
+
+// CancelListing godoc
+// @Summary Delist a resale ticket
+// @Description Takes one of the current user's listings off the marketplace. Listings reserved by a buyer cannot be canceled until the reservation lapses.
+// @Tags Resale
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Listing ID"
+// @Success 200 {object} map[string]interface{} "Listing canceled successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid listing ID"
+// @Failure 404 {object} map[string]interface{} "Listing not found"
+// @Failure 409 {object} map[string]interface{} "Listing is reserved or no longer active"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /resale/listings/{id} [delete]
+func (c *ResaleController) CancelListing(ctx *fiber.Ctx) error {
+	userID := ctx.Locals("user_id").(string)
+	id := ctx.Params("id")
+
+	listing, err := c.service.CancelListing(userID, id)
+	if err != nil {
+		return c.handleError(ctx, err)
+	}
+
+	return c.logHandler.LogSuccess(ctx, listing, "Listing canceled successfully", true)
+}
+
+// GetUserPayouts godoc
+// @Summary Get the current user's resale payouts
+// @Description Retrieves the payouts owed to the current user for resold tickets, net of the platform fee.
+// @Tags Resale
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} map[string]interface{} "Payouts retrieved successfully"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /resale/payouts [get]
+func (c *ResaleController) GetUserPayouts(ctx *fiber.Ctx) error {
+	userID := ctx.Locals("user_id").(string)
+
+	payouts, err := c.service.GetUserPayouts(userID)
+	if err != nil {
+		return c.handleError(ctx, err)
+	}
+
+	return c.logHandler.LogSuccess(ctx, payouts, "Payouts retrieved successfully", false)
+}
+
+// GetResalePolicy godoc
+// @Summary Get an event's resale policy
+// @Description Retrieves the resale policy for an event, including the price cap and minimum hold period.
+// @Tags Resale
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param event_id path string true "Event ID"
+// @Success 200 {object} map[string]interface{} "Resale policy retrieved successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid event ID"
+// @Failure 404 {object} map[string]interface{} "Event not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /resale/policy/event/{event_id} [get]
+func (c *ResaleController) GetResalePolicy(ctx *fiber.Ctx) error {
+	eventID := ctx.Params("event_id")
+
+	policy, err := c.service.GetResalePolicy(eventID)
+	if err != nil {
+		return c.handleError(ctx, err)
+	}
+
+	return c.logHandler.LogSuccess(ctx, policy, "Resale policy retrieved successfully", false)
+}
+
+// SetResalePolicy godoc
+// @Summary Set an event's resale policy
+// @Description Creates or replaces the resale policy for one of the organizer's events. The price cap is a percentage of the price originally paid.
+// @Tags Resale
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param event_id path string true "Event ID"
+// @Param input body dto.ResalePolicyInput true "Resale policy"
+// @Success 200 {object} map[string]interface{} "Resale policy updated successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid request body or event ID"
+// @Failure 404 {object} map[string]interface{} "Event or organizer not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /resale/policy/event/{event_id} [put]
+func (c *ResaleController) SetResalePolicy(ctx *fiber.Ctx) error {
+	userID := ctx.Locals("user_id").(string)
+	eventID := ctx.Params("event_id")
+
+	var input dto.ResalePolicyInput
+	if err := ctx.BodyParser(&input); err != nil {
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, "Invalid request body"), fiber.StatusBadRequest)
+	}
+
+	if err := c.validator.Struct(input); err != nil {
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, err.Error()), fiber.StatusBadRequest)
+	}
+
+	policy, err := c.service.SetResalePolicy(userID, eventID, input)
+	if err != nil {
+		return c.handleError(ctx, err)
+	}
+
+	return c.logHandler.LogSuccess(ctx, policy, "Resale policy updated successfully", true)
+}
+
+func (c *ResaleController) handleError(ctx *fiber.Ctx, err error) error {
+	switch err.Error() {
+	case "invalid user ID format", "invalid ticket ID format", "invalid listing ID format", "invalid event ID format",
+		"invalid ticket type ID format", "cannot buy your own listing", "only valid tickets can be transferred", "event has already started":
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, err.Error()), fiber.StatusBadRequest)
+	case "ticket not found", "listing not found", "event not found", "organizer not found":
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusNotFound, err.Error()), fiber.StatusNotFound)
+	case "ticket is already listed for resale", "ticket has a pending transfer", "a refund is pending for this ticket",
+		"listing is reserved by a buyer", "listing is not active", "listing is not available":
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusConflict, err.Error()), fiber.StatusConflict)
+	case "listing has expired":
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusGone, err.Error()), fiber.StatusGone)
+	case "resale is not allowed for this event", "resale price exceeds the event's price cap",
+		"ticket has not been held long enough to resell", "transfers are disabled for this ticket", "ticket has reached its transfer limit",
+		"quantity exceeds the event's ticket limit per user":
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusUnprocessableEntity, err.Error()), fiber.StatusUnprocessableEntity)
+	default:
+		return c.logHandler.LogError(ctx, err, fiber.StatusInternalServerError)
+	}
+}
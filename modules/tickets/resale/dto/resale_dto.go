@@ -0,0 +1,60 @@
+package dto
+
+import "time"
+
+// CreateListingInput defines the input structure for listing a ticket for resale
+type CreateListingInput struct {
+	TicketID string  `json:"ticket_id" example:"a1d3c4e6-89ab-44ce-8e65-123456789abc" validate:"required,uuid"`
+	Price    float64 `json:"price" example:"1500" validate:"required,gt=0"`
+}
+
+// ResalePolicyInput defines the input structure for an event's resale policy
+type ResalePolicyInput struct {
+	AllowResale        bool    `json:"allow_resale" example:"true"`
+	MaxPricePercentage float64 `json:"max_price_percentage" example:"110" validate:"gte=0,lte=1000"`
+	MinHoldDays        int     `json:"min_hold_days" example:"2" validate:"gte=0,lte=365"`
+}
+
+// ResalePolicyResponse defines the response structure for an event's resale policy
+type ResalePolicyResponse struct {
+	EventID            string  `json:"event_id"`
+	AllowResale        bool    `json:"allow_resale"`
+	MaxPricePercentage float64 `json:"max_price_percentage"`
+	MinHoldDays        int     `json:"min_hold_days"`
+}
+
+// ListingResponse defines the response structure for a resale listing
+type ListingResponse struct {
+	ID             string     `json:"id"`
+	TicketID       string     `json:"ticket_id"`
+	EventID        string     `json:"event_id"`
+	TicketTypeID   string     `json:"ticket_type_id"`
+	TicketTypeName string     `json:"ticket_type_name,omitempty"`
+	SeatNumber     string     `json:"seat_number,omitempty"`
+	SeatSection    string     `json:"seat_section,omitempty"`
+	SellerID       string     `json:"seller_id"`
+	BuyerID        string     `json:"buyer_id,omitempty"`
+	OriginalPrice  float64    `json:"original_price"`
+	Price          float64    `json:"price"`
+	PlatformFee    float64    `json:"platform_fee,omitempty"`
+	SellerProceeds float64    `json:"seller_proceeds,omitempty"`
+	Status         string     `json:"status"`
+	ListedAt       time.Time  `json:"listed_at"`
+	ExpiresAt      time.Time  `json:"expires_at"`
+	ReservedUntil  *time.Time `json:"reserved_until,omitempty"`
+	SoldAt         *time.Time `json:"sold_at,omitempty"`
+}
+
+// PayoutResponse defines the response structure for a seller's resale payout
+type PayoutResponse struct {
+	ID          string     `json:"id"`
+	ResaleID    string     `json:"resale_id"`
+	PaymentID   string     `json:"payment_id"`
+	GrossAmount float64    `json:"gross_amount"`
+	PlatformFee float64    `json:"platform_fee"`
+	NetAmount   float64    `json:"net_amount"`
+	Currency    string     `json:"currency"`
+	Status      string     `json:"status"`
+	PaidAt      *time.Time `json:"paid_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
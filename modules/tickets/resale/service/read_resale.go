@@ -0,0 +1,203 @@
+package resale_service
+
+import (
+	"errors"
+	"ticket-zetu-api/modules/events/models/events"
+	organizers_services "ticket-zetu-api/modules/organizers/services"
+	"ticket-zetu-api/modules/tickets/models/tickets"
+	"ticket-zetu-api/modules/tickets/resale/dto"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (s *resaleService) GetListing(listingID string) (*dto.ListingResponse, error) {
+	if _, err := uuid.Parse(listingID); err != nil {
+		return nil, errors.New("invalid listing ID format")
+	}
+
+	var listing tickets.TicketResale
+	if err := s.db.Preload("Ticket.TicketType").Where("id = ?", listingID).First(&listing).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("listing not found")
+		}
+		return nil, err
+	}
+	return toDTO(&listing), nil
+}
+
+// GetEventListings lists the tickets currently for sale for an event, cheapest first
+func (s *resaleService) GetEventListings(eventID, ticketTypeID string) ([]dto.ListingResponse, error) {
+	if _, err := uuid.Parse(eventID); err != nil {
+		return nil, errors.New("invalid event ID format")
+	}
+
+	now := time.Now()
+	query := s.db.Preload("Ticket.TicketType").
+		Where("event_id = ? AND expires_at > ?", eventID, now).
+		Where("status = ? OR (status = ? AND reserved_until <= ?)", tickets.ResaleListed, tickets.ResalePending, now)
+	if ticketTypeID != "" {
+		if _, err := uuid.Parse(ticketTypeID); err != nil {
+			return nil, errors.New("invalid ticket type ID format")
+		}
+		query = query.Where("ticket_type_id = ?", ticketTypeID)
+	}
+
+	var listings []tickets.TicketResale
+	if err := query.Order("resale_price ASC, listed_at ASC").Find(&listings).Error; err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.ListingResponse, len(listings))
+	for i := range listings {
+		responses[i] = *toDTO(&listings[i])
+		// Buyers see the price; what the seller nets is the seller's business
+		responses[i].PlatformFee = 0
+		responses[i].SellerProceeds = 0
+		responses[i].BuyerID = ""
+	}
+	return responses, nil
+}
+
+// GetUserListings lists the user's own listings, newest first
+func (s *resaleService) GetUserListings(userID string) ([]dto.ListingResponse, error) {
+	if _, err := uuid.Parse(userID); err != nil {
+		return nil, errors.New("invalid user ID format")
+	}
+
+	var listings []tickets.TicketResale
+	if err := s.db.Preload("Ticket.TicketType").
+		Where("original_user_id = ?", userID).
+		Order("listed_at DESC").
+		Find(&listings).Error; err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.ListingResponse, len(listings))
+	for i := range listings {
+		responses[i] = *toDTO(&listings[i])
+	}
+	return responses, nil
+}
+
+// GetUserPayouts lists what the user is owed, or has been paid, for tickets they resold
+func (s *resaleService) GetUserPayouts(userID string) ([]dto.PayoutResponse, error) {
+	if _, err := uuid.Parse(userID); err != nil {
+		return nil, errors.New("invalid user ID format")
+	}
+
+	var payouts []tickets.ResalePayout
+	if err := s.db.Where("seller_id = ?", userID).Order("created_at DESC").Find(&payouts).Error; err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.PayoutResponse, len(payouts))
+	for i, payout := range payouts {
+		responses[i] = dto.PayoutResponse{
+			ID:          payout.ID,
+			ResaleID:    payout.ResaleID,
+			PaymentID:   payout.PaymentID,
+			GrossAmount: payout.GrossAmount,
+			PlatformFee: payout.PlatformFee,
+			NetAmount:   payout.NetAmount,
+			Currency:    payout.Currency,
+			Status:      string(payout.Status),
+			PaidAt:      payout.PaidAt,
+			CreatedAt:   payout.CreatedAt,
+		}
+	}
+	return responses, nil
+}
+
+// GetResalePolicy returns the resale policy for an event. Events without a
+// policy report resale as not allowed.
+func (s *resaleService) GetResalePolicy(eventID string) (*dto.ResalePolicyResponse, error) {
+	if _, err := uuid.Parse(eventID); err != nil {
+		return nil, errors.New("invalid event ID format")
+	}
+
+	var event events.Event
+	if err := s.db.Where("id = ? AND deleted_at IS NULL", eventID).First(&event).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("event not found")
+		}
+		return nil, err
+	}
+
+	policy := tickets.ResalePolicy{EventID: eventID}
+	if err := s.db.Where("event_id = ?", eventID).First(&policy).Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	return toPolicyDTO(&policy), nil
+}
+
+// SetResalePolicy creates or replaces the resale policy for one of the
+// organizer's events. Existing listings keep the terms they were listed under.
+func (s *resaleService) SetResalePolicy(userID, eventID string, input dto.ResalePolicyInput) (*dto.ResalePolicyResponse, error) {
+	if _, err := uuid.Parse(eventID); err != nil {
+		return nil, errors.New("invalid event ID format")
+	}
+	organizer, err := organizers_services.GetUserOrganizer(s.db, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var event events.Event
+	if err := s.db.Where("id = ? AND organizer_id = ? AND deleted_at IS NULL", eventID, organizer.ID).First(&event).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("event not found")
+		}
+		return nil, err
+	}
+
+	policy := tickets.ResalePolicy{
+		EventID:            eventID,
+		AllowResale:        input.AllowResale,
+		MaxPricePercentage: input.MaxPricePercentage,
+		MinHoldDays:        input.MinHoldDays,
+	}
+	if err := s.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "event_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"allow_resale", "max_price_percentage", "min_hold_days", "updated_at"}),
+	}).Create(&policy).Error; err != nil {
+		return nil, err
+	}
+	return toPolicyDTO(&policy), nil
+}
+
+func toPolicyDTO(policy *tickets.ResalePolicy) *dto.ResalePolicyResponse {
+	return &dto.ResalePolicyResponse{
+		EventID:            policy.EventID,
+		AllowResale:        policy.AllowResale,
+		MaxPricePercentage: policy.MaxPricePercentage,
+		MinHoldDays:        policy.MinHoldDays,
+	}
+}
+
+func toDTO(listing *tickets.TicketResale) *dto.ListingResponse {
+	response := &dto.ListingResponse{
+		ID:             listing.ID,
+		TicketID:       listing.TicketID,
+		EventID:        listing.EventID,
+		TicketTypeID:   listing.TicketTypeID,
+		TicketTypeName: listing.Ticket.TicketType.Name,
+		SeatNumber:     listing.Ticket.SeatNumber,
+		SeatSection:    listing.Ticket.SeatSection,
+		SellerID:       listing.OriginalUserID,
+		OriginalPrice:  listing.OriginalPrice,
+		Price:          listing.ResalePrice,
+		PlatformFee:    listing.PlatformFee,
+		SellerProceeds: roundAmount(listing.SellerProceeds()),
+		Status:         string(listing.Status),
+		ListedAt:       listing.ListedAt,
+		ExpiresAt:      listing.ExpiresAt,
+		ReservedUntil:  listing.ReservedUntil,
+		SoldAt:         listing.SoldAt,
+	}
+	if listing.NewUserID != nil {
+		response.BuyerID = *listing.NewUserID
+	}
+	return response
+}
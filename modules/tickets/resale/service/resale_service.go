@@ -0,0 +1,465 @@
+package resale_service
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	ledger_service "ticket-zetu-api/modules/ledger/service"
+	inventory_service "ticket-zetu-api/modules/tickets/inventory/service"
+	"ticket-zetu-api/modules/tickets/models/tickets"
+	purchase_limit_service "ticket-zetu-api/modules/tickets/purchase_limits/service"
+	"ticket-zetu-api/modules/tickets/resale/dto"
+	transfer_service "ticket-zetu-api/modules/tickets/transfers/service"
+	"ticket-zetu-api/modules/users/authorization/service"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// PlatformFeePercentage is the share of each resale price kept by the platform
+	PlatformFeePercentage = 5.0
+	// reservationWindow is how long a buyer has to pay for a listing they reserved
+	reservationWindow = 15 * time.Minute
+)
+
+var activeStatuses = []tickets.ResaleStatus{tickets.ResaleListed, tickets.ResalePending}
+
+type ResaleService interface {
+	ListTicket(userID string, input dto.CreateListingInput) (*dto.ListingResponse, error)
+	CancelListing(userID, listingID string) (*dto.ListingResponse, error)
+	ReserveListing(userID, listingID string) (*dto.ListingResponse, error)
+	GetListing(listingID string) (*dto.ListingResponse, error)
+	GetEventListings(eventID, ticketTypeID string) ([]dto.ListingResponse, error)
+	GetUserListings(userID string) ([]dto.ListingResponse, error)
+	GetUserPayouts(userID string) ([]dto.PayoutResponse, error)
+	GetResalePolicy(eventID string) (*dto.ResalePolicyResponse, error)
+	SetResalePolicy(userID, eventID string, input dto.ResalePolicyInput) (*dto.ResalePolicyResponse, error)
+	QuotePurchase(tx *gorm.DB, userID, listingID string) (float64, string, error)
+	FulfillPurchase(tx *gorm.DB, payment *tickets.PaymentTransaction) error
+	ExpireListings(limit int) (int, error)
+}
+
+type resaleService struct {
+	db                   *gorm.DB
+	authorizationService authorization_service.PermissionService
+	inventoryService     inventory_service.InventoryService
+	transferService      transfer_service.TransferService
+}
+
+func NewResaleService(db *gorm.DB, authService authorization_service.PermissionService, inventoryService inventory_service.InventoryService, transferService transfer_service.TransferService) ResaleService {
+	return &resaleService{
+		db:                   db,
+		authorizationService: authService,
+		inventoryService:     inventoryService,
+		transferService:      transferService,
+	}
+}
+
+// ListTicket offers one of the user's valid tickets for resale under the
+// event's resale policy. The ticket's unit moves from sold to the resale pool.
+func (s *resaleService) ListTicket(userID string, input dto.CreateListingInput) (*dto.ListingResponse, error) {
+	if _, err := uuid.Parse(userID); err != nil {
+		return nil, errors.New("invalid user ID format")
+	}
+	if _, err := uuid.Parse(input.TicketID); err != nil {
+		return nil, errors.New("invalid ticket ID format")
+	}
+
+	var listing tickets.TicketResale
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		// Locking the ticket serialises competing listings, transfers and refunds of it
+		var ticket tickets.Ticket
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND user_id = ?", input.TicketID, userID).
+			First(&ticket).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("ticket not found")
+			}
+			return err
+		}
+		event, err := transfer_service.CheckTransferable(tx, &ticket)
+		if err != nil {
+			return err
+		}
+
+		var policy tickets.ResalePolicy
+		if err := tx.Where("event_id = ?", ticket.EventID).First(&policy).Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		if !policy.AllowResale {
+			return errors.New("resale is not allowed for this event")
+		}
+		if input.Price > roundAmount(policy.PriceCap(ticket.ActualPrice)) {
+			return errors.New("resale price exceeds the event's price cap")
+		}
+
+		heldSince, err := acquiredAt(tx, &ticket)
+		if err != nil {
+			return err
+		}
+		if time.Since(heldSince) < time.Duration(policy.MinHoldDays)*24*time.Hour {
+			return errors.New("ticket has not been held long enough to resell")
+		}
+
+		if err := checkUnencumbered(tx, ticket.ID); err != nil {
+			return err
+		}
+
+		listing = tickets.TicketResale{
+			TicketID:       ticket.ID,
+			EventID:        ticket.EventID,
+			TicketTypeID:   ticket.TicketTypeID,
+			OriginalUserID: userID,
+			OriginalPrice:  ticket.ActualPrice,
+			ResalePrice:    roundAmount(input.Price),
+			PlatformFee:    roundAmount(input.Price * PlatformFeePercentage / 100),
+			ExpiresAt:      event.StartTime,
+			Status:         tickets.ResaleListed,
+			MinHoldDays:    policy.MinHoldDays,
+		}
+		if err := tx.Create(&listing).Error; err != nil {
+			return err
+		}
+		return s.inventoryService.MoveIfTracked(tx, ticket.TicketTypeID, inventory_service.BucketSold, inventory_service.BucketResale, 1)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetListing(listing.ID)
+}
+
+// CancelListing takes a listing off the market and returns its unit to sold
+func (s *resaleService) CancelListing(userID, listingID string) (*dto.ListingResponse, error) {
+	if _, err := uuid.Parse(listingID); err != nil {
+		return nil, errors.New("invalid listing ID format")
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		listing, err := lockListing(tx, listingID)
+		if err != nil {
+			return err
+		}
+		if listing.OriginalUserID != userID {
+			return errors.New("listing not found")
+		}
+		if !open(listing, time.Now()) {
+			if listing.Status == tickets.ResalePending {
+				return errors.New("listing is reserved by a buyer")
+			}
+			return errors.New("listing is not active")
+		}
+		return s.close(tx, listing, tickets.ResaleCanceled)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetListing(listingID)
+}
+
+// ReserveListing claims a listing for the user for a short payment window.
+// The buyer then pays for it through the payments API with the "resale" purpose.
+func (s *resaleService) ReserveListing(userID, listingID string) (*dto.ListingResponse, error) {
+	if _, err := uuid.Parse(userID); err != nil {
+		return nil, errors.New("invalid user ID format")
+	}
+	if _, err := uuid.Parse(listingID); err != nil {
+		return nil, errors.New("invalid listing ID format")
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		listing, err := lockListing(tx, listingID)
+		if err != nil {
+			return err
+		}
+		if listing.OriginalUserID == userID {
+			return errors.New("cannot buy your own listing")
+		}
+
+		now := time.Now()
+		if !now.Before(listing.ExpiresAt) {
+			return errors.New("listing has expired")
+		}
+		if listing.Status == tickets.ResalePending && listing.NewUserID != nil && *listing.NewUserID == userID {
+			return nil
+		}
+		if !open(listing, now) {
+			return errors.New("listing is not available")
+		}
+		if err := purchase_limit_service.CheckEventLimit(tx, userID, listing.EventID, 1, "", ""); err != nil {
+			return err
+		}
+
+		reservedUntil := now.Add(reservationWindow)
+		if listing.ExpiresAt.Before(reservedUntil) {
+			reservedUntil = listing.ExpiresAt
+		}
+		return tx.Model(listing).UpdateColumns(map[string]interface{}{
+			"status":         tickets.ResalePending,
+			"new_user_id":    userID,
+			"reserved_until": reservedUntil,
+			"version":        gorm.Expr("version + 1"),
+			"updated_at":     now,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetListing(listingID)
+}
+
+// QuotePurchase returns the amount due for a listing the user has reserved
+func (s *resaleService) QuotePurchase(tx *gorm.DB, userID, listingID string) (float64, string, error) {
+	if _, err := uuid.Parse(listingID); err != nil {
+		return 0, "", errors.New("invalid listing ID format")
+	}
+
+	var listing tickets.TicketResale
+	if err := tx.Where("id = ? AND new_user_id = ?", listingID, userID).First(&listing).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, "", errors.New("listing not found")
+		}
+		return 0, "", err
+	}
+	if listing.Status != tickets.ResalePending || listing.ReservedUntil == nil || !time.Now().Before(*listing.ReservedUntil) {
+		return 0, "", errors.New("listing is not reserved for you")
+	}
+
+	return listing.ResalePrice, fmt.Sprintf("Resale ticket %s", listing.TicketID), nil
+}
+
+// FulfillPurchase completes a paid resale: the ticket changes hands with a
+// new credential, its unit returns from the resale pool to sold, and the
+// seller is owed the price less the platform fee. A buyer who pays after
+// their reservation lapsed still gets the ticket unless someone else has
+// reserved it since; otherwise the payment is refunded by the caller.
+func (s *resaleService) FulfillPurchase(tx *gorm.DB, payment *tickets.PaymentTransaction) error {
+	listing, err := lockListing(tx, payment.ReferenceID)
+	if err != nil {
+		return err
+	}
+	if listing.Status != tickets.ResalePending || listing.NewUserID == nil || *listing.NewUserID != payment.UserID {
+		return errors.New("listing is no longer reserved for the buyer")
+	}
+
+	if _, err := s.transferService.ReassignTicket(tx, listing.TicketID, listing.OriginalUserID, payment.UserID); err != nil {
+		return err
+	}
+	if err := s.inventoryService.MoveIfTracked(tx, listing.TicketTypeID, inventory_service.BucketResale, inventory_service.BucketSold, 1); err != nil {
+		return err
+	}
+
+	if err := purchase_limit_service.RecordPurchase(tx, payment.UserID, listing.EventID, 1); err != nil {
+		return err
+	}
+	if err := purchase_limit_service.RecordResale(tx, listing.OriginalUserID, listing.EventID); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if err := tx.Model(listing).UpdateColumns(map[string]interface{}{
+		"status":     tickets.ResaleCompleted,
+		"sold_at":    now,
+		"version":    gorm.Expr("version + 1"),
+		"updated_at": now,
+	}).Error; err != nil {
+		return err
+	}
+
+	// Resales are recorded alongside transfers so a ticket has one ownership history
+	if err := tx.Create(&tickets.TicketTransfer{
+		TicketID:    listing.TicketID,
+		FromUserID:  listing.OriginalUserID,
+		ToUserID:    payment.UserID,
+		Status:      tickets.TransferAccepted,
+		Message:     "resale",
+		ExpiresAt:   now,
+		RespondedAt: &now,
+	}).Error; err != nil {
+		return err
+	}
+
+	if err := ledger_service.RecordResaleSale(tx, listing.ID, listing.OriginalUserID, ledger_service.Amount(listing.ResalePrice), ledger_service.Amount(listing.PlatformFee), payment.Currency); err != nil {
+		return err
+	}
+	return tx.Create(&tickets.ResalePayout{
+		ResaleID:    listing.ID,
+		SellerID:    listing.OriginalUserID,
+		PaymentID:   payment.ID,
+		GrossAmount: listing.ResalePrice,
+		PlatformFee: listing.PlatformFee,
+		NetAmount:   roundAmount(listing.SellerProceeds()),
+		Currency:    payment.Currency,
+		Status:      tickets.PayoutPending,
+	}).Error
+}
+
+// LastResale finds the most recent completed resale of each ticket, for
+// tickets that have been resold
+func LastResale(tx *gorm.DB, ticketIDs []string) (map[string]*tickets.TicketResale, error) {
+	var resales []tickets.TicketResale
+	if err := tx.Where("ticket_id IN ? AND status = ?", ticketIDs, tickets.ResaleCompleted).
+		Order("sold_at ASC").
+		Find(&resales).Error; err != nil {
+		return nil, err
+	}
+	byTicket := make(map[string]*tickets.TicketResale, len(resales))
+	for i := range resales {
+		byTicket[resales[i].TicketID] = &resales[i]
+	}
+	return byTicket, nil
+}
+
+// ResalePayment returns the buyer's payment for a completed resale
+func ResalePayment(tx *gorm.DB, listingID string) (string, error) {
+	var payout tickets.ResalePayout
+	if err := tx.Where("resale_id = ?", listingID).First(&payout).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", errors.New("resale payment not found")
+		}
+		return "", err
+	}
+	return payout.PaymentID, nil
+}
+
+// ReverseResale takes back what the seller was owed for a resale refunded to
+// its buyer: a payout not yet made is canceled and the sale is reversed in the
+// ledger under the refund
+func ReverseResale(tx *gorm.DB, listingID, refundID string) error {
+	if err := tx.Model(&tickets.ResalePayout{}).
+		Where("resale_id = ? AND status = ?", listingID, tickets.PayoutPending).
+		UpdateColumns(map[string]interface{}{
+			"status":     tickets.PayoutCanceled,
+			"updated_at": time.Now(),
+		}).Error; err != nil {
+		return err
+	}
+	return ledger_service.RecordResaleRefund(tx, refundID, listingID)
+}
+
+// ExpireListings closes listings whose event has started, unless a buyer is
+// still inside their payment window, and returns how many were expired
+func (s *resaleService) ExpireListings(limit int) (int, error) {
+	now := time.Now()
+	var ids []string
+	if err := s.db.Model(&tickets.TicketResale{}).
+		Where("status IN ? AND expires_at <= ?", activeStatuses, now).
+		Where("status = ? OR reserved_until IS NULL OR reserved_until <= ?", tickets.ResaleListed, now).
+		Limit(limit).
+		Pluck("id", &ids).Error; err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	for _, id := range ids {
+		err := s.db.Transaction(func(tx *gorm.DB) error {
+			listing, err := lockListing(tx, id)
+			if err != nil {
+				return err
+			}
+			if !open(listing, now) || now.Before(listing.ExpiresAt) {
+				return nil
+			}
+			expired++
+			return s.close(tx, listing, tickets.ResaleExpired)
+		})
+		if err != nil {
+			return expired, err
+		}
+	}
+	return expired, nil
+}
+
+// close ends an open listing and returns its unit from the resale pool to sold
+func (s *resaleService) close(tx *gorm.DB, listing *tickets.TicketResale, status tickets.ResaleStatus) error {
+	if err := tx.Model(listing).UpdateColumns(map[string]interface{}{
+		"status":     status,
+		"version":    gorm.Expr("version + 1"),
+		"updated_at": time.Now(),
+	}).Error; err != nil {
+		return err
+	}
+	return s.inventoryService.MoveIfTracked(tx, listing.TicketTypeID, inventory_service.BucketResale, inventory_service.BucketSold, 1)
+}
+
+// open reports whether a listing can still be reserved, canceled or expired:
+// it is listed, or pending with a reservation that has lapsed
+func open(listing *tickets.TicketResale, now time.Time) bool {
+	switch listing.Status {
+	case tickets.ResaleListed:
+		return true
+	case tickets.ResalePending:
+		return listing.ReservedUntil == nil || !now.Before(*listing.ReservedUntil)
+	default:
+		return false
+	}
+}
+
+func lockListing(tx *gorm.DB, listingID string) (*tickets.TicketResale, error) {
+	var listing tickets.TicketResale
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", listingID).
+		First(&listing).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("listing not found")
+		}
+		return nil, err
+	}
+	return &listing, nil
+}
+
+// acquiredAt is when the ticket's current owner got it: their purchase, or
+// the last transfer or resale to them
+func acquiredAt(tx *gorm.DB, ticket *tickets.Ticket) (time.Time, error) {
+	var transfer tickets.TicketTransfer
+	err := tx.Where("ticket_id = ? AND to_user_id = ? AND status = ?", ticket.ID, ticket.UserID, tickets.TransferAccepted).
+		Order("responded_at DESC").
+		First(&transfer).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && transfer.RespondedAt == nil) {
+		return ticket.PurchaseTime, nil
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+	return *transfer.RespondedAt, nil
+}
+
+// checkUnencumbered fails when the ticket is already listed, being
+// transferred or awaiting a refund
+func checkUnencumbered(tx *gorm.DB, ticketID string) error {
+	var count int64
+	if err := tx.Model(&tickets.TicketResale{}).
+		Where("ticket_id = ? AND status IN ?", ticketID, activeStatuses).
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return errors.New("ticket is already listed for resale")
+	}
+
+	if err := tx.Model(&tickets.TicketTransfer{}).
+		Where("ticket_id = ? AND status = ? AND expires_at > ?", ticketID, tickets.TransferPending, time.Now()).
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return errors.New("ticket has a pending transfer")
+	}
+
+	if err := tx.Model(&tickets.RefundRequestItem{}).
+		Joins("JOIN refund_requests ON refund_requests.id = refund_request_items.refund_request_id").
+		Where("refund_request_items.ticket_id = ? AND refund_requests.status = ?", ticketID, tickets.RefundPending).
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return errors.New("a refund is pending for this ticket")
+	}
+	return nil
+}
+
+func roundAmount(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
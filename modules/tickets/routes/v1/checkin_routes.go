@@ -0,0 +1,32 @@
+package routes
+
+import (
+	"ticket-zetu-api/logs/handler"
+	checkin_controller "ticket-zetu-api/modules/tickets/checkin/controller"
+	checkin_service "ticket-zetu-api/modules/tickets/checkin/service"
+	ticket_credentials "ticket-zetu-api/modules/tickets/credentials"
+	"ticket-zetu-api/modules/users/authorization/service"
+	"ticket-zetu-api/modules/users/middleware"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+func SetupCheckInRoutes(router fiber.Router, db *gorm.DB, logHandler *handler.LogHandler) {
+	authMiddleware := middleware.IsAuthenticated(db, logHandler)
+	authService := authorization_service.NewPermissionService(db)
+
+	checkInService := checkin_service.NewCheckInService(db, authService, ticket_credentials.DefaultKeyRing())
+	checkInController := checkin_controller.NewCheckInController(checkInService, logHandler)
+
+	checkInGroup := router.Group("/checkin/events/:event_id", authMiddleware)
+	{
+		checkInGroup.Post("/scan", checkInController.ScanTicket)
+		checkInGroup.Post("/tickets/:ticket_id/undo", checkInController.UndoCheckIn)
+		checkInGroup.Get("/offline-manifest", checkInController.GetOfflineManifest)
+		checkInGroup.Post("/sync", checkInController.SyncCheckIns)
+		checkInGroup.Get("/scanners", checkInController.GetScanners)
+		checkInGroup.Post("/scanners", checkInController.GrantScanner)
+		checkInGroup.Delete("/scanners/:user_id", checkInController.RevokeScanner)
+	}
+}
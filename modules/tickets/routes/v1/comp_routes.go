@@ -0,0 +1,34 @@
+package routes
+
+import (
+	"ticket-zetu-api/logs/handler"
+	notification_service "ticket-zetu-api/modules/notifications/service"
+	comp_controller "ticket-zetu-api/modules/tickets/comps/controller"
+	comp_service "ticket-zetu-api/modules/tickets/comps/service"
+	ticket_credentials "ticket-zetu-api/modules/tickets/credentials"
+	inventory_service "ticket-zetu-api/modules/tickets/inventory/service"
+	mail_service "ticket-zetu-api/modules/users/authentication/mail"
+	"ticket-zetu-api/modules/users/authorization/service"
+	"ticket-zetu-api/modules/users/middleware"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+func SetupCompRoutes(router fiber.Router, db *gorm.DB, logHandler *handler.LogHandler, emailService mail_service.EmailService) {
+	authMiddleware := middleware.IsAuthenticated(db, logHandler)
+	authService := authorization_service.NewPermissionService(db)
+
+	notificationService := notification_service.NewNotificationService(db, authService)
+	compService := comp_service.NewCompService(db, authService, inventory_service.NewInventoryService(db), notificationService, emailService, ticket_credentials.DefaultKeyRing())
+	compController := comp_controller.NewCompController(compService, logHandler)
+
+	compGroup := router.Group("/comps", authMiddleware)
+	{
+		compGroup.Post("/claim", compController.ClaimComp)
+		compGroup.Post("/ticket-types/:ticket_type_id", compController.IssueComps)
+		compGroup.Post("/ticket-types/:ticket_type_id/csv", compController.IssueCompsCSV)
+		compGroup.Get("/ticket-types/:ticket_type_id", compController.GetComps)
+		compGroup.Delete("/:id", compController.RevokeComp)
+	}
+}
@@ -14,5 +14,16 @@ func SetupTicketMainRoutes(router fiber.Router, db *gorm.DB, logHandler *handler
 	// Call individual route setup functions
 	SetupTicketTypeRoutes(router, db, logHandler)
 	SetupPriceTierRoutes(router, db, logHandler)
+	SetupPricingRoutes(router, db, logHandler)
 	SetupDiscountRoutes(router, db, logHandler)
+	SetupOrderRoutes(router, db, logHandler)
+	SetupPurchaseLimitRoutes(router, db, logHandler)
+	SetupPaymentRoutes(router, db, logHandler)
+	SetupRefundRoutes(router, db, logHandler)
+	SetupTicketRoutes(router, db, logHandler)
+	SetupCheckInRoutes(router, db, logHandler)
+	SetupTransferRoutes(router, db, logHandler)
+	SetupResaleRoutes(router, db, logHandler)
+	SetupWaitlistRoutes(router, db, logHandler, emailService)
+	SetupCompRoutes(router, db, logHandler, emailService)
 }
@@ -0,0 +1,41 @@
+package routes
+
+import (
+	"ticket-zetu-api/logs/handler"
+	ticket_credentials "ticket-zetu-api/modules/tickets/credentials"
+	inventory_service "ticket-zetu-api/modules/tickets/inventory/service"
+	order_controller "ticket-zetu-api/modules/tickets/orders/controller"
+	order_service "ticket-zetu-api/modules/tickets/orders/service"
+	"ticket-zetu-api/modules/users/authorization/service"
+	"ticket-zetu-api/modules/users/middleware"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+func SetupOrderRoutes(router fiber.Router, db *gorm.DB, logHandler *handler.LogHandler) {
+	authMiddleware := middleware.IsAuthenticated(db, logHandler)
+	authService := authorization_service.NewPermissionService(db)
+
+	inventoryService := inventory_service.NewInventoryService(db)
+	orderService := order_service.NewOrderService(db, authService, inventoryService, ticket_credentials.DefaultKeyRing())
+	orderController := order_controller.NewOrderController(orderService, logHandler)
+	holdService := order_service.NewHoldService(db, authService, inventoryService)
+	holdController := order_controller.NewHoldController(holdService, logHandler)
+
+	orderGroup := router.Group("/orders", authMiddleware)
+	{
+		orderGroup.Get("/", orderController.GetUserOrders)
+		orderGroup.Post("/", orderController.CreateOrder)
+		orderGroup.Get("/:id", orderController.GetOrder)
+		orderGroup.Patch("/:id/cancel", orderController.CancelOrder)
+	}
+
+	holdGroup := router.Group("/holds", authMiddleware)
+	{
+		holdGroup.Post("/", holdController.CreateHold)
+		holdGroup.Get("/:id", holdController.GetHold)
+		holdGroup.Post("/:id/extend", holdController.ExtendHold)
+		holdGroup.Delete("/:id", holdController.ReleaseHold)
+	}
+}
@@ -0,0 +1,42 @@
+package routes
+
+import (
+	"ticket-zetu-api/logs/handler"
+	ticket_credentials "ticket-zetu-api/modules/tickets/credentials"
+	inventory_service "ticket-zetu-api/modules/tickets/inventory/service"
+	"ticket-zetu-api/modules/tickets/models/tickets"
+	order_service "ticket-zetu-api/modules/tickets/orders/service"
+	payment_controller "ticket-zetu-api/modules/tickets/payments/controller"
+	payment_providers "ticket-zetu-api/modules/tickets/payments/providers"
+	payment_service "ticket-zetu-api/modules/tickets/payments/service"
+	"ticket-zetu-api/modules/users/authorization/service"
+	"ticket-zetu-api/modules/users/middleware"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+func SetupPaymentRoutes(router fiber.Router, db *gorm.DB, logHandler *handler.LogHandler) {
+	authMiddleware := middleware.IsAuthenticated(db, logHandler)
+	authService := authorization_service.NewPermissionService(db)
+
+	inventoryService := inventory_service.NewInventoryService(db)
+	orderService := order_service.NewOrderService(db, authService, inventoryService, ticket_credentials.DefaultKeyRing())
+	paymentService := payment_service.NewPaymentService(db, authService, payment_providers.NewRegistryFromEnv(), orderService)
+	resaleService := newResaleService(db, authService)
+	paymentService.RegisterPurpose(tickets.PaymentForResale, payment_service.PurposeHandler{
+		Quote:   resaleService.QuotePurchase,
+		Fulfill: resaleService.FulfillPurchase,
+	})
+	paymentController := payment_controller.NewPaymentController(paymentService, logHandler)
+
+	// Provider callbacks authenticate with their own signatures, not a session
+	router.Post("/payments/webhooks/:provider", paymentController.HandleWebhook)
+
+	paymentGroup := router.Group("/payments", authMiddleware)
+	{
+		paymentGroup.Post("/", paymentController.InitiatePayment)
+		paymentGroup.Get("/:id", paymentController.GetPayment)
+		paymentGroup.Post("/:id/verify", paymentController.VerifyPayment)
+	}
+}
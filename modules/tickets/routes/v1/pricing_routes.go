@@ -0,0 +1,29 @@
+package routes
+
+import (
+	"ticket-zetu-api/logs/handler"
+	pricing_controller "ticket-zetu-api/modules/tickets/pricing/controller"
+	pricing_service "ticket-zetu-api/modules/tickets/pricing/service"
+	"ticket-zetu-api/modules/users/authorization/service"
+	"ticket-zetu-api/modules/users/middleware"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+func SetupPricingRoutes(router fiber.Router, db *gorm.DB, logHandler *handler.LogHandler) {
+	authMiddleware := middleware.IsAuthenticated(db, logHandler)
+	authService := authorization_service.NewPermissionService(db)
+
+	pricingService := pricing_service.NewPricingService(db, authService)
+	pricingController := pricing_controller.NewPricingController(pricingService, logHandler)
+
+	pricingGroup := router.Group("/pricing")
+	{
+		pricingGroup.Get("/ticket-types/:ticket_type_id/quote", pricingController.GetQuote)
+		pricingGroup.Get("/ticket-types/:ticket_type_id/rules", authMiddleware, pricingController.GetRules)
+		pricingGroup.Post("/ticket-types/:ticket_type_id/rules", authMiddleware, pricingController.CreateRule)
+		pricingGroup.Put("/rules/:id", authMiddleware, pricingController.UpdateRule)
+		pricingGroup.Delete("/rules/:id", authMiddleware, pricingController.DeleteRule)
+	}
+}
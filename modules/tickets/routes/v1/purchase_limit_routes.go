@@ -0,0 +1,29 @@
+package routes
+
+import (
+	"ticket-zetu-api/logs/handler"
+	purchase_limit_controller "ticket-zetu-api/modules/tickets/purchase_limits/controller"
+	purchase_limit_service "ticket-zetu-api/modules/tickets/purchase_limits/service"
+	"ticket-zetu-api/modules/users/authorization/service"
+	"ticket-zetu-api/modules/users/middleware"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+func SetupPurchaseLimitRoutes(router fiber.Router, db *gorm.DB, logHandler *handler.LogHandler) {
+	authMiddleware := middleware.IsAuthenticated(db, logHandler)
+	authService := authorization_service.NewPermissionService(db)
+
+	purchaseLimitService := purchase_limit_service.NewPurchaseLimitService(db, authService)
+	purchaseLimitController := purchase_limit_controller.NewPurchaseLimitController(purchaseLimitService, logHandler)
+
+	limitGroup := router.Group("/purchase-limits/events/:event_id", authMiddleware)
+	{
+		limitGroup.Get("/", purchaseLimitController.GetEventLimit)
+		limitGroup.Put("/", purchaseLimitController.SetEventLimit)
+		limitGroup.Get("/me", purchaseLimitController.GetAllowance)
+		limitGroup.Get("/users", purchaseLimitController.GetUserLimits)
+		limitGroup.Put("/users/:user_id", purchaseLimitController.SetUserOverride)
+	}
+}
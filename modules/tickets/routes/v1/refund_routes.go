@@ -0,0 +1,40 @@
+package routes
+
+import (
+	"ticket-zetu-api/logs/handler"
+	ticket_credentials "ticket-zetu-api/modules/tickets/credentials"
+	inventory_service "ticket-zetu-api/modules/tickets/inventory/service"
+	order_service "ticket-zetu-api/modules/tickets/orders/service"
+	payment_providers "ticket-zetu-api/modules/tickets/payments/providers"
+	payment_service "ticket-zetu-api/modules/tickets/payments/service"
+	refund_controller "ticket-zetu-api/modules/tickets/refunds/controller"
+	refund_service "ticket-zetu-api/modules/tickets/refunds/service"
+	"ticket-zetu-api/modules/users/authorization/service"
+	"ticket-zetu-api/modules/users/middleware"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+func SetupRefundRoutes(router fiber.Router, db *gorm.DB, logHandler *handler.LogHandler) {
+	authMiddleware := middleware.IsAuthenticated(db, logHandler)
+	authService := authorization_service.NewPermissionService(db)
+
+	inventoryService := inventory_service.NewInventoryService(db)
+	orderService := order_service.NewOrderService(db, authService, inventoryService, ticket_credentials.DefaultKeyRing())
+	paymentService := payment_service.NewPaymentService(db, authService, payment_providers.NewRegistryFromEnv(), orderService)
+	refundService := refund_service.NewRefundService(db, authService, inventoryService, paymentService)
+	refundController := refund_controller.NewRefundController(refundService, logHandler)
+
+	refundGroup := router.Group("/refunds", authMiddleware)
+	{
+		refundGroup.Get("/", refundController.GetUserRefunds)
+		refundGroup.Post("/", refundController.RequestRefund)
+		refundGroup.Get("/organizer", refundController.GetOrganizerRefunds)
+		refundGroup.Get("/policy/event/:event_id", refundController.GetRefundPolicy)
+		refundGroup.Put("/policy/event/:event_id", refundController.SetRefundPolicy)
+		refundGroup.Get("/:id", refundController.GetRefund)
+		refundGroup.Post("/:id/approve", refundController.ApproveRefund)
+		refundGroup.Post("/:id/reject", refundController.RejectRefund)
+	}
+}
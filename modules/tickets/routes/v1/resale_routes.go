@@ -0,0 +1,45 @@
+package routes
+
+import (
+	"ticket-zetu-api/logs/handler"
+	notification_service "ticket-zetu-api/modules/notifications/service"
+	ticket_credentials "ticket-zetu-api/modules/tickets/credentials"
+	inventory_service "ticket-zetu-api/modules/tickets/inventory/service"
+	resale_controller "ticket-zetu-api/modules/tickets/resale/controller"
+	resale_service "ticket-zetu-api/modules/tickets/resale/service"
+	transfer_service "ticket-zetu-api/modules/tickets/transfers/service"
+	"ticket-zetu-api/modules/users/authorization/service"
+	"ticket-zetu-api/modules/users/middleware"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+func SetupResaleRoutes(router fiber.Router, db *gorm.DB, logHandler *handler.LogHandler) {
+	authMiddleware := middleware.IsAuthenticated(db, logHandler)
+	authService := authorization_service.NewPermissionService(db)
+
+	resaleService := newResaleService(db, authService)
+	resaleController := resale_controller.NewResaleController(resaleService, logHandler)
+
+	resaleGroup := router.Group("/resale", authMiddleware)
+	{
+		resaleGroup.Get("/listings", resaleController.GetUserListings)
+		resaleGroup.Post("/listings", resaleController.ListTicket)
+		resaleGroup.Get("/listings/:id", resaleController.GetListing)
+		resaleGroup.Delete("/listings/:id", resaleController.CancelListing)
+		resaleGroup.Post("/listings/:id/reserve", resaleController.ReserveListing)
+		resaleGroup.Get("/events/:event_id/listings", resaleController.GetEventListings)
+		resaleGroup.Get("/payouts", resaleController.GetUserPayouts)
+		resaleGroup.Get("/policy/event/:event_id", resaleController.GetResalePolicy)
+		resaleGroup.Put("/policy/event/:event_id", resaleController.SetResalePolicy)
+	}
+}
+
+// newResaleService wires the resale service; the payment routes need one too
+// to fulfil paid resale purchases
+func newResaleService(db *gorm.DB, authService authorization_service.PermissionService) resale_service.ResaleService {
+	notificationService := notification_service.NewNotificationService(db, authService)
+	transferService := transfer_service.NewTransferService(db, authService, notificationService, ticket_credentials.DefaultKeyRing())
+	return resale_service.NewResaleService(db, authService, inventory_service.NewInventoryService(db), transferService)
+}
@@ -0,0 +1,29 @@
+package routes
+
+import (
+	"ticket-zetu-api/logs/handler"
+	ticket_credentials "ticket-zetu-api/modules/tickets/credentials"
+	ticket_controller "ticket-zetu-api/modules/tickets/ticket/controller"
+	ticket_service "ticket-zetu-api/modules/tickets/ticket/service"
+	"ticket-zetu-api/modules/users/authorization/service"
+	"ticket-zetu-api/modules/users/middleware"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+func SetupTicketRoutes(router fiber.Router, db *gorm.DB, logHandler *handler.LogHandler) {
+	authMiddleware := middleware.IsAuthenticated(db, logHandler)
+	authService := authorization_service.NewPermissionService(db)
+
+	ticketService := ticket_service.NewTicketService(db, authService, ticket_credentials.DefaultKeyRing())
+	ticketController := ticket_controller.NewTicketController(ticketService, logHandler)
+
+	ticketGroup := router.Group("/tickets", authMiddleware)
+	{
+		ticketGroup.Get("/", ticketController.GetUserTickets)
+		ticketGroup.Get("/:id", ticketController.GetTicket)
+		ticketGroup.Get("/:id/qr", ticketController.GetTicketQR)
+		ticketGroup.Put("/:id/seat", ticketController.SwapSeat)
+	}
+}
@@ -4,6 +4,7 @@ package routes
 import (
 	"ticket-zetu-api/logs/handler"
 
+	inventory_service "ticket-zetu-api/modules/tickets/inventory/service"
 	ticket_type_controller "ticket-zetu-api/modules/tickets/ticket_type/controller"
 	ticket_type_service "ticket-zetu-api/modules/tickets/ticket_type/service"
 	"ticket-zetu-api/modules/users/authorization/service"
@@ -17,7 +18,8 @@ func SetupTicketTypeRoutes(router fiber.Router, db *gorm.DB, logHandler *handler
 	authMiddleware := middleware.IsAuthenticated(db, logHandler)
 	authService := authorization_service.NewPermissionService(db)
 
-	ticketTypeService := ticket_type_service.NewTicketTypeService(db, authService)
+	inventoryService := inventory_service.NewInventoryService(db)
+	ticketTypeService := ticket_type_service.NewTicketTypeService(db, authService, inventoryService)
 	ticketTypeController := ticket_type_controller.NewTicketTypeController(ticketTypeService, logHandler)
 
 	ticketTypeGroup := router.Group("/ticket-types", authMiddleware)
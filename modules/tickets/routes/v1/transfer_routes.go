@@ -0,0 +1,33 @@
+package routes
+
+import (
+	"ticket-zetu-api/logs/handler"
+	notification_service "ticket-zetu-api/modules/notifications/service"
+	ticket_credentials "ticket-zetu-api/modules/tickets/credentials"
+	transfer_controller "ticket-zetu-api/modules/tickets/transfers/controller"
+	transfer_service "ticket-zetu-api/modules/tickets/transfers/service"
+	"ticket-zetu-api/modules/users/authorization/service"
+	"ticket-zetu-api/modules/users/middleware"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+func SetupTransferRoutes(router fiber.Router, db *gorm.DB, logHandler *handler.LogHandler) {
+	authMiddleware := middleware.IsAuthenticated(db, logHandler)
+	authService := authorization_service.NewPermissionService(db)
+
+	notificationService := notification_service.NewNotificationService(db, authService)
+	transferService := transfer_service.NewTransferService(db, authService, notificationService, ticket_credentials.DefaultKeyRing())
+	transferController := transfer_controller.NewTransferController(transferService, logHandler)
+
+	transferGroup := router.Group("/transfers", authMiddleware)
+	{
+		transferGroup.Get("/", transferController.GetTransfers)
+		transferGroup.Post("/", transferController.CreateTransfer)
+		transferGroup.Get("/ticket/:ticket_id", transferController.GetTicketTransferHistory)
+		transferGroup.Post("/:id/accept", transferController.AcceptTransfer)
+		transferGroup.Post("/:id/decline", transferController.DeclineTransfer)
+		transferGroup.Post("/:id/cancel", transferController.CancelTransfer)
+	}
+}
@@ -0,0 +1,32 @@
+package routes
+
+import (
+	"ticket-zetu-api/logs/handler"
+	notification_service "ticket-zetu-api/modules/notifications/service"
+	inventory_service "ticket-zetu-api/modules/tickets/inventory/service"
+	waitlist_controller "ticket-zetu-api/modules/tickets/waitlist/controller"
+	waitlist_service "ticket-zetu-api/modules/tickets/waitlist/service"
+	mail_service "ticket-zetu-api/modules/users/authentication/mail"
+	"ticket-zetu-api/modules/users/authorization/service"
+	"ticket-zetu-api/modules/users/middleware"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+func SetupWaitlistRoutes(router fiber.Router, db *gorm.DB, logHandler *handler.LogHandler, emailService mail_service.EmailService) {
+	authMiddleware := middleware.IsAuthenticated(db, logHandler)
+	authService := authorization_service.NewPermissionService(db)
+
+	notificationService := notification_service.NewNotificationService(db, authService)
+	waitlistService := waitlist_service.NewWaitlistService(db, authService, inventory_service.NewInventoryService(db), notificationService, emailService)
+	waitlistController := waitlist_controller.NewWaitlistController(waitlistService, logHandler)
+
+	waitlistGroup := router.Group("/waitlists", authMiddleware)
+	{
+		waitlistGroup.Post("/", waitlistController.JoinWaitlist)
+		waitlistGroup.Get("/me", waitlistController.GetUserEntries)
+		waitlistGroup.Delete("/:id", waitlistController.LeaveWaitlist)
+		waitlistGroup.Get("/events/:event_id/stats", waitlistController.GetEventWaitlistStats)
+	}
+}
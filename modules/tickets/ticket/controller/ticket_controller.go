@@ -0,0 +1,151 @@
+package ticket_controller
+
+import (
+	"ticket-zetu-api/logs/handler"
+	"ticket-zetu-api/modules/tickets/ticket/dto"
+	"ticket-zetu-api/modules/tickets/ticket/service"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+type TicketController struct {
+	service    ticket_service.TicketService
+	logHandler *handler.LogHandler
+	validator  *validator.Validate
+}
+
+func NewTicketController(service ticket_service.TicketService, logHandler *handler.LogHandler) *TicketController {
+	return &TicketController{
+		service:    service,
+		logHandler: logHandler,
+		validator:  validator.New(),
+	}
+}
+
+// GetUserTickets godoc
+// @Summary Get the current user's tickets
+// @Description Retrieves every ticket owned by the current user, newest first.
+// @Tags Tickets
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} map[string]interface{} "Tickets retrieved successfully"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /tickets [get]
+func (c *TicketController) GetUserTickets(ctx *fiber.Ctx) error {
+	userID := ctx.Locals("user_id").(string)
+
+	owned, err := c.service.GetUserTickets(userID)
+	if err != nil {
+		return c.handleError(ctx, err)
+	}
+
+	return c.logHandler.LogSuccess(ctx, owned, "Tickets retrieved successfully", false)
+}
+
+// GetTicket godoc
+// @Summary Get a ticket
+// @Description Retrieves one of the current user's tickets. Valid tickets include the signed credential encoded in their QR code.
+// @Tags Tickets
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Ticket ID"
+// @Success 200 {object} map[string]interface{} "Ticket retrieved successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid ticket ID"
+// @Failure 404 {object} map[string]interface{} "Ticket not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /tickets/{id} [get]
+func (c *TicketController) GetTicket(ctx *fiber.Ctx) error {
+	userID := ctx.Locals("user_id").(string)
+	id := ctx.Params("id")
+
+	ticket, err := c.service.GetTicket(userID, id)
+	if err != nil {
+		return c.handleError(ctx, err)
+	}
+
+	return c.logHandler.LogSuccess(ctx, ticket, "Ticket retrieved successfully", false)
+}
+
+// GetTicketQR godoc
+// @Summary Download a ticket's QR code
+// @Description Returns the QR code for one of the current user's valid tickets as a PNG or SVG image.
+// @Tags Tickets
+// @Produce png
+// @Produce image/svg+xml
+// @Security ApiKeyAuth
+// @Param id path string true "Ticket ID"
+// @Param format query string false "Image format (png, svg)" default(png)
+// @Param size query int false "Image size in pixels, up to 1024" default(256)
+// @Success 200 {file} file "QR code image"
+// @Failure 400 {object} map[string]interface{} "Invalid ticket ID or format"
+// @Failure 404 {object} map[string]interface{} "Ticket not found"
+// @Failure 409 {object} map[string]interface{} "Ticket is no longer valid"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /tickets/{id}/qr [get]
+func (c *TicketController) GetTicketQR(ctx *fiber.Ctx) error {
+	userID := ctx.Locals("user_id").(string)
+	id := ctx.Params("id")
+
+	image, contentType, err := c.service.GetTicketQR(userID, id, ctx.Query("format"), ctx.QueryInt("size"))
+	if err != nil {
+		return c.handleError(ctx, err)
+	}
+
+	ctx.Set(fiber.HeaderContentType, contentType)
+	ctx.Set(fiber.HeaderCacheControl, "private, no-store")
+	return ctx.Send(image)
+}
+
+// SwapSeat godoc
+// @Summary Move a ticket to another seat
+// @Description Lets the organizer of the ticket's event move a seated ticket to another seat at the venue. The new seat must be free for the event; the old seat is released. The ticket keeps its price and QR code.
+// @Tags Tickets
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Ticket ID"
+// @Param input body dto.SwapSeatInput true "New seat"
+// @Success 200 {object} map[string]interface{} "Ticket seat changed successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid request body, ticket without a seat or seat at another venue"
+// @Failure 404 {object} map[string]interface{} "Organizer, ticket, seat or seat reservation not found"
+// @Failure 409 {object} map[string]interface{} "Ticket no longer valid, event ended or seat taken"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /tickets/{id}/seat [put]
+func (c *TicketController) SwapSeat(ctx *fiber.Ctx) error {
+	userID := ctx.Locals("user_id").(string)
+	id := ctx.Params("id")
+
+	var input dto.SwapSeatInput
+	if err := ctx.BodyParser(&input); err != nil {
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, "Invalid request body"), fiber.StatusBadRequest)
+	}
+
+	if err := c.validator.Struct(input); err != nil {
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, err.Error()), fiber.StatusBadRequest)
+	}
+
+	ticket, err := c.service.SwapSeat(userID, id, input)
+	if err != nil {
+		return c.handleError(ctx, err)
+	}
+
+	return c.logHandler.LogSuccess(ctx, ticket, "Ticket seat changed successfully", true)
+}
+
+func (c *TicketController) handleError(ctx *fiber.Ctx, err error) error {
+	switch err.Error() {
+	case "invalid user ID format", "invalid ticket ID format", "unsupported QR code format",
+		"invalid seat ID format", "ticket has no seat", "ticket already has this seat",
+		"seat is not at the event's venue", "seat is not available for reservation":
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, err.Error()), fiber.StatusBadRequest)
+	case "ticket not found", "organizer not found", "seat not found", "seat reservation not found":
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusNotFound, err.Error()), fiber.StatusNotFound)
+	case "ticket is no longer valid", "event has already ended", "seat is already reserved for this event":
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusConflict, err.Error()), fiber.StatusConflict)
+	default:
+		return c.logHandler.LogError(ctx, err, fiber.StatusInternalServerError)
+	}
+}
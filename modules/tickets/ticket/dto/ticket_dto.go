@@ -0,0 +1,28 @@
+package dto
+
+import "time"
+
+// TicketResponse defines the response structure for a ticket owned by the current user
+type TicketResponse struct {
+	ID             string     `json:"id"`
+	TicketNumber   string     `json:"ticket_number"`
+	OrderID        string     `json:"order_id,omitempty"`
+	EventID        string     `json:"event_id"`
+	EventTitle     string     `json:"event_title,omitempty"`
+	TicketTypeID   string     `json:"ticket_type_id"`
+	TicketTypeName string     `json:"ticket_type_name,omitempty"`
+	SeatNumber     string     `json:"seat_number,omitempty"`
+	SeatSection    string     `json:"seat_section,omitempty"`
+	Status         string     `json:"status"`
+	ActualPrice    float64    `json:"actual_price"`
+	PurchaseTime   time.Time  `json:"purchase_time"`
+	CheckedInAt    *time.Time `json:"checked_in_at,omitempty"`
+	IsTransferable bool       `json:"is_transferable"`
+	// Credential is the signed payload encoded in the ticket's QR code
+	Credential string `json:"credential,omitempty"`
+}
+
+// SwapSeatInput defines the input structure for moving a ticket to another seat
+type SwapSeatInput struct {
+	SeatID string `json:"seat_id" example:"b3e5f7a9-1c3d-4e5f-8a9b-1c3d5e7f9a1b" validate:"required,uuid"`
+}
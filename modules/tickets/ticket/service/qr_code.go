@@ -0,0 +1,61 @@
+package ticket_service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/skip2/go-qrcode"
+)
+
+const (
+	defaultQRSize = 256
+	maxQRSize     = 1024
+)
+
+// renderQR encodes content as a PNG or SVG QR code of roughly size pixels square
+func renderQR(content, format string, size int) ([]byte, string, error) {
+	if size <= 0 {
+		size = defaultQRSize
+	}
+	if size > maxQRSize {
+		size = maxQRSize
+	}
+
+	code, err := qrcode.New(content, qrcode.Medium)
+	if err != nil {
+		return nil, "", err
+	}
+
+	switch format {
+	case "", "png":
+		image, err := code.PNG(size)
+		if err != nil {
+			return nil, "", err
+		}
+		return image, "image/png", nil
+	case "svg":
+		return renderSVG(code.Bitmap(), size), "image/svg+xml", nil
+	default:
+		return nil, "", errors.New("unsupported QR code format")
+	}
+}
+
+// renderSVG draws one path covering every dark module of the bitmap, which
+// already includes the quiet zone
+func renderSVG(bitmap [][]bool, size int) []byte {
+	var path strings.Builder
+	for y, row := range bitmap {
+		for x, dark := range row {
+			if dark {
+				fmt.Fprintf(&path, "M%d %dh1v1h-1z", x, y)
+			}
+		}
+	}
+
+	modules := len(bitmap)
+	return []byte(fmt.Sprintf(
+		`<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`+
+			`<rect width="100%%" height="100%%" fill="#fff"/><path fill="#000" d="%s"/></svg>`,
+		size, size, modules, modules, path.String()))
+}
@@ -0,0 +1,110 @@
+package ticket_service
+
+import (
+	"errors"
+	"ticket-zetu-api/modules/events/models/events"
+	"ticket-zetu-api/modules/events/models/seats"
+	seat_services "ticket-zetu-api/modules/events/seat_allocation/services"
+	organizers_services "ticket-zetu-api/modules/organizers/services"
+	"ticket-zetu-api/modules/tickets/models/tickets"
+	"ticket-zetu-api/modules/tickets/ticket/dto"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SwapSeat moves a seated ticket for one of the organizer's events to another
+// seat at the venue. The new seat must be free for the event and the old one
+// is released. The ticket keeps its price and credential.
+func (s *ticketService) SwapSeat(userID, ticketID string, input dto.SwapSeatInput) (*dto.TicketResponse, error) {
+	if _, err := uuid.Parse(ticketID); err != nil {
+		return nil, errors.New("invalid ticket ID format")
+	}
+	if _, err := uuid.Parse(input.SeatID); err != nil {
+		return nil, errors.New("invalid seat ID format")
+	}
+
+	organizer, err := organizers_services.GetUserOrganizer(s.db, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		var ticket tickets.Ticket
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND deleted_at IS NULL", ticketID).
+			First(&ticket).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("ticket not found")
+			}
+			return err
+		}
+
+		var event events.Event
+		if err := tx.Where("id = ? AND organizer_id = ? AND deleted_at IS NULL", ticket.EventID, organizer.ID).First(&event).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("ticket not found")
+			}
+			return err
+		}
+
+		if ticket.Status != tickets.TicketValid {
+			return errors.New("ticket is no longer valid")
+		}
+		if !time.Now().Before(event.EndTime) {
+			return errors.New("event has already ended")
+		}
+		if ticket.SeatID == "" {
+			return errors.New("ticket has no seat")
+		}
+		if ticket.SeatID == input.SeatID {
+			return errors.New("ticket already has this seat")
+		}
+
+		var seat seats.Seat
+		if err := tx.Where("id = ? AND deleted_at IS NULL", input.SeatID).First(&seat).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("seat not found")
+			}
+			return err
+		}
+		if seat.VenueID != event.VenueID {
+			return errors.New("seat is not at the event's venue")
+		}
+		if seat.Status != "available" {
+			return errors.New("seat is not available for reservation")
+		}
+
+		var reservation seats.SeatReservation
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("order_id = ? AND seat_id = ? AND status = ? AND deleted_at IS NULL", ticket.OrderID, ticket.SeatID, "confirmed").
+			First(&reservation).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("seat reservation not found")
+			}
+			return err
+		}
+		if err := seat_services.MoveBookedSeat(tx, &reservation, &seat); err != nil {
+			return err
+		}
+
+		return tx.Model(&ticket).UpdateColumns(map[string]interface{}{
+			"seat_id":      seat.ID,
+			"seat_number":  seat.SeatNumber,
+			"seat_section": seat.SeatSection,
+			"version":      gorm.Expr("version + 1"),
+			"updated_at":   time.Now(),
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var ticket tickets.Ticket
+	if err := s.db.Preload("Event").Preload("TicketType").First(&ticket, "id = ?", ticketID).Error; err != nil {
+		return nil, err
+	}
+	return toDTO(&ticket), nil
+}
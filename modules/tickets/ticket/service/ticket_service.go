@@ -0,0 +1,155 @@
+package ticket_service
+
+import (
+	"errors"
+	ticket_credentials "ticket-zetu-api/modules/tickets/credentials"
+	"ticket-zetu-api/modules/tickets/models/tickets"
+	"ticket-zetu-api/modules/tickets/ticket/dto"
+	"ticket-zetu-api/modules/users/authorization/service"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type TicketService interface {
+	GetUserTickets(userID string) ([]dto.TicketResponse, error)
+	GetTicket(userID, ticketID string) (*dto.TicketResponse, error)
+	GetTicketQR(userID, ticketID, format string, size int) ([]byte, string, error)
+	SwapSeat(userID, ticketID string, input dto.SwapSeatInput) (*dto.TicketResponse, error)
+}
+
+type ticketService struct {
+	db                   *gorm.DB
+	authorizationService authorization_service.PermissionService
+	keyRing              *ticket_credentials.KeyRing
+}
+
+func NewTicketService(db *gorm.DB, authService authorization_service.PermissionService, keyRing *ticket_credentials.KeyRing) TicketService {
+	return &ticketService{
+		db:                   db,
+		authorizationService: authService,
+		keyRing:              keyRing,
+	}
+}
+
+func (s *ticketService) GetUserTickets(userID string) ([]dto.TicketResponse, error) {
+	if _, err := uuid.Parse(userID); err != nil {
+		return nil, errors.New("invalid user ID format")
+	}
+
+	var owned []tickets.Ticket
+	if err := s.db.Preload("Event").Preload("TicketType").
+		Where("user_id = ?", userID).
+		Order("purchase_time DESC").
+		Find(&owned).Error; err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.TicketResponse, len(owned))
+	for i := range owned {
+		responses[i] = *toDTO(&owned[i])
+	}
+	return responses, nil
+}
+
+// GetTicket returns one of the user's tickets. Tickets that can still be
+// scanned include their signed credential.
+func (s *ticketService) GetTicket(userID, ticketID string) (*dto.TicketResponse, error) {
+	ticket, err := s.findUserTicket(userID, ticketID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := toDTO(ticket)
+	if ticket.Status == tickets.TicketValid {
+		credential, err := s.credential(ticket)
+		if err != nil {
+			return nil, err
+		}
+		response.Credential = credential
+	}
+	return response, nil
+}
+
+// GetTicketQR renders the ticket's credential as a QR code image
+func (s *ticketService) GetTicketQR(userID, ticketID, format string, size int) ([]byte, string, error) {
+	ticket, err := s.findUserTicket(userID, ticketID)
+	if err != nil {
+		return nil, "", err
+	}
+	if ticket.Status != tickets.TicketValid {
+		return nil, "", errors.New("ticket is no longer valid")
+	}
+
+	credential, err := s.credential(ticket)
+	if err != nil {
+		return nil, "", err
+	}
+	return renderQR(credential, format, size)
+}
+
+// credential rebuilds the ticket's signed credential. Tickets whose signing
+// key has been retired, or that predate signed credentials, are re-signed
+// with the active key; the new hash replaces the old one, so only the new
+// QR code will scan.
+func (s *ticketService) credential(ticket *tickets.Ticket) (string, error) {
+	credential, err := s.keyRing.Credential(ticket)
+	if err == nil {
+		return credential, nil
+	}
+	if !errors.Is(err, ticket_credentials.ErrNotReproducible) {
+		return "", err
+	}
+
+	credential, err = s.keyRing.Sign(ticket)
+	if err != nil {
+		return "", err
+	}
+	if err := s.db.Model(&tickets.Ticket{}).
+		Where("id = ?", ticket.ID).
+		UpdateColumns(map[string]interface{}{
+			"qr_code_hash":         ticket.QRCodeHash,
+			"credential_issued_at": ticket.CredentialIssuedAt,
+			"updated_at":           time.Now(),
+		}).Error; err != nil {
+		return "", err
+	}
+	return credential, nil
+}
+
+func (s *ticketService) findUserTicket(userID, ticketID string) (*tickets.Ticket, error) {
+	if _, err := uuid.Parse(ticketID); err != nil {
+		return nil, errors.New("invalid ticket ID format")
+	}
+
+	var ticket tickets.Ticket
+	if err := s.db.Preload("Event").Preload("TicketType").
+		Where("id = ? AND user_id = ?", ticketID, userID).
+		First(&ticket).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("ticket not found")
+		}
+		return nil, err
+	}
+	return &ticket, nil
+}
+
+func toDTO(ticket *tickets.Ticket) *dto.TicketResponse {
+	return &dto.TicketResponse{
+		ID:             ticket.ID,
+		TicketNumber:   ticket.TicketNumber,
+		OrderID:        ticket.OrderID,
+		EventID:        ticket.EventID,
+		EventTitle:     ticket.Event.Title,
+		TicketTypeID:   ticket.TicketTypeID,
+		TicketTypeName: ticket.TicketType.Name,
+		SeatNumber:     ticket.SeatNumber,
+		SeatSection:    ticket.SeatSection,
+		Status:         string(ticket.Status),
+		ActualPrice:    ticket.ActualPrice,
+		PurchaseTime:   ticket.PurchaseTime,
+		CheckedInAt:    ticket.CheckedInAt,
+		IsTransferable: ticket.IsTransferable,
+	}
+}
@@ -37,11 +37,14 @@ func (c *TicketTypeController) CreateTicketType(ctx *fiber.Ctx) error {
 		EventID:           input.EventID,
 		Name:              input.Name,
 		Description:       input.Description,
+		BasePrice:         input.BasePrice,
 		PriceModifier:     input.PriceModifier,
 		Benefits:          input.Benefits,
 		MaxTicketsPerUser: input.MaxTicketsPerUser,
 		Status:            input.Status,
 		IsDefault:         input.IsDefault,
+		TransfersDisabled: input.TransfersDisabled,
+		MaxTransfers:      input.MaxTransfers,
 		SalesStart:        input.SalesStart,
 		SalesEnd:          input.SalesEnd,
 		QuantityAvailable: input.QuantityAvailable,
@@ -78,6 +81,7 @@ func (c *TicketTypeController) CreateTicketType(ctx *fiber.Ctx) error {
 // @Success 200 {object} map[string]interface{} "TicketType updated successfully"
 // @Failure 400 {object} map[string]interface{} "Invalid request body"
 // @Failure 403 {object} map[string]interface{} "User lacks update permission"
+// @Failure 409 {object} map[string]interface{} "Quantity available is below tickets already allocated"
 // @Failure 500 {object} map[string]interface{} "Internal server error"
 // @Router /ticket-types/{id} [put]
 func (c *TicketTypeController) UpdateTicketType(ctx *fiber.Ctx) error {
@@ -97,11 +101,14 @@ func (c *TicketTypeController) UpdateTicketType(ctx *fiber.Ctx) error {
 	updateInput := dto.UpdateTicketTypeInput{
 		Name:              input.Name,
 		Description:       input.Description,
+		BasePrice:         input.BasePrice,
 		PriceModifier:     input.PriceModifier,
 		Benefits:          input.Benefits,
 		MaxTicketsPerUser: input.MaxTicketsPerUser,
 		Status:            input.Status,
 		IsDefault:         input.IsDefault,
+		TransfersDisabled: input.TransfersDisabled,
+		MaxTransfers:      input.MaxTransfers,
 		SalesStart:        input.SalesStart,
 		SalesEnd:          input.SalesEnd,
 		QuantityAvailable: input.QuantityAvailable,
@@ -120,6 +127,8 @@ func (c *TicketTypeController) UpdateTicketType(ctx *fiber.Ctx) error {
 			return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusForbidden, err.Error()), fiber.StatusForbidden)
 		case "ticket type not found":
 			return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusNotFound, err.Error()), fiber.StatusNotFound)
+		case "quantity available is below tickets already allocated":
+			return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusConflict, err.Error()), fiber.StatusConflict)
 		default:
 			return c.logHandler.LogError(ctx, err, fiber.StatusInternalServerError)
 		}
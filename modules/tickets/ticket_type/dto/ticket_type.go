@@ -2,26 +2,31 @@ package dto
 
 import (
 	//"ticket-zetu-api/modules/events/models/events"
+	pricing_dto "ticket-zetu-api/modules/tickets/pricing/dto"
 	"time"
 )
 
 type TicketTypeResponse struct {
-	ID                string              `json:"id"`
-	EventID           string              `json:"event_id"`
-	Name              string              `json:"name"`
-	Description       string              `json:"description"`
-	PriceModifier     float64             `json:"price_modifier"`
-	Benefits          string              `json:"benefits"`
-	MaxTicketsPerUser int                 `json:"max_tickets_per_user"`
-	Status            string              `json:"status"`
-	IsDefault         bool                `json:"is_default"`
-	SalesStart        time.Time           `json:"sales_start"`
-	SalesEnd          *time.Time          `json:"sales_end"`
-	QuantityAvailable *int                `json:"quantity_available"`
-	MinTicketsPerUser int                 `json:"min_tickets_per_user"`
-	CreatedAt         time.Time           `json:"created_at"`
-	UpdatedAt         time.Time           `json:"updated_at"`
-	PriceTiers        []PriceTierResponse `json:"price_tiers,omitempty"`
+	ID                string                  `json:"id"`
+	EventID           string                  `json:"event_id"`
+	Name              string                  `json:"name"`
+	Description       string                  `json:"description"`
+	BasePrice         float64                 `json:"base_price"`
+	PriceModifier     float64                 `json:"price_modifier"`
+	Benefits          string                  `json:"benefits"`
+	MaxTicketsPerUser int                     `json:"max_tickets_per_user"`
+	Status            string                  `json:"status"`
+	IsDefault         bool                    `json:"is_default"`
+	TransfersDisabled bool                    `json:"transfers_disabled"`
+	MaxTransfers      int                     `json:"max_transfers"`
+	SalesStart        time.Time               `json:"sales_start"`
+	SalesEnd          *time.Time              `json:"sales_end"`
+	QuantityAvailable *int                    `json:"quantity_available"`
+	MinTicketsPerUser int                     `json:"min_tickets_per_user"`
+	CreatedAt         time.Time               `json:"created_at"`
+	UpdatedAt         time.Time               `json:"updated_at"`
+	PriceTiers        []PriceTierResponse     `json:"price_tiers,omitempty"`
+	Pricing           *pricing_dto.PriceQuote `json:"pricing,omitempty"`
 	//Event             events.Event `json:"event,omitempty"`
 }
 
@@ -29,11 +34,14 @@ type CreateTicketTypeInput struct {
 	EventID           string     `json:"event_id" binding:"required"`
 	Name              string     `json:"name" binding:"required"`
 	Description       string     `json:"description"`
+	BasePrice         float64    `json:"base_price" validate:"gte=0"`
 	PriceModifier     float64    `json:"price_modifier" binding:"required,gt=0"`
 	Benefits          string     `json:"benefits"`
 	MaxTicketsPerUser int        `json:"max_tickets_per_user" binding:"required,gte=1"`
 	Status            string     `json:"status" binding:"required,oneof=active inactive archived"`
 	IsDefault         bool       `json:"is_default"`
+	TransfersDisabled bool       `json:"transfers_disabled"`
+	MaxTransfers      int        `json:"max_transfers" validate:"gte=0"`
 	SalesStart        time.Time  `json:"sales_start" binding:"required"`
 	SalesEnd          *time.Time `json:"sales_end"`
 	QuantityAvailable *int       `json:"quantity_available"`
@@ -44,11 +52,14 @@ type UpdateTicketTypeInput struct {
 	EventID           string     `json:"event_id" binding:"required"`
 	Name              string     `json:"name" binding:"required"`
 	Description       string     `json:"description"`
+	BasePrice         float64    `json:"base_price" validate:"gte=0"`
 	PriceModifier     float64    `json:"price_modifier" binding:"required,gt=0"`
 	Benefits          string     `json:"benefits"`
 	MaxTicketsPerUser int        `json:"max_tickets_per_user" binding:"required,gte=1"`
 	Status            string     `json:"status" binding:"required,oneof=active inactive archived"`
 	IsDefault         bool       `json:"is_default"`
+	TransfersDisabled bool       `json:"transfers_disabled"`
+	MaxTransfers      int        `json:"max_transfers" validate:"gte=0"`
 	SalesStart        time.Time  `json:"sales_start" binding:"required"`
 	SalesEnd          *time.Time `json:"sales_end"`
 	QuantityAvailable *int       `json:"quantity_available"`
@@ -60,11 +71,14 @@ type CreateTicketTypeInputData struct {
 	EventID           string     `json:"event_id" example:"a1d3c4e6-89ab-44ce-8e65-123456789abc" validate:"required,uuid"`
 	Name              string     `json:"name" example:"VIP Ticket" validate:"required,min=2,max=100"`
 	Description       string     `json:"description,omitempty" example:"Access to VIP lounge and priority entry"`
+	BasePrice         float64    `json:"base_price" example:"2500.00" validate:"gte=0"`
 	PriceModifier     float64    `json:"price_modifier" example:"1.5" validate:"required,gt=0"`
 	Benefits          string     `json:"benefits,omitempty" example:"Free drinks, front row seats"`
 	MaxTicketsPerUser int        `json:"max_tickets_per_user" example:"5" validate:"required,gte=1"`
 	Status            string     `json:"status" example:"active" validate:"required,oneof=active inactive archived"`
 	IsDefault         bool       `json:"is_default" example:"false"`
+	TransfersDisabled bool       `json:"transfers_disabled" example:"false"`
+	MaxTransfers      int        `json:"max_transfers" example:"2" validate:"gte=0"`
 	SalesStart        time.Time  `json:"sales_start" example:"2025-07-01T09:00:00Z" validate:"required"`
 	SalesEnd          *time.Time `json:"sales_end,omitempty" example:"2025-08-01T23:59:59Z"`
 	QuantityAvailable *int       `json:"quantity_available,omitempty" example:"100"`
@@ -48,9 +48,10 @@ func (s *ticketTypeService) GetAllTicketTypesForOrganization(userID, fields stri
 		return nil, err
 	}
 
+	prices := s.currentPrices(ticketTypes...)
 	responses := make([]dto.TicketTypeResponse, 0, len(ticketTypes))
 	for _, tt := range ticketTypes {
-		resp := s.toDTO(&tt)
+		resp := s.toDTO(&tt, prices[tt.ID])
 		if resp != nil {
 			responses = append(responses, *resp)
 		}
@@ -103,7 +104,7 @@ func (s *ticketTypeService) GetTicketType(userID, id, fields string) (*dto.Ticke
 		return nil, err
 	}
 
-	return s.toDTO(&ticketType), nil
+	return s.toDTO(&ticketType, s.currentPrices(ticketType)[ticketType.ID]), nil
 }
 
 func (s *ticketTypeService) GetTicketTypes(userID, eventID, fields string) ([]dto.TicketTypeResponse, error) {
@@ -156,9 +157,10 @@ func (s *ticketTypeService) GetTicketTypes(userID, eventID, fields string) ([]dt
 		return nil, err
 	}
 
+	prices := s.currentPrices(ticketTypes...)
 	responses := make([]dto.TicketTypeResponse, 0, len(ticketTypes))
 	for _, tt := range ticketTypes {
-		resp := s.toDTO(&tt)
+		resp := s.toDTO(&tt, prices[tt.ID])
 		if resp != nil {
 			responses = append(responses, *resp)
 		}
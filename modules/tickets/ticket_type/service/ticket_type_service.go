@@ -2,9 +2,13 @@ package ticket_type_service
 
 import (
 	"errors"
+	"log"
 	"ticket-zetu-api/modules/events/models/events"
 	organizers "ticket-zetu-api/modules/organizers/models"
+	inventory_service "ticket-zetu-api/modules/tickets/inventory/service"
 	"ticket-zetu-api/modules/tickets/models/tickets"
+	pricing_dto "ticket-zetu-api/modules/tickets/pricing/dto"
+	pricing_service "ticket-zetu-api/modules/tickets/pricing/service"
 	"ticket-zetu-api/modules/tickets/ticket_type/dto"
 	"ticket-zetu-api/modules/users/authorization/service"
 	"time"
@@ -28,12 +32,14 @@ type TicketTypeService interface {
 type ticketTypeService struct {
 	db                   *gorm.DB
 	authorizationService authorization_service.PermissionService
+	inventoryService     inventory_service.InventoryService
 }
 
-func NewTicketTypeService(db *gorm.DB, authService authorization_service.PermissionService) TicketTypeService {
+func NewTicketTypeService(db *gorm.DB, authService authorization_service.PermissionService, inventoryService inventory_service.InventoryService) TicketTypeService {
 	return &ticketTypeService{
 		db:                   db,
 		authorizationService: authService,
+		inventoryService:     inventoryService,
 	}
 }
 
@@ -48,7 +54,17 @@ func (s *ticketTypeService) HasPermission(userID, permission string) (bool, erro
 	return hasPerm, nil
 }
 
-func (s *ticketTypeService) toDTO(ticketType *tickets.TicketType) *dto.TicketTypeResponse {
+// currentPrices quotes ticket types for display, in one pass for a whole
+// list. A failure is logged and leaves the prices out of the response.
+func (s *ticketTypeService) currentPrices(ticketTypes ...tickets.TicketType) map[string]*pricing_dto.PriceQuote {
+	quotes, err := pricing_service.ResolveAll(s.db, ticketTypes, time.Now())
+	if err != nil {
+		log.Printf("Failed to price ticket types: %v", err)
+	}
+	return quotes
+}
+
+func (s *ticketTypeService) toDTO(ticketType *tickets.TicketType, pricing *pricing_dto.PriceQuote) *dto.TicketTypeResponse {
 	var priceTiers []tickets.PriceTier
 	if err := s.db.Model(ticketType).Association("PriceTiers").Find(&priceTiers); err != nil {
 		// Log error if needed, but don't fail the response
@@ -59,22 +75,33 @@ func (s *ticketTypeService) toDTO(ticketType *tickets.TicketType) *dto.TicketTyp
 		priceTierResponses[i] = *s.toPriceTierDTO(&pt)
 	}
 
+	// Ticket types without a stock row have unlimited capacity
+	var quantityAvailable *int
+	if stock, err := s.inventoryService.GetStock(nil, ticketType.ID); err == nil {
+		quantityAvailable = &stock.AvailableStock
+	}
+
 	return &dto.TicketTypeResponse{
 		ID:                ticketType.ID,
 		EventID:           ticketType.EventID,
 		Name:              ticketType.Name,
 		Description:       ticketType.Description,
+		BasePrice:         ticketType.BasePrice,
 		PriceModifier:     ticketType.PriceModifier,
 		Benefits:          ticketType.Benefits,
 		MaxTicketsPerUser: ticketType.MaxTicketsPerUser,
 		MinTicketsPerUser: ticketType.MinTicketsPerUser,
 		Status:            string(ticketType.Status),
 		IsDefault:         ticketType.IsDefault,
+		TransfersDisabled: ticketType.TransfersDisabled,
+		MaxTransfers:      ticketType.MaxTransfers,
 		SalesStart:        ticketType.SalesStart,
 		SalesEnd:          ticketType.SalesEnd,
+		QuantityAvailable: quantityAvailable,
 		CreatedAt:         ticketType.CreatedAt,
 		UpdatedAt:         ticketType.UpdatedAt,
 		PriceTiers:        priceTierResponses,
+		Pricing:           pricing,
 	}
 }
 
@@ -111,14 +138,16 @@ var validTicketTypeFields = map[string]bool{
 	"event_id":             true,
 	"name":                 true,
 	"description":          true,
+	"base_price":           true,
 	"price_modifier":       true,
 	"benefits":             true,
 	"max_tickets_per_user": true,
 	"status":               true,
 	"is_default":           true,
+	"transfers_disabled":   true,
+	"max_transfers":        true,
 	"sales_start":          true,
 	"sales_end":            true,
-	"quantity_available":   true,
 	"min_tickets_per_user": true,
 	"created_at":           true,
 	"updated_at":           true,
@@ -168,11 +197,14 @@ func (s *ticketTypeService) CreateTicketType(userID string, input dto.CreateTick
 		OrganizerID:       organizer.ID,
 		Name:              input.Name,
 		Description:       input.Description,
+		BasePrice:         input.BasePrice,
 		PriceModifier:     input.PriceModifier,
 		Benefits:          input.Benefits,
 		MaxTicketsPerUser: input.MaxTicketsPerUser,
 		Status:            tickets.TicketTypeStatus(input.Status),
 		IsDefault:         input.IsDefault,
+		TransfersDisabled: input.TransfersDisabled,
+		MaxTransfers:      input.MaxTransfers,
 		SalesStart:        input.SalesStart,
 		SalesEnd:          input.SalesEnd,
 		MinTicketsPerUser: input.MinTicketsPerUser,
@@ -180,11 +212,22 @@ func (s *ticketTypeService) CreateTicketType(userID string, input dto.CreateTick
 		UpdatedAt:         time.Now(),
 	}
 
-	if err := s.db.Create(ticketType).Error; err != nil {
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(ticketType).Error; err != nil {
+			return err
+		}
+		if input.QuantityAvailable != nil {
+			if _, err := s.inventoryService.CreateStock(tx, ticketType, *input.QuantityAvailable); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	return s.toDTO(ticketType), nil
+	return s.toDTO(ticketType, s.currentPrices(*ticketType)[ticketType.ID]), nil
 }
 
 func (s *ticketTypeService) UpdateTicketType(userID string, id string, input dto.UpdateTicketTypeInput) (*dto.TicketTypeResponse, error) {
@@ -227,11 +270,14 @@ func (s *ticketTypeService) UpdateTicketType(userID string, id string, input dto
 	// Update fields
 	ticketType.Name = input.Name
 	ticketType.Description = input.Description
+	ticketType.BasePrice = input.BasePrice
 	ticketType.PriceModifier = input.PriceModifier
 	ticketType.Benefits = input.Benefits
 	ticketType.MaxTicketsPerUser = input.MaxTicketsPerUser
 	ticketType.Status = tickets.TicketTypeStatus(input.Status)
 	ticketType.IsDefault = input.IsDefault
+	ticketType.TransfersDisabled = input.TransfersDisabled
+	ticketType.MaxTransfers = input.MaxTransfers
 	ticketType.SalesStart = input.SalesStart
 	ticketType.SalesEnd = input.SalesEnd
 	ticketType.MinTicketsPerUser = input.MinTicketsPerUser
@@ -239,11 +285,28 @@ func (s *ticketTypeService) UpdateTicketType(userID string, id string, input dto
 	ticketType.UpdatedAt = time.Now()
 	ticketType.Version++
 
-	if err := s.db.Save(&ticketType).Error; err != nil {
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&ticketType).Error; err != nil {
+			return err
+		}
+		if input.QuantityAvailable == nil {
+			return nil
+		}
+
+		err := s.inventoryService.SetTotalStock(tx, ticketType.ID, *input.QuantityAvailable)
+		if errors.Is(err, inventory_service.ErrStockNotFound) {
+			_, err = s.inventoryService.CreateStock(tx, &ticketType, *input.QuantityAvailable)
+		}
+		if errors.Is(err, inventory_service.ErrInsufficientStock) {
+			return errors.New("quantity available is below tickets already allocated")
+		}
+		return err
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	return s.toDTO(&ticketType), nil
+	return s.toDTO(&ticketType, s.currentPrices(ticketType)[ticketType.ID]), nil
 }
 
 func (s *ticketTypeService) DeleteTicketType(userID, id string) error {
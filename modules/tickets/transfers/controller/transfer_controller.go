@@ -0,0 +1,207 @@
+package transfer_controller
+
+import (
+	"ticket-zetu-api/logs/handler"
+	"ticket-zetu-api/modules/tickets/transfers/dto"
+	"ticket-zetu-api/modules/tickets/transfers/service"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+type TransferController struct {
+	service    transfer_service.TransferService
+	logHandler *handler.LogHandler
+	validator  *validator.Validate
+}
+
+func NewTransferController(service transfer_service.TransferService, logHandler *handler.LogHandler) *TransferController {
+	return &TransferController{
+		service:    service,
+		logHandler: logHandler,
+		validator:  validator.New(),
+	}
+}
+
+// CreateTransfer godoc
+// @Summary Offer a ticket to another user
+// @Description Offers one of the current user's valid tickets to another user by username or email. The ticket changes hands only when the recipient accepts. Offers expire after 72 hours or when the event starts.
+// @Tags Transfers
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param input body dto.CreateTransferInput true "Transfer details"
+// @Success 200 {object} map[string]interface{} "Transfer offered successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid request body or ticket not transferable"
+// @Failure 404 {object} map[string]interface{} "Ticket or recipient not found"
+// @Failure 409 {object} map[string]interface{} "Ticket already has a pending transfer"
+// @Failure 422 {object} map[string]interface{} "Transfers disabled or transfer limit reached"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /transfers [post]
+func (c *TransferController) CreateTransfer(ctx *fiber.Ctx) error {
+	userID := ctx.Locals("user_id").(string)
+
+	var input dto.CreateTransferInput
+	if err := ctx.BodyParser(&input); err != nil {
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, "Invalid request body"), fiber.StatusBadRequest)
+	}
+
+	if err := c.validator.Struct(input); err != nil {
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, err.Error()), fiber.StatusBadRequest)
+	}
+
+	transfer, err := c.service.CreateTransfer(userID, input)
+	if err != nil {
+		return c.handleError(ctx, err)
+	}
+
+	return c.logHandler.LogSuccess(ctx, transfer, "Transfer offered successfully", true)
+}
+
+// GetTransfers godoc
+// @Summary Get the current user's ticket transfers
+// @Description Retrieves transfers sent or received by the current user, newest first.
+// @Tags Transfers
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param direction query string false "Transfer direction (incoming, outgoing)"
+// @Success 200 {object} map[string]interface{} "Transfers retrieved successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid transfer direction"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /transfers [get]
+func (c *TransferController) GetTransfers(ctx *fiber.Ctx) error {
+	userID := ctx.Locals("user_id").(string)
+
+	transfers, err := c.service.GetTransfers(userID, ctx.Query("direction"))
+	if err != nil {
+		return c.handleError(ctx, err)
+	}
+
+	return c.logHandler.LogSuccess(ctx, transfers, "Transfers retrieved successfully", false)
+}
+
+// GetTicketTransferHistory godoc
+// @Summary Get a ticket's transfer history
+// @Description Retrieves the accepted transfers of one of the current user's tickets, oldest first.
+// @Tags Transfers
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param ticket_id path string true "Ticket ID"
+// @Success 200 {object} map[string]interface{} "Transfer history retrieved successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid ticket ID"
+// @Failure 404 {object} map[string]interface{} "Ticket not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /transfers/ticket/{ticket_id} [get]
+func (c *TransferController) GetTicketTransferHistory(ctx *fiber.Ctx) error {
+	userID := ctx.Locals("user_id").(string)
+	ticketID := ctx.Params("ticket_id")
+
+	transfers, err := c.service.GetTicketTransferHistory(userID, ticketID)
+	if err != nil {
+		return c.handleError(ctx, err)
+	}
+
+	return c.logHandler.LogSuccess(ctx, transfers, "Transfer history retrieved successfully", false)
+}
+
+// AcceptTransfer godoc
+// @Summary Accept a ticket transfer
+// @Description Accepts a pending transfer offered to the current user. The ticket moves to the recipient and gets a new QR code; the sender's QR code stops working.
+// @Tags Transfers
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Transfer ID"
+// @Success 200 {object} map[string]interface{} "Transfer accepted successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid transfer ID or ticket not transferable"
+// @Failure 404 {object} map[string]interface{} "Transfer not found"
+// @Failure 409 {object} map[string]interface{} "Transfer is not pending"
+// @Failure 410 {object} map[string]interface{} "Transfer has expired"
+// @Failure 422 {object} map[string]interface{} "Transfers disabled or transfer limit reached"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /transfers/{id}/accept [post]
+func (c *TransferController) AcceptTransfer(ctx *fiber.Ctx) error {
+	userID := ctx.Locals("user_id").(string)
+	id := ctx.Params("id")
+
+	transfer, err := c.service.AcceptTransfer(userID, id)
+	if err != nil {
+		return c.handleError(ctx, err)
+	}
+
+	return c.logHandler.LogSuccess(ctx, transfer, "Transfer accepted successfully", true)
+}
+
+// DeclineTransfer godoc
+// @Summary Decline a ticket transfer
+// @Description Declines a pending transfer offered to the current user. The ticket stays with the sender.
+// @Tags Transfers
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Transfer ID"
+// @Success 200 {object} map[string]interface{} "Transfer declined successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid transfer ID"
+// @Failure 404 {object} map[string]interface{} "Transfer not found"
+// @Failure 409 {object} map[string]interface{} "Transfer is not pending"
+// @Failure 410 {object} map[string]interface{} "Transfer has expired"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /transfers/{id}/decline [post]
+func (c *TransferController) DeclineTransfer(ctx *fiber.Ctx) error {
+	userID := ctx.Locals("user_id").(string)
+	id := ctx.Params("id")
+
+	transfer, err := c.service.DeclineTransfer(userID, id)
+	if err != nil {
+		return c.handleError(ctx, err)
+	}
+
+	return c.logHandler.LogSuccess(ctx, transfer, "Transfer declined successfully", true)
+}
+
+// CancelTransfer godoc
+// @Summary Cancel a ticket transfer
+// @Description Withdraws a pending transfer sent by the current user.
+// @Tags Transfers
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Transfer ID"
+// @Success 200 {object} map[string]interface{} "Transfer canceled successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid transfer ID"
+// @Failure 404 {object} map[string]interface{} "Transfer not found"
+// @Failure 409 {object} map[string]interface{} "Transfer is not pending"
+// @Failure 410 {object} map[string]interface{} "Transfer has expired"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /transfers/{id}/cancel [post]
+func (c *TransferController) CancelTransfer(ctx *fiber.Ctx) error {
+	userID := ctx.Locals("user_id").(string)
+	id := ctx.Params("id")
+
+	transfer, err := c.service.CancelTransfer(userID, id)
+	if err != nil {
+		return c.handleError(ctx, err)
+	}
+
+	return c.logHandler.LogSuccess(ctx, transfer, "Transfer canceled successfully", true)
+}
+
+func (c *TransferController) handleError(ctx *fiber.Ctx, err error) error {
+	switch err.Error() {
+	case "invalid user ID format", "invalid ticket ID format", "invalid transfer ID format", "invalid transfer direction",
+		"cannot transfer a ticket to yourself", "only valid tickets can be transferred", "event has already started":
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, err.Error()), fiber.StatusBadRequest)
+	case "ticket not found", "recipient not found", "transfer not found", "event not found":
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusNotFound, err.Error()), fiber.StatusNotFound)
+	case "ticket already has a pending transfer", "ticket is listed for resale", "transfer is not pending", "ticket is no longer owned by the sender":
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusConflict, err.Error()), fiber.StatusConflict)
+	case "transfer has expired":
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusGone, err.Error()), fiber.StatusGone)
+	case "transfers are disabled for this ticket", "ticket has reached its transfer limit":
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusUnprocessableEntity, err.Error()), fiber.StatusUnprocessableEntity)
+	default:
+		return c.logHandler.LogError(ctx, err, fiber.StatusInternalServerError)
+	}
+}
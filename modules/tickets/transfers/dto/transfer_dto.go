@@ -0,0 +1,27 @@
+package dto
+
+import "time"
+
+// CreateTransferInput defines the input structure for offering a ticket to another user.
+// Recipient is the other user's username or email address.
+type CreateTransferInput struct {
+	TicketID  string `json:"ticket_id" example:"a1d3c4e6-89ab-44ce-8e65-123456789abc" validate:"required,uuid"`
+	Recipient string `json:"recipient" example:"jane_doe" validate:"required,max=255"`
+	Message   string `json:"message,omitempty" example:"Enjoy the show!" validate:"omitempty,max=255"`
+}
+
+// TransferResponse defines the response structure for a ticket transfer
+type TransferResponse struct {
+	ID           string     `json:"id"`
+	TicketID     string     `json:"ticket_id"`
+	EventID      string     `json:"event_id,omitempty"`
+	FromUserID   string     `json:"from_user_id"`
+	FromUsername string     `json:"from_username,omitempty"`
+	ToUserID     string     `json:"to_user_id"`
+	ToUsername   string     `json:"to_username,omitempty"`
+	Status       string     `json:"status"`
+	Message      string     `json:"message,omitempty"`
+	ExpiresAt    time.Time  `json:"expires_at"`
+	RespondedAt  *time.Time `json:"responded_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
@@ -0,0 +1,111 @@
+package transfer_service
+
+import (
+	"errors"
+	"ticket-zetu-api/modules/tickets/models/tickets"
+	"ticket-zetu-api/modules/tickets/transfers/dto"
+	"ticket-zetu-api/modules/users/models/members"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// GetTransfers lists the user's incoming or outgoing transfers, or both when direction is empty
+func (s *transferService) GetTransfers(userID, direction string) ([]dto.TransferResponse, error) {
+	if _, err := uuid.Parse(userID); err != nil {
+		return nil, errors.New("invalid user ID format")
+	}
+
+	query := s.db.Preload("Ticket")
+	switch direction {
+	case "incoming":
+		query = query.Where("to_user_id = ?", userID)
+	case "outgoing":
+		query = query.Where("from_user_id = ?", userID)
+	case "":
+		query = query.Where("to_user_id = ? OR from_user_id = ?", userID, userID)
+	default:
+		return nil, errors.New("invalid transfer direction")
+	}
+
+	var transfers []tickets.TicketTransfer
+	if err := query.Order("created_at DESC").Find(&transfers).Error; err != nil {
+		return nil, err
+	}
+	return s.toDTOs(transfers)
+}
+
+// GetTicketTransferHistory lists every completed change of hands for a ticket the user owns
+func (s *transferService) GetTicketTransferHistory(userID, ticketID string) ([]dto.TransferResponse, error) {
+	if _, err := uuid.Parse(ticketID); err != nil {
+		return nil, errors.New("invalid ticket ID format")
+	}
+
+	var count int64
+	if err := s.db.Model(&tickets.Ticket{}).Where("id = ? AND user_id = ?", ticketID, userID).Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return nil, errors.New("ticket not found")
+	}
+
+	var transfers []tickets.TicketTransfer
+	if err := s.db.Preload("Ticket").
+		Where("ticket_id = ? AND status = ?", ticketID, tickets.TransferAccepted).
+		Order("responded_at ASC").
+		Find(&transfers).Error; err != nil {
+		return nil, err
+	}
+	return s.toDTOs(transfers)
+}
+
+func (s *transferService) getTransfer(transferID string) (*dto.TransferResponse, error) {
+	var transfer tickets.TicketTransfer
+	if err := s.db.Preload("Ticket").Where("id = ?", transferID).First(&transfer).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("transfer not found")
+		}
+		return nil, err
+	}
+	responses, err := s.toDTOs([]tickets.TicketTransfer{transfer})
+	if err != nil {
+		return nil, err
+	}
+	return &responses[0], nil
+}
+
+func (s *transferService) toDTOs(transfers []tickets.TicketTransfer) ([]dto.TransferResponse, error) {
+	userIDs := make([]string, 0, len(transfers)*2)
+	for _, transfer := range transfers {
+		userIDs = append(userIDs, transfer.FromUserID, transfer.ToUserID)
+	}
+	usernames := make(map[string]string)
+	if len(userIDs) > 0 {
+		var users []members.User
+		if err := s.db.Select("id", "username").Where("id IN ?", userIDs).Find(&users).Error; err != nil {
+			return nil, err
+		}
+		for _, user := range users {
+			usernames[user.ID] = user.Username
+		}
+	}
+
+	responses := make([]dto.TransferResponse, len(transfers))
+	for i, transfer := range transfers {
+		responses[i] = dto.TransferResponse{
+			ID:           transfer.ID,
+			TicketID:     transfer.TicketID,
+			EventID:      transfer.Ticket.EventID,
+			FromUserID:   transfer.FromUserID,
+			FromUsername: usernames[transfer.FromUserID],
+			ToUserID:     transfer.ToUserID,
+			ToUsername:   usernames[transfer.ToUserID],
+			Status:       string(transfer.Status),
+			Message:      transfer.Message,
+			ExpiresAt:    transfer.ExpiresAt,
+			RespondedAt:  transfer.RespondedAt,
+			CreatedAt:    transfer.CreatedAt,
+		}
+	}
+	return responses, nil
+}
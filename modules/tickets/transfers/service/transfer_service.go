@@ -0,0 +1,329 @@
+package transfer_service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"ticket-zetu-api/modules/events/models/events"
+	notification_service "ticket-zetu-api/modules/notifications/service"
+	ticket_credentials "ticket-zetu-api/modules/tickets/credentials"
+	"ticket-zetu-api/modules/tickets/models/tickets"
+	"ticket-zetu-api/modules/tickets/transfers/dto"
+	"ticket-zetu-api/modules/users/authorization/service"
+	"ticket-zetu-api/modules/users/models/members"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// transferOfferWindow is how long a recipient has to accept, unless the event starts sooner
+const transferOfferWindow = 72 * time.Hour
+
+var errTransferExpired = errors.New("transfer has expired")
+
+type TransferService interface {
+	CreateTransfer(userID string, input dto.CreateTransferInput) (*dto.TransferResponse, error)
+	AcceptTransfer(userID, transferID string) (*dto.TransferResponse, error)
+	DeclineTransfer(userID, transferID string) (*dto.TransferResponse, error)
+	CancelTransfer(userID, transferID string) (*dto.TransferResponse, error)
+	GetTransfers(userID, direction string) ([]dto.TransferResponse, error)
+	GetTicketTransferHistory(userID, ticketID string) ([]dto.TransferResponse, error)
+	ReassignTicket(tx *gorm.DB, ticketID, fromUserID, toUserID string) (*tickets.Ticket, error)
+}
+
+type transferService struct {
+	db                   *gorm.DB
+	authorizationService authorization_service.PermissionService
+	notificationService  notification_service.NotificationService
+	keyRing              *ticket_credentials.KeyRing
+}
+
+func NewTransferService(db *gorm.DB, authService authorization_service.PermissionService, notificationService notification_service.NotificationService, keyRing *ticket_credentials.KeyRing) TransferService {
+	return &transferService{
+		db:                   db,
+		authorizationService: authService,
+		notificationService:  notificationService,
+		keyRing:              keyRing,
+	}
+}
+
+// CreateTransfer offers one of the user's tickets to another user. Ownership
+// does not change until the recipient accepts.
+func (s *transferService) CreateTransfer(userID string, input dto.CreateTransferInput) (*dto.TransferResponse, error) {
+	if _, err := uuid.Parse(userID); err != nil {
+		return nil, errors.New("invalid user ID format")
+	}
+	if _, err := uuid.Parse(input.TicketID); err != nil {
+		return nil, errors.New("invalid ticket ID format")
+	}
+
+	recipient, err := s.findRecipient(strings.TrimSpace(input.Recipient))
+	if err != nil {
+		return nil, err
+	}
+	if recipient.ID == userID {
+		return nil, errors.New("cannot transfer a ticket to yourself")
+	}
+
+	var transfer tickets.TicketTransfer
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		// Locking the ticket serialises competing offers for it
+		ticket, err := lockTicket(tx, input.TicketID)
+		if err != nil {
+			return err
+		}
+		if ticket.UserID != userID {
+			return errors.New("ticket not found")
+		}
+		event, err := CheckTransferable(tx, ticket)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		var pending int64
+		if err := tx.Model(&tickets.TicketTransfer{}).
+			Where("ticket_id = ? AND status = ? AND expires_at > ?", ticket.ID, tickets.TransferPending, now).
+			Count(&pending).Error; err != nil {
+			return err
+		}
+		if pending > 0 {
+			return errors.New("ticket already has a pending transfer")
+		}
+
+		var listed int64
+		if err := tx.Model(&tickets.TicketResale{}).
+			Where("ticket_id = ? AND status IN ?", ticket.ID, []tickets.ResaleStatus{tickets.ResaleListed, tickets.ResalePending}).
+			Count(&listed).Error; err != nil {
+			return err
+		}
+		if listed > 0 {
+			return errors.New("ticket is listed for resale")
+		}
+
+		expiresAt := now.Add(transferOfferWindow)
+		if event.StartTime.Before(expiresAt) {
+			expiresAt = event.StartTime
+		}
+		transfer = tickets.TicketTransfer{
+			TicketID:   ticket.ID,
+			FromUserID: userID,
+			ToUserID:   recipient.ID,
+			Status:     tickets.TransferPending,
+			Message:    input.Message,
+			ExpiresAt:  expiresAt,
+		}
+		return tx.Create(&transfer).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.notify(userID, recipient.ID, transfer.ID, "ticket_transfer_offered", "Ticket transfer",
+		"You have been offered a ticket. Accept it before the offer expires.")
+	return s.getTransfer(transfer.ID)
+}
+
+// AcceptTransfer moves the ticket to the recipient and rotates its QR
+// credential, so the sender's copy of the QR code no longer scans
+func (s *transferService) AcceptTransfer(userID, transferID string) (*dto.TransferResponse, error) {
+	var transfer tickets.TicketTransfer
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		transfer, err = s.lockPendingTransfer(tx, transferID, "to_user_id", userID)
+		if err != nil {
+			return err
+		}
+		if _, err := s.ReassignTicket(tx, transfer.TicketID, transfer.FromUserID, transfer.ToUserID); err != nil {
+			return err
+		}
+		return respond(tx, &transfer, tickets.TransferAccepted)
+	})
+	if err != nil {
+		return nil, s.expireOn(err, transferID)
+	}
+
+	s.notify(userID, transfer.FromUserID, transfer.ID, "ticket_transfer_accepted", "Ticket transfer accepted",
+		"Your ticket transfer was accepted. The ticket now belongs to the recipient.")
+	return s.getTransfer(transfer.ID)
+}
+
+func (s *transferService) DeclineTransfer(userID, transferID string) (*dto.TransferResponse, error) {
+	var transfer tickets.TicketTransfer
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		transfer, err = s.lockPendingTransfer(tx, transferID, "to_user_id", userID)
+		if err != nil {
+			return err
+		}
+		return respond(tx, &transfer, tickets.TransferDeclined)
+	})
+	if err != nil {
+		return nil, s.expireOn(err, transferID)
+	}
+
+	s.notify(userID, transfer.FromUserID, transfer.ID, "ticket_transfer_declined", "Ticket transfer declined",
+		"Your ticket transfer was declined. The ticket is still yours.")
+	return s.getTransfer(transfer.ID)
+}
+
+func (s *transferService) CancelTransfer(userID, transferID string) (*dto.TransferResponse, error) {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		transfer, err := s.lockPendingTransfer(tx, transferID, "from_user_id", userID)
+		if err != nil {
+			return err
+		}
+		return respond(tx, &transfer, tickets.TransferCanceled)
+	})
+	if err != nil {
+		return nil, s.expireOn(err, transferID)
+	}
+	return s.getTransfer(transferID)
+}
+
+// ReassignTicket hands a valid ticket from one user to another inside tx,
+// counting the change of hands against the ticket type's transfer cap and
+// re-signing the ticket's credential
+func (s *transferService) ReassignTicket(tx *gorm.DB, ticketID, fromUserID, toUserID string) (*tickets.Ticket, error) {
+	ticket, err := lockTicket(tx, ticketID)
+	if err != nil {
+		return nil, err
+	}
+	if ticket.UserID != fromUserID {
+		return nil, errors.New("ticket is no longer owned by the sender")
+	}
+	if _, err := CheckTransferable(tx, ticket); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.keyRing.Sign(ticket); err != nil {
+		return nil, err
+	}
+	ticket.UserID = toUserID
+	ticket.TransferCount++
+	if err := tx.Model(ticket).UpdateColumns(map[string]interface{}{
+		"user_id":              toUserID,
+		"transfer_count":       ticket.TransferCount,
+		"qr_code_hash":         ticket.QRCodeHash,
+		"credential_issued_at": ticket.CredentialIssuedAt,
+		"version":              gorm.Expr("version + 1"),
+		"updated_at":           time.Now(),
+	}).Error; err != nil {
+		return nil, err
+	}
+	return ticket, nil
+}
+
+// lockPendingTransfer loads a transfer the user may respond to, as recipient
+// or sender depending on party
+func (s *transferService) lockPendingTransfer(tx *gorm.DB, transferID, party, userID string) (tickets.TicketTransfer, error) {
+	var transfer tickets.TicketTransfer
+	if _, err := uuid.Parse(transferID); err != nil {
+		return transfer, errors.New("invalid transfer ID format")
+	}
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND "+party+" = ?", transferID, userID).
+		First(&transfer).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return transfer, errors.New("transfer not found")
+		}
+		return transfer, err
+	}
+	if transfer.Status != tickets.TransferPending {
+		return transfer, errors.New("transfer is not pending")
+	}
+	if !time.Now().Before(transfer.ExpiresAt) {
+		return transfer, errTransferExpired
+	}
+	return transfer, nil
+}
+
+// expireOn records an expired offer once the failed transaction has rolled back
+func (s *transferService) expireOn(err error, transferID string) error {
+	if errors.Is(err, errTransferExpired) {
+		s.db.Model(&tickets.TicketTransfer{}).
+			Where("id = ? AND status = ?", transferID, tickets.TransferPending).
+			UpdateColumns(map[string]interface{}{
+				"status":     tickets.TransferExpired,
+				"updated_at": time.Now(),
+			})
+	}
+	return err
+}
+
+func (s *transferService) findRecipient(recipient string) (*members.User, error) {
+	var user members.User
+	if err := s.db.Select("id", "username").
+		Where("username = ? OR email = ?", recipient, recipient).
+		First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("recipient not found")
+		}
+		return nil, err
+	}
+	return &user, nil
+}
+
+// notify is best effort; a failed notification never undoes a transfer
+func (s *transferService) notify(senderID, recipientID, transferID, action, title, content string) {
+	if err := s.notificationService.TriggerNotification("tickets", action, title, content, senderID, transferID, []string{recipientID}, nil); err != nil {
+		fmt.Printf("Failed to send %s notification: %v\n", action, err)
+	}
+}
+
+func respond(tx *gorm.DB, transfer *tickets.TicketTransfer, status tickets.TransferStatus) error {
+	now := time.Now()
+	transfer.Status = status
+	transfer.RespondedAt = &now
+	return tx.Model(transfer).UpdateColumns(map[string]interface{}{
+		"status":       status,
+		"responded_at": now,
+		"updated_at":   now,
+	}).Error
+}
+
+func lockTicket(tx *gorm.DB, ticketID string) (*tickets.Ticket, error) {
+	var ticket tickets.Ticket
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", ticketID).
+		First(&ticket).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("ticket not found")
+		}
+		return nil, err
+	}
+	return &ticket, nil
+}
+
+// CheckTransferable applies the ticket's and ticket type's transfer rules to any change of
+// ownership and returns the ticket's event
+func CheckTransferable(tx *gorm.DB, ticket *tickets.Ticket) (*events.Event, error) {
+	if ticket.Status != tickets.TicketValid {
+		return nil, errors.New("only valid tickets can be transferred")
+	}
+
+	var ticketType tickets.TicketType
+	if err := tx.Unscoped().Where("id = ?", ticket.TicketTypeID).First(&ticketType).Error; err != nil {
+		return nil, err
+	}
+	if !ticket.IsTransferable || ticketType.TransfersDisabled {
+		return nil, errors.New("transfers are disabled for this ticket")
+	}
+	if ticketType.MaxTransfers > 0 && ticket.TransferCount >= ticketType.MaxTransfers {
+		return nil, errors.New("ticket has reached its transfer limit")
+	}
+
+	var event events.Event
+	if err := tx.Where("id = ?", ticket.EventID).First(&event).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("event not found")
+		}
+		return nil, err
+	}
+	if !time.Now().Before(event.StartTime) {
+		return nil, errors.New("event has already started")
+	}
+	return &event, nil
+}
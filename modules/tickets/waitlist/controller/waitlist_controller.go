@@ -0,0 +1,143 @@
+package waitlist_controller
+
+import (
+	"ticket-zetu-api/logs/handler"
+	"ticket-zetu-api/modules/tickets/waitlist/dto"
+	"ticket-zetu-api/modules/tickets/waitlist/service"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+type WaitlistController struct {
+	service    waitlist_service.WaitlistService
+	logHandler *handler.LogHandler
+	validator  *validator.Validate
+}
+
+func NewWaitlistController(service waitlist_service.WaitlistService, logHandler *handler.LogHandler) *WaitlistController {
+	return &WaitlistController{
+		service:    service,
+		logHandler: logHandler,
+		validator:  validator.New(),
+	}
+}
+
+// JoinWaitlist godoc
+// @Summary Join a waitlist
+// @Description Queues the user for a sold-out ticket type, or for any ticket type of a sold-out event when only event_id is given. When units free up, the next user in line is offered them as a hold and notified by notification and email.
+// @Tags Waitlists
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param input body dto.JoinWaitlistInput true "Waitlist details"
+// @Success 200 {object} map[string]interface{} "Joined waitlist successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid request body or event not open for sales"
+// @Failure 404 {object} map[string]interface{} "Ticket type or event not found"
+// @Failure 409 {object} map[string]interface{} "Already on the waitlist or tickets still available"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /waitlists [post]
+func (c *WaitlistController) JoinWaitlist(ctx *fiber.Ctx) error {
+	userID := ctx.Locals("user_id").(string)
+
+	var input dto.JoinWaitlistInput
+	if err := ctx.BodyParser(&input); err != nil {
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, "Invalid request body"), fiber.StatusBadRequest)
+	}
+
+	if err := c.validator.Struct(input); err != nil {
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, err.Error()), fiber.StatusBadRequest)
+	}
+
+	entry, err := c.service.JoinWaitlist(userID, input)
+	if err != nil {
+		return c.handleError(ctx, err)
+	}
+
+	return c.logHandler.LogSuccess(ctx, entry, "Joined waitlist successfully", true)
+}
+
+// GetUserEntries godoc
+// @Summary Get the user's waitlist entries
+// @Description Retrieves the user's waitlist entries, newest first, with their place in line while waiting and the hold to buy with once offered.
+// @Tags Waitlists
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} map[string]interface{} "Waitlist entries retrieved successfully"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /waitlists/me [get]
+func (c *WaitlistController) GetUserEntries(ctx *fiber.Ctx) error {
+	userID := ctx.Locals("user_id").(string)
+
+	entries, err := c.service.GetUserEntries(userID)
+	if err != nil {
+		return c.handleError(ctx, err)
+	}
+
+	return c.logHandler.LogSuccess(ctx, entries, "Waitlist entries retrieved successfully", false)
+}
+
+// LeaveWaitlist godoc
+// @Summary Leave a waitlist
+// @Description Gives up the user's place in line. Units held by an outstanding offer are returned to stock.
+// @Tags Waitlists
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Waitlist entry ID"
+// @Success 200 {object} map[string]interface{} "Left waitlist successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid waitlist entry ID"
+// @Failure 404 {object} map[string]interface{} "Waitlist entry not found"
+// @Failure 409 {object} map[string]interface{} "Waitlist entry is no longer active"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /waitlists/{id} [delete]
+func (c *WaitlistController) LeaveWaitlist(ctx *fiber.Ctx) error {
+	userID := ctx.Locals("user_id").(string)
+	entryID := ctx.Params("id")
+
+	if err := c.service.LeaveWaitlist(userID, entryID); err != nil {
+		return c.handleError(ctx, err)
+	}
+
+	return c.logHandler.LogSuccess(ctx, nil, "Left waitlist successfully", true)
+}
+
+// GetEventWaitlistStats godoc
+// @Summary Get an event's waitlist demand
+// @Description Reports, for one of the organizer's events, how many users and tickets are waiting per ticket type and how offers have played out.
+// @Tags Waitlists
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param event_id path string true "Event ID"
+// @Success 200 {object} map[string]interface{} "Waitlist stats retrieved successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid event ID"
+// @Failure 404 {object} map[string]interface{} "Event or organizer not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /waitlists/events/{event_id}/stats [get]
+func (c *WaitlistController) GetEventWaitlistStats(ctx *fiber.Ctx) error {
+	userID := ctx.Locals("user_id").(string)
+	eventID := ctx.Params("event_id")
+
+	stats, err := c.service.GetEventWaitlistStats(userID, eventID)
+	if err != nil {
+		return c.handleError(ctx, err)
+	}
+
+	return c.logHandler.LogSuccess(ctx, stats, "Waitlist stats retrieved successfully", false)
+}
+
+func (c *WaitlistController) handleError(ctx *fiber.Ctx, err error) error {
+	switch err.Error() {
+	case "invalid user ID format", "invalid event ID format", "invalid waitlist entry ID format",
+		"ticket type or event is required", "quantity exceeds the per-user limit", "event is not open for ticket sales":
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusBadRequest, err.Error()), fiber.StatusBadRequest)
+	case "ticket type not found", "event not found", "waitlist entry not found", "organizer not found":
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusNotFound, err.Error()), fiber.StatusNotFound)
+	case "already on the waitlist", "tickets are still available", "waitlist entry is no longer active":
+		return c.logHandler.LogError(ctx, fiber.NewError(fiber.StatusConflict, err.Error()), fiber.StatusConflict)
+	default:
+		return c.logHandler.LogError(ctx, err, fiber.StatusInternalServerError)
+	}
+}
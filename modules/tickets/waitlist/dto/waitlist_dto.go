@@ -0,0 +1,48 @@
+package dto
+
+import "time"
+
+// JoinWaitlistInput defines the input structure for joining a waitlist. Naming
+// a ticket type waits for that type; naming only an event waits for any of its
+// ticket types.
+type JoinWaitlistInput struct {
+	TicketTypeID string `json:"ticket_type_id,omitempty" example:"a1d3c4e6-89ab-44ce-8e65-123456789abc" validate:"omitempty,uuid"`
+	EventID      string `json:"event_id,omitempty" example:"b2e4d5f7-90bc-45df-9f76-234567890bcd" validate:"omitempty,uuid"`
+	Quantity     int    `json:"quantity" example:"2" validate:"required,gte=1"`
+}
+
+// WaitlistEntryResponse defines the response structure for a waitlist entry
+type WaitlistEntryResponse struct {
+	ID                  string     `json:"id"`
+	EventID             string     `json:"event_id"`
+	TicketTypeID        string     `json:"ticket_type_id,omitempty"`
+	Quantity            int        `json:"quantity"`
+	Status              string     `json:"status"`
+	Position            int        `json:"position,omitempty"`
+	OfferedTicketTypeID string     `json:"offered_ticket_type_id,omitempty"`
+	HoldID              string     `json:"hold_id,omitempty"`
+	OfferedAt           *time.Time `json:"offered_at,omitempty"`
+	OfferExpiresAt      *time.Time `json:"offer_expires_at,omitempty"`
+	OrderID             string     `json:"order_id,omitempty"`
+	CreatedAt           time.Time  `json:"created_at"`
+}
+
+// TicketTypeWaitlistStats defines the waitlist counts of one ticket type; an
+// empty ticket type ID covers entries waiting for any of the event's ticket types
+type TicketTypeWaitlistStats struct {
+	TicketTypeID    string `json:"ticket_type_id,omitempty"`
+	TicketTypeName  string `json:"ticket_type_name,omitempty"`
+	Waiting         int64  `json:"waiting"`
+	WaitingQuantity int64  `json:"waiting_quantity"`
+	Offered         int64  `json:"offered"`
+	Claimed         int64  `json:"claimed"`
+	Expired         int64  `json:"expired"`
+}
+
+// EventWaitlistStats defines the response structure for an event's waitlist demand
+type EventWaitlistStats struct {
+	EventID              string                    `json:"event_id"`
+	TotalWaiting         int64                     `json:"total_waiting"`
+	TotalWaitingQuantity int64                     `json:"total_waiting_quantity"`
+	TicketTypes          []TicketTypeWaitlistStats `json:"ticket_types"`
+}
@@ -0,0 +1,221 @@
+package waitlist_service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"ticket-zetu-api/modules/events/models/events"
+	organizers "ticket-zetu-api/modules/organizers/models"
+	inventory_service "ticket-zetu-api/modules/tickets/inventory/service"
+	"ticket-zetu-api/modules/tickets/models/tickets"
+	"ticket-zetu-api/modules/users/models/members"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ProcessOffers expires up to limit lapsed offers, then offers freed stock to
+// up to limit waiting users, and reports how many offers it made. Units freed
+// by expired holds, refunds or stock increases all land in available stock,
+// where holds and direct orders leave them alone while a waiting entry could
+// take them (see WaitlistFirst), so whatever freed them they are offered here
+// first.
+func (s *waitlistService) ProcessOffers(limit int) (int, error) {
+	if err := s.expireOffers(limit); err != nil {
+		return 0, err
+	}
+
+	var ticketTypeIDs []string
+	now := time.Now()
+	if err := s.db.Model(&tickets.TicketStock{}).
+		Joins("JOIN ticket_types ON ticket_types.id = ticket_stocks.ticket_type_id").
+		Joins("JOIN events ON events.id = ticket_types.event_id").
+		Where("ticket_stocks.available_stock > 0").
+		Where("ticket_types.status = ? AND ticket_types.deleted_at IS NULL", tickets.TicketTypeActive).
+		Where("ticket_types.sales_end IS NULL OR ticket_types.sales_end > ?", now).
+		Where("events.status IN ? AND events.has_tickets = ? AND events.end_time > ? AND events.deleted_at IS NULL", events.OnSaleEventStatuses, true, now).
+		Where("EXISTS (SELECT 1 FROM waitlist_entries WHERE waitlist_entries.status = ? AND (waitlist_entries.ticket_type_id = ticket_types.id OR (waitlist_entries.ticket_type_id IS NULL AND waitlist_entries.event_id = ticket_types.event_id)))", tickets.WaitlistWaiting).
+		Order("ticket_stocks.updated_at ASC").
+		Limit(limit).
+		Pluck("ticket_stocks.ticket_type_id", &ticketTypeIDs).Error; err != nil {
+		return 0, err
+	}
+
+	offered := 0
+	for _, ticketTypeID := range ticketTypeIDs {
+		for offered < limit {
+			entry, err := s.offerNext(ticketTypeID)
+			if err != nil {
+				return offered, err
+			}
+			if entry == nil {
+				break
+			}
+			offered++
+			s.notifyOffer(entry)
+		}
+	}
+	return offered, nil
+}
+
+// offerNext holds units of the ticket type for the longest-waiting entry they
+// can satisfy and returns it, or nil when no waiting entry fits the stock left
+func (s *waitlistService) offerNext(ticketTypeID string) (*tickets.WaitlistEntry, error) {
+	var entry tickets.WaitlistEntry
+	offered := false
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var ticketType tickets.TicketType
+		if err := tx.Where("id = ?", ticketTypeID).First(&ticketType).Error; err != nil {
+			return err
+		}
+		stock, err := s.inventoryService.GetStock(tx, ticketTypeID)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("status = ? AND quantity <= ? AND quantity <= ?", tickets.WaitlistWaiting, stock.AvailableStock, ticketType.MaxTicketsPerUser).
+			Where("ticket_type_id = ? OR (ticket_type_id IS NULL AND event_id = ?)", ticketType.ID, ticketType.EventID).
+			Order("created_at ASC, id ASC").
+			First(&entry).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			return err
+		}
+
+		err = s.inventoryService.Hold(tx, ticketType.ID, entry.Quantity)
+		switch {
+		case errors.Is(err, inventory_service.ErrInsufficientStock):
+			// A buyer took the units since the stock was read; try again next sweep
+			return nil
+		case err != nil:
+			return err
+		}
+
+		now := time.Now()
+		expiresAt := now.Add(offerWindow)
+		hold := tickets.TicketHold{
+			TicketTypeID: ticketType.ID,
+			UserID:       entry.UserID,
+			SessionID:    uuid.New().String(),
+			Quantity:     entry.Quantity,
+			HeldUntil:    expiresAt,
+		}
+		if err := tx.Create(&hold).Error; err != nil {
+			return err
+		}
+
+		entry.Status = tickets.WaitlistOffered
+		entry.OfferedTicketTypeID = &ticketType.ID
+		entry.HoldID = &hold.ID
+		entry.OfferedAt = &now
+		entry.OfferExpiresAt = &expiresAt
+		if err := tx.Model(&entry).UpdateColumns(map[string]interface{}{
+			"status":                 entry.Status,
+			"offered_ticket_type_id": ticketType.ID,
+			"hold_id":                hold.ID,
+			"offered_at":             now,
+			"offer_expires_at":       expiresAt,
+			"updated_at":             now,
+		}).Error; err != nil {
+			return err
+		}
+		offered = true
+		return nil
+	})
+	if err != nil || !offered {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// expireOffers closes offers whose hold has lapsed, been released or been
+// swept, returning any units still held to stock. The hold is deleted with a
+// guarded DELETE before the entry is touched, the same lock order as claims.
+// Expired holds cannot be extended or converted, so a lapsed offer stays lapsed.
+func (s *waitlistService) expireOffers(limit int) error {
+	var lapsed []tickets.WaitlistEntry
+	if err := s.db.Where("status = ?", tickets.WaitlistOffered).
+		Where("NOT EXISTS (SELECT 1 FROM ticket_holds WHERE ticket_holds.id = waitlist_entries.hold_id AND ticket_holds.held_until > ?)", time.Now()).
+		Order("offer_expires_at ASC").
+		Limit(limit).
+		Find(&lapsed).Error; err != nil {
+		return err
+	}
+
+	for i := range lapsed {
+		entry := lapsed[i]
+		err := s.db.Transaction(func(tx *gorm.DB) error {
+			if entry.HoldID != nil {
+				var hold tickets.TicketHold
+				err := tx.Where("id = ?", *entry.HoldID).First(&hold).Error
+				switch {
+				case err == nil:
+					// Only whoever deletes the hold releases its units; the hold
+					// sweeper may get there first
+					result := tx.Where("held_until <= ?", time.Now()).Delete(&hold)
+					if result.Error != nil {
+						return result.Error
+					}
+					if result.RowsAffected == 1 {
+						if err := s.releaseStock(tx, hold.TicketTypeID, hold.Quantity); err != nil {
+							return err
+						}
+					}
+				case !errors.Is(err, gorm.ErrRecordNotFound):
+					return err
+				}
+			}
+
+			return closeEntry(tx, entry.ID, entry.HoldID, tickets.WaitlistExpired, nil)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// notifyOffer is best effort; a failed notification or email never undoes an
+// offer, which still shows on the user's waitlist entries
+func (s *waitlistService) notifyOffer(entry *tickets.WaitlistEntry) {
+	var user members.User
+	if err := s.db.Where("id = ?", entry.UserID).First(&user).Error; err != nil {
+		log.Printf("Failed to load waitlisted user %s: %v", entry.UserID, err)
+		return
+	}
+	var ticketType tickets.TicketType
+	if err := s.db.Preload("Event").Where("id = ?", *entry.OfferedTicketTypeID).First(&ticketType).Error; err != nil {
+		log.Printf("Failed to load offered ticket type %s: %v", *entry.OfferedTicketTypeID, err)
+		return
+	}
+
+	if user.Email != "" {
+		if err := s.emailService.SendWaitlistOfferEmail(nil, user.Email, user.Username, ticketType.Event.Title, ticketType.Name, entry.Quantity, *entry.OfferExpiresAt); err != nil {
+			log.Printf("Failed to send waitlist offer email: %v", err)
+		}
+	}
+
+	// Offers are sent on behalf of the organizer running the event
+	var organizer organizers.Organizer
+	if err := s.db.Select("id", "created_by").Where("id = ?", ticketType.Event.OrganizerID).First(&organizer).Error; err != nil {
+		log.Printf("Failed to load organizer of event %s: %v", ticketType.EventID, err)
+		return
+	}
+
+	title := "Tickets available"
+	content := fmt.Sprintf("%d %s ticket(s) for %s are held for you until %s. Complete your purchase before then or they go to the next person in line.",
+		entry.Quantity, ticketType.Name, ticketType.Event.Title, entry.OfferExpiresAt.Format("2006-01-02 15:04"))
+	metadata := map[string]interface{}{
+		"hold_id":        *entry.HoldID,
+		"ticket_type_id": ticketType.ID,
+		"event_id":       ticketType.EventID,
+		"expires_at":     entry.OfferExpiresAt,
+	}
+	if err := s.notificationService.TriggerNotification("tickets", "waitlist_offer", title, content, organizer.CreatedBy, entry.ID, []string{entry.UserID}, metadata); err != nil {
+		log.Printf("Failed to send waitlist_offer notification: %v", err)
+	}
+}
@@ -0,0 +1,138 @@
+package waitlist_service
+
+import (
+	"errors"
+	"ticket-zetu-api/modules/events/models/events"
+	organizers_services "ticket-zetu-api/modules/organizers/services"
+	"ticket-zetu-api/modules/tickets/models/tickets"
+	"ticket-zetu-api/modules/tickets/waitlist/dto"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func (s *waitlistService) GetUserEntries(userID string) ([]dto.WaitlistEntryResponse, error) {
+	if _, err := uuid.Parse(userID); err != nil {
+		return nil, errors.New("invalid user ID format")
+	}
+
+	var entries []tickets.WaitlistEntry
+	if err := s.db.Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&entries).Error; err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.WaitlistEntryResponse, len(entries))
+	for i := range entries {
+		responses[i] = *toEntryDTO(&entries[i])
+		if entries[i].Status != tickets.WaitlistWaiting {
+			continue
+		}
+		position, err := s.position(&entries[i])
+		if err != nil {
+			return nil, err
+		}
+		responses[i].Position = position
+	}
+	return responses, nil
+}
+
+// GetEventWaitlistStats reports how many users wait for each of an
+// organizer's ticket types, as a measure of unmet demand
+func (s *waitlistService) GetEventWaitlistStats(userID, eventID string) (*dto.EventWaitlistStats, error) {
+	if _, err := uuid.Parse(eventID); err != nil {
+		return nil, errors.New("invalid event ID format")
+	}
+	organizer, err := organizers_services.GetUserOrganizer(s.db, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var event events.Event
+	if err := s.db.Where("id = ? AND organizer_id = ? AND deleted_at IS NULL", eventID, organizer.ID).First(&event).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("event not found")
+		}
+		return nil, err
+	}
+
+	var rows []struct {
+		TicketTypeID *string
+		Status       tickets.WaitlistStatus
+		Entries      int64
+		Quantity     int64
+	}
+	if err := s.db.Model(&tickets.WaitlistEntry{}).
+		Select("ticket_type_id, status, COUNT(*) AS entries, COALESCE(SUM(quantity), 0) AS quantity").
+		Where("event_id = ?", eventID).
+		Group("ticket_type_id, status").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	var ticketTypes []tickets.TicketType
+	if err := s.db.Where("event_id = ? AND deleted_at IS NULL", eventID).
+		Order("created_at ASC").
+		Find(&ticketTypes).Error; err != nil {
+		return nil, err
+	}
+
+	// Entries waiting for any ticket type come first, then each ticket type in order
+	stats := &dto.EventWaitlistStats{
+		EventID:     eventID,
+		TicketTypes: []dto.TicketTypeWaitlistStats{{}},
+	}
+	index := map[string]int{"": 0}
+	for _, ticketType := range ticketTypes {
+		index[ticketType.ID] = len(stats.TicketTypes)
+		stats.TicketTypes = append(stats.TicketTypes, dto.TicketTypeWaitlistStats{
+			TicketTypeID:   ticketType.ID,
+			TicketTypeName: ticketType.Name,
+		})
+	}
+
+	for _, row := range rows {
+		key := ""
+		if row.TicketTypeID != nil {
+			key = *row.TicketTypeID
+		}
+		i, ok := index[key]
+		if !ok {
+			continue
+		}
+		line := &stats.TicketTypes[i]
+		switch row.Status {
+		case tickets.WaitlistWaiting:
+			line.Waiting += row.Entries
+			line.WaitingQuantity += row.Quantity
+			stats.TotalWaiting += row.Entries
+			stats.TotalWaitingQuantity += row.Quantity
+		case tickets.WaitlistOffered:
+			line.Offered += row.Entries
+		case tickets.WaitlistClaimed:
+			line.Claimed += row.Entries
+		case tickets.WaitlistExpired:
+			line.Expired += row.Entries
+		}
+	}
+	return stats, nil
+}
+
+// position is the entry's 1-based place among the waiting entries that
+// compete for the same units. Event-wide entries compete with every ticket
+// type's line.
+func (s *waitlistService) position(entry *tickets.WaitlistEntry) (int, error) {
+	query := s.db.Model(&tickets.WaitlistEntry{}).
+		Where("event_id = ? AND status = ?", entry.EventID, tickets.WaitlistWaiting).
+		Where("created_at < ? OR (created_at = ? AND id < ?)", entry.CreatedAt, entry.CreatedAt, entry.ID)
+	if entry.TicketTypeID != nil {
+		query = query.Where("ticket_type_id = ? OR ticket_type_id IS NULL", *entry.TicketTypeID)
+	}
+
+	var ahead int64
+	if err := query.Count(&ahead).Error; err != nil {
+		return 0, err
+	}
+	return int(ahead) + 1, nil
+}
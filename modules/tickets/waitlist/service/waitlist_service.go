@@ -0,0 +1,285 @@
+package waitlist_service
+
+import (
+	"errors"
+	"ticket-zetu-api/modules/events/models/events"
+	notification_service "ticket-zetu-api/modules/notifications/service"
+	inventory_service "ticket-zetu-api/modules/tickets/inventory/service"
+	"ticket-zetu-api/modules/tickets/models/tickets"
+	"ticket-zetu-api/modules/tickets/waitlist/dto"
+	mail_service "ticket-zetu-api/modules/users/authentication/mail"
+	"ticket-zetu-api/modules/users/authorization/service"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// offerWindow is how long an offered user has to buy the units held for them
+const offerWindow = 30 * time.Minute
+
+var activeStatuses = []tickets.WaitlistStatus{tickets.WaitlistWaiting, tickets.WaitlistOffered}
+
+type WaitlistService interface {
+	JoinWaitlist(userID string, input dto.JoinWaitlistInput) (*dto.WaitlistEntryResponse, error)
+	LeaveWaitlist(userID, entryID string) error
+	GetUserEntries(userID string) ([]dto.WaitlistEntryResponse, error)
+	GetEventWaitlistStats(userID, eventID string) (*dto.EventWaitlistStats, error)
+	ProcessOffers(limit int) (int, error)
+}
+
+type waitlistService struct {
+	db                   *gorm.DB
+	authorizationService authorization_service.PermissionService
+	inventoryService     inventory_service.InventoryService
+	notificationService  notification_service.NotificationService
+	emailService         mail_service.EmailService
+}
+
+func NewWaitlistService(db *gorm.DB, authService authorization_service.PermissionService, inventoryService inventory_service.InventoryService, notificationService notification_service.NotificationService, emailService mail_service.EmailService) WaitlistService {
+	return &waitlistService{
+		db:                   db,
+		authorizationService: authService,
+		inventoryService:     inventoryService,
+		notificationService:  notificationService,
+		emailService:         emailService,
+	}
+}
+
+// JoinWaitlist queues the user for a sold-out ticket type, or for any ticket
+// type of an event whose ticket types are all sold out
+func (s *waitlistService) JoinWaitlist(userID string, input dto.JoinWaitlistInput) (*dto.WaitlistEntryResponse, error) {
+	if _, err := uuid.Parse(userID); err != nil {
+		return nil, errors.New("invalid user ID format")
+	}
+	if input.TicketTypeID == "" && input.EventID == "" {
+		return nil, errors.New("ticket type or event is required")
+	}
+
+	var entry tickets.WaitlistEntry
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var ticketTypes []tickets.TicketType
+		eventID := input.EventID
+		if input.TicketTypeID != "" {
+			var ticketType tickets.TicketType
+			if err := tx.Where("id = ? AND status = ? AND deleted_at IS NULL", input.TicketTypeID, tickets.TicketTypeActive).
+				First(&ticketType).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return errors.New("ticket type not found")
+				}
+				return err
+			}
+			if eventID != "" && eventID != ticketType.EventID {
+				return errors.New("ticket type not found")
+			}
+			if input.Quantity > ticketType.MaxTicketsPerUser {
+				return errors.New("quantity exceeds the per-user limit")
+			}
+			eventID = ticketType.EventID
+			ticketTypes = append(ticketTypes, ticketType)
+		} else if err := tx.Where("event_id = ? AND status = ? AND deleted_at IS NULL", eventID, tickets.TicketTypeActive).
+			Find(&ticketTypes).Error; err != nil {
+			return err
+		}
+
+		var event events.Event
+		if err := tx.Where("id = ? AND deleted_at IS NULL", eventID).First(&event).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("event not found")
+			}
+			return err
+		}
+		if !event.Status.IsOnSale() || !event.HasTickets || time.Now().After(event.EndTime) || len(ticketTypes) == 0 {
+			return errors.New("event is not open for ticket sales")
+		}
+
+		// Waiting only makes sense once every ticket type in the line is sold out
+		for _, ticketType := range ticketTypes {
+			stock, err := s.inventoryService.GetStock(tx, ticketType.ID)
+			if errors.Is(err, inventory_service.ErrStockNotFound) {
+				return errors.New("tickets are still available")
+			}
+			if err != nil {
+				return err
+			}
+			if stock.AvailableStock >= input.Quantity {
+				return errors.New("tickets are still available")
+			}
+		}
+
+		entry = tickets.WaitlistEntry{
+			EventID:  eventID,
+			UserID:   userID,
+			Quantity: input.Quantity,
+			Status:   tickets.WaitlistWaiting,
+		}
+		if input.TicketTypeID != "" {
+			entry.TicketTypeID = &input.TicketTypeID
+		}
+		key := tickets.WaitlistKey(userID, eventID, entry.TicketTypeID)
+		entry.ActiveKey = &key
+
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&entry)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("already on the waitlist")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := toEntryDTO(&entry)
+	if response.Position, err = s.position(&entry); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// LeaveWaitlist gives up the user's place in line, returning the units of an
+// outstanding offer to stock
+func (s *waitlistService) LeaveWaitlist(userID, entryID string) error {
+	if _, err := uuid.Parse(entryID); err != nil {
+		return errors.New("invalid waitlist entry ID format")
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		var entry tickets.WaitlistEntry
+		if err := tx.Where("id = ? AND user_id = ?", entryID, userID).First(&entry).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("waitlist entry not found")
+			}
+			return err
+		}
+		if entry.Status != tickets.WaitlistWaiting && entry.Status != tickets.WaitlistOffered {
+			return errors.New("waitlist entry is no longer active")
+		}
+
+		// The hold is locked before the entry, in the same order as claims and offer expiry
+		if entry.HoldID != nil {
+			var hold tickets.TicketHold
+			err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", *entry.HoldID).First(&hold).Error
+			switch {
+			case err == nil:
+				if err := tx.Delete(&hold).Error; err != nil {
+					return err
+				}
+				if err := s.releaseStock(tx, hold.TicketTypeID, hold.Quantity); err != nil {
+					return err
+				}
+			case !errors.Is(err, gorm.ErrRecordNotFound):
+				return err
+			}
+		}
+
+		return closeEntry(tx, entry.ID, entry.HoldID, tickets.WaitlistLeft, errors.New("waitlist entry is no longer active"))
+	})
+}
+
+// ClaimOffer marks the waitlist offer made through a hold as taken by the
+// order converting it. It runs inside the order's transaction with the hold
+// already locked and does nothing for holds that are not waitlist offers.
+func ClaimOffer(tx *gorm.DB, holdID, orderID string) error {
+	return tx.Model(&tickets.WaitlistEntry{}).
+		Where("hold_id = ? AND status = ?", holdID, tickets.WaitlistOffered).
+		UpdateColumns(map[string]interface{}{
+			"status":     tickets.WaitlistClaimed,
+			"order_id":   orderID,
+			"active_key": nil,
+			"updated_at": time.Now(),
+		}).Error
+}
+
+// IsOffer reports whether a hold was made as a waitlist offer
+func IsOffer(tx *gorm.DB, holdID string) (bool, error) {
+	var count int64
+	err := tx.Model(&tickets.WaitlistEntry{}).
+		Where("hold_id = ? AND status = ?", holdID, tickets.WaitlistOffered).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// WaitlistFirst reports whether the units of a ticket type now on sale are
+// owed to its waitlist: some waiting entry could be offered what is available.
+// Holds and direct orders are turned away until the offer sweep has handed
+// those units out. The stock row is locked so no buyer can take units between
+// this check and their own stock movement.
+func WaitlistFirst(tx *gorm.DB, ticketType *tickets.TicketType) (bool, error) {
+	var stock tickets.TicketStock
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("ticket_type_id = ?", ticketType.ID).
+		First(&stock).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// Ticket types without a stock row never sell out
+			return false, nil
+		}
+		return false, err
+	}
+	if stock.AvailableStock == 0 {
+		return false, nil
+	}
+
+	var waiting int64
+	err := tx.Model(&tickets.WaitlistEntry{}).
+		Where("status = ? AND quantity <= ? AND quantity <= ?", tickets.WaitlistWaiting, stock.AvailableStock, ticketType.MaxTicketsPerUser).
+		Where("ticket_type_id = ? OR (ticket_type_id IS NULL AND event_id = ?)", ticketType.ID, ticketType.EventID).
+		Count(&waiting).Error
+	return waiting > 0, err
+}
+
+// closeEntry moves an active entry to a final status, freeing its line key.
+// holdID pins the offer the caller acted on, so an entry that moved on
+// concurrently is left alone and inactive is returned.
+func closeEntry(tx *gorm.DB, entryID string, holdID *string, status tickets.WaitlistStatus, inactive error) error {
+	query := tx.Model(&tickets.WaitlistEntry{}).Where("id = ? AND status IN ?", entryID, activeStatuses)
+	if holdID != nil {
+		query = query.Where("hold_id = ?", *holdID)
+	} else {
+		query = query.Where("hold_id IS NULL")
+	}
+	result := query.UpdateColumns(map[string]interface{}{
+		"status":     status,
+		"active_key": nil,
+		"updated_at": time.Now(),
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return inactive
+	}
+	return nil
+}
+
+func (s *waitlistService) releaseStock(tx *gorm.DB, ticketTypeID string, quantity int) error {
+	return s.inventoryService.MoveIfTracked(tx, ticketTypeID, inventory_service.BucketHeld, inventory_service.BucketAvailable, quantity)
+}
+
+func toEntryDTO(entry *tickets.WaitlistEntry) *dto.WaitlistEntryResponse {
+	response := &dto.WaitlistEntryResponse{
+		ID:             entry.ID,
+		EventID:        entry.EventID,
+		Quantity:       entry.Quantity,
+		Status:         string(entry.Status),
+		OfferedAt:      entry.OfferedAt,
+		OfferExpiresAt: entry.OfferExpiresAt,
+		CreatedAt:      entry.CreatedAt,
+	}
+	if entry.TicketTypeID != nil {
+		response.TicketTypeID = *entry.TicketTypeID
+	}
+	if entry.OfferedTicketTypeID != nil {
+		response.OfferedTicketTypeID = *entry.OfferedTicketTypeID
+	}
+	if entry.HoldID != nil {
+		response.HoldID = *entry.HoldID
+	}
+	if entry.OrderID != nil {
+		response.OrderID = *entry.OrderID
+	}
+	return response
+}
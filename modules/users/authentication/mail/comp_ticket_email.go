@@ -0,0 +1,104 @@
+package mail_service
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/url"
+	"os"
+	"time"
+
+	"ticket-zetu-api/mail"
+
+	"github.com/gofiber/fiber/v2"
+	"gopkg.in/gomail.v2"
+)
+
+// SendCompTicketEmail queues an email with a link to claim complimentary tickets issued to an email without an account
+func (s *emailService) SendCompTicketEmail(c *fiber.Ctx, email, eventTitle, ticketTypeName string, quantity int, claimToken string, expiresAt time.Time) error {
+	smtpConfig := s.config.GetSMTPConfig()
+	templateConfig := s.config.GetTemplateConfig()
+	appConfig := s.config.GetAppConfig()
+
+	job := emailJob{
+		ctx: c,
+		execute: func() error {
+			return s.sendCompTicketEmail(
+				email,
+				eventTitle,
+				ticketTypeName,
+				quantity,
+				claimToken,
+				expiresAt,
+				smtpConfig,
+				templateConfig,
+				appConfig,
+			)
+		},
+	}
+
+	select {
+	case s.jobQueue <- job:
+		return nil
+	case <-time.After(100 * time.Millisecond):
+		return errors.New("email queue overloaded")
+	}
+}
+
+func (s *emailService) sendCompTicketEmail(
+	email, eventTitle, ticketTypeName string,
+	quantity int,
+	claimToken string,
+	expiresAt time.Time,
+	smtpConfig mail.EmailConfig,
+	templateConfig mail.EmailTemplateConfig,
+	appConfig mail.AppConfig,
+) error {
+	data := struct {
+		EventTitle     string
+		TicketTypeName string
+		Quantity       int
+		ClaimURL       string
+		SupportURL     string
+		PrivacyURL     string
+		TermsURL       string
+		ExpiryTime     string
+	}{
+		EventTitle:     eventTitle,
+		TicketTypeName: ticketTypeName,
+		Quantity:       quantity,
+		ClaimURL:       fmt.Sprintf("%s/comps/claim?token=%s", appConfig.SecurityURL, url.QueryEscape(claimToken)),
+		SupportURL:     appConfig.SupportURL,
+		PrivacyURL:     appConfig.PrivacyURL,
+		TermsURL:       appConfig.TermsURL,
+		ExpiryTime:     expiresAt.Format("2006-01-02 15:04:05"),
+	}
+
+	templateContent, err := os.ReadFile(templateConfig.CompTicketTemplatePath)
+	if err != nil {
+		return errors.New("failed to read template")
+	}
+
+	var buf bytes.Buffer
+	tmpl, err := template.New("compTicketEmail").Parse(string(templateContent))
+	if err != nil {
+		return errors.New("template parsing failed")
+	}
+
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return errors.New("template execution failed")
+	}
+
+	m := gomail.NewMessage()
+	m.SetHeader("From", smtpConfig.FromEmail)
+	m.SetHeader("To", email)
+	m.SetHeader("Subject", fmt.Sprintf("Your Tickets: %s", eventTitle))
+	m.SetBody("text/html", buf.String())
+
+	d := gomail.NewDialer(smtpConfig.SMTPHost, smtpConfig.SMTPPort, smtpConfig.SMTPUsername, smtpConfig.SMTPPassword)
+	if err := d.DialAndSend(m); err != nil {
+		return errors.New("failed to send email")
+	}
+	return nil
+}
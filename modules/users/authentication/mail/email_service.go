@@ -1,6 +1,7 @@
 package mail_service
 
 import (
+	"log"
 	"sync"
 	"time"
 
@@ -15,6 +16,10 @@ type EmailService interface {
 	GenerateAndSendVerificationCode(c *fiber.Ctx, email, username, userID string) (string, error)
 	SendLoginWarning(c *fiber.Ctx, email, username, userAgent, ipAddress, country, state string, loginTime time.Time, warningType string) error
 	SendPasswordResetEmail(c *fiber.Ctx, email, username, resetToken string) error
+	SendWaitlistOfferEmail(c *fiber.Ctx, email, username, eventTitle, ticketTypeName string, quantity int, expiresAt time.Time) error
+	SendInvoiceEmail(c *fiber.Ctx, email, recipientName, documentTitle, invoiceNumber, description, total string, issuedAt time.Time, document []byte) error
+	SendCompTicketEmail(c *fiber.Ctx, email, eventTitle, ticketTypeName string, quantity int, claimToken string, expiresAt time.Time) error
+	SendEventCancelledEmail(c *fiber.Ctx, email, username, eventTitle string, startTime time.Time, reason string, refundPercentage float64) error
 	Shutdown()
 }
 
@@ -57,6 +62,11 @@ func (s *emailService) worker() {
 		select {
 		case job := <-s.jobQueue:
 			if err := job.execute(); err != nil {
+				// Jobs queued by background work have no request to log against
+				if job.ctx == nil {
+					log.Printf("Failed to send email: %v", err)
+					continue
+				}
 				s.logHandler.LogError(job.ctx, err, fiber.StatusInternalServerError)
 			}
 		case <-s.shutdown:
@@ -0,0 +1,106 @@
+package mail_service
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html/template"
+	"os"
+	"strconv"
+	"time"
+
+	"ticket-zetu-api/mail"
+
+	"github.com/gofiber/fiber/v2"
+	"gopkg.in/gomail.v2"
+)
+
+// SendEventCancelledEmail queues an email telling a ticket holder that an event has been cancelled and how much of their payment is refunded
+func (s *emailService) SendEventCancelledEmail(c *fiber.Ctx, email, username, eventTitle string, startTime time.Time, reason string, refundPercentage float64) error {
+	smtpConfig := s.config.GetSMTPConfig()
+	templateConfig := s.config.GetTemplateConfig()
+	appConfig := s.config.GetAppConfig()
+
+	job := emailJob{
+		ctx: c,
+		execute: func() error {
+			return s.sendEventCancelledEmail(
+				email,
+				username,
+				eventTitle,
+				startTime,
+				reason,
+				refundPercentage,
+				smtpConfig,
+				templateConfig,
+				appConfig,
+			)
+		},
+	}
+
+	select {
+	case s.jobQueue <- job:
+		return nil
+	case <-time.After(100 * time.Millisecond):
+		return errors.New("email queue overloaded")
+	}
+}
+
+func (s *emailService) sendEventCancelledEmail(
+	email, username, eventTitle string,
+	startTime time.Time,
+	reason string,
+	refundPercentage float64,
+	smtpConfig mail.EmailConfig,
+	templateConfig mail.EmailTemplateConfig,
+	appConfig mail.AppConfig,
+) error {
+	data := struct {
+		Username         string
+		EventTitle       string
+		EventDate        string
+		Reason           string
+		RefundPercentage string
+		TicketsURL       string
+		SupportURL       string
+		PrivacyURL       string
+		TermsURL         string
+	}{
+		Username:         username,
+		EventTitle:       eventTitle,
+		EventDate:        startTime.Format("2006-01-02 15:04"),
+		Reason:           reason,
+		RefundPercentage: strconv.FormatFloat(refundPercentage, 'f', -1, 64),
+		TicketsURL:       fmt.Sprintf("%s/tickets", appConfig.SecurityURL),
+		SupportURL:       appConfig.SupportURL,
+		PrivacyURL:       appConfig.PrivacyURL,
+		TermsURL:         appConfig.TermsURL,
+	}
+
+	templateContent, err := os.ReadFile(templateConfig.EventCancelledTemplatePath)
+	if err != nil {
+		return errors.New("failed to read template")
+	}
+
+	var buf bytes.Buffer
+	tmpl, err := template.New("eventCancelledEmail").Parse(string(templateContent))
+	if err != nil {
+		return errors.New("template parsing failed")
+	}
+
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return errors.New("template execution failed")
+	}
+
+	m := gomail.NewMessage()
+	m.SetHeader("From", smtpConfig.FromEmail)
+	m.SetHeader("To", email)
+	m.SetHeader("Subject", fmt.Sprintf("Event Cancelled: %s", eventTitle))
+	m.SetBody("text/html", buf.String())
+
+	d := gomail.NewDialer(smtpConfig.SMTPHost, smtpConfig.SMTPPort, smtpConfig.SMTPUsername, smtpConfig.SMTPPassword)
+	if err := d.DialAndSend(m); err != nil {
+		return errors.New("failed to send email")
+	}
+	return nil
+}
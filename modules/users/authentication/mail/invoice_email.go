@@ -0,0 +1,117 @@
+package mail_service
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"ticket-zetu-api/mail"
+
+	"github.com/gofiber/fiber/v2"
+	"gopkg.in/gomail.v2"
+)
+
+// SendInvoiceEmail queues an email with a receipt or invoice attached as a PDF
+func (s *emailService) SendInvoiceEmail(c *fiber.Ctx, email, recipientName, documentTitle, invoiceNumber, description, total string, issuedAt time.Time, document []byte) error {
+	smtpConfig := s.config.GetSMTPConfig()
+	templateConfig := s.config.GetTemplateConfig()
+	appConfig := s.config.GetAppConfig()
+
+	job := emailJob{
+		ctx: c,
+		execute: func() error {
+			return s.sendInvoiceEmail(
+				email,
+				recipientName,
+				documentTitle,
+				invoiceNumber,
+				description,
+				total,
+				issuedAt,
+				document,
+				smtpConfig,
+				templateConfig,
+				appConfig,
+			)
+		},
+	}
+
+	select {
+	case s.jobQueue <- job:
+		return nil
+	case <-time.After(100 * time.Millisecond):
+		return errors.New("email queue overloaded")
+	}
+}
+
+func (s *emailService) sendInvoiceEmail(
+	email, recipientName, documentTitle, invoiceNumber, description, total string,
+	issuedAt time.Time,
+	document []byte,
+	smtpConfig mail.EmailConfig,
+	templateConfig mail.EmailTemplateConfig,
+	appConfig mail.AppConfig,
+) error {
+	data := struct {
+		RecipientName string
+		DocumentTitle string
+		DocumentName  string
+		InvoiceNumber string
+		Description   string
+		Total         string
+		IssuedAt      string
+		SupportURL    string
+		PrivacyURL    string
+		TermsURL      string
+	}{
+		RecipientName: recipientName,
+		DocumentTitle: documentTitle,
+		DocumentName:  strings.ToLower(documentTitle),
+		InvoiceNumber: invoiceNumber,
+		Description:   description,
+		Total:         total,
+		IssuedAt:      issuedAt.Format("2006-01-02"),
+		SupportURL:    appConfig.SupportURL,
+		PrivacyURL:    appConfig.PrivacyURL,
+		TermsURL:      appConfig.TermsURL,
+	}
+
+	templateContent, err := os.ReadFile(templateConfig.InvoiceTemplatePath)
+	if err != nil {
+		return errors.New("failed to read template")
+	}
+
+	var buf bytes.Buffer
+	tmpl, err := template.New("invoiceEmail").Parse(string(templateContent))
+	if err != nil {
+		return errors.New("template parsing failed")
+	}
+
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return errors.New("template execution failed")
+	}
+
+	m := gomail.NewMessage()
+	m.SetHeader("From", smtpConfig.FromEmail)
+	m.SetHeader("To", email)
+	m.SetHeader("Subject", fmt.Sprintf("Your %s %s", data.DocumentName, invoiceNumber))
+	m.SetBody("text/html", buf.String())
+	m.Attach(invoiceNumber+".pdf",
+		gomail.SetHeader(map[string][]string{"Content-Type": {"application/pdf"}}),
+		gomail.SetCopyFunc(func(w io.Writer) error {
+			_, err := w.Write(document)
+			return err
+		}),
+	)
+
+	d := gomail.NewDialer(smtpConfig.SMTPHost, smtpConfig.SMTPPort, smtpConfig.SMTPUsername, smtpConfig.SMTPPassword)
+	if err := d.DialAndSend(m); err != nil {
+		return errors.New("failed to send email")
+	}
+	return nil
+}
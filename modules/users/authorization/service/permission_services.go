@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type PermissionService interface {
@@ -20,6 +21,10 @@ type PermissionService interface {
 	RemovePermissionFromRole(roleID, permissionID, userID string) error
 	HasPermission(userID, permissionName string) (bool, error)
 	GetUserRoleLevel(userID string) (int, error)
+	HasEventPermission(userID, eventID, permissionName string) (bool, error)
+	GrantEventPermission(eventID, userID, permissionName, grantedBy string) error
+	RevokeEventPermission(eventID, userID, permissionName string) error
+	GetEventPermissionGrants(eventID, permissionName string) ([]model.EventPermissionGrant, error)
 }
 
 type permissionService struct {
@@ -288,6 +293,56 @@ func (s *permissionService) HasPermission(userID, permissionName string) (bool,
 	return count > 0, nil
 }
 
+// HasEventPermission reports whether the user holds the permission for one
+// event, either through their role or through a grant for that event
+func (s *permissionService) HasEventPermission(userID, eventID, permissionName string) (bool, error) {
+	hasPerm, err := s.HasPermission(userID, permissionName)
+	if err != nil || hasPerm {
+		return hasPerm, err
+	}
+
+	var count int64
+	if err := s.db.Model(&model.EventPermissionGrant{}).
+		Where("event_id = ? AND user_id = ? AND permission_name = ?", eventID, userID, permissionName).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (s *permissionService) GrantEventPermission(eventID, userID, permissionName, grantedBy string) error {
+	grant := model.EventPermissionGrant{
+		EventID:        eventID,
+		UserID:         userID,
+		PermissionName: permissionName,
+		CreatedBy:      grantedBy,
+	}
+	// Granting a permission the user already holds is a no-op
+	return s.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&grant).Error
+}
+
+func (s *permissionService) RevokeEventPermission(eventID, userID, permissionName string) error {
+	result := s.db.Where("event_id = ? AND user_id = ? AND permission_name = ?", eventID, userID, permissionName).
+		Delete(&model.EventPermissionGrant{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("event permission not found")
+	}
+	return nil
+}
+
+func (s *permissionService) GetEventPermissionGrants(eventID, permissionName string) ([]model.EventPermissionGrant, error) {
+	var grants []model.EventPermissionGrant
+	if err := s.db.Where("event_id = ? AND permission_name = ?", eventID, permissionName).
+		Order("created_at ASC").
+		Find(&grants).Error; err != nil {
+		return nil, err
+	}
+	return grants, nil
+}
+
 func (s *permissionService) GetUserRoleLevel(userID string) (int, error) {
 	var role model.Role
 	err := s.db.Table("user_profiles").
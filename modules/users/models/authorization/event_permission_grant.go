@@ -0,0 +1,30 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// EventPermissionGrant gives a user a permission for a single event only,
+// for example door staff who may scan tickets at one event
+type EventPermissionGrant struct {
+	ID             string    `gorm:"type:char(36);primaryKey" json:"id"`
+	EventID        string    `gorm:"type:char(36);not null;uniqueIndex:idx_event_user_permission" json:"event_id"`
+	UserID         string    `gorm:"type:char(36);not null;uniqueIndex:idx_event_user_permission;index" json:"user_id"`
+	PermissionName string    `gorm:"size:100;not null;uniqueIndex:idx_event_user_permission" json:"permission_name"`
+	CreatedBy      string    `gorm:"type:char(36);not null" json:"created_by"`
+	CreatedAt      time.Time `gorm:"autoCreateTime;not null" json:"created_at"`
+}
+
+func (g *EventPermissionGrant) BeforeCreate(tx *gorm.DB) (err error) {
+	if g.ID == "" {
+		g.ID = uuid.New().String()
+	}
+	return nil
+}
+
+func (EventPermissionGrant) TableName() string {
+	return "event_permission_grants"
+}
@@ -0,0 +1,97 @@
+package queue
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+type scheduledTask struct {
+	name     string
+	interval time.Duration
+	job      Job
+}
+
+// Scheduler runs registered jobs on a fixed interval through a JobQueue.
+// Before each run it takes a Redis lock that lives for one interval, so when
+// several API instances share the same Redis only one of them runs a given
+// job per interval.
+type Scheduler struct {
+	redisClient *redis.Client
+	jobQueue    *JobQueue
+	instanceID  string
+	tasks       []scheduledTask
+	stopChan    chan struct{}
+	waitGroup   sync.WaitGroup
+	stopOnce    sync.Once
+}
+
+func NewScheduler(redisClient *redis.Client, jobQueue *JobQueue) *Scheduler {
+	return &Scheduler{
+		redisClient: redisClient,
+		jobQueue:    jobQueue,
+		instanceID:  uuid.New().String(),
+		stopChan:    make(chan struct{}),
+	}
+}
+
+// Every registers a job to run once per interval. It must be called before Start.
+func (s *Scheduler) Every(name string, interval time.Duration, job Job) {
+	s.tasks = append(s.tasks, scheduledTask{
+		name:     name,
+		interval: interval,
+		job:      job,
+	})
+}
+
+func (s *Scheduler) Start() {
+	for _, task := range s.tasks {
+		s.waitGroup.Add(1)
+		go s.run(task)
+	}
+}
+
+// Stop halts the tickers and waits for them to exit. Jobs already handed to
+// the queue are left to finish there.
+func (s *Scheduler) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.stopChan)
+	})
+	s.waitGroup.Wait()
+}
+
+func (s *Scheduler) run(task scheduledTask) {
+	defer s.waitGroup.Done()
+
+	ticker := time.NewTicker(task.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stopChan:
+			return
+		case <-ticker.C:
+			if s.acquire(task) {
+				s.jobQueue.Enqueue(task.job)
+			}
+		}
+	}
+}
+
+// acquire claims the current run of a task for this instance
+func (s *Scheduler) acquire(task scheduledTask) bool {
+	if s.redisClient == nil {
+		return true
+	}
+
+	ok, err := s.redisClient.SetNX(context.Background(), "scheduler:lock:"+task.name, s.instanceID, task.interval).Result()
+	if err != nil {
+		log.Printf("Scheduler failed to acquire lock for %s: %v", task.name, err)
+		return false
+	}
+	return ok
+}